@@ -0,0 +1,60 @@
+// backup.go backup command code
+package backup
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/backup/sources"
+	"github.com/tphakala/birdnet-go/internal/backup/targets"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates the backup parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Commands for listing and restoring backups",
+	}
+
+	// Add subcommands here
+	backupCmd.AddCommand(ListCommand(settings))
+	backupCmd.AddCommand(RestoreCommand(settings))
+
+	return backupCmd
+}
+
+// newManager creates a backup manager with the database sources and all
+// enabled targets of the configuration registered. They are registered even
+// if scheduled backups are disabled so existing backups can still be restored.
+func newManager(settings *conf.Settings) (*backup.Manager, error) {
+	level := slog.LevelWarn
+	if settings.Backup.Debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	stateManager, err := backup.NewStateManager(logger)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing backup state: %w", err)
+	}
+
+	manager, err := backup.NewManager(settings, logger, stateManager, settings.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing backup manager: %w", err)
+	}
+
+	// Sources are needed to restore database dumps such as MySQL backups and
+	// clip archives, the datastore is only needed when creating clip backups
+	for _, err := range sources.RegisterConfigured(manager, settings, nil, logger) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	for _, err := range targets.RegisterConfigured(manager, &settings.Backup, logger) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	return manager, nil
+}
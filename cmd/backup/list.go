@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// ListCommand creates the list subcommand
+func ListCommand(settings *conf.Settings) *cobra.Command {
+	var jsonOutput bool
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List backups stored in the configured backup targets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := newManager(settings)
+			if err != nil {
+				return err
+			}
+
+			backups, err := manager.ListBackups(context.Background())
+			if err != nil {
+				// Partial results are still shown when only some targets failed
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+
+			if jsonOutput {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(backups)
+			}
+
+			if len(backups) == 0 {
+				fmt.Println("No backups found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tTarget\tCreated\tSize\tEncrypted\t")
+			for i := range backups {
+				b := &backups[i]
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\t\n",
+					b.ID, b.Target, b.Timestamp.Format("2006-01-02 15:04:05"), b.Size, b.Encrypted)
+			}
+			return w.Flush()
+		},
+	}
+
+	listCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print backups as JSON")
+
+	return listCmd
+}
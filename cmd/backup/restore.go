@@ -0,0 +1,96 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// RestoreCommand creates the restore subcommand
+func RestoreCommand(settings *conf.Settings) *cobra.Command {
+	var opts backup.RestoreOptions
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <backup-id>",
+		Short: "Restore the database from a backup",
+		Long: `Downloads a backup from the configured backup targets, verifies its checksum,
+decrypts it if needed and replaces the SQLite database with the archived copy.
+The previous database is kept next to the restored one with a .pre-restore
+suffix. Stop BirdNET-Go before restoring, or use --stage to have the database
+replaced the next time BirdNET-Go starts.
+
+With --config the configuration file is restored as well. Backups contain a
+sanitized configuration, so passwords and API keys have to be entered again.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.RestoreConfig && opts.ConfigPath == "" {
+				opts.ConfigPath = viper.ConfigFileUsed()
+				if opts.ConfigPath == "" {
+					configPath, err := conf.FindConfigFile()
+					if err != nil {
+						return fmt.Errorf("error finding configuration file: %w", err)
+					}
+					opts.ConfigPath = configPath
+				}
+			}
+
+			manager, err := newManager(settings)
+			if err != nil {
+				return err
+			}
+
+			result, err := manager.RestoreBackup(context.Background(), args[0], &opts)
+			if err != nil {
+				return fmt.Errorf("error restoring backup: %w", err)
+			}
+
+			printResult(result)
+			return nil
+		},
+	}
+
+	restoreCmd.Flags().StringVar(&opts.Target, "target", "", "Restore from this backup target type, e.g. local or sftp")
+	restoreCmd.Flags().StringVar(&opts.DatabasePath, "db", "", "Database file to replace, defaults to the configured SQLite path")
+	restoreCmd.Flags().BoolVar(&opts.RestoreConfig, "config", false, "Also restore the configuration file")
+	restoreCmd.Flags().StringVar(&opts.ConfigPath, "config-path", "", "Configuration file to replace, defaults to the active configuration file")
+	restoreCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Download and verify the backup without replacing any files")
+	restoreCmd.Flags().BoolVar(&opts.Stage, "stage", false, "Replace the SQLite database at the next start of BirdNET-Go, for restoring while it is running")
+
+	return restoreCmd
+}
+
+// printResult prints a summary of a restore
+func printResult(result *backup.RestoreResult) {
+	fmt.Printf("Backup:    %s (%s, created %s)\n", result.BackupID, result.Target, result.Metadata.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Encrypted: %t\n", result.Encrypted)
+	if result.ChecksumVerified {
+		fmt.Println("Checksum:  verified")
+	} else {
+		fmt.Println("Checksum:  not available, archive contents verified only")
+	}
+	fmt.Printf("Database:  %d bytes\n", result.DatabaseSize)
+
+	if result.DryRun {
+		fmt.Println("\nDry run, no files were replaced")
+		return
+	}
+
+	if result.Staged {
+		fmt.Printf("\nStaged database restore to %s, it is applied at the next start of BirdNET-Go\n", result.DatabasePath)
+	} else {
+		fmt.Printf("\nRestored database to %s\n", result.DatabasePath)
+	}
+	if result.DatabaseBackup != "" {
+		fmt.Printf("Previous database kept as %s\n", result.DatabaseBackup)
+	}
+	if result.ConfigPath != "" {
+		fmt.Printf("Restored configuration to %s\n", result.ConfigPath)
+		if result.ConfigBackup != "" {
+			fmt.Printf("Previous configuration kept as %s\n", result.ConfigBackup)
+		}
+	}
+}
@@ -74,7 +74,9 @@ func setupFlags(cmd *cobra.Command, settings *conf.Settings) error {
 	cmd.Flags().BoolVarP(&settings.Input.Recursive, "recursive", "r", false, "Recursively analyze subdirectories")
 	cmd.Flags().BoolVarP(&settings.Input.Watch, "watch", "w", false, "Watch directory for new files")
 	cmd.Flags().StringVarP(&settings.Output.File.Path, "output", "o", viper.GetString("output.file.path"), "Path to output directory")
-	cmd.Flags().StringVar(&settings.Output.File.Type, "type", viper.GetString("output.file.type"), "Output type: table, csv")
+	cmd.Flags().StringVar(&settings.Output.File.Type, "type", viper.GetString("output.file.type"), "Output type: table, csv, raven, audacity")
+	cmd.Flags().StringVar(&settings.Input.Manifest, "manifest", "", "Path to the manifest tracking analysis progress (default <output>/birdnet-go-manifest.jsonl)")
+	cmd.Flags().BoolVar(&settings.Output.File.PerFile, "per-file", viper.GetBool("output.file.perfile"), "Write one output file per analyzed audio file, otherwise combine results in one file")
 
 	if err := viper.BindPFlags(cmd.Flags()); err != nil {
 		return fmt.Errorf("error binding flags: %w", err)
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/dwca"
+)
+
+// DarwinCoreCommand creates the dwca subcommand
+func DarwinCoreCommand(settings *conf.Settings) *cobra.Command {
+	var (
+		output       string
+		verifiedOnly bool
+		filters      datastore.SearchFilters
+	)
+
+	dwcaCmd := &cobra.Command{
+		Use:   "dwca",
+		Short: "Export detections as a Darwin Core Archive for GBIF",
+		Long: `Writes detections from the database as a Darwin Core Archive with an
+occurrence core, a multimedia extension referencing the audio clips and the
+dataset metadata from output.darwincore in config.yaml. Detections reviewed as
+false positives and non-species labels are never exported.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, date := range []string{filters.DateStart, filters.DateEnd} {
+				if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+					return fmt.Errorf("invalid date %q, use YYYY-MM-DD", date)
+				}
+			}
+			filters.VerifiedOnly = verifiedOnly
+			filters.Ctx = context.Background()
+			if output == "" {
+				output = fmt.Sprintf("birdnet-go-dwca-%s.zip", time.Now().Format("20060102"))
+			}
+
+			ds := datastore.New(settings)
+			if ds == nil {
+				return fmt.Errorf("no database configured, enable sqlite or mysql output")
+			}
+			if err := ds.Open(); err != nil {
+				return fmt.Errorf("error opening database: %w", err)
+			}
+			defer func() {
+				if err := ds.Close(); err != nil {
+					fmt.Printf("Error closing database: %v\n", err)
+				}
+			}()
+
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("error creating archive: %w", err)
+			}
+
+			count, err := dwca.Export(ds, &filters, file, dwca.MetadataFromSettings(settings))
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(output)
+				return fmt.Errorf("error exporting detections: %w", err)
+			}
+
+			fmt.Printf("Exported %d occurrences to %s\n", count, output)
+			return nil
+		},
+	}
+
+	dwcaCmd.Flags().StringVarP(&output, "output", "o", "", "Archive file to write (default birdnet-go-dwca-YYYYMMDD.zip)")
+	dwcaCmd.Flags().BoolVar(&verifiedOnly, "verified-only", false, "Only export detections reviewed as correct")
+	dwcaCmd.Flags().StringVar(&filters.DateStart, "start-date", "", "First date to export (YYYY-MM-DD)")
+	dwcaCmd.Flags().StringVar(&filters.DateEnd, "end-date", "", "Last date to export (YYYY-MM-DD)")
+	dwcaCmd.Flags().StringVar(&filters.Species, "species", "", "Only export species matching this name")
+	dwcaCmd.Flags().Float64Var(&filters.ConfidenceMin, "min-confidence", 0, "Minimum confidence between 0.0 and 1.0")
+
+	return dwcaCmd
+}
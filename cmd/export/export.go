@@ -0,0 +1,20 @@
+// export.go export command code
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates the export parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Commands for exporting detections",
+	}
+
+	// Add subcommands here
+	exportCmd.AddCommand(DarwinCoreCommand(settings))
+
+	return exportCmd
+}
@@ -8,13 +8,16 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tphakala/birdnet-go/cmd/authors"
+	"github.com/tphakala/birdnet-go/cmd/backup"
 	"github.com/tphakala/birdnet-go/cmd/benchmark"
 	"github.com/tphakala/birdnet-go/cmd/directory"
+	"github.com/tphakala/birdnet-go/cmd/export"
 	"github.com/tphakala/birdnet-go/cmd/file"
 	"github.com/tphakala/birdnet-go/cmd/license"
 	"github.com/tphakala/birdnet-go/cmd/rangefilter"
 	"github.com/tphakala/birdnet-go/cmd/realtime"
 	"github.com/tphakala/birdnet-go/cmd/support"
+	"github.com/tphakala/birdnet-go/cmd/thresholds"
 	"github.com/tphakala/birdnet-go/internal/conf"
 )
 
@@ -41,6 +44,9 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 	rangeCmd := rangefilter.Command(settings)
 	supportCmd := support.Command(settings)
 	benchmarkCmd := benchmark.Command(settings)
+	thresholdsCmd := thresholds.Command(settings)
+	backupCmd := backup.Command(settings)
+	exportCmd := export.Command(settings)
 
 	subcommands := []*cobra.Command{
 		fileCmd,
@@ -51,6 +57,9 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 		rangeCmd,
 		supportCmd,
 		benchmarkCmd,
+		thresholdsCmd,
+		backupCmd,
+		exportCmd,
 	}
 
 	rootCmd.AddCommand(subcommands...)
@@ -0,0 +1,136 @@
+package thresholds
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/calibration"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// Output formats of the suggest subcommand
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// SuggestCommand creates the suggest subcommand
+func SuggestCommand(settings *conf.Settings) *cobra.Command {
+	var (
+		opts    calibration.SuggestOptions
+		species string
+		format  string
+		all     bool
+	)
+
+	suggestCmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest per-species confidence thresholds from reviewed detections",
+		Long: `Computes precision and recall at candidate thresholds for every species with
+enough reviewed detections and suggests the lowest threshold reaching the
+target precision. Use --format yaml to print a species config snippet for
+config.yaml.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case formatTable, formatJSON, formatYAML:
+			default:
+				return fmt.Errorf("invalid format %q, use table, json or yaml", format)
+			}
+
+			ds := datastore.New(settings)
+			if ds == nil {
+				return fmt.Errorf("no database configured, enable sqlite or mysql output")
+			}
+			if err := ds.Open(); err != nil {
+				return fmt.Errorf("error opening database: %w", err)
+			}
+			defer func() {
+				if err := ds.Close(); err != nil {
+					fmt.Printf("Error closing database: %v\n", err)
+				}
+			}()
+
+			reviewed, err := ds.GetReviewedDetections(species)
+			if err != nil {
+				return fmt.Errorf("error getting reviewed detections: %w", err)
+			}
+
+			opts.ChangedOnly = !all
+			suggestions := calibration.SuggestThresholds(settings, reviewed, opts)
+
+			switch format {
+			case formatJSON:
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(suggestions)
+			case formatYAML:
+				printYAML(suggestions)
+			default:
+				printTable(suggestions, len(reviewed), opts)
+			}
+			return nil
+		},
+	}
+
+	suggestCmd.Flags().IntVar(&opts.MinReviews, "min-reviews", calibration.DefaultSuggestMinReviews, "Minimum number of reviewed detections per species")
+	suggestCmd.Flags().Float64Var(&opts.TargetPrecision, "precision", calibration.DefaultSuggestTargetPrecision, "Target precision between 0.0 and 1.0")
+	suggestCmd.Flags().StringVar(&species, "species", "", "Only suggest a threshold for this scientific name")
+	suggestCmd.Flags().StringVar(&opts.Source, "source", "", "Only use reviews of this audio source, by name or URL, and its threshold")
+	suggestCmd.Flags().StringVar(&format, "format", formatTable, "Output format: table, json or yaml")
+	suggestCmd.Flags().BoolVar(&all, "all", false, "Include species whose current threshold is kept")
+
+	return suggestCmd
+}
+
+// printTable prints the suggestions as a table
+func printTable(suggestions []calibration.Suggestion, reviews int, opts calibration.SuggestOptions) {
+	fmt.Printf("Reviewed detections: %d, minimum reviews per species: %d, target precision: %.2f\n\n",
+		reviews, opts.MinReviews, opts.TargetPrecision)
+
+	if len(suggestions) == 0 {
+		fmt.Println("No threshold changes to suggest")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Species\tReviews\tFalse pos.\tCurrent\tPrecision\tSuggested\tPrecision\tRecall\t")
+	for i := range suggestions {
+		s := &suggestions[i]
+		current := fmt.Sprintf("%.2f", s.CurrentThreshold)
+		if s.CustomThreshold {
+			current += "*"
+		}
+		suggested := fmt.Sprintf("%.2f", s.SuggestedThreshold)
+		if !s.TargetReached {
+			suggested += " (!)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%.2f\t%s\t%.2f\t%.2f\t\n",
+			s.CommonName, s.Reviews, s.FalsePositives,
+			current, s.Current.Precision,
+			suggested, s.Suggested.Precision, s.Suggested.Recall)
+	}
+	_ = w.Flush()
+
+	fmt.Println("\n* custom threshold from species config")
+	fmt.Println("(!) target precision not reachable, threshold with best F1 score suggested")
+}
+
+// printYAML prints the suggestions as a species config snippet
+func printYAML(suggestions []calibration.Suggestion) {
+	fmt.Println("# Suggested species thresholds, merge into realtime.species.config")
+	fmt.Println("realtime:")
+	fmt.Println("  species:")
+	fmt.Println("    config:")
+	for i := range suggestions {
+		s := &suggestions[i]
+		fmt.Printf("      %q:\n", strings.ToLower(s.CommonName))
+		fmt.Printf("        threshold: %.2f # precision %.2f, recall %.2f, %d reviews\n",
+			s.SuggestedThreshold, s.Suggested.Precision, s.Suggested.Recall, s.Reviews)
+	}
+}
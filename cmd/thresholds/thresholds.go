@@ -0,0 +1,20 @@
+// thresholds.go threshold command code
+package thresholds
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates the thresholds parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	thresholdsCmd := &cobra.Command{
+		Use:   "thresholds",
+		Short: "Commands related to species confidence thresholds",
+	}
+
+	// Add subcommands here
+	thresholdsCmd.AddCommand(SuggestCommand(settings))
+
+	return thresholdsCmd
+}
@@ -2,11 +2,14 @@ package analysis
 
 import (
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+	"github.com/tphakala/birdnet-go/internal/predictionarchive"
 )
 
 var bn *birdnet.BirdNET // BirdNET interpreter
@@ -52,3 +55,39 @@ func UpdateBirdNETModelLoadedMetric(birdnetMetrics *metrics.BirdNETMetrics) {
 		birdnetMetrics.RecordModelLoad(modelNameBirdNET, nil)
 	}
 }
+
+// initializePredictionArchive starts the prediction archive writer if enabled in settings
+// and attaches it to the BirdNET instance. Returns nil if archiving is disabled.
+func initializePredictionArchive(settings *conf.Settings) (*predictionarchive.Writer, error) {
+	archiveSettings := settings.BirdNET.PredictionArchive
+	if !archiveSettings.Enabled || bn == nil {
+		return nil, nil
+	}
+
+	writer, err := predictionarchive.NewWriter(predictionarchive.Options{
+		Dir:           conf.GetBasePath(archiveSettings.Path),
+		BlockSize:     archiveSettings.BlockSize,
+		FlushInterval: time.Duration(archiveSettings.FlushInterval) * time.Second,
+		RetentionDays: archiveSettings.RetentionDays,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize prediction archive: %w", err)
+	}
+
+	bn.SetPredictionArchive(writer)
+	log.Printf("🗄️ Prediction archive enabled, writing full prediction vectors to %s", archiveSettings.Path)
+	return writer, nil
+}
+
+// closePredictionArchive detaches the prediction archive from BirdNET and flushes it to disk.
+func closePredictionArchive(writer *predictionarchive.Writer) {
+	if writer == nil {
+		return
+	}
+	if bn != nil {
+		bn.SetPredictionArchive(nil)
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("⚠️ Failed to close prediction archive: %v", err)
+	}
+}
@@ -184,6 +184,10 @@ func (cm *ControlMonitor) handleControlSignal(signal string) {
 		cm.handleReconfigureBirdWeather()
 	case "update_detection_intervals":
 		cm.handleUpdateDetectionIntervals()
+	case "update_action_schedule":
+		cm.handleUpdateActionSchedule()
+	case "rebuild_detection_filters":
+		cm.handleRebuildDetectionFilters()
 	case "reconfigure_sound_level":
 		cm.handleReconfigureSoundLevel()
 	case "reconfigure_telemetry":
@@ -274,11 +278,8 @@ func (cm *ControlMonitor) handleReconfigureRTSP() {
 	log.Printf("\033[32m🔄 Reconfiguring RTSP sources...\033[0m")
 	settings := conf.Setting()
 
-	// Prepare the list of active sources
-	var sources []string
-	if len(settings.Realtime.RTSP.URLs) > 0 {
-		sources = append(sources, settings.Realtime.RTSP.URLs...)
-	}
+	// Prepare the list of active sources, including named RTSP sources
+	sources := settings.Realtime.RTSP.AllURLs()
 	if settings.Realtime.Audio.Source != "" {
 		sources = append(sources, "malgo")
 	}
@@ -446,6 +447,41 @@ func (cm *ControlMonitor) handleUpdateDetectionIntervals() {
 	cm.notifySuccess("Detection rate limits updated successfully")
 }
 
+// handleUpdateActionSchedule rebuilds the schedule rules gating detection actions
+func (cm *ControlMonitor) handleUpdateActionSchedule() {
+	if cm.proc == nil {
+		log.Printf("\033[31m❌ Error: Processor not available\033[0m")
+		cm.notifyError("Failed to update action schedules", fmt.Errorf("processor not available"))
+		return
+	}
+
+	cm.proc.SetActionScheduler(processor.NewActionScheduler(conf.Setting()))
+
+	log.Printf("\033[32m✅ Action schedules updated successfully\033[0m")
+	cm.notifySuccess("Action schedules updated successfully")
+}
+
+// handleRebuildDetectionFilters reorders the detection filter chain to the
+// configured order, keeping the state of the running filters
+func (cm *ControlMonitor) handleRebuildDetectionFilters() {
+	if cm.proc == nil {
+		log.Printf("\033[31m❌ Error: Processor not available\033[0m")
+		cm.notifyError("Failed to rebuild detection filters", fmt.Errorf("processor not available"))
+		return
+	}
+
+	chain := cm.proc.GetFilterChain()
+	if chain != nil {
+		chain = chain.Reorder()
+	} else {
+		chain = processor.NewFilterChain(conf.Setting(), cm.proc.Ds)
+	}
+	cm.proc.SetFilterChain(chain)
+
+	log.Printf("\033[32m✅ Detection filters rebuilt successfully, order: %v\033[0m", chain.Names())
+	cm.notifySuccess("Detection filters rebuilt successfully")
+}
+
 // notifySuccess sends a success notification
 func (cm *ControlMonitor) notifySuccess(message string) {
 	cm.notificationChan <- handlers.Notification{
@@ -14,8 +14,112 @@ import (
 	"time"
 
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observation"
 )
 
+// directoryRun holds the state of a directory analysis shared by all analysed files
+type directoryRun struct {
+	out      *combinedOutput // Combined output of all files, nil for per file output
+	manifest *Manifest       // Progress of the analysis
+}
+
+// combinedOutput collects the results of all files of a directory analysis in
+// one output file when per file output is disabled
+type combinedOutput struct {
+	path      string
+	fileType  string
+	selection int  // Selection number of the next row
+	started   bool // Output file has been created during this analysis
+}
+
+// newCombinedOutput returns the combined output of the analysed directory, the
+// file is named after the directory
+func newCombinedOutput(settings *conf.Settings) (*combinedOutput, error) {
+	fileType := settings.Output.File.Type
+	if fileType == observation.OutputTypeAudacity {
+		return nil, fmt.Errorf("audacity labels can't combine several audio files, enable per file output")
+	}
+	if fileType != "" && !observation.IsOutputType(fileType) {
+		return nil, fmt.Errorf("unsupported output type %q, use table, csv, raven or audacity", fileType)
+	}
+
+	dirName := filepath.Base(filepath.Clean(settings.Input.Path))
+	if dirName == "." || dirName == string(filepath.Separator) {
+		dirName = "results"
+	}
+	return &combinedOutput{
+		path:      filepath.Join(settings.Output.File.Path, dirName+observation.OutputExtension(fileType)),
+		fileType:  fileType,
+		selection: 1,
+	}, nil
+}
+
+// resume continues a combined output of an earlier run at the given selection number
+func (o *combinedOutput) resume(selection int) {
+	o.selection = selection
+	o.started = true
+}
+
+// write appends the notes of one audio file to the combined output, the file
+// is truncated and the header written on the first write
+func (o *combinedOutput) write(settings *conf.Settings, notes []datastore.Note) error {
+	flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !o.started {
+		flag |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(o.path, flag, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file %s: %w", o.path, err)
+	}
+
+	next, err := observation.WriteNotes(file, settings, notes, o.fileType, o.selection, !o.started)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close output file %s: %w", o.path, closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	o.selection = next
+	o.started = true
+	log.Printf("Output of %s written to %s", settings.Input.Path, o.path)
+	return nil
+}
+
+// fileOutput writes the detections of one audio file. Detections for the
+// combined output are held back until the analysis succeeded, partial results
+// of a failed file would otherwise be duplicated when the file is retried.
+type fileOutput struct {
+	settings   *conf.Settings
+	out        *combinedOutput // Combined output of the run, nil for per file output
+	pending    []datastore.Note
+	detections int // Detections written to the output
+}
+
+// write writes the notes to the output of the file, or holds them back for
+// the combined output
+func (f *fileOutput) write(notes []datastore.Note) error {
+	if f.out != nil {
+		f.pending = notes
+		return nil
+	}
+	f.detections += countDetections(f.settings, notes)
+	return writeResults(f.settings, notes)
+}
+
+// flush appends the held back notes of a successful analysis to the combined output
+func (f *fileOutput) flush() error {
+	if f.out == nil || f.pending == nil {
+		return nil
+	}
+	notes := f.pending
+	f.pending = nil
+	f.detections += countDetections(f.settings, notes)
+	return f.out.write(f.settings, notes)
+}
+
 // cleanupProcessingFiles removes all .processing files from the output directory
 func cleanupProcessingFiles(outputPath string) {
 	pattern := filepath.Join(outputPath, "*.processing")
@@ -33,29 +137,43 @@ func cleanupProcessingFiles(outputPath string) {
 	}
 }
 
-// isProcessed checks if a file has already been processed
-func isProcessed(path, outputPath string, processedFiles map[string]bool) bool {
+// isProcessed checks if a file has already been processed. Files known to the
+// manifest are only skipped if their analysis was completed, so that failed
+// files are retried even if partial results were written.
+func isProcessed(path, outputPath string, processedFiles map[string]bool, manifest *Manifest) bool {
 	// Check if we have already processed this file in memory
 	if processedFiles[path] {
 		return true
 	}
 
+	// Check the manifest of earlier runs
+	var known bool
+	if manifest != nil {
+		var completed bool
+		if completed, known = manifest.Completed(path); completed {
+			processedFiles[path] = true
+			return true
+		}
+	}
+
 	// Get the base filename without extension
 	baseName := filepath.Base(path)
 
 	// Check for output files
 	outputPathCSV := filepath.Join(outputPath, baseName+".csv")
 	outputPathTable := filepath.Join(outputPath, baseName+".txt")
+	outputPathRaven := filepath.Join(outputPath, baseName+observation.OutputExtension(observation.OutputTypeRaven))
+	outputPathAudacity := filepath.Join(outputPath, baseName+observation.OutputExtension(observation.OutputTypeAudacity))
 	outputPathProcessing := filepath.Join(outputPath, baseName+".processing")
 
 	// Check if any of the output files exist
-	if _, err := os.Stat(outputPathCSV); err == nil {
-		processedFiles[path] = true
-		return true
-	}
-	if _, err := os.Stat(outputPathTable); err == nil {
-		processedFiles[path] = true
-		return true
+	if !known {
+		for _, output := range []string{outputPathCSV, outputPathTable, outputPathRaven, outputPathAudacity} {
+			if _, err := os.Stat(output); err == nil {
+				processedFiles[path] = true
+				return true
+			}
+		}
 	}
 
 	// Check for processing lock file
@@ -174,9 +292,10 @@ func isFileReadyForProcessing(path string, ctx context.Context) (bool, error) {
 	return true, nil
 }
 
-// processFile handles the analysis of a single audio file
-func processFile(path string, settings *conf.Settings, processedFiles map[string]bool, ctx context.Context) (bool, error) {
-	if isProcessed(path, settings.Output.File.Path, processedFiles) {
+// processFile handles the analysis of a single audio file. Results are written
+// to an output of their own unless the run has a combined output.
+func processFile(path string, settings *conf.Settings, processedFiles map[string]bool, run *directoryRun, ctx context.Context) (bool, error) {
+	if isProcessed(path, settings.Output.File.Path, processedFiles, run.manifest) {
 		return false, nil // File was already processed
 	}
 
@@ -217,6 +336,21 @@ func processFile(path string, settings *conf.Settings, processedFiles map[string
 		log.Printf("Failed to close lock file: %v", err)
 	}
 
+	// Record the start of the analysis in the manifest
+	if run.manifest != nil {
+		if err := run.manifest.Start(path); err != nil {
+			log.Printf("Failed to record %s in manifest: %v", path, err)
+		}
+	}
+
+	// Audio duration for the manifest
+	var duration time.Duration
+	if info, err := myaudio.GetAudioInfo(path); err == nil && info.SampleRate > 0 {
+		duration = time.Duration(float64(info.TotalSamples) / float64(info.SampleRate) * float64(time.Second))
+	}
+
+	output := &fileOutput{settings: settings, out: run.out}
+
 	// Save the original path and restore it after processing
 	origPath := settings.Input.Path
 	settings.Input.Path = path
@@ -228,7 +362,7 @@ func processFile(path string, settings *conf.Settings, processedFiles map[string
 	// Run FileAnalysis in a goroutine so we can handle interruption
 	analysisDone := make(chan error)
 	go func() {
-		analysisDone <- FileAnalysis(settings, analysisCtx)
+		analysisDone <- analyzeFile(settings, analysisCtx, output.write)
 	}()
 
 	// Wait for either completion or interruption
@@ -242,6 +376,11 @@ func processFile(path string, settings *conf.Settings, processedFiles map[string
 		analysisErr = <-analysisDone // Wait for FileAnalysis to clean up
 	}
 
+	// Partial results of a failed analysis are not added to the combined output
+	if analysisErr == nil {
+		analysisErr = output.flush()
+	}
+
 	settings.Input.Path = origPath
 
 	// Remove lock file regardless of processing result
@@ -249,6 +388,17 @@ func processFile(path string, settings *conf.Settings, processedFiles map[string
 		log.Printf("Warning: failed to remove lock file %s: %v", lockFile, removeErr)
 	}
 
+	// Record the result in the manifest
+	if run.manifest != nil {
+		nextSelection := 0
+		if run.out != nil {
+			nextSelection = run.out.selection
+		}
+		if err := run.manifest.Finish(path, duration, output.detections, nextSelection, analysisErr); err != nil {
+			log.Printf("Failed to record result of %s in manifest: %v", path, err)
+		}
+	}
+
 	if analysisErr != nil {
 		if errors.Is(analysisErr, context.Canceled) {
 			return false, context.Canceled
@@ -262,7 +412,7 @@ func processFile(path string, settings *conf.Settings, processedFiles map[string
 }
 
 // scanDirectory scans a directory for audio files and processes them
-func scanDirectory(watchDir string, settings *conf.Settings, processedFiles map[string]bool, ctx context.Context) error {
+func scanDirectory(watchDir string, settings *conf.Settings, processedFiles map[string]bool, run *directoryRun, ctx context.Context) error {
 	log.Printf("Scanning directory: %s", watchDir)
 	startTime := time.Now()
 	filesAnalyzed := 0
@@ -290,7 +440,7 @@ func scanDirectory(watchDir string, settings *conf.Settings, processedFiles map[
 		// Check for both .wav and .flac files (case-insensitive)
 		ext := strings.ToLower(filepath.Ext(d.Name()))
 		if ext == ".wav" || ext == ".flac" {
-			wasProcessed, err := processFile(path, settings, processedFiles, ctx)
+			wasProcessed, err := processFile(path, settings, processedFiles, run, ctx)
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
 					return context.Canceled
@@ -324,7 +474,7 @@ func scanDirectory(watchDir string, settings *conf.Settings, processedFiles map[
 }
 
 // watchDirectory continuously monitors a directory for new files
-func watchDirectory(watchDir string, settings *conf.Settings, processedFiles map[string]bool, ctx context.Context) error {
+func watchDirectory(watchDir string, settings *conf.Settings, processedFiles map[string]bool, run *directoryRun, ctx context.Context) error {
 	log.Printf("Starting directory watch on %s (Press Ctrl+C to stop)", watchDir)
 	watchStartTime := time.Now()
 
@@ -340,7 +490,7 @@ func watchDirectory(watchDir string, settings *conf.Settings, processedFiles map
 
 		case <-timer.C:
 			// Do the scan
-			if err := scanDirectory(watchDir, settings, processedFiles, ctx); err != nil {
+			if err := scanDirectory(watchDir, settings, processedFiles, run, ctx); err != nil {
 				if errors.Is(err, context.Canceled) {
 					cleanupProcessingFiles(settings.Output.File.Path)
 					return context.Canceled
@@ -372,12 +522,50 @@ func DirectoryAnalysis(settings *conf.Settings, ctx context.Context) error {
 		return err
 	}
 
+	// Combine the results of all files in one output if per file output is disabled
+	run := &directoryRun{}
+	var combinedPath string
+	if !settings.Output.File.PerFile {
+		out, err := newCombinedOutput(settings)
+		if err != nil {
+			return err
+		}
+		run.out = out
+		combinedPath = out.path
+	}
+
+	// Load the manifest of earlier runs to resume the analysis
+	manifestPath := settings.Input.Manifest
+	if manifestPath == "" {
+		manifestPath = filepath.Join(settings.Output.File.Path, manifestFileName)
+	}
+	manifest, err := LoadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Prepare(manifestKey(settings.Input.Path), settings.Output.File.Type, combinedPath); err != nil {
+		return err
+	}
+	if run.out != nil && manifest.NextSelection > 0 {
+		run.out.resume(manifest.NextSelection)
+	}
+	run.manifest = manifest
+	if summary := manifest.Summary(); summary.Files > 0 {
+		log.Printf("Resuming analysis from manifest %s, %d of %d file(s) completed", manifestPath, summary.Completed, summary.Files)
+	}
+	defer func() {
+		if err := manifest.Close(); err != nil {
+			log.Printf("Failed to close manifest: %v", err)
+		}
+	}()
+	defer reportManifest(manifest)
+
 	// Create a map to track processed files
 	processedFiles := make(map[string]bool)
 
 	// Do initial scan
 	log.Printf("Performing initial directory scan...")
-	if err := scanDirectory(settings.Input.Path, settings, processedFiles, ctx); err != nil {
+	if err := scanDirectory(settings.Input.Path, settings, processedFiles, run, ctx); err != nil {
 		if errors.Is(err, context.Canceled) {
 			return context.Canceled
 		}
@@ -391,5 +579,34 @@ func DirectoryAnalysis(settings *conf.Settings, ctx context.Context) error {
 	}
 
 	// Start watching directory
-	return watchDirectory(settings.Input.Path, settings, processedFiles, ctx)
+	return watchDirectory(settings.Input.Path, settings, processedFiles, run, ctx)
+}
+
+// countDetections returns the number of notes above the confidence threshold
+func countDetections(settings *conf.Settings, notes []datastore.Note) int {
+	count := 0
+	for i := range notes {
+		if notes[i].Confidence > settings.BirdNET.Threshold {
+			count++
+		}
+	}
+	return count
+}
+
+// reportManifest prints the summary of a directory analysis and writes it
+// next to the manifest
+func reportManifest(manifest *Manifest) {
+	var report strings.Builder
+	if err := manifest.WriteSummary(&report); err != nil {
+		log.Printf("Failed to create analysis summary: %v", err)
+		return
+	}
+	fmt.Print(report.String())
+
+	summaryPath := filepath.Join(filepath.Dir(manifest.Path()), summaryFileName)
+	if err := os.WriteFile(summaryPath, []byte(report.String()), 0o644); err != nil {
+		log.Printf("Failed to write analysis summary: %v", err)
+		return
+	}
+	log.Printf("Analysis summary written to %s", summaryPath)
 }
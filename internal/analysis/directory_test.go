@@ -0,0 +1,132 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/observation"
+)
+
+// outputTestNote returns a file analysis note starting at the given offset
+func outputTestNote(source, name string, offset time.Duration, confidence float64) datastore.Note {
+	return datastore.Note{
+		Source:         source,
+		BeginTime:      time.Time{}.Add(offset),
+		EndTime:        time.Time{}.Add(offset + 3*time.Second),
+		SpeciesCode:    "eurrob1",
+		ScientificName: "Erithacus rubecula",
+		CommonName:     name,
+		Confidence:     confidence,
+	}
+}
+
+func TestCombinedOutputRaven(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.5
+	settings.Input.Path = "/recordings/site1/"
+	settings.Output.File.Path = t.TempDir()
+	settings.Output.File.Type = observation.OutputTypeRaven
+
+	out, err := newCombinedOutput(settings)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(settings.Output.File.Path, "site1.selections.txt"), out.path)
+
+	require.NoError(t, out.write(settings, []datastore.Note{
+		outputTestNote("a.wav", "European Robin", 1500*time.Millisecond, 0.9),
+		outputTestNote("a.wav", "European Robin", 3*time.Second, 0.4), // Below threshold
+	}))
+	require.NoError(t, out.write(settings, []datastore.Note{
+		outputTestNote("b.wav", "European Robin", 6*time.Second, 0.8),
+	}))
+
+	data, err := os.ReadFile(out.path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 3, "header is written once")
+	assert.True(t, strings.HasPrefix(lines[0], "Selection\tView\tChannel\tBegin Time (s)"))
+	assert.Equal(t, "1\tSpectrogram 1\t1\t1.500\t4.500\t0\t15000\ta.wav\t1.500\teurrob1\tEuropean Robin\tErithacus rubecula\t0.9000", lines[1])
+	assert.Equal(t, "2\tSpectrogram 1\t1\t6.000\t9.000\t0\t15000\tb.wav\t6.000\teurrob1\tEuropean Robin\tErithacus rubecula\t0.8000", lines[2])
+
+	// A new analysis truncates the output of a previous run
+	again, err := newCombinedOutput(settings)
+	require.NoError(t, err)
+	require.NoError(t, again.write(settings, nil))
+	data, err = os.ReadFile(out.path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+}
+
+func TestFileOutputHoldsBackFailedResults(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.5
+	settings.Input.Path = "/recordings/site1/"
+	settings.Output.File.Path = t.TempDir()
+	settings.Output.File.Type = observation.OutputTypeCSV
+
+	out, err := newCombinedOutput(settings)
+	require.NoError(t, err)
+	notes := []datastore.Note{outputTestNote("a.wav", "European Robin", 0, 0.9)}
+
+	// Partial results of a failed analysis are never flushed
+	failed := &fileOutput{settings: settings, out: out}
+	require.NoError(t, failed.write(notes))
+	_, err = os.Stat(out.path)
+	assert.True(t, os.IsNotExist(err), "nothing is written before the analysis succeeded")
+
+	// The retried analysis writes the detections once
+	retry := &fileOutput{settings: settings, out: out}
+	require.NoError(t, retry.write(notes))
+	require.NoError(t, retry.flush())
+	require.NoError(t, retry.flush())
+	assert.Equal(t, 1, retry.detections)
+
+	data, err := os.ReadFile(out.path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "European Robin"))
+}
+
+func TestCombinedOutputRejectsAudacity(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Input.Path = "recordings"
+	settings.Output.File.Type = observation.OutputTypeAudacity
+	_, err := newCombinedOutput(settings)
+	require.Error(t, err)
+
+	settings.Output.File.Type = "xml"
+	_, err = newCombinedOutput(settings)
+	require.Error(t, err)
+}
+
+func TestWriteResultsAudacity(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.1
+	settings.Input.Path = "/recordings/a.wav"
+	settings.Output.File.Path = t.TempDir()
+	settings.Output.File.Type = observation.OutputTypeAudacity
+
+	require.NoError(t, writeResults(settings, []datastore.Note{
+		outputTestNote("a.wav", "European Robin", 4500*time.Millisecond, 0.91),
+	}))
+
+	data, err := os.ReadFile(filepath.Join(settings.Output.File.Path, "a.wav.labels.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "4.500\t7.500\tEuropean Robin (0.91)\n\\\t0\t15000\n", string(data))
+
+	settings.Output.File.Type = "xml"
+	require.Error(t, writeResults(settings, nil))
+}
@@ -59,6 +59,14 @@ func (h *errorHolder) getError() error {
 // FileAnalysis conducts an analysis of an audio file and outputs the results.
 // It reads an audio file, analyzes it for bird sounds, and prints the results based on the provided configuration.
 func FileAnalysis(settings *conf.Settings, ctx context.Context) error {
+	return analyzeFile(settings, ctx, func(notes []datastore.Note) error {
+		return writeResults(settings, notes)
+	})
+}
+
+// analyzeFile analyzes the audio file of settings.Input.Path and passes the
+// detections, or the partial detections of a failed analysis, to write.
+func analyzeFile(settings *conf.Settings, ctx context.Context, write func(notes []datastore.Note) error) error {
 	// Initialize BirdNET interpreter
 	if err := initializeBirdNET(settings); err != nil {
 		return err
@@ -84,14 +92,14 @@ func FileAnalysis(settings *conf.Settings, ctx context.Context) error {
 		// For other errors with partial results, write them
 		if len(notes) > 0 {
 			fmt.Printf("\n\033[33m⚠️  Writing partial results before exiting due to error\033[0m\n")
-			if writeErr := writeResults(settings, notes); writeErr != nil {
+			if writeErr := write(notes); writeErr != nil {
 				return fmt.Errorf("analysis error: %w; failed to write partial results: %w", err, writeErr)
 			}
 		}
 		return err
 	}
 
-	return writeResults(settings, notes)
+	return write(notes)
 }
 
 // validateAudioFile checks if the provided file path is a valid audio file.
@@ -509,12 +517,14 @@ func processAudioData(
 	channels processingChannels,
 	errHolder *errorHolder,
 ) error {
-	// Initialize filePosition before the loop
+	// Initialize filePosition before the loop, detection times are offsets
+	// from the zero time. Chunks start one step after the previous chunk.
 	filePosition := time.Time{}
+	step := time.Duration((3 - settings.BirdNET.Overlap) * float64(time.Second))
 
 	// Read and send audio chunks with timing information
 	return myaudio.ReadAudioFileBuffered(settings, func(chunkData []float32, isEOF bool) error {
-		return handleAudioChunk(
+		err := handleAudioChunk(
 			ctx,
 			chunkData,
 			isEOF,
@@ -522,6 +532,10 @@ func processAudioData(
 			channels,
 			errHolder,
 		)
+		if len(chunkData) > 0 {
+			filePosition = filePosition.Add(step)
+		}
+		return err
 	})
 }
 
@@ -644,17 +658,26 @@ func writeResults(settings *conf.Settings, notes []datastore.Note) error {
 	}
 
 	// Output the notes based on the desired output type in the configuration.
-	// If OutputType is not specified or if it's set to "table", output as a table format.
-	if settings.Output.File.Type == "" || settings.Output.File.Type == "table" {
+	switch settings.Output.File.Type {
+	case "", observation.OutputTypeTable:
+		// If OutputType is not specified or if it's set to "table", output as a table format.
 		if err := observation.WriteNotesTable(settings, notes, outputFile); err != nil {
 			return fmt.Errorf("failed to write notes table: %w", err)
 		}
-	}
-	// If OutputType is set to "csv", output as CSV format.
-	if settings.Output.File.Type == "csv" {
+	case observation.OutputTypeCSV:
 		if err := observation.WriteNotesCsv(settings, notes, outputFile); err != nil {
 			return fmt.Errorf("failed to write notes CSV: %w", err)
 		}
+	case observation.OutputTypeRaven:
+		if err := observation.WriteNotesRaven(settings, notes, outputFile); err != nil {
+			return fmt.Errorf("failed to write Raven selection table: %w", err)
+		}
+	case observation.OutputTypeAudacity:
+		if err := observation.WriteNotesAudacity(settings, notes, outputFile); err != nil {
+			return fmt.Errorf("failed to write Audacity labels: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output type %q, use table, csv, raven or audacity", settings.Output.File.Type)
 	}
 	return nil
 }
@@ -0,0 +1,223 @@
+// deadletter.go contains the dead-letter store of jobs that failed after exhausting their retries
+package jobqueue
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// JobSummary describes the detection a job acts on, without the state the
+// action is executed with
+type JobSummary struct {
+	Type       string    `json:"type,omitempty"`    // journal type of the action
+	NoteID     uint      `json:"noteId,omitempty"`  // ID of the detection
+	Species    string    `json:"species,omitempty"` // scientific name of the detected species
+	Source     string    `json:"source,omitempty"`  // audio source without credentials
+	DetectedAt time.Time `json:"detectedAt"`        // start time of the detection
+}
+
+// SummarizedAction is implemented by actions that can describe the detection
+// they act on. The summary must not contain credentials or audio data.
+type SummarizedAction interface {
+	Action
+	Summary() JobSummary
+}
+
+// Summary returns a summary of the detection the job acts on, with the
+// journal type of persistent actions. Returns nil if the action has neither.
+func (j *Job) Summary() *JobSummary {
+	var summary JobSummary
+	if action, ok := j.Action.(SummarizedAction); ok {
+		summary = action.Summary()
+	}
+	if action, ok := j.Action.(PersistentAction); ok && summary.Type == "" {
+		summary.Type = action.JournalType()
+	}
+	if summary == (JobSummary{}) {
+		return nil
+	}
+	return &summary
+}
+
+// SetMaxDeadLetters sets the maximum number of dead letters to keep, the
+// oldest dead letters are discarded beyond it
+func (q *JobQueue) SetMaxDeadLetters(maxDeadLetters int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.maxDeadLetters = maxDeadLetters
+	q.trimDeadLetters(context.Background())
+}
+
+// addDeadLetter adds a job that failed after exhausting its retries to the dead-letter store
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) addDeadLetter(ctx context.Context, job *Job) {
+	q.deadLetters = append(q.deadLetters, job)
+	q.trimDeadLetters(ctx)
+}
+
+// trimDeadLetters discards the oldest dead letters beyond the maximum
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) trimDeadLetters(ctx context.Context) {
+	if len(q.deadLetters) <= q.maxDeadLetters {
+		return
+	}
+	excess := len(q.deadLetters) - q.maxDeadLetters
+	for _, job := range q.deadLetters[:excess] {
+		q.journalRemove(job)
+		LogDeadLetterDiscarded(ctx, job.ID, job.Action.GetDescription(), "store_full")
+	}
+	q.deadLetters = slices.Delete(q.deadLetters, 0, excess)
+}
+
+// findDeadLetter returns the index of a dead letter or -1
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) findDeadLetter(id string) int {
+	return slices.IndexFunc(q.deadLetters, func(job *Job) bool { return job.ID == id })
+}
+
+// GetDeadLetters returns the jobs that failed after exhausting their retries,
+// oldest first. The returned jobs are copies.
+func (q *JobQueue) GetDeadLetters() []*Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deadLetters := make([]*Job, 0, len(q.deadLetters))
+	for _, job := range q.deadLetters {
+		jobCopy := *job
+		deadLetters = append(deadLetters, &jobCopy)
+	}
+	return deadLetters
+}
+
+// GetDeadLetter returns a copy of a dead letter or ErrJobNotFound
+func (q *JobQueue) GetDeadLetter(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.findDeadLetter(id)
+	if i < 0 {
+		return nil, deadLetterNotFound("get_dead_letter", id)
+	}
+	jobCopy := *q.deadLetters[i]
+	return &jobCopy, nil
+}
+
+// ReplayDeadLetter re-queues a dead letter with a fresh set of attempts.
+// Returns ErrJobNotFound for unknown jobs and ErrQueueFull when the queue is full.
+func (q *JobQueue) ReplayDeadLetter(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.findDeadLetter(id)
+	if i < 0 {
+		return deadLetterNotFound("replay_dead_letter", id)
+	}
+	if !q.requeue(q.deadLetters[i]) {
+		return errors.New(ErrQueueFull).
+			Context("operation", "replay_dead_letter").
+			Context("job_id", id).
+			Context("max_jobs", q.maxJobs).
+			Build()
+	}
+	q.deadLetters = slices.Delete(q.deadLetters, i, i+1)
+	return nil
+}
+
+// ReplayDeadLetters re-queues the dead letters with the given IDs, or all dead
+// letters if no IDs are given, oldest first and up to the capacity of the
+// queue. Unknown IDs are ignored. Returns the number of re-queued jobs.
+func (q *JobQueue) ReplayDeadLetters(ids []string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	replayed := 0
+	q.deadLetters = slices.DeleteFunc(q.deadLetters, func(job *Job) bool {
+		if len(ids) > 0 && !slices.Contains(ids, job.ID) {
+			return false
+		}
+		if !q.requeue(job) {
+			return false
+		}
+		replayed++
+		return true
+	})
+	return replayed
+}
+
+// RetryFailedJobs re-queues all jobs that failed after exhausting their
+// retries, see ReplayDeadLetters. Returns the number of re-queued jobs.
+func (q *JobQueue) RetryFailedJobs() int {
+	return q.ReplayDeadLetters(nil)
+}
+
+// DiscardDeadLetter removes a dead letter without running it again.
+// Returns ErrJobNotFound for unknown jobs.
+func (q *JobQueue) DiscardDeadLetter(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := q.findDeadLetter(id)
+	if i < 0 {
+		return deadLetterNotFound("discard_dead_letter", id)
+	}
+	job := q.deadLetters[i]
+	q.journalRemove(job)
+	LogDeadLetterDiscarded(context.Background(), job.ID, job.Action.GetDescription(), "discarded")
+	q.deadLetters = slices.Delete(q.deadLetters, i, i+1)
+	return nil
+}
+
+// DiscardDeadLetters removes the dead letters with the given IDs, or all dead
+// letters if no IDs are given. Unknown IDs are ignored. Returns the number of
+// discarded jobs.
+func (q *JobQueue) DiscardDeadLetters(ids []string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	discarded := 0
+	q.deadLetters = slices.DeleteFunc(q.deadLetters, func(job *Job) bool {
+		if len(ids) > 0 && !slices.Contains(ids, job.ID) {
+			return false
+		}
+		q.journalRemove(job)
+		LogDeadLetterDiscarded(context.Background(), job.ID, job.Action.GetDescription(), "discarded")
+		discarded++
+		return true
+	})
+	return discarded
+}
+
+// requeue moves a failed job back to the queue as pending with a fresh set
+// of attempts. Returns false if the queue is full.
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) requeue(job *Job) bool {
+	// Failed jobs stay in the queue until they are archived
+	queued := slices.Contains(q.jobs, job)
+	if !queued && len(q.jobs) >= q.maxJobs {
+		return false
+	}
+
+	job.Status = JobStatusPending
+	job.Attempts = 0
+	job.NextRetryAt = q.clock.Now()
+	job.FailedAt = time.Time{}
+	q.journalUpdate(job)
+
+	if !queued {
+		q.archivedJobs = slices.DeleteFunc(q.archivedJobs, func(archived *Job) bool { return archived == job })
+		q.stats.ArchivedJobs = len(q.archivedJobs)
+		q.jobs = append(q.jobs, job)
+	}
+	return true
+}
+
+// deadLetterNotFound returns ErrJobNotFound for an unknown dead letter
+func deadLetterNotFound(operation, id string) error {
+	return errors.New(ErrJobNotFound).
+		Context("operation", operation).
+		Context("job_id", id).
+		Build()
+}
@@ -0,0 +1,116 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failDeadLetters enqueues a failing job without retries for each name and
+// waits until they are dead letters, the dead-letter store must not be full
+func failDeadLetters(t *testing.T, queue *JobQueue, names ...string) []string {
+	t.Helper()
+	want := len(queue.GetDeadLetters()) + len(names)
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		action := &journalAction{Name: name}
+		action.ExecuteFunc = func(any) error { return errors.New("upload failed") }
+		job, err := queue.Enqueue(context.Background(), action, nil, RetryConfig{})
+		require.NoError(t, err)
+		ids = append(ids, job.ID)
+	}
+	queue.ProcessImmediately(context.Background())
+	require.Eventually(t, func() bool { return len(queue.GetDeadLetters()) == want }, time.Second, 10*time.Millisecond)
+	return ids
+}
+
+func TestDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	queue := NewJobQueueWithOptions(10, 10, false)
+	queue.SetProcessingInterval(time.Hour)
+	queue.Start()
+	defer func() { assert.NoError(t, queue.StopWithTimeout(time.Second)) }()
+
+	ids := failDeadLetters(t, queue, "first", "second")
+	assert.Equal(t, 2, queue.GetStats().DeadLetterJobs)
+
+	deadLetter, err := queue.GetDeadLetter(ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusFailed, deadLetter.Status)
+	assert.Equal(t, 1, deadLetter.Attempts)
+	assert.False(t, deadLetter.FailedAt.IsZero())
+	assert.EqualError(t, deadLetter.LastError, "upload failed")
+	require.NotNil(t, deadLetter.Summary())
+	assert.Equal(t, testJournalType, deadLetter.Summary().Type)
+
+	_, err = queue.GetDeadLetter("unknown")
+	require.ErrorIs(t, err, ErrJobNotFound)
+
+	// Replayed jobs run again with a fresh set of attempts
+	require.NoError(t, queue.ReplayDeadLetter(ids[0]))
+	require.ErrorIs(t, queue.ReplayDeadLetter(ids[0]), ErrJobNotFound)
+	pending := queue.GetPendingJobs()
+	require.Len(t, pending, 1)
+	assert.Equal(t, ids[0], pending[0].ID)
+	assert.Equal(t, 0, pending[0].Attempts)
+
+	require.NoError(t, queue.DiscardDeadLetter(ids[1]))
+	assert.Empty(t, queue.GetDeadLetters())
+	require.ErrorIs(t, queue.DiscardDeadLetter(ids[1]), ErrJobNotFound)
+
+	// The replayed job fails again
+	queue.ProcessImmediately(context.Background())
+	require.Eventually(t, func() bool { return len(queue.GetDeadLetters()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestDeadLettersBulk(t *testing.T) {
+	t.Parallel()
+
+	queue := NewJobQueueWithOptions(2, 10, false)
+	queue.SetProcessingInterval(time.Hour)
+	queue.Start()
+	defer func() { assert.NoError(t, queue.StopWithTimeout(time.Second)) }()
+
+	ids := failDeadLetters(t, queue, "a", "b")
+	queue.ProcessImmediately(context.Background()) // archive the failed jobs
+	ids = append(ids, failDeadLetters(t, queue, "c")...)
+	queue.ProcessImmediately(context.Background())
+
+	// Replaying is limited by the capacity of the queue, oldest first
+	assert.Equal(t, 2, queue.ReplayDeadLetters(nil))
+	remaining := queue.GetDeadLetters()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, ids[2], remaining[0].ID)
+
+	assert.Equal(t, 0, queue.DiscardDeadLetters([]string{"unknown"}))
+	assert.Equal(t, 1, queue.DiscardDeadLetters([]string{ids[2]}))
+	assert.Empty(t, queue.GetDeadLetters())
+}
+
+func TestDeadLettersLimit(t *testing.T) {
+	t.Parallel()
+
+	queue := NewJobQueueWithOptions(10, 10, false)
+	queue.SetProcessingInterval(time.Hour)
+	queue.Start()
+	defer func() { assert.NoError(t, queue.StopWithTimeout(time.Second)) }()
+
+	failDeadLetters(t, queue, "a")
+	failDeadLetters(t, queue, "b")
+	failDeadLetters(t, queue, "c")
+	queue.SetMaxDeadLetters(2)
+	var names []string
+	for _, job := range queue.GetDeadLetters() {
+		names = append(names, job.Action.(*journalAction).Name)
+	}
+	assert.Equal(t, []string{"b", "c"}, names, "oldest dead letters are discarded")
+
+	// Job data isn't summarized
+	job := &Job{Action: &MockAction{}, Data: &TestData{ID: "data"}}
+	assert.Nil(t, job.Summary())
+}
@@ -18,6 +18,14 @@ type Job struct {
 	LastError              error       // Last error encountered
 	Config                 RetryConfig // Retry configuration for this job
 	TestExemptFromDropping bool        // Flag to indicate if this job should be exempt from dropping during queue overflow
+	FailedAt               time.Time   // When the job failed after exhausting its retries
+
+	journaled bool // Whether the job is written to the job journal
+}
+
+// Journaled reports whether the job is kept in the on-disk job journal
+func (j *Job) Journaled() bool {
+	return j.journaled
 }
 
 // JobStats tracks statistics about job processing
@@ -45,6 +53,7 @@ type JobStatsSnapshot struct {
 
 	// Current queue state
 	PendingJobs      int     // Current number of jobs in the queue
+	DeadLetterJobs   int     // Current number of failed jobs in the dead-letter store
 	MaxQueueSize     int     // Maximum queue capacity
 	QueueUtilization float64 // Queue utilization percentage
 
@@ -125,6 +134,7 @@ func (s *JobStatsSnapshot) toJSON(prettyPrint bool) (string, error) {
 			"dropped":       s.DroppedJobs,
 			"retryAttempts": s.RetryAttempts,
 			"pending":       s.PendingJobs,
+			"deadLetters":   s.DeadLetterJobs,
 			"maxSize":       s.MaxQueueSize,
 			"utilization":   s.QueueUtilization,
 		},
@@ -0,0 +1,473 @@
+// journal.go contains the on-disk journal keeping pending jobs over restarts
+package jobqueue
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// PersistentAction is implemented by actions whose jobs can be written to the
+// job journal and restored after a restart. JournalType identifies the
+// ActionRestorer rebuilding the action, an empty type means that the action
+// isn't journaled. Job data isn't journaled, so MarshalJournal must return
+// the JSON encoded state the action needs to execute. The state is written to
+// disk as is, so it should leave out secrets the action doesn't need, such as
+// credentials in RTSP URLs.
+type PersistentAction interface {
+	Action
+	JournalType() string
+	MarshalJournal() ([]byte, error)
+}
+
+// ActionRestorer rebuilds an action from the state returned by its MarshalJournal
+type ActionRestorer func(payload []byte) (Action, error)
+
+// Journal operations
+const (
+	journalOpEnqueue = "enqueue" // job added, includes the action state
+	journalOpUpdate  = "update"  // job status or attempts changed
+	journalOpRemove  = "remove"  // job completed, dropped or discarded
+)
+
+// journalCompactSize is the journal size in bytes above which the journal is
+// rewritten with only the live jobs, once it is more than twice their size
+const journalCompactSize = 8 << 20
+
+// journalRecord is a line of the job journal
+type journalRecord struct {
+	Op          string          `json:"op"`
+	ID          string          `json:"id"`
+	Type        string          `json:"type,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Status      JobStatus       `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	NextRetryAt time.Time       `json:"nextRetryAt"`
+	LastError   string          `json:"lastError,omitempty"`
+	FailedAt    time.Time       `json:"failedAt"`
+	Config      RetryConfig     `json:"config"`
+}
+
+// journal is an append-only file of job records. Replaying the records gives
+// the latest state of every job that hasn't been removed.
+type journal struct {
+	path    string
+	file    *os.File
+	size    int64                     // bytes in the file
+	records map[string]*journalRecord // latest state of the live jobs
+}
+
+// openJournal replays the journal at path, creating it if needed, and
+// compacts it to the live jobs
+func openJournal(path string) (*journal, error) {
+	j := &journal{path: path, records: make(map[string]*journalRecord)}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, journalError(err, "open_journal", path)
+	}
+
+	file, err := os.Open(path)
+	switch {
+	case err == nil:
+		skipped, err := j.replay(file)
+		file.Close()
+		if err != nil {
+			return nil, journalError(err, "read_journal", path)
+		}
+		if skipped > 0 {
+			// A crash while writing leaves a partial last line
+			logger.Warn("Skipped malformed job journal records", "path", path, "skipped", skipped)
+		}
+	case !os.IsNotExist(err):
+		return nil, journalError(err, "open_journal", path)
+	}
+
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// replay applies the records read from r and returns the number of
+// malformed records skipped
+func (j *journal) replay(r io.Reader) (int, error) {
+	reader := bufio.NewReader(r)
+	skipped := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var record journalRecord
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil || record.ID == "" {
+				skipped++
+			} else {
+				j.apply(&record)
+			}
+		}
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+	}
+}
+
+// apply updates the live jobs with a record
+func (j *journal) apply(record *journalRecord) {
+	switch record.Op {
+	case journalOpEnqueue:
+		j.records[record.ID] = record
+	case journalOpUpdate:
+		if current, ok := j.records[record.ID]; ok {
+			// Updates don't repeat the action state
+			record.Type, record.Payload = current.Type, current.Payload
+			j.records[record.ID] = record
+		}
+	case journalOpRemove:
+		delete(j.records, record.ID)
+	}
+}
+
+// write applies the record and appends it to the journal file
+func (j *journal) write(record *journalRecord) error {
+	if record.Op != journalOpEnqueue {
+		if _, ok := j.records[record.ID]; !ok {
+			return nil
+		}
+	}
+	j.apply(record)
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return journalError(err, "write_journal", j.path)
+	}
+	n, err := j.file.Write(append(line, '\n'))
+	j.size += int64(n)
+	if err != nil {
+		return journalError(err, "write_journal", j.path)
+	}
+	return nil
+}
+
+// sortedRecords returns the live jobs in the order they were created
+func (j *journal) sortedRecords() []*journalRecord {
+	records := make([]*journalRecord, 0, len(j.records))
+	for _, record := range j.records {
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(a, b int) bool {
+		if records[a].CreatedAt.Equal(records[b].CreatedAt) {
+			return records[a].ID < records[b].ID
+		}
+		return records[a].CreatedAt.Before(records[b].CreatedAt)
+	})
+	return records
+}
+
+// compact rewrites the journal with only the live jobs and reopens it for appending
+func (j *journal) compact() error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return journalError(err, "compact_journal", j.path)
+	}
+
+	writer := bufio.NewWriter(tmp)
+	var size int64
+	for _, record := range j.sortedRecords() {
+		record.Op = journalOpEnqueue
+		line, err := json.Marshal(record)
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return journalError(err, "compact_journal", j.path)
+		}
+		n, _ := writer.Write(append(line, '\n'))
+		size += int64(n)
+	}
+	if err := writer.Flush(); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return journalError(err, "compact_journal", j.path)
+	}
+
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		os.Remove(tmpPath)
+		return journalError(err, "compact_journal", j.path)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return journalError(err, "compact_journal", j.path)
+	}
+	j.file = file
+	j.size = size
+	return nil
+}
+
+// maybeCompact compacts the journal when it has no live jobs left, or when
+// it has grown large and mostly holds records of finished jobs
+func (j *journal) maybeCompact() error {
+	if j.size == 0 {
+		return nil
+	}
+	if len(j.records) > 0 {
+		if j.size < journalCompactSize {
+			return nil
+		}
+		var live int64
+		for _, record := range j.records {
+			live += int64(len(record.Payload)) + 256 // approximate size of the other fields
+		}
+		if j.size < 2*live {
+			return nil
+		}
+	}
+	return j.compact()
+}
+
+// close closes the journal file
+func (j *journal) close() error {
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	if err != nil {
+		return journalError(err, "close_journal", j.path)
+	}
+	return nil
+}
+
+// newJournalRecord returns a record of the current state of a job
+func newJournalRecord(op string, job *Job) *journalRecord {
+	record := &journalRecord{
+		Op:          op,
+		ID:          job.ID,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		CreatedAt:   job.CreatedAt,
+		NextRetryAt: job.NextRetryAt,
+		FailedAt:    job.FailedAt,
+		Config:      job.Config,
+	}
+	if job.LastError != nil {
+		record.LastError = sanitizeErrorMessage(job.LastError)
+	}
+	return record
+}
+
+// toJob rebuilds the job of the record with its restored action
+func (r *journalRecord) toJob(action Action) *Job {
+	job := &Job{
+		ID:          r.ID,
+		Action:      action,
+		Attempts:    r.Attempts,
+		MaxAttempts: r.MaxAttempts,
+		CreatedAt:   r.CreatedAt,
+		NextRetryAt: r.NextRetryAt,
+		Status:      r.Status,
+		Config:      r.Config,
+		FailedAt:    r.FailedAt,
+		journaled:   true,
+	}
+	if r.LastError != "" {
+		job.LastError = errors.NewStd(r.LastError)
+	}
+	// Jobs running when the journal was last written are run again
+	if job.Status == JobStatusRunning {
+		job.Status = JobStatusPending
+		if job.Attempts > 0 {
+			job.Status = JobStatusRetrying
+		}
+	}
+	return job
+}
+
+// RegisterRestorer registers the restorer of journaled actions of a type.
+// Restorers must be registered before the journal is opened.
+func (q *JobQueue) RegisterRestorer(journalType string, restorer ActionRestorer) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.restorers == nil {
+		q.restorers = make(map[string]ActionRestorer)
+	}
+	q.restorers[journalType] = restorer
+}
+
+// OpenJournal enables the on-disk job journal at path. Jobs of persistent
+// actions are written to the journal until they complete, so that pending,
+// retrying jobs and dead letters survive restarts. Jobs of the previous run
+// are restored with the registered restorers and replayed in the order they
+// were created, ahead of jobs enqueued since. Returns the number of restored
+// jobs, including dead letters.
+//
+// Records whose action can't be restored, for example because its integration
+// isn't connected yet, are kept in the journal and restored on the next open.
+// Only malformed records are dropped.
+//
+// Journal records are written without syncing, they survive a crash of the
+// process but may be lost on power loss.
+func (q *JobQueue) OpenJournal(path string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.journal != nil {
+		return 0, errors.Newf("job journal is already open").
+			Component("analysis.jobqueue").
+			Category(errors.CategoryState).
+			Context("operation", "open_journal").
+			Build()
+	}
+
+	j, err := openJournal(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending, deadLetters []*Job
+	unrestored := 0
+	for _, record := range j.sortedRecords() {
+		action, err := q.restoreAction(record)
+		if err != nil {
+			LogJournalError(context.Background(), "restore_job", record.ID, err)
+			unrestored++
+			continue
+		}
+		job := record.toJob(action)
+		if job.Status == JobStatusFailed {
+			deadLetters = append(deadLetters, job)
+		} else {
+			pending = append(pending, job)
+		}
+	}
+
+	if unrestored > 0 {
+		logger.Warn("Kept job journal records that couldn't be restored until the next start",
+			"path", path,
+			"count", unrestored)
+	}
+
+	q.journal = j
+	q.jobs = append(pending, q.jobs...)
+	q.deadLetters = append(deadLetters, q.deadLetters...)
+	q.trimDeadLetters(context.Background())
+
+	return len(pending) + len(deadLetters), nil
+}
+
+// restoreAction rebuilds the action of a journal record
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) restoreAction(record *journalRecord) (Action, error) {
+	restorer, ok := q.restorers[record.Type]
+	if !ok {
+		return nil, errors.Newf("no restorer registered for journaled action type %q", record.Type).
+			Component("analysis.jobqueue").
+			Category(errors.CategoryNotFound).
+			Priority(errors.PriorityLow).
+			Context("operation", "restore_job").
+			Context("journal_type", record.Type).
+			Build()
+	}
+	action, err := restorer(record.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if action == nil {
+		return nil, ErrNilAction
+	}
+	return action, nil
+}
+
+// CloseJournal closes the job journal, jobs are no longer journaled
+func (q *JobQueue) CloseJournal() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.journal == nil {
+		return nil
+	}
+	err := q.journal.close()
+	q.journal = nil
+	return err
+}
+
+// journalEnqueue writes a new job of a persistent action to the journal
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) journalEnqueue(job *Job) {
+	if q.journal == nil {
+		return
+	}
+	action, ok := job.Action.(PersistentAction)
+	if !ok || action.JournalType() == "" {
+		return
+	}
+
+	payload, err := action.MarshalJournal()
+	if err != nil {
+		LogJournalError(context.Background(), "marshal_job", job.ID, err)
+		return
+	}
+	record := newJournalRecord(journalOpEnqueue, job)
+	record.Type = action.JournalType()
+	record.Payload = payload
+
+	if err := q.journal.write(record); err != nil {
+		LogJournalError(context.Background(), journalOpEnqueue, job.ID, err)
+		return
+	}
+	job.journaled = true
+}
+
+// journalUpdate writes the current state of a journaled job
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) journalUpdate(job *Job) {
+	if q.journal == nil || !job.journaled {
+		return
+	}
+	if err := q.journal.write(newJournalRecord(journalOpUpdate, job)); err != nil {
+		LogJournalError(context.Background(), journalOpUpdate, job.ID, err)
+	}
+}
+
+// journalRemove removes a journaled job from the journal
+// IMPORTANT: This method must be called with q.mu already locked.
+func (q *JobQueue) journalRemove(job *Job) {
+	if q.journal == nil || !job.journaled {
+		return
+	}
+	if err := q.journal.write(&journalRecord{Op: journalOpRemove, ID: job.ID}); err != nil {
+		LogJournalError(context.Background(), journalOpRemove, job.ID, err)
+	}
+	job.journaled = false
+}
+
+// journalError wraps an error of a job journal file operation
+func journalError(err error, operation, path string) error {
+	return errors.New(err).
+		Component("analysis.jobqueue").
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Context("journal_path", path).
+		Build()
+}
@@ -0,0 +1,191 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJournalType = "test"
+
+// journalAction is a persistent action for journal tests
+type journalAction struct {
+	MockAction
+	Name string
+}
+
+func (a *journalAction) JournalType() string { return testJournalType }
+
+func (a *journalAction) MarshalJournal() ([]byte, error) { return json.Marshal(a.Name) }
+
+// setupJournalQueue creates a started queue using the journal at path. Jobs
+// are only processed by ProcessImmediately, restored actions run execute.
+func setupJournalQueue(t *testing.T, path string, execute func(data any) error) (*JobQueue, int) {
+	t.Helper()
+	queue := NewJobQueueWithOptions(10, 10, false)
+	queue.SetProcessingInterval(time.Hour)
+	queue.RegisterRestorer(testJournalType, func(payload []byte) (Action, error) {
+		action := &journalAction{}
+		action.ExecuteFunc = execute
+		return action, json.Unmarshal(payload, &action.Name)
+	})
+	restored, err := queue.OpenJournal(path)
+	require.NoError(t, err)
+	queue.Start()
+	t.Cleanup(func() {
+		assert.NoError(t, queue.StopWithTimeout(time.Second))
+		assert.NoError(t, queue.CloseJournal())
+	})
+	return queue, restored
+}
+
+func pendingNames(queue *JobQueue) []string {
+	var names []string
+	for _, job := range queue.GetPendingJobs() {
+		names = append(names, job.Action.(*journalAction).Name)
+	}
+	return names
+}
+
+func TestJournalRestoresPendingJobsInOrder(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "jobs.journal")
+
+	queue, restored := setupJournalQueue(t, path, nil)
+	assert.Equal(t, 0, restored)
+	config := RetryConfig{Enabled: true, MaxRetries: 3, InitialDelay: time.Minute, MaxDelay: time.Hour, Multiplier: 2}
+	for _, name := range []string{"first", "second", "third"} {
+		job, err := queue.Enqueue(context.Background(), &journalAction{Name: name}, nil, config)
+		require.NoError(t, err)
+		assert.True(t, job.Journaled())
+	}
+	// Actions that aren't persistent are not journaled
+	job, err := queue.Enqueue(context.Background(), &MockAction{}, nil, config)
+	require.NoError(t, err)
+	assert.False(t, job.Journaled())
+
+	require.NoError(t, queue.StopWithTimeout(time.Second))
+	require.NoError(t, queue.CloseJournal())
+
+	restoredQueue, restored := setupJournalQueue(t, path, nil)
+	assert.Equal(t, 3, restored)
+	assert.Equal(t, []string{"first", "second", "third"}, pendingNames(restoredQueue))
+	for _, job := range restoredQueue.GetPendingJobs() {
+		assert.Equal(t, config, job.Config)
+		assert.Equal(t, 4, job.MaxAttempts)
+	}
+}
+
+func TestJournalRemovesCompletedJobs(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "jobs.journal")
+
+	queue, _ := setupJournalQueue(t, path, nil)
+	_, err := queue.Enqueue(context.Background(), &journalAction{Name: "done"}, nil, RetryConfig{})
+	require.NoError(t, err)
+
+	queue.ProcessImmediately(context.Background())
+	require.Eventually(t, func() bool { return queue.GetStats().SuccessfulJobs == 1 }, time.Second, 10*time.Millisecond)
+
+	// The journal is compacted once it has no live jobs
+	queue.ProcessImmediately(context.Background())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	require.NoError(t, queue.CloseJournal())
+	_, restored := setupJournalQueue(t, path, nil)
+	assert.Equal(t, 0, restored)
+}
+
+func TestJournalRetryFailedJobs(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "jobs.journal")
+	fail := func(any) error { return errors.New("service unavailable") }
+
+	queue, _ := setupJournalQueue(t, path, nil)
+	action := &journalAction{Name: "upload"}
+	action.ExecuteFunc = fail
+	_, err := queue.Enqueue(context.Background(), action, nil, RetryConfig{})
+	require.NoError(t, err)
+	queue.ProcessImmediately(context.Background())
+	require.Eventually(t, func() bool { return queue.GetStats().FailedJobs == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, queue.StopWithTimeout(time.Second))
+	require.NoError(t, queue.CloseJournal())
+
+	// Failed jobs are restored but not retried automatically
+	restoredQueue, restored := setupJournalQueue(t, path, fail)
+	assert.Equal(t, 1, restored)
+	assert.Empty(t, restoredQueue.GetPendingJobs())
+
+	assert.Equal(t, 1, restoredQueue.RetryFailedJobs())
+	pending := restoredQueue.GetPendingJobs()
+	require.Len(t, pending, 1)
+	assert.Equal(t, 0, pending[0].Attempts)
+	assert.EqualError(t, pending[0].LastError, "service unavailable")
+	assert.Equal(t, 0, restoredQueue.RetryFailedJobs(), "no failed jobs left")
+
+	// The retry is journaled
+	require.NoError(t, restoredQueue.StopWithTimeout(time.Second))
+	require.NoError(t, restoredQueue.CloseJournal())
+	retriedQueue, _ := setupJournalQueue(t, path, nil)
+	assert.Equal(t, []string{"upload"}, pendingNames(retriedQueue))
+}
+
+func TestJournalKeepsUnrestorableRecords(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "jobs.journal")
+
+	now := time.Now()
+	records := []journalRecord{
+		{Op: journalOpEnqueue, ID: "a", Type: testJournalType, Payload: json.RawMessage(`"kept"`), CreatedAt: now},
+		{Op: journalOpEnqueue, ID: "b", Type: "unknown", Payload: json.RawMessage(`{}`), CreatedAt: now},
+		{Op: journalOpEnqueue, ID: "c", Type: testJournalType, Payload: json.RawMessage(`"removed"`), CreatedAt: now},
+		{Op: journalOpRemove, ID: "c"},
+		{Op: journalOpUpdate, ID: "a", Status: JobStatusRunning, Attempts: 1, MaxAttempts: 3, CreatedAt: now},
+	}
+	var content []byte
+	for i := range records {
+		line, err := json.Marshal(&records[i])
+		require.NoError(t, err)
+		content = append(append(content, line...), '\n')
+	}
+	// A crash while writing leaves a partial record
+	content = append(content, []byte(`{"op":"enqueue","id":"d","pay`)...)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+
+	queue, restored := setupJournalQueue(t, path, nil)
+	assert.Equal(t, 1, restored)
+	pending := queue.GetPendingJobs()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "a", pending[0].ID)
+	assert.Equal(t, JobStatusRetrying, pending[0].Status, "interrupted jobs are run again")
+	assert.Equal(t, "kept", pending[0].Action.(*journalAction).Name)
+
+	// Records that couldn't be restored stay in the journal for the next start
+	require.NoError(t, queue.CloseJournal())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"id":"b"`)
+	assert.NotContains(t, string(content), `"id":"c"`)
+	assert.NotContains(t, string(content), `"id":"d"`)
+
+	reopened, restored := setupJournalQueue(t, path, nil)
+	assert.Equal(t, 1, restored, "jobs without a restorer are still kept")
+	require.NoError(t, reopened.CloseJournal())
+
+	reopened = NewJobQueueWithOptions(10, 10, false)
+	reopened.RegisterRestorer(testJournalType, func([]byte) (Action, error) { return &journalAction{}, nil })
+	reopened.RegisterRestorer("unknown", func([]byte) (Action, error) { return &journalAction{}, nil })
+	restored, err = reopened.OpenJournal(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored, "jobs are restored once their restorer is available")
+	require.NoError(t, reopened.CloseJournal())
+}
@@ -220,6 +220,32 @@ func LogJobSuccess(ctx context.Context, jobID, actionDesc string, attempt int) {
 	logger.InfoContext(ctx, "Job succeeded", args...)
 }
 
+// LogDeadLetterDiscarded logs when a job that failed after exhausting its retries is discarded
+func LogDeadLetterDiscarded(ctx context.Context, jobID, actionDesc, reason string) {
+	args := []any{
+		"job_id", jobID,
+		"action_type", actionDesc,
+		"reason", reason,
+	}
+	if traceID := extractTraceID(ctx); traceID != "" {
+		args = append(args, "trace_id", traceID)
+	}
+	logger.InfoContext(ctx, "Dead letter discarded", args...)
+}
+
+// LogJournalError logs when a job can't be written to or restored from the job journal
+func LogJournalError(ctx context.Context, operation, jobID string, err error) {
+	args := []any{
+		"operation", operation,
+		"job_id", jobID,
+		"error", err,
+	}
+	if traceID := extractTraceID(ctx); traceID != "" {
+		args = append(args, "trace_id", traceID)
+	}
+	logger.WarnContext(ctx, "Job journal operation failed", args...)
+}
+
 // Context key types for safe context value retrieval
 type contextKey string
 
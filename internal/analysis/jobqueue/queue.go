@@ -42,8 +42,12 @@ type JobQueue struct {
 	droppedJobs        int  // Counter for jobs dropped due to queue full
 	logAllSuccesses    bool // Whether to log all successful jobs, not just retries
 	processCancel      context.CancelFunc
-	processingInterval time.Duration // Interval for the processing ticker (for testing)
-	clock              Clock         // Clock interface for time-related operations
+	processingInterval time.Duration             // Interval for the processing ticker (for testing)
+	clock              Clock                     // Clock interface for time-related operations
+	deadLetters        []*Job                    // Jobs that failed after exhausting their retries
+	maxDeadLetters     int                       // Maximum number of dead letters to keep
+	journal            *journal                  // On-disk journal of persistent jobs, nil if disabled
+	restorers          map[string]ActionRestorer // Restorers of journaled actions by journal type
 }
 
 // NewJobQueue creates a new job queue with default settings
@@ -58,6 +62,7 @@ func NewJobQueueWithOptions(maxJobs, maxArchivedJobs int, logAllSuccesses bool)
 		archivedJobs:       make([]*Job, 0),
 		stopCh:             make(chan struct{}),
 		maxArchivedJobs:    maxArchivedJobs,
+		maxDeadLetters:     maxArchivedJobs,
 		maxJobs:            maxJobs,
 		logAllSuccesses:    logAllSuccesses,
 		processingInterval: 1 * time.Second, // Default processing interval
@@ -232,6 +237,7 @@ func (q *JobQueue) Enqueue(ctx context.Context, action Action, data any, config
 
 	q.jobs = append(q.jobs, job)
 	q.stats.TotalJobs++
+	q.journalEnqueue(job)
 
 	// Update action-specific stats
 	actionKey := getActionKey(action)
@@ -299,6 +305,7 @@ func (q *JobQueue) _dropOldestPendingJob(ctx context.Context) bool {
 	stats.Dropped++
 	q.stats.ActionStats[actionKey] = stats
 
+	q.journalRemove(oldestJob)
 	LogJobDropped(ctx, oldestJob.ID, oldestJob.Action.GetDescription())
 	return true
 }
@@ -384,6 +391,12 @@ func (q *JobQueue) cleanupStaleJobs(ctx context.Context) {
 		q.archivedJobs = q.archivedJobs[excess:]
 		q.stats.ArchivedJobs = len(q.archivedJobs)
 	}
+
+	if q.journal != nil {
+		if err := q.journal.maybeCompact(); err != nil {
+			LogJournalError(ctx, "compact_journal", "", err)
+		}
+	}
 }
 
 // calculateBackoffDelay calculates the delay before the next retry attempt
@@ -652,11 +665,15 @@ func (q *JobQueue) executeJob(ctx context.Context, job *Job) {
 		if job.Attempts >= job.MaxAttempts {
 			// No more retries
 			job.Status = JobStatusFailed
+			job.FailedAt = executionEndTime
 
 			q.stats.FailedJobs++
 			stats.Failed++
 			q.stats.ActionStats[actionKey] = stats
 
+			// Dead letters stay in the journal so that they can be replayed later
+			q.addDeadLetter(ctx, job)
+			q.journalUpdate(job)
 			LogJobFailed(ctx, job.ID, actionDesc, job.Attempts, job.MaxAttempts, err)
 		} else {
 			// Schedule for retry
@@ -665,6 +682,7 @@ func (q *JobQueue) executeJob(ctx context.Context, job *Job) {
 			// Calculate backoff with exponential strategy
 			delay := calculateBackoffDelay(job.Config, job.Attempts, q.clock)
 			job.NextRetryAt = q.clock.Now().Add(delay)
+			q.journalUpdate(job)
 
 			// Log detailed retry scheduling information
 			LogJobRetryScheduled(ctx, job.ID, actionDesc, job.Attempts, job.MaxAttempts, delay, job.NextRetryAt, err)
@@ -679,6 +697,7 @@ func (q *JobQueue) executeJob(ctx context.Context, job *Job) {
 		q.stats.SuccessfulJobs++
 		stats.Successful++
 		q.stats.ActionStats[actionKey] = stats
+		q.journalRemove(job)
 
 		// Log success based on configuration
 		if job.Attempts > 1 || q.logAllSuccesses {
@@ -834,8 +853,9 @@ func (q *JobQueue) GetStats() JobStatsSnapshot {
 		RetryAttempts:  q.stats.RetryAttempts,
 
 		// Current queue state
-		PendingJobs:  len(q.jobs),
-		MaxQueueSize: q.maxJobs,
+		PendingJobs:    len(q.jobs),
+		DeadLetterJobs: len(q.deadLetters),
+		MaxQueueSize:   q.maxJobs,
 		QueueUtilization: func() float64 {
 			if q.maxJobs == 0 { // Avoid division by zero
 				return 0
@@ -953,16 +973,18 @@ func (q *JobQueue) ProcessImmediately(ctx context.Context) {
 	q.processDueJobs(ctx)
 }
 
-// GetPendingJobs returns a slice of all pending jobs in the queue
-// This method is primarily intended for testing purposes
+// GetPendingJobs returns the backlog of the queue, the jobs waiting for their
+// first attempt or a retry, in the order they are processed. The returned
+// jobs are copies and aren't updated as the queue processes them.
 func (q *JobQueue) GetPendingJobs() []*Job {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	pendingJobs := make([]*Job, 0, len(q.jobs))
 	for _, job := range q.jobs {
-		if job.Status == JobStatusPending {
-			pendingJobs = append(pendingJobs, job)
+		if job.Status == JobStatusPending || job.Status == JobStatusRetrying {
+			jobCopy := *job
+			pendingJobs = append(pendingJobs, &jobCopy)
 		}
 	}
 
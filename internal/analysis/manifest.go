@@ -0,0 +1,476 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Manifest files of directory analysis, stored in the output directory by default
+const (
+	manifestFileName = "birdnet-go-manifest.jsonl"
+	summaryFileName  = "birdnet-go-summary.txt"
+	manifestVersion  = 2
+)
+
+// FileStatus is the analysis status of one audio file in the manifest
+type FileStatus string
+
+const (
+	FileStatusRunning     FileStatus = "running"     // Analysis started, a crash leaves files in this state
+	FileStatusCompleted   FileStatus = "completed"   // Analysis completed and results were written
+	FileStatusFailed      FileStatus = "failed"      // Analysis failed, the file is retried on the next run
+	FileStatusInterrupted FileStatus = "interrupted" // Analysis was cancelled, the file is retried on the next run
+)
+
+// ManifestEntry records the analysis of one audio file
+type ManifestEntry struct {
+	Status     FileStatus `json:"status"`
+	Hash       string     `json:"hash,omitempty"` // SHA-256 of the file content
+	Size       int64      `json:"size"`
+	ModTime    time.Time  `json:"modTime"`
+	Duration   float64    `json:"durationSeconds"` // Audio duration in seconds
+	Detections int        `json:"detections"`      // Detections above the confidence threshold
+	Attempts   int        `json:"attempts"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt time.Time  `json:"finishedAt"`
+}
+
+// Manifest tracks the progress of a directory analysis so that an interrupted
+// analysis can be resumed. Completed files are skipped on later runs unless
+// their content changed, failed and interrupted files are analysed again.
+//
+// The manifest file starts with a header record of the run followed by a
+// record for every change of a file entry. Replaying the records gives the
+// latest state of every file, Prepare and Close rewrite the file with only
+// the latest records.
+type Manifest struct {
+	mu   sync.Mutex
+	path string
+	file *os.File // manifest file open for appending, nil until Prepare
+
+	Version    int
+	Input      string
+	OutputType string
+	// Combined output file of all analysed files, empty for per file output
+	Output string
+	// Selection number of the next row of the combined output
+	NextSelection int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Files         map[string]*ManifestEntry
+}
+
+// manifestHeader is the first record of the manifest file
+type manifestHeader struct {
+	Version       int       `json:"version"`
+	Input         string    `json:"input"`
+	OutputType    string    `json:"outputType"`
+	Output        string    `json:"output,omitempty"`
+	NextSelection int       `json:"nextSelection,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// manifestRecord is a record of the manifest file following the header
+type manifestRecord struct {
+	File          string         `json:"file"`
+	Entry         *ManifestEntry `json:"entry"`
+	NextSelection int            `json:"nextSelection,omitempty"`
+}
+
+// ManifestSummary sums up the files of a manifest
+type ManifestSummary struct {
+	Files       int
+	Completed   int
+	Failed      int
+	Interrupted int
+	Detections  int
+	Duration    time.Duration // Audio duration of completed files
+	FailedFiles []string
+}
+
+// LoadManifest reads the manifest at path, a missing manifest returns an empty one
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{
+		path:      path,
+		Version:   manifestVersion,
+		CreatedAt: time.Now(),
+		Files:     make(map[string]*ManifestEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+	if err := m.replay(data); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// replay reads the header and applies the file records of a manifest file.
+// Malformed file records are skipped, a crash while writing leaves a partial
+// last line.
+func (m *Manifest) replay(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	header := true
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if header {
+			var h manifestHeader
+			if err := json.Unmarshal(line, &h); err != nil {
+				return err
+			}
+			if h.Version != manifestVersion {
+				return fmt.Errorf("unsupported manifest version %d", h.Version)
+			}
+			m.Input = h.Input
+			m.OutputType = h.OutputType
+			m.Output = h.Output
+			m.NextSelection = h.NextSelection
+			m.CreatedAt = h.CreatedAt
+			m.UpdatedAt = h.UpdatedAt
+			header = false
+			continue
+		}
+
+		var record manifestRecord
+		if err := json.Unmarshal(line, &record); err != nil || record.File == "" || record.Entry == nil {
+			continue
+		}
+		m.Files[record.File] = record.Entry
+		if record.NextSelection > 0 {
+			m.NextSelection = record.NextSelection
+		}
+	}
+	return scanner.Err()
+}
+
+// Path returns the file of the manifest
+func (m *Manifest) Path() string {
+	return m.path
+}
+
+// Prepare binds the manifest to an analysis run. Recorded results are
+// discarded if the input, output type or combined output changed, or if the
+// combined output file no longer exists, as skipped files would be missing
+// from the new output.
+func (m *Manifest) Prepare(input, outputType, output string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reset := len(m.Files) > 0 && (m.Input != input || m.OutputType != outputType || m.Output != output)
+	if output != "" && len(m.Files) > 0 {
+		if _, err := os.Stat(output); err != nil {
+			reset = true
+		}
+	}
+	if reset {
+		m.Files = make(map[string]*ManifestEntry)
+		m.NextSelection = 0
+	}
+
+	m.Input = input
+	m.OutputType = outputType
+	m.Output = output
+	return m.compact()
+}
+
+// Close rewrites the manifest with the latest state of every file and closes it
+func (m *Manifest) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.file == nil {
+		return nil
+	}
+	err := m.compact()
+	if closeErr := m.file.Close(); err == nil {
+		err = closeErr
+	}
+	m.file = nil
+	return err
+}
+
+// Completed reports whether the file was analysed on an earlier run and is
+// unchanged. Files with a different size or modification time are hashed, a
+// matching hash still counts as completed. known reports whether the manifest
+// has an entry for the file.
+func (m *Manifest) Completed(path string) (completed, known bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.Files[manifestKey(path)]
+	if !ok {
+		return false, false
+	}
+	if entry.Status != FileStatusCompleted {
+		return false, true
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, true
+	}
+	if info.Size() == entry.Size && info.ModTime().Equal(entry.ModTime) {
+		return true, true
+	}
+
+	// Size or modification time changed, compare the content
+	hash, err := hashFile(path)
+	if err != nil || hash != entry.Hash {
+		return false, true
+	}
+	entry.Size = info.Size()
+	entry.ModTime = info.ModTime()
+	if err := m.append(manifestKey(path), entry); err != nil {
+		GetLogger().Warn("Failed to save manifest", "path", m.path, "error", err)
+	}
+	return true, true
+}
+
+// Start records the start of the analysis of a file
+func (m *Manifest) Start(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := manifestKey(path)
+	entry, ok := m.Files[key]
+	if !ok {
+		entry = &ManifestEntry{}
+		m.Files[key] = entry
+	}
+	entry.Status = FileStatusRunning
+	entry.Hash = hash
+	entry.Size = info.Size()
+	entry.ModTime = info.ModTime()
+	entry.Attempts++
+	entry.Error = ""
+	entry.StartedAt = time.Now()
+	entry.FinishedAt = time.Time{}
+	return m.append(key, entry)
+}
+
+// Finish records the result of the analysis of a file. nextSelection is the
+// selection number of the next row of the combined output, or zero.
+func (m *Manifest) Finish(path string, duration time.Duration, detections, nextSelection int, analysisErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := manifestKey(path)
+	entry, ok := m.Files[key]
+	if !ok {
+		return fmt.Errorf("file %s was not started in manifest", path)
+	}
+	entry.Duration = duration.Seconds()
+	entry.Detections = detections
+	entry.FinishedAt = time.Now()
+	switch {
+	case analysisErr == nil:
+		entry.Status = FileStatusCompleted
+	case errors.Is(analysisErr, context.Canceled):
+		entry.Status = FileStatusInterrupted
+	default:
+		entry.Status = FileStatusFailed
+		entry.Error = analysisErr.Error()
+	}
+	if nextSelection > 0 {
+		m.NextSelection = nextSelection
+	}
+	return m.append(key, entry)
+}
+
+// Summary sums up the files of the manifest
+func (m *Manifest) Summary() ManifestSummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var s ManifestSummary
+	for path, entry := range m.Files {
+		s.Files++
+		switch entry.Status {
+		case FileStatusCompleted:
+			s.Completed++
+			s.Detections += entry.Detections
+			s.Duration += time.Duration(entry.Duration * float64(time.Second))
+		case FileStatusFailed:
+			s.Failed++
+			s.FailedFiles = append(s.FailedFiles, path)
+		default:
+			s.Interrupted++
+		}
+	}
+	sort.Strings(s.FailedFiles)
+	return s
+}
+
+// WriteSummary writes a report of the manifest
+func (m *Manifest) WriteSummary(w io.Writer) error {
+	s := m.Summary()
+
+	m.mu.Lock()
+	input := m.Input
+	errs := make(map[string]string, len(s.FailedFiles))
+	for _, path := range s.FailedFiles {
+		errs[path] = m.Files[path].Error
+	}
+	m.mu.Unlock()
+
+	lines := []string{
+		fmt.Sprintf("Directory analysis summary of %s", input),
+		fmt.Sprintf("Generated: %s", time.Now().Format(time.RFC3339)),
+		"",
+		fmt.Sprintf("Files:       %d", s.Files),
+		fmt.Sprintf("Completed:   %d", s.Completed),
+		fmt.Sprintf("Failed:      %d", s.Failed),
+		fmt.Sprintf("Interrupted: %d", s.Interrupted),
+		fmt.Sprintf("Audio:       %v", s.Duration.Round(time.Second)),
+		fmt.Sprintf("Detections:  %d", s.Detections),
+	}
+	if len(s.FailedFiles) > 0 {
+		lines = append(lines, "", "Failed files:")
+		for _, path := range s.FailedFiles {
+			lines = append(lines, fmt.Sprintf("  %s: %s", path, errs[path]))
+		}
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// append writes a record of a file entry to the manifest, the caller must
+// hold the lock. A manifest that isn't open yet is written completely.
+func (m *Manifest) append(key string, entry *ManifestEntry) error {
+	if m.file == nil {
+		return m.compact()
+	}
+
+	line, err := json.Marshal(&manifestRecord{File: key, Entry: entry, NextSelection: m.NextSelection})
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest record: %w", err)
+	}
+	if _, err := m.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", m.path, err)
+	}
+	return nil
+}
+
+// compact atomically rewrites the manifest with the header and the latest
+// record of every file and reopens it for appending, the caller must hold
+// the lock
+func (m *Manifest) compact() error {
+	m.UpdatedAt = time.Now()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(&manifestHeader{
+		Version:       m.Version,
+		Input:         m.Input,
+		OutputType:    m.OutputType,
+		Output:        m.Output,
+		NextSelection: m.NextSelection,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}); err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	keys := make([]string, 0, len(m.Files))
+	for key := range m.Files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := encoder.Encode(&manifestRecord{File: key, Entry: m.Files[key]}); err != nil {
+			return fmt.Errorf("failed to encode manifest: %w", err)
+		}
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(m.path), "manifest-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	tempName := tempFile.Name()
+	if _, err := tempFile.Write(buf.Bytes()); err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempName)
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		_ = os.Remove(tempName)
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if m.file != nil {
+		_ = m.file.Close()
+		m.file = nil
+	}
+	if err := os.Rename(tempName, m.path); err != nil {
+		_ = os.Remove(tempName)
+		return fmt.Errorf("failed to replace manifest %s: %w", m.path, err)
+	}
+
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open manifest %s: %w", m.path, err)
+	}
+	m.file = file
+	return nil
+}
+
+// manifestKey returns the manifest key of an audio file, its absolute path
+func manifestKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// hashFile returns the hex encoded SHA-256 of a file
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,189 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeManifestTestFile creates an audio file stand-in with the given content
+func writeManifestTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestManifestResume(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, manifestFileName)
+	done := writeManifestTestFile(t, dir, "done.wav", "done")
+	failed := writeManifestTestFile(t, dir, "failed.wav", "failed")
+	interrupted := writeManifestTestFile(t, dir, "interrupted.wav", "interrupted")
+
+	m, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	require.NoError(t, m.Prepare(dir, "csv", ""))
+	for _, path := range []string{done, failed, interrupted} {
+		require.NoError(t, m.Start(path))
+	}
+	require.NoError(t, m.Finish(done, 90*time.Second, 4, 0, nil))
+	require.NoError(t, m.Finish(failed, 0, 0, 0, errors.New("bad header")))
+	require.NoError(t, m.Finish(interrupted, 0, 0, 0, context.Canceled))
+
+	// A later run continues from the saved manifest
+	m, err = LoadManifest(manifestPath)
+	require.NoError(t, err)
+	require.NoError(t, m.Prepare(dir, "csv", ""))
+
+	completed, known := m.Completed(done)
+	assert.True(t, completed)
+	assert.True(t, known)
+	completed, known = m.Completed(failed)
+	assert.False(t, completed, "failed files are retried")
+	assert.True(t, known)
+	completed, known = m.Completed(filepath.Join(dir, "new.wav"))
+	assert.False(t, completed)
+	assert.False(t, known)
+
+	s := m.Summary()
+	assert.Equal(t, 3, s.Files)
+	assert.Equal(t, 1, s.Completed)
+	assert.Equal(t, 1, s.Failed)
+	assert.Equal(t, 1, s.Interrupted)
+	assert.Equal(t, 4, s.Detections)
+	assert.Equal(t, 90*time.Second, s.Duration)
+	assert.Equal(t, []string{manifestKey(failed)}, s.FailedFiles)
+
+	var report strings.Builder
+	require.NoError(t, m.WriteSummary(&report))
+	assert.Contains(t, report.String(), "Completed:   1")
+	assert.Contains(t, report.String(), "failed.wav: bad header")
+
+	// A retry increments the attempts
+	require.NoError(t, m.Start(failed))
+	assert.Equal(t, 2, m.Files[manifestKey(failed)].Attempts)
+}
+
+func TestManifestAppendsAndCompacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, manifestFileName)
+	a := writeManifestTestFile(t, dir, "a.wav", "a")
+	b := writeManifestTestFile(t, dir, "b.wav", "b")
+
+	countLines := func() int {
+		data, err := os.ReadFile(manifestPath)
+		require.NoError(t, err)
+		return strings.Count(string(data), "\n")
+	}
+
+	m, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	require.NoError(t, m.Prepare(dir, "csv", ""))
+	assert.Equal(t, 1, countLines(), "header only")
+
+	// Every change appends a record of the file
+	for _, path := range []string{a, b} {
+		require.NoError(t, m.Start(path))
+		require.NoError(t, m.Finish(path, time.Second, 1, 0, nil))
+	}
+	assert.Equal(t, 5, countLines())
+
+	// A crash while writing leaves a partial last line
+	file, err := os.OpenFile(manifestPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"file":"` + manifestKey(b) + `","entry":{"sta`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	loaded, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.Summary().Completed)
+
+	// Closing keeps only the latest record of every file
+	require.NoError(t, m.Close())
+	assert.Equal(t, 3, countLines())
+	loaded, err = LoadManifest(manifestPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.Summary().Completed)
+}
+
+func TestManifestChangedFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeManifestTestFile(t, dir, "a.wav", "original")
+
+	m, err := LoadManifest(filepath.Join(dir, manifestFileName))
+	require.NoError(t, err)
+	require.NoError(t, m.Prepare(dir, "table", ""))
+	require.NoError(t, m.Start(path))
+	require.NoError(t, m.Finish(path, time.Second, 1, 0, nil))
+
+	// Touching the file keeps it completed as the content is unchanged
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, later, later))
+	completed, _ := m.Completed(path)
+	assert.True(t, completed)
+
+	// Changed content is analysed again
+	require.NoError(t, os.WriteFile(path, []byte("modified"), 0o644))
+	completed, known := m.Completed(path)
+	assert.False(t, completed)
+	assert.True(t, known)
+}
+
+func TestManifestPrepareResetsChangedOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeManifestTestFile(t, dir, "a.wav", "audio")
+	combined := writeManifestTestFile(t, dir, "combined.csv", "header\n")
+
+	m, err := LoadManifest(filepath.Join(dir, manifestFileName))
+	require.NoError(t, err)
+	require.NoError(t, m.Prepare(dir, "csv", combined))
+	require.NoError(t, m.Start(path))
+	require.NoError(t, m.Finish(path, time.Second, 1, 5, nil))
+
+	// Same output keeps the results and selection number
+	require.NoError(t, m.Prepare(dir, "csv", combined))
+	assert.Len(t, m.Files, 1)
+	assert.Equal(t, 5, m.NextSelection)
+
+	// A missing combined output is written again from the start
+	require.NoError(t, os.Remove(combined))
+	require.NoError(t, m.Prepare(dir, "csv", combined))
+	assert.Empty(t, m.Files)
+	assert.Zero(t, m.NextSelection)
+}
+
+func TestIsProcessedRetriesFailedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	outDir := t.TempDir()
+	path := writeManifestTestFile(t, dir, "a.wav", "audio")
+	// Partial results of the failed analysis
+	writeManifestTestFile(t, outDir, "a.wav.csv", "partial")
+
+	m, err := LoadManifest(filepath.Join(outDir, manifestFileName))
+	require.NoError(t, err)
+	require.NoError(t, m.Prepare(dir, "csv", ""))
+	require.NoError(t, m.Start(path))
+	require.NoError(t, m.Finish(path, 0, 0, 0, errors.New("decode error")))
+
+	assert.False(t, isProcessed(path, outDir, map[string]bool{}, m))
+	assert.True(t, isProcessed(path, outDir, map[string]bool{}, nil), "without manifest the output file marks the file processed")
+}
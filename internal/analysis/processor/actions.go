@@ -209,8 +209,8 @@ func (a *DatabaseAction) Execute(data interface{}) error {
 		return err
 	}
 	
-	// After successful save, publish detection event for new species
-	a.publishNewSpeciesDetectionEvent(isNewSpecies, daysSinceFirstSeen)
+	// After successful save, publish detection event for new species and detection rules
+	a.publishDetectionEvent(isNewSpecies, daysSinceFirstSeen)
 
 	// Save audio clip to file if enabled
 	if a.Settings.Realtime.Audio.Export.Enabled {
@@ -242,10 +242,11 @@ func (a *DatabaseAction) Execute(data interface{}) error {
 	return nil
 }
 
-// publishNewSpeciesDetectionEvent publishes a detection event for new species
+// publishDetectionEvent publishes a detection event for new species, or for every
+// detection when detection notification rules are configured
 // This helper method handles event bus retrieval, event creation, publishing, and debug logging
-func (a *DatabaseAction) publishNewSpeciesDetectionEvent(isNewSpecies bool, daysSinceFirstSeen int) {
-	if !isNewSpecies || !events.IsInitialized() {
+func (a *DatabaseAction) publishDetectionEvent(isNewSpecies bool, daysSinceFirstSeen int) {
+	if (!isNewSpecies && !hasDetectionRules(a.Settings)) || !events.IsInitialized() {
 		return
 	}
 
@@ -271,12 +272,22 @@ func (a *DatabaseAction) publishNewSpeciesDetectionEvent(isNewSpecies bool, days
 
 	// Publish the detection event
 	if published := eventBus.TryPublishDetection(detectionEvent); published {
-		if a.Settings.Debug {
+		if a.Settings.Debug && isNewSpecies {
 			log.Printf("🌟 Published new species detection event: %s", a.Note.CommonName)
 		}
 	}
 }
 
+// hasDetectionRules reports whether any detection notification rule is enabled
+func hasDetectionRules(settings *conf.Settings) bool {
+	for i := range settings.Notification.Rules {
+		if settings.Notification.Rules[i].Enabled {
+			return true
+		}
+	}
+	return false
+}
+
 // Execute saves the audio clip to a file
 func (a *SaveAudioAction) Execute(data interface{}) error {
 	a.mu.Lock()
@@ -523,6 +534,11 @@ func (a *UpdateRangeFilterAction) Execute(data interface{}) error {
 		}
 
 		a.Settings.UpdateIncludedSpecies(includedSpecies)
+
+		// Update species lists of sources with their own location or species lists
+		if err := birdnet.BuildSourceRangeFilters(a.Bn, a.Settings, today); err != nil {
+			return err
+		}
 	}
 	return nil
 }
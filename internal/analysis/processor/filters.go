@@ -0,0 +1,566 @@
+// filters.go contains the detection filter chain deciding whether pending detections are discarded
+package processor
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// DetectionFilter decides whether a pending detection is discarded before its
+// actions run. Filters run in the configured order and the first filter
+// discarding a detection stops the chain. Filters may also annotate detections
+// they keep, e.g. record an adjustment on the note or hold it for review.
+type DetectionFilter interface {
+	// Name returns the name used in the filter order and species filter settings
+	Name() string
+	// Discard reports whether the detection should be discarded and why
+	Discard(item *PendingDetection, now time.Time) (discard bool, reason string)
+}
+
+// ResultObserver is implemented by filters that track prediction results
+// which are not detections themselves, such as dog barks or human voices.
+type ResultObserver interface {
+	// Observe is called for every prediction result of an analysed chunk
+	Observe(source string, t time.Time, label string, confidence float32)
+}
+
+// ApprovalObserver is implemented by filters that track approved detections
+type ApprovalObserver interface {
+	// Approved is called when a detection passed all filters
+	Approved(item *PendingDetection, now time.Time)
+}
+
+// WeatherSource provides the latest weather data for the weather filter
+type WeatherSource interface {
+	LatestHourlyWeather() (*datastore.HourlyWeather, error)
+}
+
+// FilterChain runs detection filters in order
+type FilterChain struct {
+	settings *conf.Settings
+	weather  WeatherSource
+	filters  []DetectionFilter
+}
+
+// NewFilterChain creates the built-in filters in the configured order.
+// Filters missing from the configured order run after it in the default order.
+// The weather filter is inactive if weather is nil.
+func NewFilterChain(settings *conf.Settings, weather WeatherSource) *FilterChain {
+	chain := &FilterChain{settings: settings, weather: weather}
+
+	for _, name := range filterOrder(settings) {
+		filter := newBuiltinFilter(name, settings, weather)
+		if filter == nil {
+			log.Printf("⚠️ Ignoring unknown detection filter %q", name)
+			continue
+		}
+		chain.filters = append(chain.filters, filter)
+	}
+
+	return chain
+}
+
+// Reorder returns a chain running the same filters in the configured order,
+// so that their state, such as recent human voices, dog barks and species
+// cooldowns, is kept. Filters added with Append run after the built-in filters.
+func (c *FilterChain) Reorder() *FilterChain {
+	builtin := make(map[string]DetectionFilter, len(c.filters))
+	var custom []DetectionFilter
+	for _, filter := range c.filters {
+		if slices.Contains(conf.DefaultFilterOrder, filter.Name()) {
+			builtin[filter.Name()] = filter
+		} else {
+			custom = append(custom, filter)
+		}
+	}
+
+	chain := &FilterChain{settings: c.settings, weather: c.weather}
+	for _, name := range filterOrder(c.settings) {
+		filter, ok := builtin[name]
+		if !ok {
+			if filter = newBuiltinFilter(name, c.settings, c.weather); filter == nil {
+				continue
+			}
+		}
+		chain.filters = append(chain.filters, filter)
+	}
+	chain.filters = append(chain.filters, custom...)
+
+	return chain
+}
+
+// filterOrder returns the configured filter order without duplicates,
+// followed by the remaining filters in the default order
+func filterOrder(settings *conf.Settings) []string {
+	var order []string
+	for _, name := range settings.Realtime.Filters.Order {
+		name = strings.ToLower(name)
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	for _, name := range conf.DefaultFilterOrder {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// newBuiltinFilter returns the built-in filter with the given name, or nil
+func newBuiltinFilter(name string, settings *conf.Settings, weather WeatherSource) DetectionFilter {
+	switch name {
+	case conf.FilterConsecutive:
+		return &consecutiveFilter{settings: settings}
+	case conf.FilterPrivacy:
+		return &privacyFilter{settings: settings, lastHuman: make(map[string]time.Time)}
+	case conf.FilterDogBark:
+		return &dogBarkFilter{settings: settings, lastBark: make(map[string]time.Time)}
+	case conf.FilterNoise:
+		return &noiseFilter{settings: settings, lastNoise: make(map[string]time.Time)}
+	case conf.FilterWeather:
+		return &weatherFilter{settings: settings, source: weather}
+	case conf.FilterCooldown:
+		return &cooldownFilter{settings: settings, lastApproved: make(map[string]time.Time)}
+	default:
+		return nil
+	}
+}
+
+// Append adds a filter to the end of the chain. The chain must not be in use
+// by the processor yet.
+func (c *FilterChain) Append(filter DetectionFilter) {
+	c.filters = append(c.filters, filter)
+}
+
+// Names returns the names of the filters in the order they run
+func (c *FilterChain) Names() []string {
+	names := make([]string, 0, len(c.filters))
+	for _, filter := range c.filters {
+		names = append(names, filter.Name())
+	}
+	return names
+}
+
+// Observe passes a prediction result to the filters tracking results
+func (c *FilterChain) Observe(source string, t time.Time, label string, confidence float32) {
+	if c == nil {
+		return
+	}
+	for _, filter := range c.filters {
+		if observer, ok := filter.(ResultObserver); ok {
+			observer.Observe(source, t, label, confidence)
+		}
+	}
+}
+
+// Discard runs the filters not disabled for the species of the detection and
+// returns the reason of the first filter discarding it.
+func (c *FilterChain) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	if c == nil {
+		return false, ""
+	}
+	disabled := speciesFilterSettings(c.settings, item).Disable
+	for _, filter := range c.filters {
+		if slices.ContainsFunc(disabled, func(name string) bool { return strings.EqualFold(name, filter.Name()) }) {
+			continue
+		}
+		if discard, reason := filter.Discard(item, now); discard {
+			return true, reason
+		}
+	}
+	return false, ""
+}
+
+// Approved passes an approved detection to the filters tracking approvals
+func (c *FilterChain) Approved(item *PendingDetection, now time.Time) {
+	if c == nil {
+		return
+	}
+	for _, filter := range c.filters {
+		if observer, ok := filter.(ApprovalObserver); ok {
+			observer.Approved(item, now)
+		}
+	}
+}
+
+// speciesFilterSettings returns the filter overrides configured for the species of a detection
+func speciesFilterSettings(settings *conf.Settings, item *PendingDetection) conf.SpeciesFilterSettings {
+	config, ok := settings.Realtime.Species.Config[strings.ToLower(item.Detection.Note.CommonName)]
+	if !ok {
+		config = settings.Realtime.Species.Config[strings.ToLower(item.Detection.Note.ScientificName)]
+	}
+	return config.Filters
+}
+
+// speciesInList reports whether the common or scientific name of a detection is in a species list
+func speciesInList(list []string, item *PendingDetection) bool {
+	for _, species := range list {
+		if strings.EqualFold(species, item.Detection.Note.CommonName) ||
+			strings.EqualFold(species, item.Detection.Note.ScientificName) {
+			return true
+		}
+	}
+	return false
+}
+
+// minDetectionsForOverlap returns the number of matches a detection needs
+// before it is approved, about one match per three seconds of audio
+func minDetectionsForOverlap(overlap float64) int {
+	segmentLength := math.Max(0.1, 3.0-overlap)
+	return int(math.Max(1, 3/segmentLength))
+}
+
+// consecutiveFilter discards detections matched too few times during the
+// detection hold period, these are likely false positives.
+type consecutiveFilter struct {
+	settings *conf.Settings
+}
+
+func (f *consecutiveFilter) Name() string { return conf.FilterConsecutive }
+
+func (f *consecutiveFilter) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	minDetections := speciesFilterSettings(f.settings, item).MinDetections
+	if minDetections == 0 {
+		minDetections = f.settings.Realtime.Filters.Consecutive.MinDetections
+	}
+	if minDetections == 0 {
+		minDetections = minDetectionsForOverlap(f.settings.BirdNET.Overlap)
+	}
+
+	if item.Count < minDetections {
+		return true, fmt.Sprintf("false positive, matched %d/%d times", item.Count, minDetections)
+	}
+	return false, ""
+}
+
+// privacyFilter discards detections if a human voice is detected on the same
+// source after the detection started.
+type privacyFilter struct {
+	settings  *conf.Settings
+	mu        sync.RWMutex
+	lastHuman map[string]time.Time // last human voice per audio source
+}
+
+func (f *privacyFilter) Name() string { return conf.FilterPrivacy }
+
+func (f *privacyFilter) Observe(source string, t time.Time, label string, confidence float32) {
+	settings := &f.settings.Realtime.PrivacyFilter
+	if !settings.Enabled || !strings.Contains(label, "human ") || confidence <= settings.Confidence {
+		return
+	}
+	log.Printf("Human detected with confidence %.3f/%.3f from source %s", confidence, settings.Confidence, source)
+	f.mu.Lock()
+	f.lastHuman[source] = t
+	f.mu.Unlock()
+}
+
+func (f *privacyFilter) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	if !f.settings.Realtime.PrivacyFilter.Enabled {
+		return false, ""
+	}
+	f.mu.RLock()
+	lastHuman, exists := f.lastHuman[item.Source]
+	f.mu.RUnlock()
+	if exists && lastHuman.After(item.FirstDetected) {
+		return true, "privacy filter"
+	}
+	return false, ""
+}
+
+// dogBarkFilter discards detections of configured species, often mistaken
+// for dog barks, for a while after a dog bark on the same source.
+type dogBarkFilter struct {
+	settings *conf.Settings
+	mu       sync.RWMutex
+	lastBark map[string]time.Time // last dog bark per audio source
+}
+
+func (f *dogBarkFilter) Name() string { return conf.FilterDogBark }
+
+func (f *dogBarkFilter) Observe(source string, t time.Time, label string, confidence float32) {
+	settings := &f.settings.Realtime.DogBarkFilter
+	if !settings.Enabled || !strings.Contains(label, "dog") || confidence <= settings.Confidence {
+		return
+	}
+	log.Printf("Dog detected with confidence %.3f/%.3f from source %s", confidence, settings.Confidence, source)
+	f.mu.Lock()
+	f.lastBark[source] = t
+	f.mu.Unlock()
+}
+
+func (f *dogBarkFilter) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	settings := &f.settings.Realtime.DogBarkFilter
+	if !settings.Enabled {
+		return false, ""
+	}
+
+	f.mu.RLock()
+	if settings.Debug {
+		log.Printf("Last dog detection: %s\n", f.lastBark)
+	}
+	lastBark, exists := f.lastBark[item.Source]
+	f.mu.RUnlock()
+
+	remember := time.Duration(settings.Remember) * time.Minute
+	if exists && speciesInList(settings.Species, item) && now.Sub(lastBark) <= remember {
+		return true, "recent dog bark"
+	}
+	return false, ""
+}
+
+// noiseFilter discards detections if rain, wind or other noise is detected on
+// the same source during the detection.
+type noiseFilter struct {
+	settings  *conf.Settings
+	mu        sync.RWMutex
+	lastNoise map[string]time.Time // last noise per audio source
+}
+
+func (f *noiseFilter) Name() string { return conf.FilterNoise }
+
+func (f *noiseFilter) Observe(source string, t time.Time, label string, confidence float32) {
+	settings := &f.settings.Realtime.Filters.Noise
+	if !settings.Enabled || confidence <= settings.Confidence {
+		return
+	}
+	if !slices.ContainsFunc(settings.Labels, func(noise string) bool {
+		return noise != "" && strings.Contains(label, strings.ToLower(noise))
+	}) {
+		return
+	}
+	if f.settings.Debug {
+		log.Printf("Noise %s detected with confidence %.3f/%.3f from source %s", label, confidence, settings.Confidence, source)
+	}
+	f.mu.Lock()
+	f.lastNoise[source] = t
+	f.mu.Unlock()
+}
+
+func (f *noiseFilter) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	settings := &f.settings.Realtime.Filters.Noise
+	if !settings.Enabled {
+		return false, ""
+	}
+	if len(settings.Species) > 0 && !speciesInList(settings.Species, item) {
+		return false, ""
+	}
+
+	f.mu.RLock()
+	lastNoise, exists := f.lastNoise[item.Source]
+	f.mu.RUnlock()
+
+	since := item.FirstDetected.Add(-time.Duration(settings.Remember) * time.Second)
+	if exists && !lastNoise.Before(since) {
+		return true, "rain or wind noise"
+	}
+	return false, ""
+}
+
+// weatherCacheDuration is how long the weather filter reuses weather data before querying it again
+const weatherCacheDuration = time.Minute
+
+// weatherFilter raises the confidence threshold of detections, or holds them
+// for review, while the latest weather data shows heavy rain or strong wind.
+type weatherFilter struct {
+	settings  *conf.Settings
+	source    WeatherSource
+	mu        sync.Mutex
+	weather   *datastore.HourlyWeather // cached latest weather, nil if unavailable
+	fetchedAt time.Time                // time weather was last queried
+}
+
+func (f *weatherFilter) Name() string { return conf.FilterWeather }
+
+func (f *weatherFilter) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	settings := &f.settings.Realtime.Filters.Weather
+	if !settings.Enabled || f.source == nil {
+		return false, ""
+	}
+	if len(settings.Species) > 0 && !speciesInList(settings.Species, item) {
+		return false, ""
+	}
+
+	weather := f.latestWeather(now)
+	if weather == nil {
+		return false, ""
+	}
+	if maxAge := time.Duration(settings.MaxAge) * time.Minute; maxAge > 0 && item.FirstDetected.Sub(weather.Time) > maxAge {
+		return false, ""
+	}
+
+	conditions := weatherFilterConditions(settings, weather)
+	if len(conditions) == 0 {
+		return false, ""
+	}
+	condition := strings.Join(conditions, ", ")
+
+	note := &item.Detection.Note
+	if strings.EqualFold(settings.Action, conf.WeatherFilterActionReview) {
+		note.WeatherAdjustment = "held for review: " + condition
+		note.HeldForReview = true
+		return false, ""
+	}
+
+	threshold := f.detectionThreshold(item) + settings.ThresholdIncrease
+	if note.Confidence < threshold {
+		return true, fmt.Sprintf("weather (%s), confidence %.2f below raised threshold %.2f", condition, note.Confidence, threshold)
+	}
+	note.Threshold = threshold
+	note.WeatherAdjustment = fmt.Sprintf("threshold raised by %.2f to %.2f: %s", settings.ThresholdIncrease, threshold, condition)
+	return false, ""
+}
+
+// latestWeather returns the latest weather data, cached for a minute
+func (f *weatherFilter) latestWeather(now time.Time) *datastore.HourlyWeather {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.fetchedAt.IsZero() && now.Sub(f.fetchedAt) < weatherCacheDuration {
+		return f.weather
+	}
+
+	weather, err := f.source.LatestHourlyWeather()
+	if err != nil {
+		if f.settings.Debug {
+			log.Printf("Weather filter could not get latest weather: %v", err)
+		}
+		weather = nil
+	}
+	f.weather = weather
+	f.fetchedAt = now
+	return weather
+}
+
+// detectionThreshold returns the configured threshold of a detection, the
+// species threshold if set, otherwise the threshold recorded on the note
+func (f *weatherFilter) detectionThreshold(item *PendingDetection) float64 {
+	if config, ok := f.settings.Realtime.Species.Config[strings.ToLower(item.Detection.Note.CommonName)]; ok && config.Threshold > 0 {
+		return config.Threshold
+	}
+	return item.Detection.Note.Threshold
+}
+
+// weatherFilterConditions returns the weather conditions the filter applies to, empty if none
+func weatherFilterConditions(settings *conf.WeatherFilterSettings, weather *datastore.HourlyWeather) []string {
+	var conditions []string
+	if settings.WindSpeed > 0 && weather.WindSpeed >= settings.WindSpeed {
+		conditions = append(conditions, fmt.Sprintf("wind %.1f", weather.WindSpeed))
+	}
+	if settings.WindGust > 0 && weather.WindGust >= settings.WindGust {
+		conditions = append(conditions, fmt.Sprintf("gusts %.1f", weather.WindGust))
+	}
+
+	// Providers describe the weather differently, e.g. "heavy intensity rain"
+	// and "heavyrainshowers_day", so spaces and underscores are ignored
+	description := normalizeWeatherCondition(weather.WeatherMain + weather.WeatherDesc)
+	for _, condition := range settings.Conditions {
+		if condition := normalizeWeatherCondition(condition); condition != "" && strings.Contains(description, condition) {
+			conditions = append(conditions, strings.TrimSpace(weather.WeatherDesc))
+			break
+		}
+	}
+	return conditions
+}
+
+// normalizeWeatherCondition lower cases a weather description and removes spaces and underscores
+func normalizeWeatherCondition(description string) string {
+	return strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(description))
+}
+
+// cooldownFilter discards detections of a species approved within the
+// cooldown interval, so each species is reported at most once per interval.
+type cooldownFilter struct {
+	settings     *conf.Settings
+	mu           sync.Mutex
+	lastApproved map[string]time.Time // last approved detection per lower case species
+}
+
+func (f *cooldownFilter) Name() string { return conf.FilterCooldown }
+
+// interval returns the cooldown of the species of a detection, 0 if disabled
+func (f *cooldownFilter) interval(item *PendingDetection) time.Duration {
+	return f.speciesInterval(strings.ToLower(item.Detection.Note.CommonName), strings.ToLower(item.Detection.Note.ScientificName))
+}
+
+// speciesInterval returns the cooldown of a lower case species, 0 if disabled
+func (f *cooldownFilter) speciesInterval(commonName, scientificName string) time.Duration {
+	config, ok := f.settings.Realtime.Species.Config[commonName]
+	if !ok {
+		config = f.settings.Realtime.Species.Config[scientificName]
+	}
+	if config.Filters.Cooldown > 0 {
+		return time.Duration(config.Filters.Cooldown) * time.Minute
+	}
+	if f.settings.Realtime.Filters.Cooldown.Enabled {
+		return time.Duration(f.settings.Realtime.Filters.Cooldown.Interval) * time.Minute
+	}
+	return 0
+}
+
+func (f *cooldownFilter) Discard(item *PendingDetection, now time.Time) (discard bool, reason string) {
+	interval := f.interval(item)
+	if interval <= 0 {
+		return false, ""
+	}
+
+	f.mu.Lock()
+	lastApproved, exists := f.lastApproved[strings.ToLower(item.Detection.Note.CommonName)]
+	f.mu.Unlock()
+
+	if exists && now.Sub(lastApproved) < interval {
+		return true, fmt.Sprintf("species cooldown, last approved %s ago", now.Sub(lastApproved).Round(time.Second))
+	}
+	return false, ""
+}
+
+func (f *cooldownFilter) Approved(item *PendingDetection, now time.Time) {
+	if f.interval(item) <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Forget species whose cooldown has passed to keep the map small
+	for species, t := range f.lastApproved {
+		if now.Sub(t) >= f.speciesInterval(species, "") {
+			delete(f.lastApproved, species)
+		}
+	}
+	f.lastApproved[strings.ToLower(item.Detection.Note.CommonName)] = now
+}
+
+// heldForReviewActions returns the actions run for detections held for review,
+// the database action and internal actions
+func heldForReviewActions(actions []Action) []Action {
+	held := actions[:0]
+	for _, action := range actions {
+		if actionType := scheduleActionType(action); actionType == "" || actionType == "database" {
+			held = append(held, action)
+		}
+	}
+	return held
+}
+
+// GetFilterChain safely returns the current detection filter chain
+func (p *Processor) GetFilterChain() *FilterChain {
+	p.filtersMu.RLock()
+	defer p.filtersMu.RUnlock()
+	return p.filters
+}
+
+// SetFilterChain safely replaces the detection filter chain, nil disables all filters
+func (p *Processor) SetFilterChain(chain *FilterChain) {
+	p.filtersMu.Lock()
+	defer p.filtersMu.Unlock()
+	p.filters = chain
+}
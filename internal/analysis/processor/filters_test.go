@@ -0,0 +1,263 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+func newFilterTestItem(commonName, scientificName string, firstDetected time.Time, count int) *PendingDetection {
+	return &PendingDetection{
+		Detection:     Detections{Note: datastore.Note{CommonName: commonName, ScientificName: scientificName}},
+		Source:        "mic",
+		FirstDetected: firstDetected,
+		Count:         count,
+	}
+}
+
+func TestNewFilterChainOrder(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	assert.Equal(t, conf.DefaultFilterOrder, NewFilterChain(settings, nil).Names())
+
+	settings.Realtime.Filters.Order = []string{"Cooldown", "noise", "unknown"}
+	assert.Equal(t, []string{"cooldown", "noise", "consecutive", "privacy", "dogbark", "weather"}, NewFilterChain(settings, nil).Names())
+}
+
+func TestFilterChainReorder(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.Filters.Cooldown = conf.CooldownFilterSettings{Enabled: true, Interval: 10}
+	chain := NewFilterChain(settings, nil)
+
+	now := time.Now()
+	item := newFilterTestItem("Great Tit", "Parus major", now, 5)
+	chain.Approved(item, now)
+
+	settings.Realtime.Filters.Order = []string{"cooldown", "weather"}
+	reordered := chain.Reorder()
+	assert.Equal(t, []string{"cooldown", "weather", "consecutive", "privacy", "dogbark", "noise"}, reordered.Names())
+
+	// Running filters keep their state
+	discard, reason := reordered.Discard(item, now.Add(time.Second))
+	assert.True(t, discard)
+	assert.Contains(t, reason, "species cooldown")
+}
+
+func TestFilterChainConsecutive(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Overlap = 1.5 // two matches needed
+	chain := NewFilterChain(settings, nil)
+	now := time.Now()
+
+	discard, reason := chain.Discard(newFilterTestItem("Great Tit", "Parus major", now, 1), now)
+	assert.True(t, discard)
+	assert.Equal(t, "false positive, matched 1/2 times", reason)
+
+	discard, _ = chain.Discard(newFilterTestItem("Great Tit", "Parus major", now, 2), now)
+	assert.False(t, discard)
+
+	// Species overrides the global minimum
+	settings.Realtime.Filters.Consecutive.MinDetections = 4
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"great tit": {Filters: conf.SpeciesFilterSettings{MinDetections: 1}},
+	}
+	discard, _ = chain.Discard(newFilterTestItem("Great Tit", "Parus major", now, 1), now)
+	assert.False(t, discard)
+	discard, _ = chain.Discard(newFilterTestItem("Eurasian Blackbird", "Turdus merula", now, 3), now)
+	assert.True(t, discard)
+}
+
+func TestFilterChainPrivacyAndDogBark(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.PrivacyFilter.Enabled = true
+	settings.Realtime.PrivacyFilter.Confidence = 0.1
+	settings.Realtime.DogBarkFilter.Enabled = true
+	settings.Realtime.DogBarkFilter.Confidence = 0.1
+	settings.Realtime.DogBarkFilter.Remember = 5
+	settings.Realtime.DogBarkFilter.Species = []string{"Eurasian Eagle-Owl"}
+	chain := NewFilterChain(settings, nil)
+
+	start := time.Now()
+	item := newFilterTestItem("Great Tit", "Parus major", start, 5)
+
+	// Human voice below the confidence threshold is ignored
+	chain.Observe("mic", start.Add(time.Second), "human vocal", 0.05)
+	discard, _ := chain.Discard(item, start.Add(15*time.Second))
+	assert.False(t, discard)
+
+	chain.Observe("mic", start.Add(time.Second), "human vocal", 0.5)
+	discard, reason := chain.Discard(item, start.Add(15*time.Second))
+	assert.True(t, discard)
+	assert.Equal(t, "privacy filter", reason)
+
+	// Species can opt out of a filter
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"great tit": {Filters: conf.SpeciesFilterSettings{Disable: []string{"privacy"}}},
+	}
+	discard, _ = chain.Discard(item, start.Add(15*time.Second))
+	assert.False(t, discard)
+
+	// Dog barks discard listed species only, until the remember time has passed
+	settings.Realtime.PrivacyFilter.Enabled = false
+	chain.Observe("mic", start, "dog", 0.8)
+	discard, _ = chain.Discard(item, start.Add(time.Minute))
+	assert.False(t, discard)
+	owl := newFilterTestItem("Eurasian Eagle-Owl", "Bubo bubo", start.Add(-time.Minute), 5)
+	discard, reason = chain.Discard(owl, start.Add(time.Minute))
+	assert.True(t, discard)
+	assert.Equal(t, "recent dog bark", reason)
+	discard, _ = chain.Discard(owl, start.Add(6*time.Minute))
+	assert.False(t, discard)
+}
+
+func TestFilterChainNoise(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.Filters.Noise = conf.NoiseFilterSettings{
+		Enabled:    true,
+		Confidence: 0.5,
+		Labels:     []string{"Environmental"},
+		Remember:   10,
+	}
+	chain := NewFilterChain(settings, nil)
+
+	start := time.Now()
+	item := newFilterTestItem("Great Tit", "Parus major", start, 5)
+
+	chain.Observe("mic", start.Add(-time.Minute), "environmental", 0.9)
+	discard, _ := chain.Discard(item, start)
+	assert.False(t, discard, "noise before the remember window")
+
+	chain.Observe("other", start, "environmental", 0.9)
+	discard, _ = chain.Discard(item, start)
+	assert.False(t, discard, "noise on another source")
+
+	chain.Observe("mic", start.Add(-5*time.Second), "environmental", 0.9)
+	discard, reason := chain.Discard(item, start)
+	assert.True(t, discard)
+	assert.Equal(t, "rain or wind noise", reason)
+
+	settings.Realtime.Filters.Noise.Species = []string{"Turdus merula"}
+	discard, _ = chain.Discard(item, start)
+	assert.False(t, discard, "species not in noise filter list")
+}
+
+func TestFilterChainCooldown(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.Filters.Cooldown = conf.CooldownFilterSettings{Enabled: true, Interval: 10}
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"eurasian blackbird": {Filters: conf.SpeciesFilterSettings{Cooldown: 30}},
+	}
+	chain := NewFilterChain(settings, nil)
+
+	now := time.Now()
+	tit := newFilterTestItem("Great Tit", "Parus major", now, 5)
+	blackbird := newFilterTestItem("Eurasian Blackbird", "Turdus merula", now, 5)
+
+	for _, item := range []*PendingDetection{tit, blackbird} {
+		discard, _ := chain.Discard(item, now)
+		require.False(t, discard)
+		chain.Approved(item, now)
+	}
+
+	discard, reason := chain.Discard(tit, now.Add(5*time.Minute))
+	assert.True(t, discard)
+	assert.Contains(t, reason, "species cooldown")
+
+	discard, _ = chain.Discard(tit, now.Add(10*time.Minute))
+	assert.False(t, discard)
+
+	discard, _ = chain.Discard(blackbird, now.Add(20*time.Minute))
+	assert.True(t, discard, "species cooldown overrides the global interval")
+}
+
+func TestFilterChainNil(t *testing.T) {
+	t.Parallel()
+
+	var chain *FilterChain
+	chain.Observe("mic", time.Now(), "dog", 1)
+	discard, _ := chain.Discard(newFilterTestItem("Great Tit", "Parus major", time.Now(), 0), time.Now())
+	assert.False(t, discard)
+}
+
+type stubWeatherSource struct {
+	weather *datastore.HourlyWeather
+	calls   int
+}
+
+func (s *stubWeatherSource) LatestHourlyWeather() (*datastore.HourlyWeather, error) {
+	s.calls++
+	return s.weather, nil
+}
+
+func TestFilterChainWeather(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	source := &stubWeatherSource{weather: &datastore.HourlyWeather{Time: now.Add(-30 * time.Minute), WindSpeed: 12, WeatherDesc: "cloudy"}}
+	settings := &conf.Settings{}
+	settings.Realtime.Filters.Weather = conf.WeatherFilterSettings{
+		Enabled:           true,
+		Action:            conf.WeatherFilterActionThreshold,
+		WindSpeed:         10,
+		Conditions:        []string{"heavy rain"},
+		ThresholdIncrease: 0.2,
+		MaxAge:            60,
+	}
+	chain := NewFilterChain(settings, source)
+
+	newItem := func(confidence float64) *PendingDetection {
+		item := newFilterTestItem("Great Tit", "Parus major", now, 5)
+		item.Detection.Note.Confidence = confidence
+		item.Detection.Note.Threshold = 0.7
+		return item
+	}
+
+	discard, reason := chain.Discard(newItem(0.8), now)
+	assert.True(t, discard)
+	assert.Contains(t, reason, "wind 12.0")
+
+	item := newItem(0.95)
+	discard, _ = chain.Discard(item, now)
+	require.False(t, discard)
+	assert.InDelta(t, 0.9, item.Detection.Note.Threshold, 0.0001)
+	assert.Equal(t, "threshold raised by 0.20 to 0.90: wind 12.0", item.Detection.Note.WeatherAdjustment)
+	assert.False(t, item.Detection.Note.HeldForReview)
+	assert.Equal(t, 1, source.calls, "weather is cached")
+
+	// Heavy rain holds detections for review
+	settings.Realtime.Filters.Weather.Action = conf.WeatherFilterActionReview
+	source.weather.WindSpeed = 3
+	source.weather.WeatherDesc = "heavyrainshowers_day"
+	chain = NewFilterChain(settings, source)
+	item = newItem(0.8)
+	discard, _ = chain.Discard(item, now)
+	require.False(t, discard)
+	assert.True(t, item.Detection.Note.HeldForReview)
+	assert.Equal(t, "held for review: heavyrainshowers_day", item.Detection.Note.WeatherAdjustment)
+
+	actions := heldForReviewActions([]Action{&DatabaseAction{}, &MqttAction{}, &UpdateRangeFilterAction{}})
+	assert.Len(t, actions, 2)
+
+	// Old weather data is ignored
+	source.weather.Time = now.Add(-2 * time.Hour)
+	chain = NewFilterChain(settings, source)
+	item = newItem(0.8)
+	discard, _ = chain.Discard(item, now)
+	assert.False(t, discard)
+	assert.Empty(t, item.Detection.Note.WeatherAdjustment)
+}
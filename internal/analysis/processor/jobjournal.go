@@ -0,0 +1,185 @@
+// jobjournal.go contains persistence of BirdWeather and MQTT jobs in the job journal
+package processor
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// Journal types of the persistent actions
+const (
+	birdweatherJournalType = "birdweather"
+	mqttJournalType        = "mqtt"
+)
+
+// birdweatherJournal is the journaled state of a BirdWeather upload
+type birdweatherJournal struct {
+	Note    datastore.Note `json:"note"`
+	PCMData []byte         `json:"pcmData"` // 3s clip uploaded with the detection
+}
+
+// mqttJournal is the journaled state of an MQTT publish
+type mqttJournal struct {
+	Note datastore.Note `json:"note"`
+}
+
+// JournalType implements jobqueue.PersistentAction
+func (a *BirdWeatherAction) JournalType() string {
+	return birdweatherJournalType
+}
+
+// MarshalJournal implements jobqueue.PersistentAction. The upload is
+// journaled with its audio clip, which is uploaded with the detection and
+// can't be read from the capture buffer after a restart. Credentials are
+// stripped from the source, the upload doesn't use it.
+func (a *BirdWeatherAction) MarshalJournal() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return json.Marshal(birdweatherJournal{Note: journalNote(&a.Note), PCMData: a.pcmData})
+}
+
+// Summary implements jobqueue.SummarizedAction
+func (a *BirdWeatherAction) Summary() jobqueue.JobSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return noteJobSummary(birdweatherJournalType, &a.Note)
+}
+
+// JournalType implements jobqueue.PersistentAction
+func (a *MqttAction) JournalType() string {
+	return mqttJournalType
+}
+
+// MarshalJournal implements jobqueue.PersistentAction. Credentials are
+// stripped from the source, the publish sanitizes it anyway.
+func (a *MqttAction) MarshalJournal() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return json.Marshal(mqttJournal{Note: journalNote(&a.Note)})
+}
+
+// journalNote returns a copy of a note to journal, without credentials in its source
+func journalNote(note *datastore.Note) datastore.Note {
+	journaled := *note
+	journaled.Source = privacy.SanitizeRTSPUrl(journaled.Source)
+	return journaled
+}
+
+// Summary implements jobqueue.SummarizedAction
+func (a *MqttAction) Summary() jobqueue.JobSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return noteJobSummary(mqttJournalType, &a.Note)
+}
+
+// noteJobSummary returns the summary of a job acting on a detection
+func noteJobSummary(journalType string, note *datastore.Note) jobqueue.JobSummary {
+	return jobqueue.JobSummary{
+		Type:       journalType,
+		NoteID:     note.ID,
+		Species:    note.ScientificName,
+		Source:     privacy.SanitizeRTSPUrl(note.Source),
+		DetectedAt: note.BeginTime,
+	}
+}
+
+// initializeJobJournal opens the job journal if job persistence is enabled and
+// restores the BirdWeather and MQTT jobs left from the previous run. It must
+// be called after the integration clients are initialized.
+func (p *Processor) initializeJobJournal(settings *conf.Settings) {
+	if maxDeadLetters := settings.Realtime.JobQueue.MaxDeadLetters; maxDeadLetters > 0 {
+		p.JobQueue.SetMaxDeadLetters(maxDeadLetters)
+	}
+
+	if !settings.Realtime.JobQueue.Persist {
+		return
+	}
+
+	p.JobQueue.RegisterRestorer(birdweatherJournalType, p.restoreBirdWeatherAction)
+	p.JobQueue.RegisterRestorer(mqttJournalType, p.restoreMqttAction)
+
+	path := settings.Realtime.JobQueue.JournalPath
+	restored, err := p.JobQueue.OpenJournal(path)
+	if err != nil {
+		log.Printf("⚠️ Failed to open job journal %s, pending jobs will not survive restarts: %v", path, err)
+		return
+	}
+	if restored > 0 {
+		log.Printf("Restored %d jobs from job journal %s", restored, path)
+	}
+}
+
+// restoreBirdWeatherAction rebuilds a journaled BirdWeather upload
+func (p *Processor) restoreBirdWeatherAction(payload []byte) (jobqueue.Action, error) {
+	var state birdweatherJournal
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, err
+	}
+
+	bwClient := p.GetBwClient()
+	if !p.Settings.Realtime.Birdweather.Enabled || bwClient == nil {
+		return nil, integrationUnavailableError("birdweather")
+	}
+
+	return &ActionAdapter{action: &BirdWeatherAction{
+		Settings:     p.Settings,
+		EventTracker: p.GetEventTracker(),
+		BwClient:     bwClient,
+		Note:         state.Note,
+		pcmData:      state.PCMData,
+		RetryConfig:  newRetryConfig(&p.Settings.Realtime.Birdweather.RetrySettings),
+	}}, nil
+}
+
+// restoreMqttAction rebuilds a journaled MQTT publish
+func (p *Processor) restoreMqttAction(payload []byte) (jobqueue.Action, error) {
+	var state mqttJournal
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, err
+	}
+
+	mqttClient := p.GetMQTTClient()
+	if !p.Settings.Realtime.MQTT.Enabled || mqttClient == nil {
+		return nil, integrationUnavailableError("mqtt")
+	}
+
+	return &ActionAdapter{action: &MqttAction{
+		Settings:       p.Settings,
+		MqttClient:     mqttClient,
+		EventTracker:   p.GetEventTracker(),
+		Note:           state.Note,
+		BirdImageCache: p.BirdImageCache,
+		RetryConfig:    newRetryConfig(&p.Settings.Realtime.MQTT.RetrySettings),
+	}}, nil
+}
+
+// newRetryConfig converts retry settings to a job queue retry configuration
+func newRetryConfig(settings *conf.RetrySettings) jobqueue.RetryConfig {
+	return jobqueue.RetryConfig{
+		Enabled:      settings.Enabled,
+		MaxRetries:   settings.MaxRetries,
+		InitialDelay: time.Duration(settings.InitialDelay) * time.Second,
+		MaxDelay:     time.Duration(settings.MaxDelay) * time.Second,
+		Multiplier:   settings.BackoffMultiplier,
+	}
+}
+
+// integrationUnavailableError is returned when a journaled job can't be
+// restored because its integration is disabled or not connected, the job
+// stays in the journal
+func integrationUnavailableError(integration string) error {
+	return errors.Newf("%s integration is not available, keeping journaled job for the next start", integration).
+		Component("analysis.processor").
+		Category(errors.CategoryIntegration).
+		Priority(errors.PriorityLow).
+		Context("operation", "restore_job").
+		Context("integration", integration).
+		Build()
+}
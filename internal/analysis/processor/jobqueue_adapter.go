@@ -4,6 +4,8 @@
 // with the jobqueue package.
 package processor
 
+import "github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+
 // ActionAdapter adapts the processor.Action interface to the jobqueue.Action interface
 type ActionAdapter struct {
 	action Action
@@ -18,3 +20,29 @@ func (a *ActionAdapter) Execute(data interface{}) error {
 func (a *ActionAdapter) GetDescription() string {
 	return a.action.GetDescription()
 }
+
+// JournalType implements jobqueue.PersistentAction, actions that can't be
+// journaled return an empty type
+func (a *ActionAdapter) JournalType() string {
+	if action, ok := a.action.(jobqueue.PersistentAction); ok {
+		return action.JournalType()
+	}
+	return ""
+}
+
+// MarshalJournal implements jobqueue.PersistentAction
+func (a *ActionAdapter) MarshalJournal() ([]byte, error) {
+	if action, ok := a.action.(jobqueue.PersistentAction); ok {
+		return action.MarshalJournal()
+	}
+	return nil, nil
+}
+
+// Summary implements jobqueue.SummarizedAction, actions that can't be
+// summarized return an empty summary
+func (a *ActionAdapter) Summary() jobqueue.JobSummary {
+	if action, ok := a.action.(jobqueue.SummarizedAction); ok {
+		return action.Summary()
+	}
+	return jobqueue.JobSummary{}
+}
@@ -0,0 +1,130 @@
+// noise.go attaches the ambient noise level measured around a detection to its note
+package processor
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// noiseHistoryDuration is how long sound level measurements are kept per source
+	noiseHistoryDuration = 2 * time.Minute
+	// noiseMaxGap is how far from a detection a measurement may end or start
+	// to describe its noise when no measurement overlaps the detection
+	noiseMaxGap = 30 * time.Second
+)
+
+// NoiseLevels keeps recent sound level measurements of each audio source so
+// that detections can be saved with the ambient noise level around them.
+type NoiseLevels struct {
+	mu           sync.Mutex
+	measurements map[string][]myaudio.SoundLevelData // measurements per source, oldest first
+}
+
+// NewNoiseLevels creates an empty noise level history
+func NewNoiseLevels() *NoiseLevels {
+	return &NoiseLevels{measurements: make(map[string][]myaudio.SoundLevelData)}
+}
+
+// Record adds a sound level measurement and drops measurements of the source
+// older than the kept history
+func (n *NoiseLevels) Record(data *myaudio.SoundLevelData) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	history := append(n.measurements[data.Source], *data)
+	cutoff := data.Timestamp.Add(-noiseHistoryDuration)
+	first := 0
+	for first < len(history) && history[first].Timestamp.Before(cutoff) {
+		first++
+	}
+	n.measurements[data.Source] = history[first:]
+}
+
+// Context returns the broadband and per-band noise levels in dB of a source
+// between begin and end. Levels are energy averages of the measurements
+// overlapping the period, or of the nearest measurement within noiseMaxGap.
+// It returns false if there are no measurements near the period.
+func (n *NoiseLevels) Context(source string, begin, end time.Time) (level float64, bands map[string]float64, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var selected []*myaudio.SoundLevelData
+	var nearest *myaudio.SoundLevelData
+	nearestGap := noiseMaxGap + 1
+	history := n.measurements[source]
+	for i := range history {
+		m := &history[i]
+		mEnd := m.Timestamp
+		mBegin := mEnd.Add(-time.Duration(m.Duration) * time.Second)
+
+		var gap time.Duration
+		switch {
+		case mEnd.Before(begin):
+			gap = begin.Sub(mEnd)
+		case mBegin.After(end):
+			gap = mBegin.Sub(end)
+		default:
+			selected = append(selected, m)
+			continue
+		}
+		if gap <= noiseMaxGap && gap < nearestGap {
+			nearest, nearestGap = m, gap
+		}
+	}
+	if len(selected) == 0 && nearest != nil {
+		selected = append(selected, nearest)
+	}
+	if len(selected) == 0 {
+		return 0, nil, false
+	}
+
+	// Average band levels on the energy scale weighted by their sample counts
+	energy := make(map[string]float64)
+	weights := make(map[string]float64)
+	for _, m := range selected {
+		for band, data := range m.OctaveBands {
+			weight := float64(max(data.SampleCount, 1))
+			energy[band] += weight * math.Pow(10, data.Mean/10)
+			weights[band] += weight
+		}
+	}
+	if len(energy) == 0 {
+		return 0, nil, false
+	}
+
+	bands = make(map[string]float64, len(energy))
+	var total float64
+	for band, e := range energy {
+		mean := e / weights[band]
+		bands[band] = roundNoiseLevel(10 * math.Log10(mean))
+		total += mean
+	}
+	return roundNoiseLevel(10 * math.Log10(total)), bands, true
+}
+
+// roundNoiseLevel rounds a dB value to two decimal places
+func roundNoiseLevel(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// applyNoiseContext attaches the ambient noise around a detection to its note
+func (p *Processor) applyNoiseContext(item *PendingDetection) {
+	if p.NoiseLevels == nil {
+		return
+	}
+	note := &item.Detection.Note
+	end := note.EndTime
+	if end.Before(note.BeginTime) {
+		end = note.BeginTime
+	}
+	level, bands, ok := p.NoiseLevels.Context(item.Source, note.BeginTime, end)
+	if !ok {
+		return
+	}
+	note.NoiseLevel = &level
+	note.NoiseBands = bands
+}
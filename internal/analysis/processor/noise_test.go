@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// noiseMeasurement creates a 10 second measurement ending at end with the given band means
+func noiseMeasurement(source string, end time.Time, bands map[string]float64) *myaudio.SoundLevelData {
+	data := &myaudio.SoundLevelData{
+		Timestamp:   end,
+		Source:      source,
+		Duration:    10,
+		OctaveBands: make(map[string]myaudio.OctaveBandData),
+	}
+	for band, mean := range bands {
+		data.OctaveBands[band] = myaudio.OctaveBandData{Mean: mean, Min: mean, Max: mean, SampleCount: 10}
+	}
+	return data
+}
+
+func TestNoiseLevelsContext(t *testing.T) {
+	t.Parallel()
+
+	noise := NewNoiseLevels()
+	start := time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)
+	noise.Record(noiseMeasurement("mic", start.Add(10*time.Second), map[string]float64{"125_Hz": -40, "1.0_kHz": -50}))
+	noise.Record(noiseMeasurement("mic", start.Add(20*time.Second), map[string]float64{"125_Hz": -30, "1.0_kHz": -50}))
+	noise.Record(noiseMeasurement("rtsp", start.Add(20*time.Second), map[string]float64{"125_Hz": -10}))
+
+	// A detection spanning both measurements averages them on the energy scale
+	level, bands, ok := noise.Context("mic", start.Add(8*time.Second), start.Add(11*time.Second))
+	require.True(t, ok)
+	wantLow := 10 * math.Log10((math.Pow(10, -4)+math.Pow(10, -3))/2)
+	assert.InDelta(t, wantLow, bands["125_Hz"], 0.01)
+	assert.InDelta(t, -50, bands["1.0_kHz"], 0.01)
+	assert.InDelta(t, 10*math.Log10(math.Pow(10, wantLow/10)+math.Pow(10, -5)), level, 0.01)
+
+	// A detection after the last measurement uses the nearest measurement
+	_, bands, ok = noise.Context("mic", start.Add(40*time.Second), start.Add(43*time.Second))
+	require.True(t, ok)
+	assert.InDelta(t, -30, bands["125_Hz"], 0.01)
+
+	// Measurements too far from the detection or of other sources are not used
+	_, _, ok = noise.Context("mic", start.Add(time.Minute), start.Add(63*time.Second))
+	assert.False(t, ok)
+	_, _, ok = noise.Context("unknown", start, start.Add(3*time.Second))
+	assert.False(t, ok)
+}
+
+func TestNoiseLevelsRecordDropsOldMeasurements(t *testing.T) {
+	t.Parallel()
+
+	noise := NewNoiseLevels()
+	start := time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)
+	noise.Record(noiseMeasurement("mic", start, map[string]float64{"125_Hz": -40}))
+	noise.Record(noiseMeasurement("mic", start.Add(noiseHistoryDuration+time.Second), map[string]float64{"125_Hz": -30}))
+
+	noise.mu.Lock()
+	defer noise.mu.Unlock()
+	require.Len(t, noise.measurements["mic"], 1)
+	assert.InDelta(t, -30, noise.measurements["mic"][0].OctaveBands["125_Hz"].Mean, 0)
+}
+
+func TestApplyNoiseContext(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 6, 1, 6, 0, 0, 0, time.UTC)
+	p := &Processor{NoiseLevels: NewNoiseLevels()}
+	p.NoiseLevels.Record(noiseMeasurement("mic", start.Add(10*time.Second), map[string]float64{"1.0_kHz": -45}))
+
+	item := &PendingDetection{
+		Detection: Detections{Note: datastore.Note{BeginTime: start.Add(2 * time.Second), EndTime: start.Add(5 * time.Second)}},
+		Source:    "mic",
+	}
+	p.applyNoiseContext(item)
+	require.NotNil(t, item.Detection.Note.NoiseLevel)
+	assert.InDelta(t, -45, *item.Detection.Note.NoiseLevel, 0.01)
+	assert.Equal(t, map[string]float64{"1.0_kHz": -45}, item.Detection.Note.NoiseBands)
+
+	// Detections without measurements are saved without noise context
+	other := &PendingDetection{Detection: Detections{Note: datastore.Note{BeginTime: start}}, Source: "rtsp"}
+	p.applyNoiseContext(other)
+	assert.Nil(t, other.Detection.Note.NoiseLevel)
+
+	(&Processor{}).applyNoiseContext(other)
+	assert.Nil(t, other.Detection.Note.NoiseBands)
+}
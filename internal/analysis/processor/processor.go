@@ -14,6 +14,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/birdweather"
+	"github.com/tphakala/birdnet-go/internal/calibration"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
@@ -38,16 +39,17 @@ type Processor struct {
 	speciesTrackerMu    sync.RWMutex         // Mutex to protect NewSpeciesTracker access
 	lastSyncAttempt     time.Time            // Last time sync was attempted
 	syncMutex           sync.Mutex           // Mutex to protect sync operations
-	LastDogDetection    map[string]time.Time // keep track of dog barks per audio source
-	LastHumanDetection  map[string]time.Time // keep track of human vocal per audio source
 	Metrics             *observability.Metrics
 	DynamicThresholds   map[string]*DynamicThreshold
+	Calibrator          *calibration.Calibrator // Per-species confidence calibration, nil if disabled
+	scheduler           *ActionScheduler        // Schedule rules gating actions, nil if disabled
+	schedulerMu         sync.RWMutex            // Mutex to protect access to scheduler
+	filters             *FilterChain            // Filters discarding pending detections, e.g. privacy and dog bark
+	filtersMu           sync.RWMutex            // Mutex to protect access to filters
+	NoiseLevels         *NoiseLevels            // Recent sound levels for the noise context of detections
 	thresholdsMutex     sync.RWMutex // Mutex to protect access to DynamicThresholds
 	pendingDetections   map[string]PendingDetection
 	pendingMutex        sync.Mutex // Mutex to protect access to pendingDetections
-	lastDogDetectionLog map[string]time.Time
-	dogDetectionMutex   sync.Mutex
-	detectionMutex      sync.RWMutex // Mutex to protect LastDogDetection and LastHumanDetection maps
 	controlChan         chan string
 	JobQueue            *jobqueue.JobQueue // Queue for managing job retries
 	workerCancel        context.CancelFunc // Function to cancel worker goroutines
@@ -104,11 +106,9 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 			settings.Realtime.Species.Config,
 		),
 		Metrics:             metrics,
-		LastDogDetection:    make(map[string]time.Time),
-		LastHumanDetection:  make(map[string]time.Time),
 		DynamicThresholds:   make(map[string]*DynamicThreshold),
 		pendingDetections:   make(map[string]PendingDetection),
-		lastDogDetectionLog: make(map[string]time.Time),
+		NoiseLevels:         NewNoiseLevels(),
 		controlChan:         make(chan string, 10),  // Buffered channel to prevent blocking
 		JobQueue:            jobqueue.NewJobQueue(), // Initialize the job queue
 	}
@@ -147,6 +147,18 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 		}
 	}
 
+	// Build the filter chain for pending detections
+	p.filters = NewFilterChain(settings, ds)
+
+	// Parse schedule rules gating detection actions
+	p.scheduler = NewActionScheduler(settings)
+
+	// Fit confidence calibration curves from reviewed detections if enabled
+	if settings.Realtime.Calibration.Enabled && ds != nil {
+		p.Calibrator = calibration.New(ds, settings.Realtime.Calibration.Method, settings.Realtime.Calibration.MinSamples)
+		p.Calibrator.Start(time.Duration(settings.Realtime.Calibration.RefreshInterval) * time.Hour)
+	}
+
 	// Start the detection processor
 	p.startDetectionProcessor()
 
@@ -170,6 +182,9 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 	// Initialize MQTT client if enabled in settings
 	p.initializeMQTT(settings)
 
+	// Restore integration jobs journaled by the previous run
+	p.initializeJobJournal(settings)
+
 	// Start the job queue
 	p.JobQueue.Start()
 
@@ -229,7 +244,7 @@ func (p *Processor) processDetections(item *birdnet.Results) {
 		}
 
 		// Update the dynamic threshold for this species if enabled
-		p.updateDynamicThreshold(commonName, confidence)
+		p.updateDynamicThreshold(commonName, item.Source, confidence)
 
 		// Unlock the mutex to allow other goroutines to access shared resources
 		p.pendingMutex.Unlock()
@@ -265,6 +280,8 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 		p.syncMutex.Unlock()
 	}
 
+	filters := p.GetFilterChain()
+
 	// Process each result in item.Results
 	for _, result := range item.Results {
 		var confidenceThreshold float32
@@ -298,13 +315,12 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 			speciesLowercase = strings.ToLower(scientificName)
 		}
 
-		// Handle dog and human detection, this sets LastDogDetection and LastHumanDetection which is
-		// later used to discard detection if privacy filter or dog bark filters are enabled in settings.
-		p.handleDogDetection(item, speciesLowercase, result)
-		p.handleHumanDetection(item, speciesLowercase, result)
+		// Pass the result to filters tracking e.g. dog barks and human voices, these are
+		// later used to discard pending detections of the same source
+		filters.Observe(item.Source, item.StartTime, speciesLowercase, result.Confidence)
 
 		// Determine base confidence threshold
-		baseThreshold := p.getBaseConfidenceThreshold(speciesLowercase)
+		baseThreshold := p.getBaseConfidenceThreshold(speciesLowercase, item.Source)
 
 		// If result is human and detection exceeds base threshold, discard it
 		// due to privacy reasons we do not want human detections to reach actions stage
@@ -326,8 +342,8 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 			continue
 		}
 
-		// Match against location-based filter
-		if !p.Settings.IsSpeciesIncluded(result.Species) {
+		// Match against location-based filter of the audio source
+		if !p.Settings.IsSpeciesIncludedForSource(item.Source, result.Species) {
 			if p.Settings.Debug {
 				log.Printf("Species not on included list: %s\n", result.Species)
 			}
@@ -375,33 +391,9 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 	return detections
 }
 
-// handleDogDetection handles the detection of dog barks and updates the last detection timestamp.
-func (p *Processor) handleDogDetection(item *birdnet.Results, speciesLowercase string, result datastore.Results) {
-	if p.Settings.Realtime.DogBarkFilter.Enabled && strings.Contains(speciesLowercase, "dog") &&
-		result.Confidence > p.Settings.Realtime.DogBarkFilter.Confidence {
-		log.Printf("Dog detected with confidence %.3f/%.3f from source %s", result.Confidence, p.Settings.Realtime.DogBarkFilter.Confidence, item.Source)
-		p.detectionMutex.Lock()
-		p.LastDogDetection[item.Source] = item.StartTime
-		p.detectionMutex.Unlock()
-	}
-}
-
-// handleHumanDetection handles the detection of human vocalizations and updates the last detection timestamp.
-func (p *Processor) handleHumanDetection(item *birdnet.Results, speciesLowercase string, result datastore.Results) {
-	// only check this if privacy filter is enabled
-	if p.Settings.Realtime.PrivacyFilter.Enabled && strings.Contains(speciesLowercase, "human ") &&
-		result.Confidence > p.Settings.Realtime.PrivacyFilter.Confidence {
-		log.Printf("Human detected with confidence %.3f/%.3f from source %s", result.Confidence, p.Settings.Realtime.PrivacyFilter.Confidence, item.Source)
-		// put human detection timestamp into LastHumanDetection map. This is used to discard
-		// bird detections if a human vocalization is detected after the first detection
-		p.detectionMutex.Lock()
-		p.LastHumanDetection[item.Source] = item.StartTime
-		p.detectionMutex.Unlock()
-	}
-}
-
-// getBaseConfidenceThreshold retrieves the confidence threshold for a species, using custom or global thresholds.
-func (p *Processor) getBaseConfidenceThreshold(speciesLowercase string) float32 {
+// getBaseConfidenceThreshold retrieves the confidence threshold for a species, using custom,
+// per-source or global thresholds.
+func (p *Processor) getBaseConfidenceThreshold(speciesLowercase, source string) float32 {
 	// Check if species has a custom threshold in the new structure
 	if config, exists := p.Settings.Realtime.Species.Config[speciesLowercase]; exists {
 		if p.Settings.Debug {
@@ -410,8 +402,24 @@ func (p *Processor) getBaseConfidenceThreshold(speciesLowercase string) float32
 		return float32(config.Threshold)
 	}
 
-	// Fall back to global threshold
-	return float32(p.Settings.BirdNET.Threshold)
+	// Source threshold, which defaults to the global threshold
+	sourceThreshold := p.Settings.ResolveSourceSettings(source).Threshold
+
+	// Use the calibrated threshold if the species has enough reviewed detections.
+	// Detections below the source threshold were never saved or reviewed, so
+	// calibration only raises the threshold.
+	if p.Calibrator != nil {
+		target := p.Settings.Realtime.Calibration.Target
+		if threshold, ok := p.Calibrator.Threshold(speciesLowercase, target); ok {
+			threshold = math.Max(threshold, sourceThreshold)
+			if p.Settings.Debug {
+				log.Printf("Using calibrated confidence threshold of %.2f for %s (target %.2f)\n", threshold, speciesLowercase, target)
+			}
+			return float32(threshold)
+		}
+	}
+
+	return float32(sourceThreshold)
 }
 
 // generateClipName generates a clip name for the given scientific name and confidence.
@@ -443,40 +451,9 @@ func (p *Processor) generateClipName(scientificName string, confidence float32)
 	return clipName
 }
 
-// shouldDiscardDetection checks if a detection should be discarded based on various criteria
-func (p *Processor) shouldDiscardDetection(item *PendingDetection, minDetections int) (shouldDiscard bool, reason string) {
-	// Check minimum detection count
-	if item.Count < minDetections {
-		return true, fmt.Sprintf("false positive, matched %d/%d times", item.Count, minDetections)
-	}
-
-	// Check privacy filter
-	if p.Settings.Realtime.PrivacyFilter.Enabled {
-		p.detectionMutex.RLock()
-		lastHumanDetection, exists := p.LastHumanDetection[item.Source]
-		p.detectionMutex.RUnlock()
-		if exists && lastHumanDetection.After(item.FirstDetected) {
-			return true, "privacy filter"
-		}
-	}
-
-	// Check dog bark filter
-	if p.Settings.Realtime.DogBarkFilter.Enabled {
-		if p.Settings.Realtime.DogBarkFilter.Debug {
-			p.detectionMutex.RLock()
-			log.Printf("Last dog detection: %s\n", p.LastDogDetection)
-			p.detectionMutex.RUnlock()
-		}
-		p.detectionMutex.RLock()
-		lastDogDetection := p.LastDogDetection[item.Source]
-		p.detectionMutex.RUnlock()
-		if p.CheckDogBarkFilter(item.Detection.Note.CommonName, lastDogDetection) ||
-			p.CheckDogBarkFilter(item.Detection.Note.ScientificName, lastDogDetection) {
-			return true, "recent dog bark"
-		}
-	}
-
-	return false, ""
+// shouldDiscardDetection checks if a detection should be discarded by the detection filter chain
+func (p *Processor) shouldDiscardDetection(item *PendingDetection, now time.Time) (shouldDiscard bool, reason string) {
+	return p.GetFilterChain().Discard(item, now)
 }
 
 // processApprovedDetection handles an approved detection by sending it to the worker queue
@@ -485,7 +462,12 @@ func (p *Processor) processApprovedDetection(item *PendingDetection, species str
 		species, item.Source, item.Count)
 
 	item.Detection.Note.BeginTime = item.FirstDetected
+	p.applyNoiseContext(item)
 	actionList := p.getActionsForItem(&item.Detection)
+	actionList = p.filterScheduledActions(&item.Detection, actionList)
+	if item.Detection.Note.HeldForReview {
+		actionList = heldForReviewActions(actionList)
+	}
 	for _, action := range actionList {
 		task := &Task{Type: TaskTypeAction, Detection: item.Detection, Action: action}
 		if err := p.EnqueueTask(task); err != nil {
@@ -509,10 +491,6 @@ func (p *Processor) processApprovedDetection(item *PendingDetection, species str
 // pendingDetectionsFlusher runs a goroutine that periodically checks the pending detections
 // and flushes them to the worker queue if their deadline has passed.
 func (p *Processor) pendingDetectionsFlusher() {
-	// Calculate minimum detections based on overlap setting
-	segmentLength := math.Max(0.1, 3.0-p.Settings.BirdNET.Overlap)
-	minDetections := int(math.Max(1, 3/segmentLength))
-
 	go func() {
 		ticker := time.NewTicker(1 * time.Second)
 		defer ticker.Stop()
@@ -525,13 +503,14 @@ func (p *Processor) pendingDetectionsFlusher() {
 			for species := range p.pendingDetections {
 				item := p.pendingDetections[species]
 				if now.After(item.FlushDeadline) {
-					if shouldDiscard, reason := p.shouldDiscardDetection(&item, minDetections); shouldDiscard {
+					if shouldDiscard, reason := p.shouldDiscardDetection(&item, now); shouldDiscard {
 						log.Printf("Discarding detection of %s from source %s due to %s\n",
 							species, item.Source, reason)
 						delete(p.pendingDetections, species)
 						continue
 					}
 
+					p.GetFilterChain().Approved(&item, now)
 					p.processApprovedDetection(&item, species)
 					delete(p.pendingDetections, species)
 				}
@@ -567,6 +546,10 @@ func (p *Processor) getActionsForItem(detection *Detections) []Action {
 						Params:  parseCommandParams(actionConfig.Parameters, detection),
 					})
 				}
+			case "Webhook":
+				if actionConfig.Webhook != nil {
+					actions = append(actions, p.newWebhookAction(actionConfig.Webhook, detection))
+				}
 			case "SendNotification":
 				// Add notification action handling
 				// ... implementation ...
@@ -637,13 +620,7 @@ func (p *Processor) getDefaultActions(detection *Detections) []Action {
 		bwClient := p.GetBwClient() // Use getter for thread safety
 		if bwClient != nil {
 			// Create BirdWeather retry config from settings
-			bwRetryConfig := jobqueue.RetryConfig{
-				Enabled:      p.Settings.Realtime.Birdweather.RetrySettings.Enabled,
-				MaxRetries:   p.Settings.Realtime.Birdweather.RetrySettings.MaxRetries,
-				InitialDelay: time.Duration(p.Settings.Realtime.Birdweather.RetrySettings.InitialDelay) * time.Second,
-				MaxDelay:     time.Duration(p.Settings.Realtime.Birdweather.RetrySettings.MaxDelay) * time.Second,
-				Multiplier:   p.Settings.Realtime.Birdweather.RetrySettings.BackoffMultiplier,
-			}
+			bwRetryConfig := newRetryConfig(&p.Settings.Realtime.Birdweather.RetrySettings)
 
 			actions = append(actions, &BirdWeatherAction{
 				Settings:     p.Settings,
@@ -661,13 +638,7 @@ func (p *Processor) getDefaultActions(detection *Detections) []Action {
 		mqttClient := p.GetMQTTClient()
 		if mqttClient != nil && mqttClient.IsConnected() {
 			// Create MQTT retry config from settings
-			mqttRetryConfig := jobqueue.RetryConfig{
-				Enabled:      p.Settings.Realtime.MQTT.RetrySettings.Enabled,
-				MaxRetries:   p.Settings.Realtime.MQTT.RetrySettings.MaxRetries,
-				InitialDelay: time.Duration(p.Settings.Realtime.MQTT.RetrySettings.InitialDelay) * time.Second,
-				MaxDelay:     time.Duration(p.Settings.Realtime.MQTT.RetrySettings.MaxDelay) * time.Second,
-				Multiplier:   p.Settings.Realtime.MQTT.RetrySettings.BackoffMultiplier,
-			}
+			mqttRetryConfig := newRetryConfig(&p.Settings.Realtime.MQTT.RetrySettings)
 
 			actions = append(actions, &MqttAction{
 				Settings:       p.Settings,
@@ -702,6 +673,13 @@ func (p *Processor) getDefaultActions(detection *Detections) []Action {
 		})
 	}
 
+	// Add webhook actions for enabled webhooks
+	for i := range p.Settings.Realtime.Webhooks {
+		if webhook := &p.Settings.Realtime.Webhooks[i]; webhook.Enabled {
+			actions = append(actions, p.newWebhookAction(webhook, detection))
+		}
+	}
+
 	// Check if UpdateRangeFilterAction needs to be executed for the day
 	today := time.Now().Truncate(24 * time.Hour) // Current date with time set to midnight
 	if p.Settings.BirdNET.RangeFilter.LastUpdated.Before(today) {
@@ -772,6 +750,11 @@ func (p *Processor) GetBirdNET() *birdnet.BirdNET {
 	return p.Bn
 }
 
+// GetCalibrator returns the confidence calibrator, nil if calibration is disabled
+func (p *Processor) GetCalibrator() *calibration.Calibrator {
+	return p.Calibrator
+}
+
 // SetSSEBroadcaster safely sets the SSE broadcaster function
 func (p *Processor) SetSSEBroadcaster(broadcaster func(note *datastore.Note, birdImage *imageprovider.BirdImage) error) {
 	p.sseBroadcasterMutex.Lock()
@@ -825,6 +808,14 @@ func (p *Processor) Shutdown() error {
 	if err := p.JobQueue.StopWithTimeout(30 * time.Second); err != nil {
 		log.Printf("Warning: job queue shutdown timed out: %v", err)
 	}
+	if err := p.JobQueue.CloseJournal(); err != nil {
+		log.Printf("Warning: failed to close job journal: %v", err)
+	}
+
+	// Stop refitting calibration curves
+	if p.Calibrator != nil {
+		p.Calibrator.Stop()
+	}
 
 	// Disconnect BirdWeather client
 	p.DisconnectBwClient()
@@ -875,6 +866,9 @@ func (p *Processor) NewWithSpeciesInfo(
 	// Round confidence to two decimal places
 	roundedConfidence := math.Round(confidence*100) / 100
 
+	// Resolve location and threshold of the audio source
+	sourceSettings := p.Settings.ResolveSourceSettings(source)
+
 	// Return a new Note struct populated with the provided parameters and the current date and time
 	return datastore.Note{
 		SourceNode:     p.Settings.Main.Name,           // From the provided configuration settings
@@ -887,9 +881,9 @@ func (p *Processor) NewWithSpeciesInfo(
 		ScientificName: scientificName,                 // Scientific name from taxonomy lookup
 		CommonName:     commonName,                     // Common name from taxonomy lookup
 		Confidence:     roundedConfidence,              // Confidence score of the observation
-		Latitude:       p.Settings.BirdNET.Latitude,    // Geographic latitude where the observation was made
-		Longitude:      p.Settings.BirdNET.Longitude,   // Geographic longitude where the observation was made
-		Threshold:      p.Settings.BirdNET.Threshold,   // Threshold setting from configuration
+		Latitude:       sourceSettings.Latitude,        // Geographic latitude where the observation was made
+		Longitude:      sourceSettings.Longitude,       // Geographic longitude where the observation was made
+		Threshold:      sourceSettings.Threshold,       // Threshold setting of the audio source
 		Sensitivity:    p.Settings.BirdNET.Sensitivity, // Sensitivity setting from configuration
 		ClipName:       clipName,                       // Name of the audio clip
 		ProcessingTime: elapsedTime,                    // Time taken to process the observation
@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/calibration"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// reviewSource returns a fixed list of reviewed detections
+type reviewSource struct {
+	reviewed []datastore.ReviewedDetection
+}
+
+func (r *reviewSource) GetReviewedDetections(string) ([]datastore.ReviewedDetection, error) {
+	return r.reviewed, nil
+}
+
+func TestBaseConfidenceThresholdCalibrated(t *testing.T) {
+	// Most reviewed detections are correct, so a low target is reached far
+	// below the reviewed confidences
+	source := &reviewSource{}
+	for i, confidence := range []float64{0.3, 0.35, 0.4, 0.45, 0.5, 0.55, 0.6, 0.65} {
+		verified := "correct"
+		if i == 0 || i == 2 {
+			verified = "false_positive"
+		}
+		source.reviewed = append(source.reviewed, datastore.ReviewedDetection{
+			ScientificName: "Turdus merula",
+			CommonName:     "Eurasian Blackbird",
+			Confidence:     confidence,
+			Verified:       verified,
+		})
+	}
+	calibrator := calibration.New(source, calibration.MethodLogistic, 2)
+	require.NoError(t, calibrator.Refresh())
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.1
+	settings.Realtime.Calibration.Target = 0.05
+	p := &Processor{Settings: settings, Calibrator: calibrator}
+
+	// Never below the lowest reviewed confidence
+	assert.InDelta(t, 0.3, p.getBaseConfidenceThreshold("eurasian blackbird", ""), 0.0001)
+
+	// Never below the source threshold
+	settings.BirdNET.Threshold = 0.7
+	assert.InDelta(t, 0.7, p.getBaseConfidenceThreshold("eurasian blackbird", ""), 0.0001)
+
+	// Species without reviews use the source threshold
+	assert.InDelta(t, 0.7, p.getBaseConfidenceThreshold("great tit", ""), 0.0001)
+}
@@ -0,0 +1,279 @@
+// schedule.go gates detection actions with time of day, weekday and sun relative schedule rules
+package processor
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+// scheduleRule is a parsed conf.ScheduleRule
+type scheduleRule struct {
+	name     string
+	mute     bool                  // true to mute actions inside the window, false to allow them only inside it
+	actions  map[string]bool       // action types the rule applies to, empty for all
+	species  map[string]bool       // lower case common or scientific names, empty for all
+	weekdays map[time.Weekday]bool // weekdays the window may start on, empty for every day
+	start    conf.ScheduleTime
+	end      conf.ScheduleTime
+}
+
+// ActionScheduler decides whether an action may run for a detection at a
+// given time. An action is suppressed if it is inside the window of a
+// matching mute rule or outside the window of a matching allow rule. Sun
+// events are calculated for the location of the audio source.
+type ActionScheduler struct {
+	rules    []scheduleRule
+	settings *conf.Settings
+	mu       sync.Mutex
+	sunCalcs map[[2]float64]*suncalc.SunCalc // sun calculators by latitude and longitude
+}
+
+// NewActionScheduler creates a scheduler from the global and species schedule
+// rules. It returns nil if scheduling is disabled or no rules are configured.
+// Invalid rules are logged and skipped.
+func NewActionScheduler(settings *conf.Settings) *ActionScheduler {
+	if !settings.Realtime.Schedule.Enabled {
+		return nil
+	}
+
+	s := &ActionScheduler{
+		settings: settings,
+		sunCalcs: make(map[[2]float64]*suncalc.SunCalc),
+	}
+
+	for i := range settings.Realtime.Schedule.Rules {
+		s.addRule(&settings.Realtime.Schedule.Rules[i], nil)
+	}
+	for species, config := range settings.Realtime.Species.Config {
+		for i := range config.Schedule {
+			s.addRule(&config.Schedule[i], []string{species})
+		}
+	}
+
+	if len(s.rules) == 0 {
+		return nil
+	}
+	return s
+}
+
+// addRule parses a rule and adds it to the scheduler. Species rules pass the
+// species they are configured for, which is added to the rule species.
+func (s *ActionScheduler) addRule(rule *conf.ScheduleRule, species []string) {
+	name := rule.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", rule.Start, rule.End)
+	}
+
+	start, err := conf.ParseScheduleTime(rule.Start)
+	if err != nil {
+		log.Printf("⚠️ Skipping schedule rule %s: %v", name, err)
+		return
+	}
+	end, err := conf.ParseScheduleTime(rule.End)
+	if err != nil {
+		log.Printf("⚠️ Skipping schedule rule %s: %v", name, err)
+		return
+	}
+
+	r := scheduleRule{
+		name:     name,
+		mute:     !strings.EqualFold(rule.Mode, conf.ScheduleModeAllow),
+		actions:  make(map[string]bool),
+		species:  make(map[string]bool),
+		weekdays: make(map[time.Weekday]bool),
+		start:    start,
+		end:      end,
+	}
+	for _, action := range rule.Actions {
+		r.actions[strings.ToLower(action)] = true
+	}
+	for _, sp := range append(species, rule.Species...) {
+		r.species[strings.ToLower(sp)] = true
+	}
+	for _, day := range rule.Weekdays {
+		weekday, err := conf.ParseScheduleWeekday(day)
+		if err != nil {
+			log.Printf("⚠️ Skipping schedule rule %s: %v", name, err)
+			return
+		}
+		r.weekdays[weekday] = true
+	}
+
+	s.rules = append(s.rules, r)
+}
+
+// Allowed reports whether an action type may run for a species detected on an
+// audio source at the given time. If not, it also returns the name of the rule
+// suppressing the action.
+func (s *ActionScheduler) Allowed(actionType, source, commonName, scientificName string, t time.Time) (allowed bool, rule string) {
+	if s == nil || actionType == "" {
+		return true, ""
+	}
+
+	commonName = strings.ToLower(commonName)
+	scientificName = strings.ToLower(scientificName)
+	sunCalc := s.sunCalcFor(source)
+
+	for i := range s.rules {
+		r := &s.rules[i]
+		if len(r.actions) > 0 && !r.actions[actionType] {
+			continue
+		}
+		if len(r.species) > 0 && !r.species[commonName] && !r.species[scientificName] {
+			continue
+		}
+		if inWindow(r, sunCalc, t) == r.mute {
+			return false, r.name
+		}
+	}
+	return true, ""
+}
+
+// sunCalcFor returns the sun calculator for the location of an audio source
+func (s *ActionScheduler) sunCalcFor(source string) *suncalc.SunCalc {
+	resolved := s.settings.ResolveSourceSettings(source)
+	key := [2]float64{resolved.Latitude, resolved.Longitude}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sunCalc, ok := s.sunCalcs[key]
+	if !ok {
+		sunCalc = suncalc.NewSunCalc(resolved.Latitude, resolved.Longitude)
+		s.sunCalcs[key] = sunCalc
+	}
+	return sunCalc
+}
+
+// inWindow reports whether t is inside the window of the rule. Windows that
+// span midnight belong to the day they start on, so the window started on the
+// previous day is checked as well.
+func inWindow(r *scheduleRule, sunCalc *suncalc.SunCalc, t time.Time) bool {
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if len(r.weekdays) > 0 && !r.weekdays[day.Weekday()] {
+			continue
+		}
+
+		start, ok := resolveScheduleTime(r.start, sunCalc, day)
+		if !ok {
+			continue
+		}
+		end, ok := resolveScheduleTime(r.end, sunCalc, day)
+		if !ok {
+			continue
+		}
+		if !end.After(start) {
+			if end, ok = resolveScheduleTime(r.end, sunCalc, day.AddDate(0, 0, 1)); !ok {
+				continue
+			}
+		}
+
+		if !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveScheduleTime returns the time of a schedule time on the given day. It
+// returns false if the sun event does not occur on that day, e.g. in polar regions.
+func resolveScheduleTime(st conf.ScheduleTime, sunCalc *suncalc.SunCalc, day time.Time) (time.Time, bool) {
+	if st.SunEvent == "" {
+		// Use the wall clock time so daylight saving changes do not shift the window
+		hours := int(st.Offset / time.Hour)
+		minutes := int((st.Offset % time.Hour) / time.Minute)
+		return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, day.Location()), true
+	}
+
+	times, err := sunCalc.GetSunEventTimes(day)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var event time.Time
+	switch st.SunEvent {
+	case conf.SunEventSunrise:
+		event = times.Sunrise
+	case conf.SunEventSunset:
+		event = times.Sunset
+	case conf.SunEventDawn:
+		event = times.CivilDawn
+	case conf.SunEventDusk:
+		event = times.CivilDusk
+	}
+	if event.IsZero() {
+		return time.Time{}, false
+	}
+	return event.In(day.Location()).Add(st.Offset), true
+}
+
+// scheduleActionType returns the schedule action type of an action, or an
+// empty string for internal actions that are never gated by schedules.
+func scheduleActionType(action Action) string {
+	switch action.(type) {
+	case *DatabaseAction:
+		return "database"
+	case *LogAction:
+		return "log"
+	case *BirdWeatherAction:
+		return "birdweather"
+	case *MqttAction:
+		return "mqtt"
+	case *SSEAction:
+		return "sse"
+	case *WebhookAction:
+		return "webhook"
+	case *ExecuteCommandAction, ExecuteCommandAction:
+		return "command"
+	default:
+		return ""
+	}
+}
+
+// filterScheduledActions removes the actions muted by schedule rules at the
+// time of the detection.
+func (p *Processor) filterScheduledActions(detection *Detections, actions []Action) []Action {
+	scheduler := p.GetActionScheduler()
+	if scheduler == nil {
+		return actions
+	}
+
+	t := detection.Note.BeginTime
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	allowed := actions[:0]
+	for _, action := range actions {
+		ok, rule := scheduler.Allowed(scheduleActionType(action), detection.Note.Source, detection.Note.CommonName, detection.Note.ScientificName, t)
+		if !ok {
+			if p.Settings.Debug {
+				log.Printf("Schedule rule %s muted action %q for %s", rule, action.GetDescription(), detection.Note.CommonName)
+			}
+			continue
+		}
+		allowed = append(allowed, action)
+	}
+	return allowed
+}
+
+// GetActionScheduler safely returns the current action scheduler
+func (p *Processor) GetActionScheduler() *ActionScheduler {
+	p.schedulerMu.RLock()
+	defer p.schedulerMu.RUnlock()
+	return p.scheduler
+}
+
+// SetActionScheduler safely replaces the action scheduler, nil disables schedules
+func (p *Processor) SetActionScheduler(scheduler *ActionScheduler) {
+	p.schedulerMu.Lock()
+	defer p.schedulerMu.Unlock()
+	p.scheduler = scheduler
+}
@@ -0,0 +1,169 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func newScheduleTestSettings(rules ...conf.ScheduleRule) *conf.Settings {
+	settings := &conf.Settings{}
+	settings.BirdNET.Latitude = 60.17
+	settings.BirdNET.Longitude = 24.94
+	settings.Realtime.Schedule.Enabled = true
+	settings.Realtime.Schedule.Rules = rules
+	return settings
+}
+
+func TestActionSchedulerFixedWindow(t *testing.T) {
+	t.Parallel()
+
+	scheduler := NewActionScheduler(newScheduleTestSettings(conf.ScheduleRule{
+		Name:    "night",
+		Actions: []string{"mqtt"},
+		Species: []string{"Eurasian Blackbird"},
+		Start:   "22:00",
+		End:     "06:00",
+	}))
+	require.NotNil(t, scheduler)
+
+	// Wednesday 2024-05-15
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 15, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		action    string
+		species   string
+		t         time.Time
+		wantAllow bool
+	}{
+		{"inside before midnight", "mqtt", "eurasian blackbird", at(23, 0), false},
+		{"inside after midnight", "mqtt", "Eurasian Blackbird", at(5, 59), false},
+		{"window end is exclusive", "mqtt", "Eurasian Blackbird", at(6, 0), true},
+		{"outside window", "mqtt", "Eurasian Blackbird", at(12, 0), true},
+		{"other action", "database", "Eurasian Blackbird", at(23, 0), true},
+		{"other species", "mqtt", "Great Tit", at(23, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			allowed, _ := scheduler.Allowed(tt.action, "", tt.species, "", tt.t)
+			assert.Equal(t, tt.wantAllow, allowed)
+		})
+	}
+}
+
+func TestActionSchedulerAllowAndWeekdays(t *testing.T) {
+	t.Parallel()
+
+	scheduler := NewActionScheduler(newScheduleTestSettings(conf.ScheduleRule{
+		Name:     "weekend days",
+		Mode:     conf.ScheduleModeAllow,
+		Actions:  []string{"webhook"},
+		Weekdays: []string{"sat", "Sunday"},
+		Start:    "08:00",
+		End:      "20:00",
+	}))
+	require.NotNil(t, scheduler)
+
+	saturday := time.Date(2024, 5, 18, 12, 0, 0, 0, time.Local)
+	monday := time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local)
+
+	allowed, _ := scheduler.Allowed("webhook", "", "Great Tit", "Parus major", saturday)
+	assert.True(t, allowed)
+
+	allowed, rule := scheduler.Allowed("webhook", "", "Great Tit", "Parus major", monday)
+	assert.False(t, allowed)
+	assert.Equal(t, "weekend days", rule)
+
+	allowed, _ = scheduler.Allowed("webhook", "", "Great Tit", "Parus major", saturday.Add(9*time.Hour))
+	assert.False(t, allowed)
+}
+
+func TestActionSchedulerSunRelative(t *testing.T) {
+	t.Parallel()
+
+	settings := newScheduleTestSettings(conf.ScheduleRule{Start: "sunset+30m", End: "sunrise-30m"})
+	scheduler := NewActionScheduler(settings)
+	require.NotNil(t, scheduler)
+
+	day := time.Date(2024, 3, 20, 0, 0, 0, 0, time.Local)
+	times, err := scheduler.sunCalcFor("").GetSunEventTimes(day)
+	require.NoError(t, err)
+
+	allowed, _ := scheduler.Allowed("mqtt", "", "Tawny Owl", "Strix aluco", times.Sunset.Add(time.Hour))
+	assert.False(t, allowed, "muted after sunset")
+	allowed, _ = scheduler.Allowed("mqtt", "", "Tawny Owl", "Strix aluco", times.Sunset.Add(15*time.Minute))
+	assert.True(t, allowed, "allowed before sunset offset")
+	allowed, _ = scheduler.Allowed("mqtt", "", "Tawny Owl", "Strix aluco", times.Sunrise.Add(-time.Hour))
+	assert.False(t, allowed, "muted before sunrise")
+	allowed, _ = scheduler.Allowed("mqtt", "", "Tawny Owl", "Strix aluco", times.Sunrise)
+	assert.True(t, allowed, "allowed after sunrise offset")
+}
+
+func TestActionSchedulerSourceLocation(t *testing.T) {
+	t.Parallel()
+
+	settings := newScheduleTestSettings(conf.ScheduleRule{Start: "sunset+30m", End: "sunrise-30m"})
+	latitude, longitude := 37.77, -122.42
+	settings.Realtime.RTSP.Sources = []conf.RTSPSourceSettings{
+		{Name: "california", URL: "rtsp://camera", Latitude: &latitude, Longitude: &longitude},
+	}
+	scheduler := NewActionScheduler(settings)
+	require.NotNil(t, scheduler)
+
+	day := time.Date(2024, 3, 20, 0, 0, 0, 0, time.Local)
+	times, err := scheduler.sunCalcFor("").GetSunEventTimes(day)
+	require.NoError(t, err)
+
+	// An hour after sunset at the global location is midday in California
+	allowed, _ := scheduler.Allowed("mqtt", "", "Tawny Owl", "Strix aluco", times.Sunset.Add(time.Hour))
+	assert.False(t, allowed, "muted after sunset at the global location")
+	allowed, _ = scheduler.Allowed("mqtt", "rtsp://camera", "Tawny Owl", "Strix aluco", times.Sunset.Add(time.Hour))
+	assert.True(t, allowed, "allowed during the day at the source location")
+}
+
+func TestNewActionSchedulerSpeciesRules(t *testing.T) {
+	t.Parallel()
+
+	settings := newScheduleTestSettings()
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"eurasian blackbird": {Schedule: []conf.ScheduleRule{{Start: "00:00", End: "24:00"}}},
+	}
+	scheduler := NewActionScheduler(settings)
+	require.NotNil(t, scheduler)
+
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	allowed, _ := scheduler.Allowed("database", "", "Eurasian Blackbird", "Turdus merula", now)
+	assert.False(t, allowed)
+	allowed, _ = scheduler.Allowed("database", "", "Great Tit", "Parus major", now)
+	assert.True(t, allowed)
+
+	// Disabled schedules create no scheduler
+	settings.Realtime.Schedule.Enabled = false
+	assert.Nil(t, NewActionScheduler(settings))
+}
+
+func TestFilterScheduledActions(t *testing.T) {
+	t.Parallel()
+
+	p := &Processor{Settings: &conf.Settings{}}
+	p.SetActionScheduler(NewActionScheduler(newScheduleTestSettings(conf.ScheduleRule{
+		Actions: []string{"mqtt", "log"},
+		Start:   "00:00",
+		End:     "24:00",
+	})))
+
+	detection := newWebhookTestDetection()
+	actions := []Action{&DatabaseAction{}, &MqttAction{}, &LogAction{}, &UpdateRangeFilterAction{}}
+	filtered := p.filterScheduledActions(&detection, actions)
+
+	require.Len(t, filtered, 2)
+	assert.IsType(t, &DatabaseAction{}, filtered[0])
+	assert.IsType(t, &UpdateRangeFilterAction{}, filtered[1], "internal actions are never muted")
+}
@@ -0,0 +1,248 @@
+// webhook.go contains the action posting detections to HTTP endpoints
+package processor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+const (
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of the
+	// timestamp and body as "sha256=<hex>" when a webhook secret is set
+	WebhookSignatureHeader = "X-BirdNET-Signature"
+	// WebhookTimestampHeader carries the unix time used in the signature
+	WebhookTimestampHeader = "X-BirdNET-Timestamp"
+
+	defaultWebhookTimeout = 10 * time.Second
+	maxWebhookErrorBody   = 512 // bytes of the response body included in errors
+)
+
+// webhookClient is shared by all webhook actions, timeouts are set per request
+var webhookClient = &http.Client{}
+
+// webhookTemplates caches parsed body templates by template text
+var webhookTemplates sync.Map
+
+type WebhookAction struct {
+	Settings    *conf.Settings
+	Webhook     conf.WebhookSettings
+	Detection   Detections
+	RetryConfig jobqueue.RetryConfig // Configuration for retry behavior
+	Description string
+	mu          sync.Mutex // Protect concurrent access to Detection
+}
+
+// newWebhookAction creates a webhook action for a detection
+func (p *Processor) newWebhookAction(webhook *conf.WebhookSettings, detection *Detections) *WebhookAction {
+	return &WebhookAction{
+		Settings:  p.Settings,
+		Webhook:   *webhook,
+		Detection: *detection,
+		RetryConfig: jobqueue.RetryConfig{
+			Enabled:      webhook.RetrySettings.Enabled,
+			MaxRetries:   webhook.RetrySettings.MaxRetries,
+			InitialDelay: time.Duration(webhook.RetrySettings.InitialDelay) * time.Second,
+			MaxDelay:     time.Duration(webhook.RetrySettings.MaxDelay) * time.Second,
+			Multiplier:   webhook.RetrySettings.BackoffMultiplier,
+		},
+	}
+}
+
+// GetDescription returns a human-readable description of the WebhookAction
+func (a *WebhookAction) GetDescription() string {
+	if a.Description != "" {
+		return a.Description
+	}
+	if a.Webhook.Name != "" {
+		return fmt.Sprintf("Send detection to webhook %s", a.Webhook.Name)
+	}
+	return "Send detection to webhook"
+}
+
+// Execute sends the detection to the webhook endpoint
+func (a *WebhookAction) Execute(data interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	name := a.webhookName()
+
+	// Hide RTSP credentials from the payload
+	detection := a.Detection
+	detection.Note.Source = conf.SanitizeRTSPUrl(detection.Note.Source)
+
+	body, err := renderWebhookBody(a.Webhook.Template, &detection)
+	if err != nil {
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "webhook_render").
+			Context("integration", "webhook").
+			Context("webhook", name).
+			Context("retryable", false). // Template errors are not retryable
+			Build()
+	}
+
+	method := strings.ToUpper(a.Webhook.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	timeout := defaultWebhookTimeout
+	if a.Webhook.Timeout > 0 {
+		timeout = time.Duration(a.Webhook.Timeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var bodyReader io.Reader
+	if method != http.MethodGet {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.Webhook.URL, bodyReader)
+	if err != nil {
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "webhook_request").
+			Context("integration", "webhook").
+			Context("webhook", name).
+			Context("retryable", false).
+			Build()
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BirdNET-Go")
+	for key, value := range a.Webhook.Headers {
+		req.Header.Set(key, value)
+	}
+	if a.Webhook.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookBody(a.Webhook.Secret, timestamp, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		// Drop the URL from the error, it may contain tokens
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = fmt.Errorf("%s %s: %w", urlErr.Op, name, urlErr.Err)
+		}
+		a.logFailure(name, sanitizeError(err))
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryNetwork).
+			Context("operation", "webhook_send").
+			Context("integration", "webhook").
+			Context("webhook", name).
+			Context("species", detection.Note.CommonName).
+			Context("retryable", true). // Network errors are typically temporary
+			Build()
+	}
+	defer func() {
+		// Drain so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+		err := fmt.Errorf("webhook %s returned status %d: %s", name, resp.StatusCode, strings.TrimSpace(string(respBody)))
+		a.logFailure(name, err)
+		// Server errors, rate limiting and timeouts may succeed on retry
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusRequestTimeout
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryHTTP).
+			Context("operation", "webhook_send").
+			Context("integration", "webhook").
+			Context("webhook", name).
+			Context("status_code", resp.StatusCode).
+			Context("species", detection.Note.CommonName).
+			Context("retryable", retryable).
+			Build()
+	}
+
+	if a.Settings.Debug {
+		log.Printf("✅ Sent %s detection to webhook %s (status %d)\n", detection.Note.CommonName, name, resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookName returns the name of the webhook for logs
+func (a *WebhookAction) webhookName() string {
+	if a.Webhook.Name != "" {
+		return a.Webhook.Name
+	}
+	// Use the host only, the URL path or query may contain tokens
+	if u, err := url.Parse(a.Webhook.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "webhook"
+}
+
+// logFailure logs a failed webhook call and notifies if it will not be retried
+func (a *WebhookAction) logFailure(name string, err error) {
+	if a.RetryConfig.Enabled {
+		log.Printf("❌ Error sending %s detection to webhook %s (will retry): %v\n", a.Detection.Note.CommonName, name, err)
+		return
+	}
+	log.Printf("❌ Error sending %s detection to webhook %s: %v\n", a.Detection.Note.CommonName, name, err)
+	notification.NotifyIntegrationFailure("Webhook", err)
+}
+
+// renderWebhookBody renders the request body of a detection. An empty
+// template sends the note as JSON.
+func renderWebhookBody(text string, detection *Detections) ([]byte, error) {
+	if strings.TrimSpace(text) == "" {
+		return json.Marshal(detection.Note)
+	}
+
+	var tmpl *template.Template
+	if cached, ok := webhookTemplates.Load(text); ok {
+		tmpl = cached.(*template.Template)
+	} else {
+		parsed, err := conf.ParseWebhookTemplate(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		webhookTemplates.Store(text, parsed)
+		tmpl = parsed
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, detection); err != nil {
+		return nil, fmt.Errorf("error rendering webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SignWebhookBody returns the signature of a webhook request, the hex encoded
+// HMAC-SHA256 of the timestamp and body joined with a dot, prefixed "sha256=".
+func SignWebhookBody(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
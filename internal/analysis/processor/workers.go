@@ -108,6 +108,8 @@ func getJobQueueRetryConfig(action Action) jobqueue.RetryConfig {
 		return a.RetryConfig // Now directly returns jobqueue.RetryConfig
 	case *MqttAction:
 		return a.RetryConfig // Now directly returns jobqueue.RetryConfig
+	case *WebhookAction:
+		return a.RetryConfig
 	default:
 		// Default no retry for actions that don't support it
 		return jobqueue.RetryConfig{Enabled: false}
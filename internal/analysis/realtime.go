@@ -18,6 +18,8 @@ import (
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	"github.com/tphakala/birdnet-go/internal/audiocore/adapter"
 	"github.com/tphakala/birdnet-go/internal/backup"
+	backupsources "github.com/tphakala/birdnet-go/internal/backup/sources"
+	backuptargets "github.com/tphakala/birdnet-go/internal/backup/targets"
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
@@ -111,6 +113,12 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 			Build()
 	}
 
+	// Start the optional prediction archive, failure is not fatal for realtime analysis
+	predictionArchive, err := initializePredictionArchive(settings)
+	if err != nil {
+		log.Printf("⚠️ Warning: %v", err)
+	}
+
 	// Clean up any leftover HLS streaming files from previous runs
 	if err := cleanupHLSStreamingFiles(); err != nil {
 		log.Printf("⚠️ Warning: Failed to clean up HLS streaming files: %v", err)
@@ -125,6 +133,9 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 	// Print system details and configuration
 	printSystemDetails(settings)
 
+	// Replace the database with a restore staged while it was in use
+	applyPendingRestore(settings)
+
 	// Initialize database access.
 	dataStore := datastore.New(settings)
 
@@ -176,6 +187,9 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 	
 	// Note: datastore monitoring is automatically started when the database is opened
 
+	// Keep notification history in the database if configured
+	initializeNotificationStore(settings, dataStore)
+
 	// Initialize bird image cache if needed
 	birdImageCache := initializeBirdImageCacheIfNeeded(settings, dataStore, metrics)
 
@@ -188,7 +202,7 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 		log.Println("Error: Backup logger is nil. Logging may not be initialized.")
 		backupLogger = slog.Default() // Use default as fallback
 	}
-	backupManager, backupScheduler, err := initializeBackupSystem(settings, backupLogger)
+	backupManager, backupScheduler, err := initializeBackupSystem(settings, dataStore, backupLogger)
 	if err != nil {
 		// Log the specific error from initialization
 		backupLogger.Error("Failed to initialize backup system", "error", err)
@@ -210,6 +224,9 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 			Build()
 	}
 
+	// Load species history for detection rules matching species not seen in a number of days
+	seedDetectionRuleHistory(settings, dataStore)
+
 	// Initialize system monitor if monitoring is enabled
 	systemMonitor := initializeSystemMonitor(settings)
 
@@ -224,7 +241,7 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 	bufferManager := MustNewBufferManager(bn, quitChan, &wg)
 
 	// Start buffer monitors for each audio source only if we have active sources
-	if len(settings.Realtime.RTSP.URLs) > 0 || settings.Realtime.Audio.Source != "" {
+	if len(settings.Realtime.RTSP.AllURLs()) > 0 || settings.Realtime.Audio.Source != "" {
 		if err := bufferManager.UpdateMonitors(sources); err != nil {
 			// Use structured logging to improve error visibility and triage
 			logger := GetLogger()
@@ -253,7 +270,7 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 	// The control monitor will start sound level monitoring if enabled in settings.
 
 	// RTSP health monitoring is now built into the FFmpeg manager
-	if len(settings.Realtime.RTSP.URLs) > 0 {
+	if len(settings.Realtime.RTSP.AllURLs()) > 0 {
 		log.Println("🔍 RTSP streams will be monitored by FFmpeg manager")
 	}
 
@@ -375,6 +392,7 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 				if notification.IsInitialized() {
 					log.Println("  8️⃣ Stopping notification service...")
 					if service := notification.GetService(); service != nil {
+						notification.StopPush()
 						service.Stop()
 					}
 				}
@@ -384,8 +402,9 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 					return
 				}
 				
-				// Step 9: Delete BirdNET interpreter
+				// Step 9: Flush prediction archive and delete BirdNET interpreter
 				log.Println("  9️⃣ Cleaning up BirdNET interpreter...")
+				closePredictionArchive(predictionArchive)
 				bn.Delete()
 				
 				log.Printf("✅ Graceful shutdown completed in %v", time.Since(shutdownStart))
@@ -1052,8 +1071,24 @@ func cleanupHLSStreamingFiles() error {
 	return nil
 }
 
+// applyPendingRestore replaces the SQLite database with a backup restore
+// staged through the API, it must run before the database is opened.
+func applyPendingRestore(settings *conf.Settings) {
+	if !settings.Output.SQLite.Enabled || settings.Output.SQLite.Path == "" {
+		return
+	}
+	applied, previous, err := backup.ApplyPendingRestore(settings.Output.SQLite.Path)
+	if err != nil {
+		log.Printf("⚠️ Failed to apply staged database restore, keeping the current database: %v", err)
+		return
+	}
+	if applied {
+		log.Printf("♻️ Applied staged database restore to %s, previous database kept as %s", settings.Output.SQLite.Path, previous)
+	}
+}
+
 // initializeBackupSystem sets up the backup manager and scheduler.
-func initializeBackupSystem(settings *conf.Settings, backupLogger *slog.Logger) (*backup.Manager, *backup.Scheduler, error) {
+func initializeBackupSystem(settings *conf.Settings, dataStore datastore.Interface, backupLogger *slog.Logger) (*backup.Manager, *backup.Scheduler, error) {
 	backupLogger.Info("Initializing backup system...")
 
 	stateManager, err := backup.NewStateManager(backupLogger)
@@ -1074,6 +1109,18 @@ func initializeBackupSystem(settings *conf.Settings, backupLogger *slog.Logger)
 			Context("operation", "initialize_backup_manager").
 			Build()
 	}
+
+	// Register the database and clip sources and configured targets so backups
+	// can be created and restored
+	if settings.Backup.Enabled {
+		for _, err := range backupsources.RegisterConfigured(backupManager, settings, dataStore, backupLogger) {
+			backupLogger.Error("Failed to register backup source", "error", err)
+		}
+		for _, err := range backuptargets.RegisterConfigured(backupManager, &settings.Backup, backupLogger) {
+			backupLogger.Error("Failed to register backup target", "error", err)
+		}
+	}
+
 	backupScheduler, err := backup.NewScheduler(backupManager, backupLogger, stateManager)
 	if err != nil {
 		return nil, nil, errors.New(err).
@@ -1085,7 +1132,7 @@ func initializeBackupSystem(settings *conf.Settings, backupLogger *slog.Logger)
 
 	// Load schedule for backupScheduler if backup is enabled
 	switch {
-	case settings.Backup.Enabled && len(settings.Backup.Schedules) > 0:
+	case settings.Backup.Enabled && (len(settings.Backup.Schedules) > 0 || len(settings.Backup.Clips.Schedules) > 0):
 		backupLogger.Info("Loading backup schedule from configuration")
 		if err := backupScheduler.LoadFromConfig(&settings.Backup); err != nil {
 			// Log the error but don't necessarily stop initialization
@@ -1167,12 +1214,65 @@ func initializeBirdImageCacheIfNeeded(settings *conf.Settings, dataStore datasto
 	return initBirdImageCache(dataStore, metrics)
 }
 
+// initializeNotificationStore replaces the in-memory notification store with a
+// database store when configured, so that notifications and their read state
+// survive restarts. Failures leave the in-memory store in use.
+func initializeNotificationStore(settings *conf.Settings, dataStore datastore.Interface) {
+	if settings.Notification.Store.Type != "database" || !notification.IsInitialized() {
+		return
+	}
+	service := notification.GetService()
+	if service == nil {
+		return
+	}
+
+	retention := time.Duration(settings.Notification.Store.RetentionDays) * 24 * time.Hour
+	store := datastore.NewNotificationStore(dataStore, retention)
+	if err := service.SetStore(store); err != nil {
+		log.Printf("Failed to use database notification store, keeping notifications in memory: %v", err)
+		return
+	}
+	// Remove notifications past retention left from earlier runs
+	if err := store.DeleteExpired(); err != nil {
+		log.Printf("Failed to delete expired notifications: %v", err)
+	}
+}
+
+// seedDetectionRuleHistory loads the last detection of each species from the
+// database for detection notification rules matching species not seen in a
+// number of days, so that the rules work across restarts
+func seedDetectionRuleHistory(settings *conf.Settings, dataStore datastore.Interface) {
+	needed := false
+	for i := range settings.Notification.Rules {
+		rule := &settings.Notification.Rules[i]
+		if rule.Enabled && rule.NotSeenDays > 0 {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return
+	}
+
+	summaries, err := dataStore.GetSpeciesSummaryData("", "")
+	if err != nil {
+		log.Printf("Failed to load species history for detection rules: %v", err)
+		return
+	}
+	lastSeen := make(map[string]time.Time, len(summaries))
+	for i := range summaries {
+		lastSeen[summaries[i].ScientificName] = summaries[i].LastSeen
+	}
+	notification.SeedDetectionRuleHistory(lastSeen)
+}
+
 // initializeAudioSources prepares and validates audio sources
 func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 	var sources []string
-	if len(settings.Realtime.RTSP.URLs) > 0 || settings.Realtime.Audio.Source != "" {
-		if len(settings.Realtime.RTSP.URLs) > 0 {
-			sources = settings.Realtime.RTSP.URLs
+	rtspURLs := settings.Realtime.RTSP.AllURLs()
+	if len(rtspURLs) > 0 || settings.Realtime.Audio.Source != "" {
+		if len(rtspURLs) > 0 {
+			sources = rtspURLs
 		}
 		if settings.Realtime.Audio.Source != "" {
 			// We'll add malgo to sources only if device initialization succeeds
@@ -36,6 +36,9 @@ const (
 	errMsgOutOfRange        = "dB values out of range in octave band %s"
 	errMsgNonFiniteValues   = "non-finite values in octave band %s"
 	errMsgNoOctaveBandData  = "no octave band data"
+
+	// Buffered measurements per publisher
+	soundLevelFanOutBuffer = 20
 )
 
 // Package-level logger for sound level monitoring
@@ -518,22 +521,73 @@ func startSoundLevelPublishers(wg *sync.WaitGroup, doneChan chan struct{}, proc
 		close(mergedQuitChan)
 	}()
 
+	// Each publisher receives every measurement from its own channel
+	var outputs []chan myaudio.SoundLevelData
+	subscribe := func() chan myaudio.SoundLevelData {
+		output := make(chan myaudio.SoundLevelData, soundLevelFanOutBuffer)
+		outputs = append(outputs, output)
+		return output
+	}
+
 	// Start MQTT publisher if enabled
 	if settings.Realtime.MQTT.Enabled {
-		startSoundLevelMQTTPublisherWithDone(wg, mergedQuitChan, proc, soundLevelChan)
+		startSoundLevelMQTTPublisherWithDone(wg, mergedQuitChan, proc, subscribe())
 	}
 
 	// Start SSE publisher if API is available
 	if httpServer != nil && httpServer.APIV2 != nil {
-		startSoundLevelSSEPublisherWithDone(wg, mergedQuitChan, httpServer.APIV2, soundLevelChan)
+		startSoundLevelSSEPublisherWithDone(wg, mergedQuitChan, httpServer.APIV2, subscribe())
 	}
 
 	// Start metrics publisher
 	if proc != nil && proc.Metrics != nil && proc.Metrics.SoundLevel != nil {
-		startSoundLevelMetricsPublisherWithDone(wg, mergedQuitChan, proc.Metrics, soundLevelChan)
+		startSoundLevelMetricsPublisherWithDone(wg, mergedQuitChan, proc.Metrics, subscribe())
+	}
+
+	// Record measurements for the noise context of detections
+	if proc != nil && proc.NoiseLevels != nil {
+		startSoundLevelNoisePublisherWithDone(wg, mergedQuitChan, proc.NoiseLevels, subscribe())
+	}
+
+	// Start store publisher if sound level history is enabled
+	if storage := &settings.Realtime.Audio.SoundLevel.Storage; storage.Enabled && proc != nil && proc.Ds != nil {
+		startSoundLevelStorePublisherWithDone(wg, mergedQuitChan, proc.Ds, storage, subscribe())
+	}
+
+	if len(outputs) > 0 {
+		startSoundLevelFanOut(wg, mergedQuitChan, soundLevelChan, outputs)
 	}
 }
 
+// startSoundLevelFanOut copies every sound level measurement to each publisher channel.
+// A publisher that falls behind misses measurements instead of blocking the others.
+func startSoundLevelFanOut(wg *sync.WaitGroup, doneChan <-chan struct{}, soundLevelChan <-chan myaudio.SoundLevelData, outputs []chan myaudio.SoundLevelData) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-doneChan:
+				return
+			case soundData, ok := <-soundLevelChan:
+				if !ok {
+					getSoundLevelLogger().Info("Sound level channel closed, stopping sound level fan-out")
+					return
+				}
+				for _, output := range outputs {
+					select {
+					case output <- soundData:
+					default:
+						getSoundLevelLogger().Debug("sound level publisher channel full, dropping measurement",
+							"source", soundData.Source)
+					}
+				}
+			}
+		}
+	}()
+}
+
 // startSoundLevelMQTTPublisherWithDone starts MQTT publisher with a custom done channel
 func startSoundLevelMQTTPublisherWithDone(wg *sync.WaitGroup, doneChan <-chan struct{}, proc *processor.Processor, soundLevelChan <-chan myaudio.SoundLevelData) {
 	wg.Add(1)
@@ -686,6 +740,30 @@ func startSoundLevelMetricsPublisherWithDone(wg *sync.WaitGroup, doneChan chan s
 	}()
 }
 
+// startSoundLevelNoisePublisherWithDone starts a publisher recording sound levels for the noise context of detections
+func startSoundLevelNoisePublisherWithDone(wg *sync.WaitGroup, doneChan <-chan struct{}, noiseLevels *processor.NoiseLevels, soundLevelChan <-chan myaudio.SoundLevelData) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-doneChan:
+				return
+			case soundData, ok := <-soundLevelChan:
+				if !ok {
+					return
+				}
+				if err := validateSoundLevelData(&soundData); err != nil {
+					continue
+				}
+				sanitizedData := sanitizeSoundLevelData(soundData)
+				noiseLevels.Record(&sanitizedData)
+			}
+		}
+	}()
+}
+
 // registerSoundLevelProcessorsForActiveSources registers sound level processors for all active audio sources
 func registerSoundLevelProcessorsForActiveSources(settings *conf.Settings) error {
 	var errs []error
@@ -715,7 +793,7 @@ func registerSoundLevelProcessorsForActiveSources(settings *conf.Settings) error
 	
 	// Register for each configured RTSP source, but prioritize actually running streams
 	configuredURLs := make(map[string]bool)
-	for _, url := range settings.Realtime.RTSP.URLs {
+	for _, url := range settings.Realtime.RTSP.AllURLs() {
 		configuredURLs[url] = true
 		totalSources++
 		displayName := conf.SanitizeRTSPUrl(url)
@@ -776,7 +854,7 @@ func unregisterAllSoundLevelProcessors(settings *conf.Settings) {
 	}
 
 	// Unregister all RTSP sources
-	for _, url := range settings.Realtime.RTSP.URLs {
+	for _, url := range settings.Realtime.RTSP.AllURLs() {
 		myaudio.UnregisterSoundLevelProcessor(url)
 		LogSoundLevelProcessorUnregistered(conf.SanitizeRTSPUrl(url), "rtsp_stream", "analysis.soundlevel")
 	}
@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// soundLevelCleanupInterval is how often stored sound levels past retention are deleted
+const soundLevelCleanupInterval = time.Hour
+
+// soundLevelWindow holds the measurements of one source in the current storage period
+type soundLevelWindow struct {
+	start  time.Time
+	levels []datastore.SoundLevel
+}
+
+// soundLevelRecorder downsamples sound level measurements into storage periods
+// per source and band. A period of a source is complete once a measurement of
+// the source starts in a later period, measurements of a source arrive in order.
+type soundLevelRecorder struct {
+	period  time.Duration
+	windows map[string]*soundLevelWindow // current period per source
+}
+
+// newSoundLevelRecorder creates a recorder aggregating measurements into periods of the given length
+func newSoundLevelRecorder(period time.Duration) *soundLevelRecorder {
+	return &soundLevelRecorder{
+		period:  period,
+		windows: make(map[string]*soundLevelWindow),
+	}
+}
+
+// add records a measurement and returns the aggregated levels of a period it completes
+func (r *soundLevelRecorder) add(data *myaudio.SoundLevelData) []datastore.SoundLevel {
+	// Measurement timestamps mark the end of the measurement interval
+	start := data.Timestamp.Add(-time.Duration(data.Duration) * time.Second).UTC()
+	seconds := int64(r.period / time.Second)
+	windowStart := time.Unix(start.Unix()/seconds*seconds, 0).UTC()
+
+	var completed []datastore.SoundLevel
+	window, ok := r.windows[data.Source]
+	if ok && !window.start.Equal(windowStart) {
+		completed = datastore.AggregateSoundLevels(window.levels, r.period)
+		ok = false
+	}
+	if !ok {
+		window = &soundLevelWindow{start: windowStart}
+		r.windows[data.Source] = window
+	}
+
+	for band, bandData := range data.OctaveBands {
+		window.levels = append(window.levels, datastore.SoundLevel{
+			Timestamp:  start,
+			Source:     data.Source,
+			Name:       data.Name,
+			Band:       band,
+			CenterFreq: bandData.CenterFreq,
+			Duration:   data.Duration,
+			// Level means are averages of 1-second levels and stand in for Leq of the interval
+			Leq:     bandData.Mean,
+			Lmax:    bandData.Max,
+			Lmin:    bandData.Min,
+			Samples: bandData.SampleCount,
+		})
+	}
+	return completed
+}
+
+// flush returns the aggregated levels of all incomplete periods and resets the recorder
+func (r *soundLevelRecorder) flush() []datastore.SoundLevel {
+	var levels []datastore.SoundLevel
+	for source, window := range r.windows {
+		levels = append(levels, datastore.AggregateSoundLevels(window.levels, r.period)...)
+		delete(r.windows, source)
+	}
+	return levels
+}
+
+// startSoundLevelStorePublisherWithDone starts a publisher persisting downsampled
+// sound levels to the datastore and deleting levels past the retention period
+func startSoundLevelStorePublisherWithDone(wg *sync.WaitGroup, doneChan <-chan struct{}, store datastore.Interface, settings *conf.SoundLevelStorageSettings, soundLevelChan <-chan myaudio.SoundLevelData) {
+	recorder := newSoundLevelRecorder(time.Duration(settings.Interval) * time.Second)
+	retentionDays := settings.RetentionDays
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		getSoundLevelLogger().Info("Started sound level store publisher",
+			"interval_seconds", settings.Interval,
+			"retention_days", retentionDays)
+
+		cleanupTicker := time.NewTicker(soundLevelCleanupInterval)
+		defer cleanupTicker.Stop()
+		cleanupSoundLevels(store, retentionDays)
+
+		for {
+			select {
+			case <-doneChan:
+				// Partial periods are stored so that no measurements are lost on restart
+				saveSoundLevels(store, recorder.flush())
+				getSoundLevelLogger().Info("Stopping sound level store publisher")
+				return
+			case soundData, ok := <-soundLevelChan:
+				if !ok {
+					saveSoundLevels(store, recorder.flush())
+					getSoundLevelLogger().Info("Sound level channel closed, stopping store publisher")
+					return
+				}
+				if err := validateSoundLevelData(&soundData); err != nil {
+					getSoundLevelLogger().Debug("skipping invalid sound level data for storage",
+						"source", soundData.Source,
+						"error", err)
+					continue
+				}
+				sanitizedData := sanitizeSoundLevelData(soundData)
+				saveSoundLevels(store, recorder.add(&sanitizedData))
+			case <-cleanupTicker.C:
+				cleanupSoundLevels(store, retentionDays)
+			}
+		}
+	}()
+}
+
+// saveSoundLevels stores aggregated sound levels, logging failures
+func saveSoundLevels(store datastore.Interface, levels []datastore.SoundLevel) {
+	if len(levels) == 0 {
+		return
+	}
+	if err := store.SaveSoundLevels(levels); err != nil {
+		getSoundLevelLogger().Error("Failed to store sound levels",
+			"error", err,
+			"count", len(levels))
+		return
+	}
+	if conf.Setting().Realtime.Audio.SoundLevel.Debug {
+		getSoundLevelLogger().Debug("stored sound levels",
+			"count", len(levels),
+			"period_start", levels[0].Timestamp)
+	}
+}
+
+// cleanupSoundLevels deletes stored sound levels older than the retention period, 0 keeps all
+func cleanupSoundLevels(store datastore.Interface, retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	deleted, err := store.DeleteSoundLevelsBefore(cutoff)
+	if err != nil {
+		getSoundLevelLogger().Error("Failed to delete old sound levels",
+			"error", err,
+			"retention_days", retentionDays)
+		return
+	}
+	if deleted > 0 {
+		getSoundLevelLogger().Info("Deleted sound levels past retention",
+			"deleted", deleted,
+			"cutoff", cutoff)
+	}
+}
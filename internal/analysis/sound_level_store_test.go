@@ -0,0 +1,154 @@
+package analysis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// soundLevelTestStore records stored sound levels, other datastore methods are not implemented
+type soundLevelTestStore struct {
+	datastore.Interface
+	mu      sync.Mutex
+	saved   []datastore.SoundLevel
+	cutoffs []time.Time
+}
+
+func (s *soundLevelTestStore) SaveSoundLevels(levels []datastore.SoundLevel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = append(s.saved, levels...)
+	return nil
+}
+
+func (s *soundLevelTestStore) DeleteSoundLevelsBefore(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cutoffs = append(s.cutoffs, before)
+	return 0, nil
+}
+
+func (s *soundLevelTestStore) savedLevels() []datastore.SoundLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]datastore.SoundLevel(nil), s.saved...)
+}
+
+// testSoundLevelData creates a 10 second measurement of a single band ending at the given time
+func testSoundLevelData(source string, end time.Time, mean float64) myaudio.SoundLevelData {
+	return myaudio.SoundLevelData{
+		Timestamp: end,
+		Source:    source,
+		Name:      source,
+		Duration:  10,
+		OctaveBands: map[string]myaudio.OctaveBandData{
+			"1.0_kHz": {CenterFreq: 1000, Min: mean - 5, Max: mean + 5, Mean: mean, SampleCount: 10},
+		},
+	}
+}
+
+func TestSoundLevelRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := newSoundLevelRecorder(time.Minute)
+	minute := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Six measurements fill the first minute of each source
+	for i := 1; i <= 6; i++ {
+		end := minute.Add(time.Duration(i*10) * time.Second)
+		for _, source := range []string{"mic", "rtsp"} {
+			data := testSoundLevelData(source, end, -40)
+			assert.Empty(t, recorder.add(&data), "period completed early")
+		}
+	}
+
+	// The first measurement of the next minute completes the first minute of its source only
+	next := testSoundLevelData("mic", minute.Add(70*time.Second), -30)
+	completed := recorder.add(&next)
+	require.Len(t, completed, 1)
+	assert.Equal(t, "mic", completed[0].Source)
+	assert.True(t, completed[0].Timestamp.Equal(minute))
+	assert.Equal(t, 60, completed[0].Duration)
+	assert.Equal(t, 60, completed[0].Samples)
+	assert.InDelta(t, -40, completed[0].Leq, 1e-9)
+	assert.InDelta(t, -35, completed[0].Lmax, 1e-9)
+	assert.InDelta(t, -45, completed[0].Lmin, 1e-9)
+
+	remaining := recorder.flush()
+	require.Len(t, remaining, 2)
+	for _, level := range remaining {
+		switch level.Source {
+		case "mic":
+			assert.True(t, level.Timestamp.Equal(minute.Add(time.Minute)))
+			assert.Equal(t, 10, level.Duration)
+		case "rtsp":
+			assert.True(t, level.Timestamp.Equal(minute))
+			assert.Equal(t, 60, level.Duration)
+		}
+	}
+	assert.Empty(t, recorder.flush())
+}
+
+func TestSoundLevelStorePublisher(t *testing.T) {
+	t.Parallel()
+
+	store := &soundLevelTestStore{}
+	settings := &conf.SoundLevelStorageSettings{Enabled: true, Interval: 60, RetentionDays: 7}
+	soundLevelChan := make(chan myaudio.SoundLevelData, 10)
+	doneChan := make(chan struct{})
+	var wg sync.WaitGroup
+
+	startSoundLevelStorePublisherWithDone(&wg, doneChan, store, settings, soundLevelChan)
+
+	end := time.Now().Add(-time.Minute)
+	soundLevelChan <- testSoundLevelData("mic", end, -40)
+	// Invalid measurements are not stored
+	invalid := testSoundLevelData("mic", end, -40)
+	invalid.OctaveBands = nil
+	soundLevelChan <- invalid
+
+	require.Eventually(t, func() bool { return len(soundLevelChan) == 0 }, time.Second, 10*time.Millisecond)
+	close(doneChan)
+	wg.Wait()
+
+	// The partial period is stored on shutdown
+	saved := store.savedLevels()
+	require.Len(t, saved, 1)
+	assert.Equal(t, 10, saved[0].Samples)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	require.Len(t, store.cutoffs, 1)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), store.cutoffs[0], time.Minute)
+}
+
+func TestSoundLevelFanOut(t *testing.T) {
+	t.Parallel()
+
+	soundLevelChan := make(chan myaudio.SoundLevelData, 10)
+	outputs := []chan myaudio.SoundLevelData{
+		make(chan myaudio.SoundLevelData, 10),
+		make(chan myaudio.SoundLevelData, 1),
+	}
+	doneChan := make(chan struct{})
+	var wg sync.WaitGroup
+
+	startSoundLevelFanOut(&wg, doneChan, soundLevelChan, outputs)
+
+	for i := range 3 {
+		soundLevelChan <- testSoundLevelData("mic", time.Now().Add(time.Duration(i)*time.Second), -40)
+	}
+	require.Eventually(t, func() bool { return len(outputs[0]) == 3 }, time.Second, 10*time.Millisecond)
+
+	// A full publisher channel drops measurements without blocking the others
+	assert.Len(t, outputs[1], 1)
+
+	close(doneChan)
+	wg.Wait()
+}
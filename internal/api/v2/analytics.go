@@ -14,6 +14,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/calibration"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 )
@@ -104,6 +105,7 @@ func (c *Controller) initAnalyticsRoutes() {
 	speciesGroup.GET("/summary", c.GetSpeciesSummary)
 	speciesGroup.GET("/detections/new", c.GetNewSpeciesDetections) // Renamed endpoint
 	speciesGroup.GET("/thumbnails", c.GetSpeciesThumbnails)        // Batch thumbnail endpoint
+	speciesGroup.GET("/thresholds", c.GetThresholdSuggestions)     // Threshold suggestions from reviews
 
 	// Time analytics routes (can be implemented later)
 	timeGroup := analyticsGroup.Group("/time")
@@ -1447,3 +1449,65 @@ func (c *Controller) GetSpeciesThumbnails(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, result)
 }
+
+// ThresholdSuggestionsResponse contains per-species threshold suggestions
+type ThresholdSuggestionsResponse struct {
+	MinReviews      int                      `json:"min_reviews"`
+	TargetPrecision float64                  `json:"target_precision"`
+	Count           int                      `json:"count"`
+	Species         []calibration.Suggestion `json:"species"`
+}
+
+// GetThresholdSuggestions handles GET /api/v2/analytics/species/thresholds
+// Computes precision and recall at candidate thresholds from reviewed detections
+// and suggests a threshold for each species with enough reviews
+func (c *Controller) GetThresholdSuggestions(ctx echo.Context) error {
+	opts := calibration.SuggestOptions{
+		MinReviews:      calibration.DefaultSuggestMinReviews,
+		TargetPrecision: calibration.DefaultSuggestTargetPrecision,
+	}
+
+	if minReviewsStr := ctx.QueryParam("min_reviews"); minReviewsStr != "" {
+		minReviews, err := strconv.Atoi(minReviewsStr)
+		if err != nil || minReviews < 1 {
+			return c.HandleError(ctx, err, "min_reviews must be a positive integer", http.StatusBadRequest)
+		}
+		opts.MinReviews = minReviews
+	}
+
+	if precisionStr := ctx.QueryParam("precision"); precisionStr != "" {
+		precision, err := strconv.ParseFloat(precisionStr, 64)
+		if err != nil || precision <= 0 || precision > 1 {
+			return c.HandleError(ctx, err, "precision must be a number between 0 and 1", http.StatusBadRequest)
+		}
+		opts.TargetPrecision = precision
+	}
+
+	opts.Source = ctx.QueryParam("source")
+	opts.ChangedOnly = ctx.QueryParam("changed_only") == "true"
+
+	reviewed, err := c.DS.GetReviewedDetections(ctx.QueryParam("species"))
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get reviewed detections", http.StatusInternalServerError)
+	}
+
+	suggestions := calibration.SuggestThresholds(c.Settings, reviewed, opts)
+
+	if c.apiLogger != nil {
+		c.apiLogger.Info("Threshold suggestions computed",
+			"reviews", len(reviewed),
+			"species", len(suggestions),
+			"min_reviews", opts.MinReviews,
+			"precision", opts.TargetPrecision,
+			"ip", ctx.RealIP(),
+			"path", ctx.Request().URL.Path,
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, ThresholdSuggestionsResponse{
+		MinReviews:      opts.MinReviews,
+		TargetPrecision: opts.TargetPrecision,
+		Count:           len(suggestions),
+		Species:         suggestions,
+	})
+}
@@ -1213,3 +1213,101 @@ func TestGetDailySpeciesSummary_DatabaseError(t *testing.T) {
 	assert.Equal(t, "Failed to get daily species data", errorResponse["message"])
 	assert.InDelta(t, http.StatusInternalServerError, errorResponse["code"], 0.01)
 }
+
+// newThresholdTestReviews returns reviewed detections of a species with the given verdict
+func newThresholdTestReviews(scientificName, commonName, verified string, confidences ...float64) []datastore.ReviewedDetection {
+	reviews := make([]datastore.ReviewedDetection, 0, len(confidences))
+	for _, confidence := range confidences {
+		reviews = append(reviews, datastore.ReviewedDetection{
+			ScientificName: scientificName,
+			CommonName:     commonName,
+			Confidence:     confidence,
+			Verified:       verified,
+		})
+	}
+	return reviews
+}
+
+// TestGetThresholdSuggestions tests the species threshold suggestions endpoint
+func TestGetThresholdSuggestions(t *testing.T) {
+	t.Parallel()
+	e, mockDS, controller := setupAnalyticsTestEnvironment(t)
+	controller.Settings = &conf.Settings{}
+	controller.Settings.BirdNET.Threshold = 0.7
+
+	// Blackbird has false positives below 0.8, great tit is already precise
+	var reviewed []datastore.ReviewedDetection
+	reviewed = append(reviewed, newThresholdTestReviews("Turdus merula", "Eurasian Blackbird", "false_positive", 0.71, 0.74, 0.78, 0.82)...)
+	reviewed = append(reviewed, newThresholdTestReviews("Turdus merula", "Eurasian Blackbird", "correct", 0.8, 0.86, 0.88, 0.9, 0.93, 0.95, 0.97, 0.99)...)
+	reviewed = append(reviewed, newThresholdTestReviews("Parus major", "Great Tit", "correct", 0.76, 0.8, 0.85, 0.9, 0.95)...)
+	mockDS.On("GetReviewedDetections", "").Return(reviewed, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/analytics/species/thresholds?"+query, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v2/analytics/species/thresholds")
+		require.NoError(t, controller.GetThresholdSuggestions(c))
+		return rec
+	}
+
+	rec := get("min_reviews=5&precision=0.85")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response ThresholdSuggestionsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 5, response.MinReviews)
+	assert.InDelta(t, 0.85, response.TargetPrecision, 0.0001)
+	require.Equal(t, 2, response.Count)
+	assert.Equal(t, "Turdus merula", response.Species[0].ScientificName, "sorted by false positives")
+	assert.InDelta(t, 0.7, response.Species[0].CurrentThreshold, 0.0001)
+	assert.InDelta(t, 0.8, response.Species[0].SuggestedThreshold, 0.0001)
+	assert.Equal(t, "Parus major", response.Species[1].ScientificName)
+
+	// Only species whose suggested threshold differs from the current one
+	rec = get("min_reviews=5&precision=0.85&changed_only=true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response = ThresholdSuggestionsResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "Turdus merula", response.Species[0].ScientificName)
+
+	// Species below the default minimum number of reviews are left out
+	rec = get("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response = ThresholdSuggestionsResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Zero(t, response.Count)
+
+	mockDS.AssertExpectations(t)
+}
+
+// TestGetThresholdSuggestionsErrors tests invalid parameters and database errors of the threshold suggestions endpoint
+func TestGetThresholdSuggestionsErrors(t *testing.T) {
+	t.Parallel()
+	e, mockDS, controller := setupAnalyticsTestEnvironment(t)
+	controller.Settings = &conf.Settings{}
+	mockDS.On("GetReviewedDetections", "Turdus merula").Return(nil, errors.New("database connection error"))
+
+	testCases := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "Invalid min reviews", query: "min_reviews=abc", wantStatus: http.StatusBadRequest},
+		{name: "Zero min reviews", query: "min_reviews=0", wantStatus: http.StatusBadRequest},
+		{name: "Precision above one", query: "precision=1.5", wantStatus: http.StatusBadRequest},
+		{name: "Database error", query: "species=Turdus+merula", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/analytics/species/thresholds?"+tc.query, http.NoBody)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			require.NoError(t, controller.GetThresholdSuggestions(c))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+		})
+	}
+
+	mockDS.AssertExpectations(t)
+}
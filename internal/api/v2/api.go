@@ -51,6 +51,7 @@ type Controller struct {
 	DisableSaveSettings bool          // Flag to disable saving settings to disk (for tests)
 	settingsMutex       sync.RWMutex // Mutex for settings operations
 	detectionCache      *cache.Cache // Cache for detection queries
+	calibrationCache    calibrationCache // Calibration curves fitted on demand
 	startTime           *time.Time
 	SFS                 *securefs.SecureFS     // Add SecureFS instance
 	apiLogger           *slog.Logger           // Structured logger for API operations
@@ -419,6 +420,7 @@ func (c *Controller) initRoutes() {
 		{"analytics routes", c.initAnalyticsRoutes},
 		{"weather routes", c.initWeatherRoutes},
 		{"system routes", c.initSystemRoutes},
+		{"job routes", c.initJobRoutes},
 		{"settings routes", c.initSettingsRoutes},
 		{"filesystem routes", c.initFileSystemRoutes},
 		{"stream routes", c.initStreamRoutes},
@@ -427,6 +429,10 @@ func (c *Controller) initRoutes() {
 		{"auth routes", c.initAuthRoutes},
 		{"media routes", c.initMediaRoutes},
 		{"range routes", c.initRangeRoutes},
+		{"calibration routes", c.initCalibrationRoutes},
+		{"review routes", c.initReviewRoutes},
+		{"sound level routes", c.initSoundLevelRoutes},
+		{"backup routes", c.initBackupRoutes},
 		{"sse routes", c.initSSERoutes},
 		{"notification routes", c.initNotificationRoutes},
 		{"support routes", c.initSupportRoutes},
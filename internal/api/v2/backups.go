@@ -0,0 +1,159 @@
+// backups.go contains API v2 endpoints for listing and restoring backups
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// BackupRestoreRequest controls a backup restore
+type BackupRestoreRequest struct {
+	Target        string `json:"target,omitempty"`        // Target to restore from, empty for any target holding the backup
+	DryRun        bool   `json:"dryRun"`                  // Download and verify the backup without replacing any files
+	RestoreConfig bool   `json:"restoreConfig,omitempty"` // Also restore the sanitized configuration file
+}
+
+// BackupRestoreResponse is the outcome of a backup restore
+type BackupRestoreResponse struct {
+	Result          *backup.RestoreResult `json:"result"`
+	RestartRequired bool                  `json:"restartRequired"` // True if the restore takes effect once BirdNET-Go is restarted
+	Message         string                `json:"message"`
+}
+
+// initBackupRoutes registers all backup related API endpoints
+func (c *Controller) initBackupRoutes() {
+	// Backups contain the full detection database, so all endpoints are protected
+	backupGroup := c.Group.Group("/backups", c.AuthMiddleware)
+	backupGroup.GET("", c.ListBackups)
+	backupGroup.POST("/:id/restore", c.RestoreBackup)
+}
+
+// getBackupManager returns the backup manager of the running processor
+func (c *Controller) getBackupManager() (*backup.Manager, error) {
+	if c.Processor == nil {
+		return nil, fmt.Errorf("backup system is not available")
+	}
+	manager, ok := c.Processor.GetBackupManager().(*backup.Manager)
+	if !ok || manager == nil {
+		return nil, fmt.Errorf("backup system is not available")
+	}
+	return manager, nil
+}
+
+// backupErrorStatus maps a backup error to an HTTP status code
+func backupErrorStatus(err error) int {
+	switch {
+	case backup.IsErrorCode(err, backup.ErrValidation):
+		return http.StatusBadRequest
+	case backup.IsErrorCode(err, backup.ErrNotFound):
+		return http.StatusNotFound
+	case backup.IsErrorCode(err, backup.ErrLocked):
+		return http.StatusConflict
+	case backup.IsErrorCode(err, backup.ErrCorruption), backup.IsErrorCode(err, backup.ErrEncryption):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListBackups returns the backups stored in the configured backup targets
+// @Summary List backups
+// @Description Returns the backups stored in all registered backup targets, newest first
+// @Tags backups
+// @Produce json
+// @Success 200 {array} backup.BackupInfo
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/backups [get]
+func (c *Controller) ListBackups(ctx echo.Context) error {
+	manager, err := c.getBackupManager()
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusServiceUnavailable)
+	}
+
+	backups, err := manager.ListBackups(ctx.Request().Context())
+	if err != nil {
+		// Backups from reachable targets are still returned
+		c.logAPIRequest(ctx, slog.LevelWarn, "Failed to list backups from some targets", "error", err.Error())
+	}
+	if backups == nil {
+		backups = []backup.BackupInfo{}
+	}
+
+	return ctx.JSON(http.StatusOK, backups)
+}
+
+// RestoreBackup restores the database from a backup
+// @Summary Restore a backup
+// @Description Downloads and verifies a backup and restores the database from it. SQLite databases are in use while BirdNET-Go runs, so they are staged and replace the database file at the next start. With dryRun only the verification is done.
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Param id path string true "Backup ID"
+// @Param request body BackupRestoreRequest false "Restore options"
+// @Success 200 {object} BackupRestoreResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/backups/{id}/restore [post]
+func (c *Controller) RestoreBackup(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if err := backup.ValidateBackupID(id); err != nil {
+		return c.HandleError(ctx, err, "Invalid backup ID", http.StatusBadRequest)
+	}
+
+	req := &BackupRestoreRequest{}
+	if ctx.Request().ContentLength != 0 {
+		if err := ctx.Bind(req); err != nil {
+			return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+		}
+	}
+
+	manager, err := c.getBackupManager()
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusServiceUnavailable)
+	}
+
+	opts := &backup.RestoreOptions{
+		Target:        req.Target,
+		RestoreConfig: req.RestoreConfig,
+		DryRun:        req.DryRun,
+		// The open SQLite database can't be replaced under the running datastore
+		Stage: true,
+	}
+	if req.RestoreConfig {
+		if opts.ConfigPath, err = conf.FindConfigFile(); err != nil {
+			return c.HandleError(ctx, err, "Configuration file not found", http.StatusInternalServerError)
+		}
+	}
+
+	result, err := manager.RestoreBackup(ctx.Request().Context(), id, opts)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to restore backup", backupErrorStatus(err))
+	}
+
+	response := BackupRestoreResponse{Result: result}
+	switch {
+	case req.DryRun:
+		response.Message = "Backup verified, no files were replaced"
+	case result.Staged:
+		response.RestartRequired = true
+		response.Message = "Backup staged, restart BirdNET-Go to replace the database with it"
+	default:
+		response.RestartRequired = req.RestoreConfig
+		response.Message = "Backup restored"
+		if req.RestoreConfig {
+			response.Message = "Backup restored, restart BirdNET-Go to use the restored configuration"
+		}
+	}
+
+	c.logAPIRequest(ctx, slog.LevelInfo, "Backup restore completed",
+		"backup_id", id, "target", result.Target, "dry_run", req.DryRun)
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+)
+
+// TestRestoreBackupValidation tests request validation of the backup restore endpoint
+func TestRestoreBackupValidation(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	testCases := []struct {
+		name           string
+		id             string
+		requestBody    string
+		expectedStatus int
+	}{
+		{name: "Path traversal ID", id: "..", requestBody: `{"dryRun": true}`, expectedStatus: http.StatusBadRequest},
+		{name: "ID with separator", id: `a\b`, requestBody: `{"dryRun": true}`, expectedStatus: http.StatusBadRequest},
+		{name: "Malformed body", id: "birdnet-20240501-120000", requestBody: `{"dryRun": "yes"}`, expectedStatus: http.StatusBadRequest},
+		{name: "No backup system", id: "birdnet-20240501-120000", requestBody: `{"dryRun": true}`, expectedStatus: http.StatusServiceUnavailable},
+		{name: "No body", id: "birdnet-20240501-120000", expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/backups/x/restore", strings.NewReader(tc.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+
+			require.NoError(t, controller.RestoreBackup(c))
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+// TestBackupErrorStatus tests mapping of backup errors to HTTP status codes
+func TestBackupErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, backupErrorStatus(backup.NewError(backup.ErrNotFound, "missing", nil)))
+	assert.Equal(t, http.StatusConflict, backupErrorStatus(backup.NewError(backup.ErrLocked, "busy", nil)))
+	assert.Equal(t, http.StatusUnprocessableEntity, backupErrorStatus(backup.NewError(backup.ErrCorruption, "bad", nil)))
+	assert.Equal(t, http.StatusUnprocessableEntity, backupErrorStatus(backup.NewError(backup.ErrEncryption, "bad key", nil)))
+	assert.Equal(t, http.StatusInternalServerError, backupErrorStatus(backup.NewError(backup.ErrIO, "io", nil)))
+}
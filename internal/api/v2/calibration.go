@@ -0,0 +1,225 @@
+// calibration.go contains API v2 endpoints for species confidence calibration
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/calibration"
+)
+
+// CalibrationCurveResponse is a calibration curve with the raw confidence
+// threshold that reaches the target probability
+type CalibrationCurveResponse struct {
+	*calibration.Curve
+	Target    float64  `json:"target"`
+	Threshold *float64 `json:"threshold,omitempty"` // Nil if the curve never reaches the target
+}
+
+// CalibrationListResponse lists the calibration curves of all calibrated species
+type CalibrationListResponse struct {
+	Enabled     bool                       `json:"enabled"`
+	Method      string                     `json:"method"`
+	Target      float64                    `json:"target"`
+	MinSamples  int                        `json:"minSamples"`
+	LastUpdated time.Time                  `json:"lastUpdated"`
+	Curves      []CalibrationCurveResponse `json:"curves"`
+	Count       int                        `json:"count"`
+}
+
+// calibrationCache holds the calibrator fitted on demand while calibration is
+// disabled, so the public endpoints don't refit the curves on every request.
+// It is invalidated when reviews change.
+type calibrationCache struct {
+	mu         sync.Mutex
+	calibrator *calibration.Calibrator
+	method     string // Method the curves were fitted with
+	minSamples int    // Minimum samples the curves were fitted with
+}
+
+// initCalibrationRoutes sets up the confidence calibration routes
+func (c *Controller) initCalibrationRoutes() {
+	calibrationGroup := c.Group.Group("/calibration")
+	calibrationGroup.GET("", c.GetCalibrationCurves)
+	calibrationGroup.GET("/:species", c.GetCalibrationCurve)
+	calibrationGroup.POST("/refresh", c.RefreshCalibration, c.getEffectiveAuthMiddleware())
+}
+
+// calibrator returns the calibrator of the running processor. When
+// calibration is disabled a calibrator is fitted on demand so the curves can
+// be inspected before enabling calibrated thresholds. The fitted calibrator is
+// cached until reviews or the calibration settings change.
+func (c *Controller) calibrator() (*calibration.Calibrator, error) {
+	if c.Processor != nil {
+		if calibrator := c.Processor.GetCalibrator(); calibrator != nil {
+			return calibrator, nil
+		}
+	}
+
+	if c.DS == nil {
+		return nil, fmt.Errorf("datastore not available")
+	}
+
+	settings := c.Settings.Realtime.Calibration
+	cache := &c.calibrationCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.calibrator != nil && cache.method == settings.Method && cache.minSamples == settings.MinSamples {
+		return cache.calibrator, nil
+	}
+
+	calibrator := calibration.New(c.DS, settings.Method, settings.MinSamples)
+	if err := calibrator.Refresh(); err != nil {
+		return nil, err
+	}
+	cache.calibrator = calibrator
+	cache.method = settings.Method
+	cache.minSamples = settings.MinSamples
+	return calibrator, nil
+}
+
+// invalidateCalibration discards the calibration curves fitted on demand,
+// they are refitted on the next request
+func (c *Controller) invalidateCalibration() {
+	c.calibrationCache.mu.Lock()
+	defer c.calibrationCache.mu.Unlock()
+	c.calibrationCache.calibrator = nil
+}
+
+// calibrationTarget returns the target probability from the query or settings
+func (c *Controller) calibrationTarget(ctx echo.Context) (float64, error) {
+	target := c.Settings.Realtime.Calibration.Target
+	if targetStr := ctx.QueryParam("target"); targetStr != "" {
+		parsed, err := strconv.ParseFloat(targetStr, 64)
+		if err != nil || parsed <= 0 || parsed >= 1 {
+			return 0, echo.NewHTTPError(http.StatusBadRequest, "target must be a number between 0 and 1")
+		}
+		target = parsed
+	}
+	return target, nil
+}
+
+// newCalibrationCurveResponse builds the response for a single curve
+func newCalibrationCurveResponse(curve *calibration.Curve, target float64) CalibrationCurveResponse {
+	response := CalibrationCurveResponse{Curve: curve, Target: target}
+	if threshold, ok := curve.ThresholdFor(target); ok {
+		response.Threshold = &threshold
+	}
+	return response
+}
+
+// GetCalibrationCurves returns the calibration curves of all species
+// @Summary List species calibration curves
+// @Description Returns calibration curves fitted from reviewed detections and the thresholds reaching the target probability
+// @Tags calibration
+// @Produce json
+// @Param target query number false "Target calibrated probability, defaults to configured target"
+// @Success 200 {object} CalibrationListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/calibration [get]
+func (c *Controller) GetCalibrationCurves(ctx echo.Context) error {
+	target, err := c.calibrationTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	calibrator, err := c.calibrator()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to fit calibration curves", http.StatusInternalServerError)
+	}
+
+	settings := c.Settings.Realtime.Calibration
+	curves := calibrator.Curves()
+	response := CalibrationListResponse{
+		Enabled:     settings.Enabled,
+		Method:      settings.Method,
+		Target:      target,
+		MinSamples:  settings.MinSamples,
+		LastUpdated: calibrator.LastUpdated(),
+		Curves:      make([]CalibrationCurveResponse, 0, len(curves)),
+		Count:       len(curves),
+	}
+	for _, curve := range curves {
+		response.Curves = append(response.Curves, newCalibrationCurveResponse(curve, target))
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetCalibrationCurve returns the calibration curve of a single species
+// @Summary Get species calibration curve
+// @Description Returns the calibration curve of a species by common or scientific name
+// @Tags calibration
+// @Produce json
+// @Param species path string true "Common or scientific name"
+// @Param target query number false "Target calibrated probability, defaults to configured target"
+// @Success 200 {object} CalibrationCurveResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/calibration/{species} [get]
+func (c *Controller) GetCalibrationCurve(ctx echo.Context) error {
+	species := ctx.Param("species")
+	if species == "" {
+		return c.HandleError(ctx, nil, "Species is required", http.StatusBadRequest)
+	}
+
+	target, err := c.calibrationTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	calibrator, err := c.calibrator()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to fit calibration curves", http.StatusInternalServerError)
+	}
+
+	curve, ok := calibrator.Curve(species)
+	if !ok {
+		return c.HandleError(ctx, nil, "No calibration curve for species, not enough reviewed detections", http.StatusNotFound)
+	}
+
+	return ctx.JSON(http.StatusOK, newCalibrationCurveResponse(curve, target))
+}
+
+// RefreshCalibration refits the calibration curves used by the processor
+// @Summary Refit calibration curves
+// @Description Refits the calibration curves from the current reviewed detections
+// @Tags calibration
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/calibration/refresh [post]
+func (c *Controller) RefreshCalibration(ctx echo.Context) error {
+	var calibrator *calibration.Calibrator
+	if c.Processor != nil {
+		calibrator = c.Processor.GetCalibrator()
+	}
+
+	if calibrator != nil {
+		if err := calibrator.Refresh(); err != nil {
+			return c.HandleError(ctx, err, "Failed to refit calibration curves", http.StatusInternalServerError)
+		}
+	} else {
+		c.invalidateCalibration()
+		var err error
+		if calibrator, err = c.calibrator(); err != nil {
+			return c.HandleError(ctx, err, "Failed to fit calibration curves", http.StatusInternalServerError)
+		}
+	}
+
+	count := len(calibrator.Curves())
+	c.logAPIRequest(ctx, slog.LevelInfo, "Calibration curves refitted", "species_count", count)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"message":     "Calibration curves refitted successfully",
+		"count":       count,
+		"lastUpdated": calibrator.LastUpdated(),
+	})
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/calibration"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// newCalibrationTestReviews returns reviewed detections of a species, false
+// positives at low confidence and correct detections at high confidence
+func newCalibrationTestReviews() []datastore.ReviewedDetection {
+	var reviewed []datastore.ReviewedDetection
+	for i, confidence := range []float64{0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 0.95} {
+		verified := "correct"
+		if i < 3 {
+			verified = "false_positive"
+		}
+		reviewed = append(reviewed, datastore.ReviewedDetection{
+			ScientificName: "Turdus merula",
+			CommonName:     "Eurasian Blackbird",
+			Confidence:     confidence,
+			Verified:       verified,
+		})
+	}
+	return reviewed
+}
+
+// setupCalibrationTestEnvironment creates a controller fitting curves on demand
+func setupCalibrationTestEnvironment(t *testing.T) (*echo.Echo, *MockDataStore, *Controller) {
+	t.Helper()
+	e, mockDS, controller := setupTestEnvironment(t)
+	controller.Settings.Realtime.Calibration.Method = calibration.MethodLogistic
+	controller.Settings.Realtime.Calibration.MinSamples = 5
+	controller.Settings.Realtime.Calibration.Target = 0.9
+	return e, mockDS, controller
+}
+
+// TestGetCalibrationCurves tests listing the calibration curves and caching of the fitted curves
+func TestGetCalibrationCurves(t *testing.T) {
+	e, mockDS, controller := setupCalibrationTestEnvironment(t)
+	mockDS.On("GetReviewedDetections", "").Return(newCalibrationTestReviews(), nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/calibration?"+query, http.NoBody)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.GetCalibrationCurves(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := get("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response CalibrationListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.False(t, response.Enabled)
+	assert.Equal(t, calibration.MethodLogistic, response.Method)
+	assert.InDelta(t, 0.9, response.Target, 0.0001)
+	require.Equal(t, 1, response.Count)
+	assert.Equal(t, "Turdus merula", response.Curves[0].ScientificName)
+	assert.Equal(t, 8, response.Curves[0].Samples)
+	require.NotNil(t, response.Curves[0].Threshold)
+
+	// The target can be overridden per request
+	rec = get("target=0.5")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	response = CalibrationListResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.InDelta(t, 0.5, response.Target, 0.0001)
+
+	// Later requests use the cached curves until reviews change
+	mockDS.AssertNumberOfCalls(t, "GetReviewedDetections", 1)
+	mockDS.On("SaveNoteReview", mock.Anything).Return(nil)
+	require.NoError(t, controller.AddReview(1, true, "alice"))
+	get("")
+	mockDS.AssertNumberOfCalls(t, "GetReviewedDetections", 2)
+
+	// Changed settings refit the curves
+	controller.Settings.Realtime.Calibration.MinSamples = 10
+	rec = get("")
+	response = CalibrationListResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Zero(t, response.Count, "not enough reviews")
+	mockDS.AssertNumberOfCalls(t, "GetReviewedDetections", 3)
+}
+
+// TestGetCalibrationCurve tests getting the calibration curve of a single species
+func TestGetCalibrationCurve(t *testing.T) {
+	e, mockDS, controller := setupCalibrationTestEnvironment(t)
+	mockDS.On("GetReviewedDetections", "").Return(newCalibrationTestReviews(), nil)
+
+	get := func(species, query string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/calibration/species?"+query, http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("species")
+		c.SetParamValues(species)
+		return rec, controller.GetCalibrationCurve(c)
+	}
+
+	rec, err := get("Eurasian Blackbird", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response CalibrationCurveResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Turdus merula", response.ScientificName)
+	assert.Equal(t, 5, response.Positives)
+
+	rec, err = get("Parus major", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	_, err = get("Turdus merula", "target=1.5")
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+// TestRefreshCalibration tests refitting the calibration curves
+func TestRefreshCalibration(t *testing.T) {
+	e, mockDS, controller := setupCalibrationTestEnvironment(t)
+	mockDS.On("GetReviewedDetections", "").Return(newCalibrationTestReviews(), nil).Once()
+	mockDS.On("GetReviewedDetections", "").Return(nil, errors.New("database locked"))
+
+	refresh := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/calibration/refresh", http.NoBody)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.RefreshCalibration(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := refresh()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"count":1`)
+
+	// A refresh always refits the curves
+	rec = refresh()
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
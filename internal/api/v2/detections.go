@@ -62,6 +62,7 @@ func (c *Controller) initDetectionRoutes() {
 	c.Group.GET("/detections", c.GetDetections)
 	c.Group.GET("/detections/:id", c.GetDetection)
 	c.Group.GET("/detections/recent", c.GetRecentDetections)
+	c.Group.GET("/detections/export", c.ExportDetections)
 	c.Group.GET("/detections/:id/time-of-day", c.GetDetectionTimeOfDay)
 
 	// Protected detection management endpoints
@@ -98,6 +99,12 @@ type DetectionResponse struct {
 	DaysThisYear       int          `json:"daysThisYear,omitempty"`       // Days since first this year
 	DaysThisSeason     int          `json:"daysThisSeason,omitempty"`     // Days since first this season
 	CurrentSeason      string       `json:"currentSeason,omitempty"`      // Current season name
+
+	WeatherAdjustment string `json:"weatherAdjustment,omitempty"` // Weather filter adjustment applied to the detection
+	HeldForReview     bool   `json:"heldForReview,omitempty"`     // True if a filter held the detection for review
+
+	NoiseLevel *float64           `json:"noiseLevel,omitempty"` // Broadband ambient noise in dB around the detection
+	NoiseBands map[string]float64 `json:"noiseBands,omitempty"` // Ambient octave band levels in dB around the detection
 }
 
 // WeatherInfo represents weather data for a detection
@@ -119,6 +126,7 @@ type DetectionRequest struct {
 	IgnoreSpecies string `json:"ignoreSpecies,omitempty"`
 	Locked        bool   `json:"locked,omitempty"`
 	LockDetection bool   `json:"lock_detection,omitempty"`
+	Reviewer      string `json:"reviewer,omitempty"`
 }
 
 // PaginatedResponse represents a paginated API response
@@ -484,6 +492,11 @@ func (c *Controller) noteToDetectionResponse(note *datastore.Note, includeWeathe
 		CommonName:     note.CommonName,
 		Confidence:     note.Confidence,
 		Locked:         note.Locked,
+
+		WeatherAdjustment: note.WeatherAdjustment,
+		HeldForReview:     note.HeldForReview,
+		NoiseLevel:        note.NoiseLevel,
+		NoiseBands:        note.NoiseBands,
 	}
 
 	// Add species tracking metadata if processor has tracker
@@ -1108,6 +1121,11 @@ func (c *Controller) ReviewDetection(ctx echo.Context) error {
 
 	// Handle verification if provided
 	if req.Verified != "" {
+		reviewer, err := validateReviewer(req.Reviewer, false)
+		if err != nil {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+
 		var verified bool
 		switch req.Verified {
 		case "correct":
@@ -1119,7 +1137,7 @@ func (c *Controller) ReviewDetection(ctx echo.Context) error {
 		}
 
 		// Save review using the datastore method for reviews
-		err = c.AddReview(note.ID, verified)
+		err = c.AddReview(note.ID, verified, reviewer)
 		if err != nil {
 			return c.HandleError(ctx, err, fmt.Sprintf("Failed to update verification: %v", err), http.StatusInternalServerError)
 		}
@@ -1289,8 +1307,9 @@ func (c *Controller) AddComment(noteID uint, commentText string) error {
 	return c.DS.SaveNoteComment(comment)
 }
 
-// AddReview creates or updates a review for a note
-func (c *Controller) AddReview(noteID uint, verified bool) error {
+// AddReview creates or updates a review for a note. The reviewer identifies
+// who gave the verdict and may be empty.
+func (c *Controller) AddReview(noteID uint, verified bool, reviewer string) error {
 	// Convert bool to string value
 	verifiedStr := map[bool]string{
 		true:  "correct",
@@ -1300,11 +1319,16 @@ func (c *Controller) AddReview(noteID uint, verified bool) error {
 	review := &datastore.NoteReview{
 		NoteID:    noteID,
 		Verified:  verifiedStr,
+		Reviewer:  reviewer,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	return c.DS.SaveNoteReview(review)
+	if err := c.DS.SaveNoteReview(review); err != nil {
+		return err
+	}
+	c.invalidateCalibration()
+	return nil
 }
 
 // AddLock creates or removes a lock for a note
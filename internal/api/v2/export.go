@@ -0,0 +1,356 @@
+// export.go contains the API v2 endpoint for bulk export of detections
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/dwca"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// Detection export formats
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatRaven  = "raven"
+	exportFormatDwCA   = "dwca"
+)
+
+// exportFlushInterval is the number of detections written between flushes of the response
+const exportFlushInterval = 200
+
+// detectionExportWriter writes detections of one export format
+type detectionExportWriter interface {
+	WriteHeader() error
+	WriteNote(note *datastore.Note) error
+	Flush() error
+	Close() error
+	Abort()
+}
+
+// parseExportFilters parses the query parameters of the detection export
+// and returns the search filters and the export format. The source is
+// resolved to the exact stored sources, so credentials in stored RTSP URLs
+// can't be guessed with partial matches.
+func parseExportFilters(ctx echo.Context, settings *conf.Settings) (*datastore.SearchFilters, string, error) {
+	format := strings.ToLower(strings.TrimSpace(ctx.QueryParam("format")))
+	switch format {
+	case "":
+		format = exportFormatCSV
+	case exportFormatCSV, exportFormatNDJSON, exportFormatRaven, exportFormatDwCA:
+	default:
+		return nil, "", fmt.Errorf("invalid format, must be %s, %s, %s or %s",
+			exportFormatCSV, exportFormatNDJSON, exportFormatRaven, exportFormatDwCA)
+	}
+
+	filters := &datastore.SearchFilters{
+		Species:       strings.TrimSpace(ctx.QueryParam("species")),
+		DateStart:     ctx.QueryParam("start_date"),
+		DateEnd:       ctx.QueryParam("end_date"),
+		ConfidenceMax: 1,
+		Sources:       settings.StoredSources(strings.TrimSpace(ctx.QueryParam("source"))),
+		Device:        strings.TrimSpace(ctx.QueryParam("device")),
+	}
+
+	if err := validateDateParam(filters.DateStart, "start_date"); err != nil {
+		return nil, "", err
+	}
+	if err := validateDateParam(filters.DateEnd, "end_date"); err != nil {
+		return nil, "", err
+	}
+	if filters.DateStart != "" && filters.DateEnd != "" && filters.DateStart > filters.DateEnd {
+		return nil, "", fmt.Errorf("start_date must not be after end_date")
+	}
+
+	var err error
+	if filters.ConfidenceMin, err = parseExportConfidence(ctx.QueryParam("min_confidence"), "min_confidence", 0); err != nil {
+		return nil, "", err
+	}
+	if filters.ConfidenceMax, err = parseExportConfidence(ctx.QueryParam("max_confidence"), "max_confidence", 1); err != nil {
+		return nil, "", err
+	}
+	if filters.ConfidenceMin > filters.ConfidenceMax {
+		return nil, "", fmt.Errorf("min_confidence must not be greater than max_confidence")
+	}
+
+	switch ctx.QueryParam("verified") {
+	case "", "any":
+	case "verified":
+		filters.VerifiedOnly = true
+	case "unverified":
+		filters.UnverifiedOnly = true
+	default:
+		return nil, "", fmt.Errorf("invalid verified value, must be any, verified or unverified")
+	}
+
+	switch ctx.QueryParam("locked") {
+	case "", "any":
+	case "locked":
+		filters.LockedOnly = true
+	case "unlocked":
+		filters.UnlockedOnly = true
+	default:
+		return nil, "", fmt.Errorf("invalid locked value, must be any, locked or unlocked")
+	}
+
+	return filters, format, nil
+}
+
+// parseExportConfidence parses a confidence limit between 0 and 1
+func parseExportConfidence(value, paramName string, defaultValue float64) (float64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	confidence, err := strconv.ParseFloat(value, 64)
+	if err != nil || confidence < 0 || confidence > 1 {
+		return 0, fmt.Errorf("invalid %s, must be a number between 0 and 1", paramName)
+	}
+	return confidence, nil
+}
+
+// ExportDetections streams detections matching the filters as a file download
+// @Summary Export detections
+// @Description Streams all detections matching the filters as CSV, newline delimited JSON, a Raven selection table or a Darwin Core Archive for GBIF. Detections are written in the order they were saved. Raven begin and end times are seconds from the start of the day of the detection. Darwin Core Archives leave out false positives and non-species labels.
+// @Tags detections
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce text/plain
+// @Produce application/zip
+// @Param format query string false "Export format: csv (default), ndjson, raven or dwca"
+// @Param species query string false "Scientific or common name, matched as a substring"
+// @Param start_date query string false "First date to include (YYYY-MM-DD)"
+// @Param end_date query string false "Last date to include (YYYY-MM-DD)"
+// @Param min_confidence query number false "Minimum confidence between 0 and 1"
+// @Param max_confidence query number false "Maximum confidence between 0 and 1"
+// @Param verified query string false "Verification status: any, verified or unverified"
+// @Param locked query string false "Lock status: any, locked or unlocked"
+// @Param source query string false "Audio source name, or RTSP URL as shown in detections"
+// @Param device query string false "Source node, matched as a substring"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/detections/export [get]
+func (c *Controller) ExportDetections(ctx echo.Context) error {
+	filters, format, err := parseExportFilters(ctx, c.Settings)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	filters.Ctx = ctx.Request().Context()
+
+	// The response is only committed when the first buffered data is flushed,
+	// so errors before any detection was written are still reported as JSON
+	resp := ctx.Response()
+	var writer detectionExportWriter
+	var contentType, extension string
+	switch format {
+	case exportFormatNDJSON:
+		writer = &ndjsonExportWriter{w: bufio.NewWriter(resp), controller: c}
+		contentType, extension = "application/x-ndjson", "ndjson"
+	case exportFormatRaven:
+		writer = &ravenExportWriter{w: bufio.NewWriter(resp)}
+		contentType, extension = "text/plain; charset=utf-8", "txt"
+	case exportFormatDwCA:
+		archive, err := dwca.NewArchiveWriter(resp, dwca.MetadataFromSettings(c.Settings))
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to create archive", http.StatusInternalServerError)
+		}
+		writer = &dwcaExportWriter{archive: archive}
+		contentType, extension = "application/zip", "zip"
+	default:
+		writer = &csvExportWriter{w: csv.NewWriter(resp), controller: c}
+		contentType, extension = "text/csv; charset=utf-8", "csv"
+	}
+
+	filename := fmt.Sprintf("birdnet-go-detections-%s.%s", time.Now().Format("20060102-150405"), extension)
+	resp.Header().Set(echo.HeaderContentType, contentType)
+	resp.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := writer.WriteHeader(); err != nil {
+		writer.Abort()
+		return c.HandleError(ctx, err, "Failed to write export", http.StatusInternalServerError)
+	}
+
+	count := 0
+	err = c.DS.ExportDetections(filters, func(note *datastore.Note) error {
+		if err := writer.WriteNote(note); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			resp.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		writer.Abort()
+		if !resp.Committed {
+			resp.Header().Del(echo.HeaderContentType)
+			resp.Header().Del("Content-Disposition")
+			return c.HandleError(ctx, err, "Failed to export detections", http.StatusInternalServerError)
+		}
+		// The status has already been sent, the truncated download is all the client gets
+		c.logAPIRequest(ctx, slog.LevelWarn, "Detection export aborted", "format", format, "exported", count, "error", err.Error())
+		return nil
+	}
+
+	if err := writer.Close(); err != nil {
+		c.logAPIRequest(ctx, slog.LevelWarn, "Detection export aborted", "format", format, "exported", count, "error", err.Error())
+		return nil
+	}
+	resp.Flush()
+	c.logAPIRequest(ctx, slog.LevelInfo, "Detections exported", "format", format, "exported", count)
+	return nil
+}
+
+// formatExportNoiseLevel formats an optional noise level, empty if not measured
+func formatExportNoiseLevel(level *float64) string {
+	if level == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*level, 'f', 2, 64)
+}
+
+// csvExportWriter writes detections as comma separated values
+type csvExportWriter struct {
+	w          *csv.Writer
+	controller *Controller
+}
+
+func (e *csvExportWriter) WriteHeader() error {
+	return e.w.Write([]string{
+		"id", "date", "time", "begin_time", "end_time", "source", "species_code",
+		"scientific_name", "common_name", "confidence", "latitude", "longitude",
+		"verified", "locked", "clip_name", "noise_level",
+	})
+}
+
+func (e *csvExportWriter) WriteNote(note *datastore.Note) error {
+	begin, end := note.DetectionTimes()
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(note.ID), 10),
+		note.Date,
+		note.Time,
+		begin.Format(time.RFC3339),
+		end.Format(time.RFC3339),
+		privacy.SanitizeRTSPUrl(note.Source),
+		note.SpeciesCode,
+		note.ScientificName,
+		note.CommonName,
+		strconv.FormatFloat(note.Confidence, 'f', 4, 64),
+		strconv.FormatFloat(note.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(note.Longitude, 'f', -1, 64),
+		e.controller.mapVerificationStatus(note.Verified),
+		strconv.FormatBool(note.Locked),
+		note.ClipName,
+		formatExportNoiseLevel(note.NoiseLevel),
+	})
+}
+
+func (e *csvExportWriter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) Close() error { return e.Flush() }
+func (e *csvExportWriter) Abort()       {}
+
+// ndjsonExportWriter writes detections as newline delimited JSON in the
+// format of the detections endpoint
+type ndjsonExportWriter struct {
+	w          *bufio.Writer
+	controller *Controller
+}
+
+func (e *ndjsonExportWriter) WriteHeader() error {
+	return nil
+}
+
+func (e *ndjsonExportWriter) WriteNote(note *datastore.Note) error {
+	// Encode appends the newline separating the records
+	return json.NewEncoder(e.w).Encode(e.controller.noteToDetectionResponse(note, false, nil))
+}
+
+func (e *ndjsonExportWriter) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *ndjsonExportWriter) Close() error { return e.Flush() }
+func (e *ndjsonExportWriter) Abort()       {}
+
+// ravenExportWriter writes detections as a Raven selection table with the
+// columns of the file analysis output
+type ravenExportWriter struct {
+	w         *bufio.Writer
+	selection int
+}
+
+func (e *ravenExportWriter) WriteHeader() error {
+	_, err := io.WriteString(e.w, "Selection\tView\tChannel\tBegin File\tBegin Time (s)\tEnd Time (s)\tLow Freq (Hz)\tHigh Freq (Hz)\tSpecies Code\tCommon Name\tConfidence\n")
+	return err
+}
+
+func (e *ravenExportWriter) WriteNote(note *datastore.Note) error {
+	begin, end := note.DetectionTimes()
+	day := time.Date(begin.Year(), begin.Month(), begin.Day(), 0, 0, 0, 0, begin.Location())
+
+	e.selection++
+	_, err := fmt.Fprintf(e.w, "%d\tSpectrogram 1\t1\t%s\t%.1f\t%.1f\t0\t15000\t%s\t%s\t%.4f\n",
+		e.selection, ravenBeginFile(note.ClipName), begin.Sub(day).Seconds(), end.Sub(day).Seconds(),
+		ravenField(note.SpeciesCode), ravenField(note.CommonName), note.Confidence)
+	return err
+}
+
+func (e *ravenExportWriter) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *ravenExportWriter) Close() error { return e.Flush() }
+func (e *ravenExportWriter) Abort()       {}
+
+// dwcaExportWriter writes detections as a Darwin Core Archive
+type dwcaExportWriter struct {
+	archive *dwca.ArchiveWriter
+}
+
+func (e *dwcaExportWriter) WriteHeader() error {
+	// The archive writes its header when it is created
+	return nil
+}
+
+func (e *dwcaExportWriter) WriteNote(note *datastore.Note) error {
+	_, err := e.archive.Add(note)
+	return err
+}
+
+func (e *dwcaExportWriter) Flush() error { return e.archive.Flush() }
+func (e *dwcaExportWriter) Close() error { return e.archive.Close() }
+func (e *dwcaExportWriter) Abort()       { e.archive.Abort() }
+
+// ravenBeginFile returns the file name of a detection clip, empty if the detection has no clip
+func ravenBeginFile(clipName string) string {
+	if clipName == "" {
+		return ""
+	}
+	return ravenField(filepath.Base(clipName))
+}
+
+// ravenField replaces the tab and newline separators of a selection table in a value
+func ravenField(value string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+}
@@ -0,0 +1,318 @@
+// jobs.go contains API v2 endpoints for the backlog of the integration job queue
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// JobResponse is a job of the job queue
+type JobResponse struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"maxAttempts"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	NextRetryAt time.Time  `json:"nextRetryAt"`
+	FailedAt    *time.Time `json:"failedAt,omitempty"` // set for dead letters
+	LastError   string     `json:"lastError,omitempty"`
+	Persistent  bool       `json:"persistent"` // true if the job is kept in the job journal over restarts
+}
+
+// DeadLetterResponse is a failed job with a summary of the detection it acts on
+type DeadLetterResponse struct {
+	JobResponse
+	Summary *jobqueue.JobSummary `json:"summary,omitempty"`
+}
+
+// DeadLetterBulkRequest selects dead letters for a bulk replay or discard,
+// all dead letters are selected if no IDs are given
+type DeadLetterBulkRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// JobListResponse lists jobs of the job queue
+type JobListResponse struct {
+	Jobs  []JobResponse `json:"jobs"`
+	Count int           `json:"count"`
+}
+
+// initJobRoutes sets up the job queue backlog routes
+func (c *Controller) initJobRoutes() {
+	jobsGroup := c.Group.Group("/system/jobs", c.getEffectiveAuthMiddleware())
+	jobsGroup.GET("/pending", c.GetPendingJobs)
+	jobsGroup.POST("/retry", c.RetryFailedJobs)
+
+	jobsGroup.GET("/failed", c.GetDeadLetters)
+	jobsGroup.POST("/failed/replay", c.ReplayDeadLetters)
+	jobsGroup.POST("/failed/discard", c.DiscardDeadLetters)
+	jobsGroup.GET("/failed/:id", c.GetDeadLetter)
+	jobsGroup.POST("/failed/:id/replay", c.ReplayDeadLetter)
+	jobsGroup.DELETE("/failed/:id", c.DiscardDeadLetter)
+}
+
+// jobQueue returns the job queue of the running processor
+func (c *Controller) jobQueue() (*jobqueue.JobQueue, error) {
+	if c.Processor == nil || c.Processor.JobQueue == nil {
+		return nil, fmt.Errorf("job queue not available")
+	}
+	return c.Processor.JobQueue, nil
+}
+
+// newJobResponse converts a job to its response, error messages are scrubbed
+// of URLs and credentials
+func newJobResponse(job *jobqueue.Job) JobResponse {
+	response := JobResponse{
+		ID:          job.ID,
+		Description: job.Action.GetDescription(),
+		Status:      job.Status.String(),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		CreatedAt:   job.CreatedAt,
+		NextRetryAt: job.NextRetryAt,
+		Persistent:  job.Journaled(),
+	}
+	if !job.FailedAt.IsZero() {
+		failedAt := job.FailedAt
+		response.FailedAt = &failedAt
+	}
+	if job.LastError != nil {
+		response.LastError = privacy.ScrubMessage(job.LastError.Error())
+	}
+	return response
+}
+
+// newJobListResponse converts jobs to a list response
+func newJobListResponse(jobs []*jobqueue.Job) JobListResponse {
+	response := JobListResponse{Jobs: make([]JobResponse, 0, len(jobs)), Count: len(jobs)}
+	for _, job := range jobs {
+		response.Jobs = append(response.Jobs, newJobResponse(job))
+	}
+	return response
+}
+
+// deadLetterErrorStatus maps a dead-letter error to an HTTP status code
+func deadLetterErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, jobqueue.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, jobqueue.ErrQueueFull):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// bindDeadLetterBulkRequest reads the optional bulk request body
+func bindDeadLetterBulkRequest(ctx echo.Context) (*DeadLetterBulkRequest, error) {
+	req := &DeadLetterBulkRequest{}
+	if ctx.Request().ContentLength != 0 {
+		if err := ctx.Bind(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// GetPendingJobs returns the backlog of the job queue
+// @Summary List pending jobs
+// @Description Returns the jobs waiting for their first attempt or a retry, in the order they are processed
+// @Tags system
+// @Produce json
+// @Success 200 {object} JobListResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/pending [get]
+func (c *Controller) GetPendingJobs(ctx echo.Context) error {
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	return ctx.JSON(http.StatusOK, newJobListResponse(queue.GetPendingJobs()))
+}
+
+// RetryFailedJobs re-queues the jobs that failed after exhausting their retries
+// @Summary Retry all failed jobs
+// @Description Re-queues failed jobs with a fresh set of attempts, up to the capacity of the queue
+// @Tags system
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/retry [post]
+func (c *Controller) RetryFailedJobs(ctx echo.Context) error {
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	count := queue.RetryFailedJobs()
+	c.logAPIRequest(ctx, slog.LevelInfo, "Failed jobs re-queued", "count", count)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%d failed jobs re-queued", count),
+		"count":   count,
+	})
+}
+
+// GetDeadLetters returns the jobs that failed after exhausting their retries
+// @Summary List failed jobs
+// @Description Returns the dead letters, jobs that failed after exhausting their retries, oldest first
+// @Tags system
+// @Produce json
+// @Success 200 {object} JobListResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/failed [get]
+func (c *Controller) GetDeadLetters(ctx echo.Context) error {
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	return ctx.JSON(http.StatusOK, newJobListResponse(queue.GetDeadLetters()))
+}
+
+// GetDeadLetter returns a failed job with its payload
+// @Summary Inspect a failed job
+// @Description Returns a dead letter with a summary of the detection it acts on
+// @Tags system
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} DeadLetterResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/failed/{id} [get]
+func (c *Controller) GetDeadLetter(ctx echo.Context) error {
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	job, err := queue.GetDeadLetter(ctx.Param("id"))
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed job not found", deadLetterErrorStatus(err))
+	}
+	return ctx.JSON(http.StatusOK, DeadLetterResponse{JobResponse: newJobResponse(job), Summary: job.Summary()})
+}
+
+// ReplayDeadLetter re-queues a failed job
+// @Summary Replay a failed job
+// @Description Re-queues a dead letter with a fresh set of attempts
+// @Tags system
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Job queue is full"
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/failed/{id}/replay [post]
+func (c *Controller) ReplayDeadLetter(ctx echo.Context) error {
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	id := ctx.Param("id")
+	if err := queue.ReplayDeadLetter(id); err != nil {
+		return c.HandleError(ctx, err, "Failed to replay job", deadLetterErrorStatus(err))
+	}
+	c.logAPIRequest(ctx, slog.LevelInfo, "Failed job replayed", "job_id", id)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Job %s re-queued", id),
+	})
+}
+
+// DiscardDeadLetter removes a failed job
+// @Summary Discard a failed job
+// @Description Removes a dead letter without running it again
+// @Tags system
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/failed/{id} [delete]
+func (c *Controller) DiscardDeadLetter(ctx echo.Context) error {
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	id := ctx.Param("id")
+	if err := queue.DiscardDeadLetter(id); err != nil {
+		return c.HandleError(ctx, err, "Failed to discard job", deadLetterErrorStatus(err))
+	}
+	c.logAPIRequest(ctx, slog.LevelInfo, "Failed job discarded", "job_id", id)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Job %s discarded", id),
+	})
+}
+
+// ReplayDeadLetters re-queues failed jobs in bulk
+// @Summary Replay failed jobs
+// @Description Re-queues the selected dead letters, or all of them if no IDs are given, up to the capacity of the queue
+// @Tags system
+// @Accept json
+// @Produce json
+// @Param request body DeadLetterBulkRequest false "Jobs to replay"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/failed/replay [post]
+func (c *Controller) ReplayDeadLetters(ctx echo.Context) error {
+	req, err := bindDeadLetterBulkRequest(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	count := queue.ReplayDeadLetters(req.IDs)
+	c.logAPIRequest(ctx, slog.LevelInfo, "Failed jobs replayed", "count", count)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%d failed jobs re-queued", count),
+		"count":   count,
+	})
+}
+
+// DiscardDeadLetters removes failed jobs in bulk
+// @Summary Discard failed jobs
+// @Description Removes the selected dead letters, or all of them if no IDs are given
+// @Tags system
+// @Accept json
+// @Produce json
+// @Param request body DeadLetterBulkRequest false "Jobs to discard"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v2/system/jobs/failed/discard [post]
+func (c *Controller) DiscardDeadLetters(ctx echo.Context) error {
+	req, err := bindDeadLetterBulkRequest(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	queue, err := c.jobQueue()
+	if err != nil {
+		return c.HandleError(ctx, err, "Job queue not available", http.StatusServiceUnavailable)
+	}
+
+	count := queue.DiscardDeadLetters(req.IDs)
+	c.logAPIRequest(ctx, slog.LevelInfo, "Failed jobs discarded", "count", count)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%d failed jobs discarded", count),
+		"count":   count,
+	})
+}
@@ -0,0 +1,559 @@
+// review.go contains API v2 endpoints for the detection review queue
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// Review queue limits
+const (
+	defaultReviewQueueLimit = 50
+	maxReviewQueueLimit     = 500
+	defaultReviewClaimCount = 20
+	maxReviewClaimCount     = 100
+	defaultReviewClaimTTL   = 30 * time.Minute
+	maxReviewClaimTTL       = 24 * time.Hour
+	maxReviewVerdictBatch   = 500
+	maxReviewerLength       = 100
+)
+
+// ReviewQueueItemResponse is an unreviewed detection in the review queue
+type ReviewQueueItemResponse struct {
+	ID             uint       `json:"id"`
+	Date           string     `json:"date"`
+	Time           string     `json:"time"`
+	Source         string     `json:"source"`
+	ScientificName string     `json:"scientificName"`
+	CommonName     string     `json:"commonName"`
+	Confidence     float64    `json:"confidence"`
+	ClipName       string     `json:"clipName,omitempty"`
+	HeldForReview  bool       `json:"heldForReview"`            // True if a filter held the detection for review
+	SpeciesCount   int64      `json:"speciesCount"`             // Detections of the species, lower is rarer
+	ClaimedBy      string     `json:"claimedBy,omitempty"`      // Reviewer holding an active claim
+	ClaimExpiresAt *time.Time `json:"claimExpiresAt,omitempty"` // Expiry of the active claim
+}
+
+// ReviewQueueResponse is a page of the review queue
+type ReviewQueueResponse struct {
+	Items  []ReviewQueueItemResponse `json:"items"`
+	Total  int64                     `json:"total"`
+	Limit  int                       `json:"limit"`
+	Offset int                       `json:"offset"`
+}
+
+// ReviewClaimRequest claims a batch of detections from the review queue
+type ReviewClaimRequest struct {
+	Reviewer      string  `json:"reviewer"`
+	Count         int     `json:"count,omitempty"`
+	Species       string  `json:"species,omitempty"`
+	MinConfidence float64 `json:"minConfidence,omitempty"`
+	MaxConfidence float64 `json:"maxConfidence,omitempty"`
+	Sort          string  `json:"sort,omitempty"`
+	TTLMinutes    int     `json:"ttlMinutes,omitempty"`
+	Held          bool    `json:"held,omitempty"` // Only claim detections held for review by a filter
+}
+
+// ReviewReleaseRequest releases claims held by a reviewer
+type ReviewReleaseRequest struct {
+	Reviewer string `json:"reviewer"`
+	IDs      []uint `json:"ids,omitempty"` // Empty to release all claims of the reviewer
+}
+
+// ReviewVerdictRequest is a verdict for a single detection
+type ReviewVerdictRequest struct {
+	ID       uint   `json:"id"`
+	Verified string `json:"verified"` // "correct" or "false_positive"
+}
+
+// ReviewVerdictsRequest applies a batch of verdicts by a reviewer
+type ReviewVerdictsRequest struct {
+	Reviewer string                 `json:"reviewer"`
+	Verdicts []ReviewVerdictRequest `json:"verdicts"`
+}
+
+// ReviewVerdictResultResponse is the outcome of a single verdict
+type ReviewVerdictResultResponse struct {
+	ID        uint   `json:"id"`
+	Status    string `json:"status"`
+	ClaimedBy string `json:"claimedBy,omitempty"`
+}
+
+// ReviewVerdictsResponse is the outcome of a batch of verdicts
+type ReviewVerdictsResponse struct {
+	Results []ReviewVerdictResultResponse `json:"results"`
+	Applied int                           `json:"applied"`
+	Skipped int                           `json:"skipped"`
+}
+
+// ReviewHistoryEntryResponse is a single verdict given for a detection
+type ReviewHistoryEntryResponse struct {
+	Verified  string    `json:"verified"`
+	Reviewer  string    `json:"reviewer,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ReviewerProgressResponse summarizes the verdicts of a single reviewer
+type ReviewerProgressResponse struct {
+	Reviewer      string     `json:"reviewer"`
+	Verdicts      int64      `json:"verdicts"`
+	Correct       int64      `json:"correct"`
+	FalsePositive int64      `json:"falsePositive"`
+	Detections    int64      `json:"detections"`
+	ActiveClaims  int64      `json:"activeClaims"`
+	LastReviewAt  *time.Time `json:"lastReviewAt,omitempty"`
+}
+
+// ReviewProgressResponse summarizes the review queue and reviewer progress
+type ReviewProgressResponse struct {
+	Unreviewed int64                      `json:"unreviewed"`
+	Claimed    int64                      `json:"claimed"`
+	Since      string                     `json:"since,omitempty"`
+	Reviewers  []ReviewerProgressResponse `json:"reviewers"`
+}
+
+// initReviewRoutes sets up the review queue routes
+func (c *Controller) initReviewRoutes() {
+	c.Group.GET("/review/queue", c.GetReviewQueue)
+	c.Group.GET("/review/progress", c.GetReviewProgress)
+	c.Group.GET("/review/history/:id", c.GetReviewHistory)
+
+	// Protected review management endpoints
+	reviewGroup := c.Group.Group("/review", c.AuthMiddleware)
+	reviewGroup.POST("/claim", c.ClaimReviews)
+	reviewGroup.DELETE("/claims", c.ReleaseReviewClaims)
+	reviewGroup.POST("/verdicts", c.ApplyReviewVerdicts)
+}
+
+// validateReviewer checks a reviewer identifier
+func validateReviewer(reviewer string, required bool) (string, error) {
+	reviewer = strings.TrimSpace(reviewer)
+	if reviewer == "" {
+		if required {
+			return "", fmt.Errorf("reviewer is required")
+		}
+		return "", nil
+	}
+	if len(reviewer) > maxReviewerLength {
+		return "", fmt.Errorf("reviewer must be at most %d characters", maxReviewerLength)
+	}
+	return reviewer, nil
+}
+
+// validateReviewSort checks a review queue sort order, empty selects the default
+func validateReviewSort(sort string) error {
+	switch sort {
+	case "", datastore.ReviewSortPriority, datastore.ReviewSortConfidence, datastore.ReviewSortNewest:
+		return nil
+	default:
+		return fmt.Errorf("sort must be %s, %s or %s", datastore.ReviewSortPriority, datastore.ReviewSortConfidence, datastore.ReviewSortNewest)
+	}
+}
+
+// validateConfidenceRange checks optional confidence bounds
+func validateConfidenceRange(minConfidence, maxConfidence float64) error {
+	if minConfidence < 0 || minConfidence > 1 || maxConfidence < 0 || maxConfidence > 1 {
+		return fmt.Errorf("confidence must be between 0 and 1")
+	}
+	if maxConfidence > 0 && minConfidence > maxConfidence {
+		return fmt.Errorf("min_confidence must not be greater than max_confidence")
+	}
+	return nil
+}
+
+// parseReviewQueueQuery parses the review queue query parameters
+func parseReviewQueueQuery(ctx echo.Context) (*datastore.ReviewQueueQuery, error) {
+	reviewer, err := validateReviewer(ctx.QueryParam("reviewer"), false)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &datastore.ReviewQueueQuery{
+		Reviewer:  reviewer,
+		Species:   strings.TrimSpace(ctx.QueryParam("species")),
+		StartDate: ctx.QueryParam("start_date"),
+		EndDate:   ctx.QueryParam("end_date"),
+		Sort:      ctx.QueryParam("sort"),
+		Limit:     defaultReviewQueueLimit,
+	}
+
+	if err := validateReviewSort(query.Sort); err != nil {
+		return nil, err
+	}
+
+	for _, param := range []struct {
+		name  string
+		value string
+	}{{"start_date", query.StartDate}, {"end_date", query.EndDate}} {
+		if param.value != "" {
+			if err := validateDateParam(param.value, param.name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if minStr := ctx.QueryParam("min_confidence"); minStr != "" {
+		if query.MinConfidence, err = strconv.ParseFloat(minStr, 64); err != nil {
+			return nil, fmt.Errorf("invalid min_confidence: %w", err)
+		}
+	}
+	if maxStr := ctx.QueryParam("max_confidence"); maxStr != "" {
+		if query.MaxConfidence, err = strconv.ParseFloat(maxStr, 64); err != nil {
+			return nil, fmt.Errorf("invalid max_confidence: %w", err)
+		}
+	}
+	if err := validateConfidenceRange(query.MinConfidence, query.MaxConfidence); err != nil {
+		return nil, err
+	}
+
+	if claimedStr := ctx.QueryParam("claimed"); claimedStr != "" {
+		if query.ClaimedOnly, err = strconv.ParseBool(claimedStr); err != nil {
+			return nil, fmt.Errorf("invalid claimed: %w", err)
+		}
+		if query.ClaimedOnly && query.Reviewer == "" {
+			return nil, fmt.Errorf("reviewer is required to list claimed detections")
+		}
+	}
+
+	if heldStr := ctx.QueryParam("held"); heldStr != "" {
+		if query.HeldOnly, err = strconv.ParseBool(heldStr); err != nil {
+			return nil, fmt.Errorf("invalid held: %w", err)
+		}
+	}
+
+	if limitStr := ctx.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxReviewQueueLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxReviewQueueLimit)
+		}
+		query.Limit = limit
+	}
+	if offsetStr := ctx.QueryParam("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("offset must be a non-negative integer")
+		}
+		query.Offset = offset
+	}
+
+	return query, nil
+}
+
+// newReviewQueueItemResponses converts review queue items to responses
+func newReviewQueueItemResponses(items []datastore.ReviewQueueItem) []ReviewQueueItemResponse {
+	responses := make([]ReviewQueueItemResponse, 0, len(items))
+	for i := range items {
+		item := &items[i]
+		responses = append(responses, ReviewQueueItemResponse{
+			ID:             item.ID,
+			Date:           item.Date,
+			Time:           item.Time,
+			Source:         privacy.SanitizeRTSPUrl(item.Source),
+			ScientificName: item.ScientificName,
+			CommonName:     item.CommonName,
+			Confidence:     item.Confidence,
+			ClipName:       item.ClipName,
+			HeldForReview:  item.HeldForReview,
+			SpeciesCount:   item.SpeciesCount,
+			ClaimedBy:      item.ClaimedBy,
+			ClaimExpiresAt: item.ClaimExpiresAt,
+		})
+	}
+	return responses
+}
+
+// GetReviewQueue returns unreviewed detections ordered for review
+// @Summary Get the review queue
+// @Description Returns unreviewed, unlocked detections not claimed by other reviewers, rarest species and lowest confidence first
+// @Tags review
+// @Produce json
+// @Param reviewer query string false "Reviewer identifier, their own claims are included"
+// @Param species query string false "Common or scientific name"
+// @Param min_confidence query number false "Minimum confidence"
+// @Param max_confidence query number false "Maximum confidence"
+// @Param start_date query string false "First date (YYYY-MM-DD)"
+// @Param end_date query string false "Last date (YYYY-MM-DD)"
+// @Param sort query string false "priority, confidence or newest"
+// @Param claimed query bool false "Only detections claimed by the reviewer"
+// @Param held query bool false "Only detections held for review by a filter"
+// @Param limit query int false "Page size, defaults to 50"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} ReviewQueueResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/review/queue [get]
+func (c *Controller) GetReviewQueue(ctx echo.Context) error {
+	query, err := parseReviewQueueQuery(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	items, total, err := c.DS.GetReviewQueue(query)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get review queue", http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, ReviewQueueResponse{
+		Items:  newReviewQueueItemResponses(items),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+}
+
+// ClaimReviews claims a batch of detections from the review queue
+// @Summary Claim detections for review
+// @Description Claims unreviewed detections for a reviewer so other reviewers do not get them, refreshing claims the reviewer already holds
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param request body ReviewClaimRequest true "Claim request"
+// @Success 200 {object} ReviewQueueResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/review/claim [post]
+func (c *Controller) ClaimReviews(ctx echo.Context) error {
+	req := &ReviewClaimRequest{}
+	if err := ctx.Bind(req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+
+	reviewer, err := validateReviewer(req.Reviewer, true)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	if err := validateReviewSort(req.Sort); err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	if err := validateConfidenceRange(req.MinConfidence, req.MaxConfidence); err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = defaultReviewClaimCount
+	}
+	if count < 1 || count > maxReviewClaimCount {
+		err := fmt.Errorf("count must be between 1 and %d", maxReviewClaimCount)
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	ttl := defaultReviewClaimTTL
+	if req.TTLMinutes != 0 {
+		ttl = time.Duration(req.TTLMinutes) * time.Minute
+	}
+	if ttl <= 0 || ttl > maxReviewClaimTTL {
+		err := fmt.Errorf("ttlMinutes must be between 1 and %d", int(maxReviewClaimTTL/time.Minute))
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	query := &datastore.ReviewQueueQuery{
+		Reviewer:      reviewer,
+		Species:       strings.TrimSpace(req.Species),
+		MinConfidence: req.MinConfidence,
+		MaxConfidence: req.MaxConfidence,
+		Sort:          req.Sort,
+		HeldOnly:      req.Held,
+		Limit:         count,
+	}
+	items, err := c.DS.ClaimReviews(query, ttl)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to claim detections", http.StatusInternalServerError)
+	}
+
+	c.logAPIRequest(ctx, slog.LevelInfo, "Detections claimed for review", "reviewer", reviewer, "count", len(items))
+	return ctx.JSON(http.StatusOK, ReviewQueueResponse{
+		Items: newReviewQueueItemResponses(items),
+		Total: int64(len(items)),
+		Limit: count,
+	})
+}
+
+// ReleaseReviewClaims releases claims held by a reviewer
+// @Summary Release review claims
+// @Description Releases the given claims of a reviewer, or all of them if no IDs are given
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param request body ReviewReleaseRequest true "Release request"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/review/claims [delete]
+func (c *Controller) ReleaseReviewClaims(ctx echo.Context) error {
+	req := &ReviewReleaseRequest{}
+	if err := ctx.Bind(req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+
+	// DELETE bodies are not always sent by clients, allow the reviewer as a query parameter
+	if req.Reviewer == "" {
+		req.Reviewer = ctx.QueryParam("reviewer")
+	}
+	reviewer, err := validateReviewer(req.Reviewer, true)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	released, err := c.DS.ReleaseReviewClaims(reviewer, req.IDs)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to release claims", http.StatusInternalServerError)
+	}
+
+	c.logAPIRequest(ctx, slog.LevelInfo, "Review claims released", "reviewer", reviewer, "count", released)
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"released": released,
+	})
+}
+
+// ApplyReviewVerdicts applies a batch of verdicts by a reviewer
+// @Summary Apply review verdicts
+// @Description Saves verdicts for a batch of detections. Locked detections and detections claimed by other reviewers are skipped.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param request body ReviewVerdictsRequest true "Verdicts"
+// @Success 200 {object} ReviewVerdictsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/review/verdicts [post]
+func (c *Controller) ApplyReviewVerdicts(ctx echo.Context) error {
+	req := &ReviewVerdictsRequest{}
+	if err := ctx.Bind(req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+
+	reviewer, err := validateReviewer(req.Reviewer, true)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	if len(req.Verdicts) == 0 {
+		return c.HandleError(ctx, fmt.Errorf("no verdicts"), "At least one verdict is required", http.StatusBadRequest)
+	}
+	if len(req.Verdicts) > maxReviewVerdictBatch {
+		err := fmt.Errorf("at most %d verdicts can be applied at once", maxReviewVerdictBatch)
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	verdicts := make([]datastore.ReviewVerdict, len(req.Verdicts))
+	for i, verdict := range req.Verdicts {
+		verdicts[i] = datastore.ReviewVerdict{NoteID: verdict.ID, Verified: verdict.Verified}
+	}
+
+	results, err := c.DS.ApplyReviewVerdicts(reviewer, verdicts)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to apply verdicts", http.StatusInternalServerError)
+	}
+	c.invalidateCalibration()
+
+	response := ReviewVerdictsResponse{Results: make([]ReviewVerdictResultResponse, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, ReviewVerdictResultResponse{
+			ID:        result.NoteID,
+			Status:    result.Status,
+			ClaimedBy: result.ClaimedBy,
+		})
+		if result.Status == datastore.ReviewVerdictApplied {
+			response.Applied++
+		} else {
+			response.Skipped++
+		}
+	}
+
+	if response.Applied > 0 {
+		c.invalidateDetectionCache()
+	}
+
+	c.logAPIRequest(ctx, slog.LevelInfo, "Review verdicts applied",
+		"reviewer", reviewer, "applied", response.Applied, "skipped", response.Skipped)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetReviewHistory returns all verdicts given for a detection
+// @Summary Get review history of a detection
+// @Description Returns every verdict given for a detection, oldest first
+// @Tags review
+// @Produce json
+// @Param id path int true "Detection ID"
+// @Success 200 {array} ReviewHistoryEntryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/review/history/{id} [get]
+func (c *Controller) GetReviewHistory(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		return c.HandleError(ctx, err, "Invalid detection ID", http.StatusBadRequest)
+	}
+
+	history, err := c.DS.GetNoteReviewHistory(uint(id))
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get review history", http.StatusInternalServerError)
+	}
+
+	response := make([]ReviewHistoryEntryResponse, 0, len(history))
+	for i := range history {
+		response = append(response, ReviewHistoryEntryResponse{
+			Verified:  history[i].Verified,
+			Reviewer:  history[i].Reviewer,
+			CreatedAt: history[i].CreatedAt,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetReviewProgress returns the review queue size and per-reviewer progress
+// @Summary Get review progress
+// @Description Returns the number of unreviewed and claimed detections and the verdicts of each reviewer
+// @Tags review
+// @Produce json
+// @Param since query string false "Only count verdicts from this date (YYYY-MM-DD)"
+// @Success 200 {object} ReviewProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/review/progress [get]
+func (c *Controller) GetReviewProgress(ctx echo.Context) error {
+	var since time.Time
+	sinceStr := ctx.QueryParam("since")
+	if sinceStr != "" {
+		if err := validateDateParam(sinceStr, "since"); err != nil {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		since, _ = time.ParseInLocation("2006-01-02", sinceStr, time.Local)
+	}
+
+	progress, err := c.DS.GetReviewProgress(since)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get review progress", http.StatusInternalServerError)
+	}
+
+	response := ReviewProgressResponse{
+		Unreviewed: progress.Unreviewed,
+		Claimed:    progress.Claimed,
+		Since:      sinceStr,
+		Reviewers:  make([]ReviewerProgressResponse, 0, len(progress.Reviewers)),
+	}
+	for i := range progress.Reviewers {
+		p := &progress.Reviewers[i]
+		response.Reviewers = append(response.Reviewers, ReviewerProgressResponse{
+			Reviewer:      p.Reviewer,
+			Verdicts:      p.Verdicts,
+			Correct:       p.Correct,
+			FalsePositive: p.FalsePositive,
+			Detections:    p.Detections,
+			ActiveClaims:  p.ActiveClaims,
+			LastReviewAt:  p.LastReviewAt,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
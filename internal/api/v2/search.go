@@ -29,17 +29,19 @@ func (c *Controller) initSearchRoutes() {
 
 // SearchRequest defines the structure of the search API request
 type SearchRequest struct {
-	Species        string  `json:"species"`
-	DateStart      string  `json:"dateStart"`
-	DateEnd        string  `json:"dateEnd"`
-	ConfidenceMin  float64 `json:"confidenceMin"`
-	ConfidenceMax  float64 `json:"confidenceMax"`
-	VerifiedStatus string  `json:"verifiedStatus"`
-	LockedStatus   string  `json:"lockedStatus"`
-	DeviceFilter   string  `json:"deviceFilter"`
-	TimeOfDay      string  `json:"timeOfDay"`
-	Page           int     `json:"page"`
-	SortBy         string  `json:"sortBy"`
+	Species        string   `json:"species"`
+	DateStart      string   `json:"dateStart"`
+	DateEnd        string   `json:"dateEnd"`
+	ConfidenceMin  float64  `json:"confidenceMin"`
+	ConfidenceMax  float64  `json:"confidenceMax"`
+	VerifiedStatus string   `json:"verifiedStatus"`
+	LockedStatus   string   `json:"lockedStatus"`
+	DeviceFilter   string   `json:"deviceFilter"`
+	TimeOfDay      string   `json:"timeOfDay"`
+	NoiseMin       *float64 `json:"noiseMin,omitempty"` // Minimum broadband noise level in dB
+	NoiseMax       *float64 `json:"noiseMax,omitempty"` // Maximum broadband noise level in dB
+	Page           int      `json:"page"`
+	SortBy         string   `json:"sortBy"`
 }
 
 // SearchResponse defines the structure of the search API response
@@ -146,6 +148,8 @@ func (c *Controller) buildSearchFilters(req *SearchRequest, ctxTimeout context.C
 		UnlockedOnly:   req.LockedStatus == "unlocked",
 		Device:         req.DeviceFilter,
 		TimeOfDay:      req.TimeOfDay,
+		NoiseMin:       req.NoiseMin,
+		NoiseMax:       req.NoiseMax,
 		Page:           req.Page,
 		PerPage:        defaultPerPage,
 		SortBy:         req.SortBy,
@@ -210,6 +214,11 @@ func (c *Controller) validateAndNormalizeSearchRequest(ctx echo.Context, req *Se
 		return err
 	}
 
+	err = c.validateSearchNoiseRange(path, ip, req)
+	if err != nil {
+		return err
+	}
+
 	err = c.validateSearchSortBy(path, ip, req)
 	if err != nil {
 		return err
@@ -287,6 +296,17 @@ func (c *Controller) validateSearchTimeOfDay(path, ip string, req *SearchRequest
 	return nil
 }
 
+// validateSearchNoiseRange validates NoiseMin and NoiseMax.
+func (c *Controller) validateSearchNoiseRange(path, ip string, req *SearchRequest) error {
+	if req.NoiseMin != nil && req.NoiseMax != nil && *req.NoiseMin > *req.NoiseMax {
+		if c.apiLogger != nil {
+			c.apiLogger.Error("Invalid noise range: noiseMin is greater than noiseMax", "noiseMin", *req.NoiseMin, "noiseMax", *req.NoiseMax, "path", path, "ip", ip)
+		}
+		return fmt.Errorf("'noiseMin' (%.1f) must be less than or equal to 'noiseMax' (%.1f)", *req.NoiseMin, *req.NoiseMax)
+	}
+	return nil
+}
+
 // validateSearchConfidenceRange validates and normalizes ConfidenceMin and ConfidenceMax.
 func (c *Controller) validateSearchConfidenceRange(path, ip string, req *SearchRequest) error {
 	if req.ConfidenceMin < 0 {
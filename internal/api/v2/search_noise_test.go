@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// TestHandleSearchNoiseFilters tests that noise level filters reach the datastore and
+// that the noise context of detections is returned
+func TestHandleSearchNoiseFilters(t *testing.T) {
+	e, mockDS, controller := setupTestEnvironment(t)
+
+	noise := -48.5
+	mockDS.On("SearchDetections", mock.MatchedBy(func(filters *datastore.SearchFilters) bool {
+		return filters.NoiseMin != nil && *filters.NoiseMin == -60 &&
+			filters.NoiseMax != nil && *filters.NoiseMax == -40
+	})).Return([]datastore.DetectionRecord{
+		{ID: "1", CommonName: "Great Tit", NoiseLevel: &noise, NoiseBands: map[string]float64{"1.0_kHz": -50}},
+	}, 1, nil)
+
+	search := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/search", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.HandleSearch(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := search(`{"noiseMin": -60, "noiseMax": -40}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response SearchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Results, 1)
+	require.NotNil(t, response.Results[0].NoiseLevel)
+	assert.InDelta(t, noise, *response.Results[0].NoiseLevel, 1e-9)
+	assert.InDelta(t, -50, response.Results[0].NoiseBands["1.0_kHz"], 1e-9)
+
+	rec = search(`{"noiseMin": -40, "noiseMax": -60}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockDS.AssertNumberOfCalls(t, "SearchDetections", 1)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -1014,6 +1015,22 @@ func (c *Controller) handleSettingsChanges(oldSettings, currentSettings *conf.Se
 		_ = c.SendToast("Updating detection intervals...", "info", 3000)
 	}
 
+	// Check action schedule settings
+	if scheduleSettingsChanged(oldSettings, currentSettings) {
+		c.Debug("Action schedule settings changed, triggering update")
+		reconfigActions = append(reconfigActions, "update_action_schedule")
+		// Send toast notification
+		_ = c.SendToast("Updating action schedules...", "info", 3000)
+	}
+
+	// Check detection filter order, other filter settings are read when filtering
+	if detectionFilterOrderChanged(oldSettings, currentSettings) {
+		c.Debug("Detection filter order changed, triggering rebuild")
+		reconfigActions = append(reconfigActions, "rebuild_detection_filters")
+		// Send toast notification
+		_ = c.SendToast("Rebuilding detection filters...", "info", 3000)
+	}
+
 	// Check MQTT settings
 	if mqttSettingsChanged(oldSettings, currentSettings) {
 		c.Debug("MQTT settings changed, triggering reconfiguration")
@@ -1116,6 +1133,11 @@ func birdnetSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		return true
 	}
 
+	// Check for changes in the model ensemble
+	if !reflect.DeepEqual(oldSettings.BirdNET.Ensemble, currentSettings.BirdNET.Ensemble) {
+		return true
+	}
+
 	return false
 }
 
@@ -1139,6 +1161,11 @@ func rangeFilterSettingsChanged(oldSettings, currentSettings *conf.Settings) boo
 		return true
 	}
 
+	// Check for changes in per-source location and species lists
+	if !reflect.DeepEqual(oldSettings.Realtime.RTSP.Sources, currentSettings.Realtime.RTSP.Sources) {
+		return true
+	}
+
 	return false
 }
 
@@ -1181,6 +1208,11 @@ func rtspSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		}
 	}
 
+	// Check for changes in named RTSP sources
+	if !reflect.DeepEqual(oldRTSP.Sources, newRTSP.Sources) {
+		return true
+	}
+
 	return false
 }
 
@@ -1189,6 +1221,38 @@ func audioDeviceSettingChanged(oldSettings, currentSettings *conf.Settings) bool
 	return oldSettings.Realtime.Audio.Source != currentSettings.Realtime.Audio.Source
 }
 
+// scheduleSettingsChanged checks if the global or any species schedule rules have changed
+func scheduleSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
+	if !reflect.DeepEqual(oldSettings.Realtime.Schedule, currentSettings.Realtime.Schedule) {
+		return true
+	}
+
+	// Compare species schedules, species without schedule rules are equal to missing species
+	allSpecies := make(map[string]bool)
+	for species := range oldSettings.Realtime.Species.Config {
+		allSpecies[species] = true
+	}
+	for species := range currentSettings.Realtime.Species.Config {
+		allSpecies[species] = true
+	}
+	for species := range allSpecies {
+		oldSchedule := oldSettings.Realtime.Species.Config[species].Schedule
+		newSchedule := currentSettings.Realtime.Species.Config[species].Schedule
+		if len(oldSchedule) == 0 && len(newSchedule) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(oldSchedule, newSchedule) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectionFilterOrderChanged checks if the detection filter order has changed
+func detectionFilterOrderChanged(oldSettings, currentSettings *conf.Settings) bool {
+	return !slices.EqualFunc(oldSettings.Realtime.Filters.Order, currentSettings.Realtime.Filters.Order, strings.EqualFold)
+}
+
 // speciesIntervalSettingsChanged checks if any species-specific interval settings have changed
 func speciesIntervalSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 	// Get the old and new species configs
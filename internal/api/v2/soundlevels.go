@@ -0,0 +1,215 @@
+// soundlevels.go contains API v2 endpoints for stored sound level history
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// Sound level history limits
+const (
+	defaultSoundLevelHistoryRange = 24 * time.Hour
+	maxSoundLevelHistoryRange     = 31 * 24 * time.Hour
+	maxSoundLevelHistoryRows      = 100000
+	maxSoundLevelBands            = 20
+	maxSoundLevelInterval         = 24 * 60 * 60 // seconds
+)
+
+// SoundLevelEntryResponse is the sound level of one octave band over one period
+type SoundLevelEntryResponse struct {
+	Timestamp  time.Time `json:"timestamp"` // Start of the period
+	Source     string    `json:"source"`
+	Name       string    `json:"name,omitempty"`
+	Band       string    `json:"band"`
+	CenterFreq float64   `json:"centerFreq"`
+	Duration   int       `json:"duration"` // Seconds of measurements in the period
+	Leq        float64   `json:"leq"`
+	Lmax       float64   `json:"lmax"`
+	Lmin       float64   `json:"lmin"`
+	Samples    int       `json:"samples"`
+}
+
+// SoundLevelHistoryResponse is the stored sound level history of a time range
+type SoundLevelHistoryResponse struct {
+	Source    string                    `json:"source,omitempty"`
+	Bands     []string                  `json:"bands,omitempty"`
+	Start     time.Time                 `json:"start"`
+	End       time.Time                 `json:"end"`
+	Interval  int                       `json:"interval,omitempty"` // Aggregation period in seconds, omitted for stored periods
+	Levels    []SoundLevelEntryResponse `json:"levels"`
+	Truncated bool                      `json:"truncated"` // True if the row limit was reached
+}
+
+// SoundLevelSourceResponse is an audio source with stored sound levels
+type SoundLevelSourceResponse struct {
+	Source string    `json:"source"`
+	Name   string    `json:"name,omitempty"`
+	First  time.Time `json:"first"`
+	Last   time.Time `json:"last"`
+}
+
+// initSoundLevelRoutes sets up the sound level history routes
+func (c *Controller) initSoundLevelRoutes() {
+	c.Group.GET("/soundlevels/history", c.GetSoundLevelHistory)
+	c.Group.GET("/soundlevels/sources", c.GetSoundLevelSources)
+}
+
+// parseSoundLevelTime parses an RFC3339 timestamp or a YYYY-MM-DD date in local
+// time. Dates used as the end of a range include the whole day.
+func parseSoundLevelTime(value, paramName string, endOfRange bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if err := validateDateParam(value, paramName); err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s, use RFC3339 or YYYY-MM-DD", paramName)
+	}
+	t, _ := time.ParseInLocation("2006-01-02", value, time.Local)
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// parseSoundLevelHistoryQuery parses the query parameters of the sound level history
+// and returns the datastore query and the aggregation interval in seconds
+func parseSoundLevelHistoryQuery(ctx echo.Context, now time.Time) (*datastore.SoundLevelQuery, int, error) {
+	query := &datastore.SoundLevelQuery{
+		Source: strings.TrimSpace(ctx.QueryParam("source")),
+		End:    now,
+		Limit:  maxSoundLevelHistoryRows + 1,
+	}
+
+	if bands := ctx.QueryParam("bands"); bands != "" {
+		for _, band := range strings.Split(bands, ",") {
+			if band = strings.TrimSpace(band); band != "" {
+				query.Bands = append(query.Bands, band)
+			}
+		}
+		if len(query.Bands) > maxSoundLevelBands {
+			return nil, 0, fmt.Errorf("at most %d bands can be requested", maxSoundLevelBands)
+		}
+	}
+
+	var err error
+	if end := ctx.QueryParam("end"); end != "" {
+		if query.End, err = parseSoundLevelTime(end, "end", true); err != nil {
+			return nil, 0, err
+		}
+	}
+	query.Start = query.End.Add(-defaultSoundLevelHistoryRange)
+	if start := ctx.QueryParam("start"); start != "" {
+		if query.Start, err = parseSoundLevelTime(start, "start", false); err != nil {
+			return nil, 0, err
+		}
+	}
+	if !query.End.After(query.Start) {
+		return nil, 0, fmt.Errorf("end must be after start")
+	}
+	if query.End.Sub(query.Start) > maxSoundLevelHistoryRange {
+		return nil, 0, fmt.Errorf("time range must not exceed %d days", int(maxSoundLevelHistoryRange.Hours()/24))
+	}
+
+	interval := 0
+	if intervalStr := ctx.QueryParam("interval"); intervalStr != "" {
+		interval, err = strconv.Atoi(intervalStr)
+		if err != nil || interval <= 0 || interval > maxSoundLevelInterval {
+			return nil, 0, fmt.Errorf("interval must be between 1 and %d seconds", maxSoundLevelInterval)
+		}
+	}
+
+	return query, interval, nil
+}
+
+// roundDB rounds a dB value to two decimal places
+func roundDB(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// GetSoundLevelHistory returns stored sound levels
+// @Summary Get sound level history
+// @Description Returns stored sound levels per octave band and source, optionally aggregated into longer periods. Leq is energy averaged, Lmax and Lmin are the extremes of each period.
+// @Tags soundlevels
+// @Produce json
+// @Param source query string false "Audio source identifier, all sources if omitted"
+// @Param bands query string false "Comma separated octave band keys, e.g. 125_Hz,1.0_kHz"
+// @Param start query string false "Start of the range (RFC3339 or YYYY-MM-DD), defaults to 24 hours before end"
+// @Param end query string false "End of the range (RFC3339 or YYYY-MM-DD, inclusive), defaults to now"
+// @Param interval query int false "Aggregation period in seconds, stored periods if omitted"
+// @Success 200 {object} SoundLevelHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/soundlevels/history [get]
+func (c *Controller) GetSoundLevelHistory(ctx echo.Context) error {
+	query, interval, err := parseSoundLevelHistoryQuery(ctx, time.Now())
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	levels, err := c.DS.GetSoundLevels(query)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get sound level history", http.StatusInternalServerError)
+	}
+
+	truncated := len(levels) > maxSoundLevelHistoryRows
+	if truncated {
+		levels = levels[:maxSoundLevelHistoryRows]
+	}
+	if interval > 0 {
+		levels = datastore.AggregateSoundLevels(levels, time.Duration(interval)*time.Second)
+	}
+
+	response := SoundLevelHistoryResponse{
+		Source:    query.Source,
+		Bands:     query.Bands,
+		Start:     query.Start,
+		End:       query.End,
+		Interval:  interval,
+		Levels:    make([]SoundLevelEntryResponse, 0, len(levels)),
+		Truncated: truncated,
+	}
+	for i := range levels {
+		l := &levels[i]
+		response.Levels = append(response.Levels, SoundLevelEntryResponse{
+			Timestamp:  l.Timestamp,
+			Source:     l.Source,
+			Name:       l.Name,
+			Band:       l.Band,
+			CenterFreq: l.CenterFreq,
+			Duration:   l.Duration,
+			Leq:        roundDB(l.Leq),
+			Lmax:       roundDB(l.Lmax),
+			Lmin:       roundDB(l.Lmin),
+			Samples:    l.Samples,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetSoundLevelSources returns the audio sources with stored sound levels
+// @Summary Get sound level sources
+// @Description Returns the audio sources with stored sound level history and the range of the stored history
+// @Tags soundlevels
+// @Produce json
+// @Success 200 {array} SoundLevelSourceResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v2/soundlevels/sources [get]
+func (c *Controller) GetSoundLevelSources(ctx echo.Context) error {
+	sources, err := c.DS.GetSoundLevelSources()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get sound level sources", http.StatusInternalServerError)
+	}
+
+	response := make([]SoundLevelSourceResponse, 0, len(sources))
+	for _, source := range sources {
+		response = append(response, SoundLevelSourceResponse(source))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
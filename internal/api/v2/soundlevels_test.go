@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// TestParseSoundLevelHistoryQuery tests query parameter parsing of the sound level history endpoint
+func TestParseSoundLevelHistoryQuery(t *testing.T) {
+	e, _, _ := setupTestEnvironment(t)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	june1 := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name             string
+		query            string
+		expectedQuery    *datastore.SoundLevelQuery
+		expectedInterval int
+		expectError      bool
+	}{
+		{
+			name:  "Defaults to the last day",
+			query: "",
+			expectedQuery: &datastore.SoundLevelQuery{
+				Start: now.Add(-24 * time.Hour),
+				End:   now,
+				Limit: maxSoundLevelHistoryRows + 1,
+			},
+		},
+		{
+			name:  "Dates include the end day",
+			query: "source=mic&bands=125_Hz,+1.0_kHz&start=2024-06-01&end=2024-06-07&interval=3600",
+			expectedQuery: &datastore.SoundLevelQuery{
+				Source: "mic",
+				Bands:  []string{"125_Hz", "1.0_kHz"},
+				Start:  june1,
+				End:    june1.AddDate(0, 0, 7),
+				Limit:  maxSoundLevelHistoryRows + 1,
+			},
+			expectedInterval: 3600,
+		},
+		{
+			name:  "RFC3339 timestamps",
+			query: "start=2024-06-15T10:00:00Z&end=2024-06-15T11:00:00Z",
+			expectedQuery: &datastore.SoundLevelQuery{
+				Start: time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 6, 15, 11, 0, 0, 0, time.UTC),
+				Limit: maxSoundLevelHistoryRows + 1,
+			},
+		},
+		{name: "Invalid start", query: "start=yesterday", expectError: true},
+		{name: "Inverted range", query: "start=2024-06-10&end=2024-06-01", expectError: true},
+		{name: "Range too long", query: "start=2024-01-01&end=2024-06-01", expectError: true},
+		{name: "Invalid interval", query: "interval=0", expectError: true},
+		{name: "Interval too long", query: "interval=172800", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/soundlevels/history?"+tc.query, http.NoBody)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			query, interval, err := parseSoundLevelHistoryQuery(c, now)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedQuery.Source, query.Source)
+			assert.Equal(t, tc.expectedQuery.Bands, query.Bands)
+			assert.True(t, tc.expectedQuery.Start.Equal(query.Start), "start = %v", query.Start)
+			assert.True(t, tc.expectedQuery.End.Equal(query.End), "end = %v", query.End)
+			assert.Equal(t, tc.expectedQuery.Limit, query.Limit)
+			assert.Equal(t, tc.expectedInterval, interval)
+		})
+	}
+}
+
+// TestGetSoundLevelHistory tests aggregation of stored sound levels by the history endpoint
+func TestGetSoundLevelHistory(t *testing.T) {
+	e, mockDS, controller := setupTestEnvironment(t)
+
+	start := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	var levels []datastore.SoundLevel
+	for i := range 4 {
+		levels = append(levels, datastore.SoundLevel{
+			Timestamp: start.Add(time.Duration(i) * time.Minute), Source: "mic", Band: "1.0_kHz",
+			CenterFreq: 1000, Duration: 60, Leq: -40.123, Lmax: -30 + float64(i), Lmin: -50, Samples: 60,
+		})
+	}
+	mockDS.On("GetSoundLevels", mock.AnythingOfType("*datastore.SoundLevelQuery")).Return(levels, nil)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v2/soundlevels/history?source=mic&start=2024-06-15T10:00:00Z&end=2024-06-15T11:00:00Z&interval=120", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetSoundLevelHistory(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response SoundLevelHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 120, response.Interval)
+	assert.False(t, response.Truncated)
+	require.Len(t, response.Levels, 2)
+	assert.InDelta(t, -40.12, response.Levels[0].Leq, 1e-9)
+	assert.InDelta(t, -29, response.Levels[0].Lmax, 1e-9)
+	assert.Equal(t, 120, response.Levels[1].Duration)
+	assert.InDelta(t, -27, response.Levels[1].Lmax, 1e-9)
+	mockDS.AssertExpectations(t)
+}
+
+// TestGetSoundLevelSources tests the sound level sources endpoint
+func TestGetSoundLevelSources(t *testing.T) {
+	e, mockDS, controller := setupTestEnvironment(t)
+
+	first := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	mockDS.On("GetSoundLevelSources").Return([]datastore.SoundLevelSource{
+		{Source: "mic", Name: "Garden", First: first, Last: first.Add(time.Hour)},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/soundlevels/sources", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetSoundLevelSources(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response []SoundLevelSourceResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "Garden", response[0].Name)
+	assert.True(t, response[0].Last.Equal(first.Add(time.Hour)))
+}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/mock"
@@ -308,6 +309,11 @@ func (m *MockDataStore) SearchDetections(filters *datastore.SearchFilters) ([]da
 	return safeSlice[datastore.DetectionRecord](args, 0), args.Int(1), args.Error(2)
 }
 
+func (m *MockDataStore) ExportDetections(filters *datastore.SearchFilters, fn func(note *datastore.Note) error) error {
+	args := m.Called(filters, fn)
+	return args.Error(0)
+}
+
 // GetNewSpeciesDetections implements the datastore.Interface GetNewSpeciesDetections method
 func (m *MockDataStore) GetNewSpeciesDetections(startDate, endDate string, limit, offset int) ([]datastore.NewSpeciesData, error) {
 	args := m.Called(startDate, endDate, limit, offset)
@@ -320,6 +326,75 @@ func (m *MockDataStore) GetSpeciesFirstDetectionInPeriod(startDate, endDate stri
 	return safeSlice[datastore.NewSpeciesData](args, 0), args.Error(1)
 }
 
+// GetReviewedDetections implements the datastore.Interface GetReviewedDetections method
+func (m *MockDataStore) GetReviewedDetections(scientificName string) ([]datastore.ReviewedDetection, error) {
+	args := m.Called(scientificName)
+	return safeSlice[datastore.ReviewedDetection](args, 0), args.Error(1)
+}
+
+// GetReviewQueue implements the datastore.Interface GetReviewQueue method
+func (m *MockDataStore) GetReviewQueue(query *datastore.ReviewQueueQuery) ([]datastore.ReviewQueueItem, int64, error) {
+	args := m.Called(query)
+	return safeSlice[datastore.ReviewQueueItem](args, 0), args.Get(1).(int64), args.Error(2)
+}
+
+// ClaimReviews implements the datastore.Interface ClaimReviews method
+func (m *MockDataStore) ClaimReviews(query *datastore.ReviewQueueQuery, ttl time.Duration) ([]datastore.ReviewQueueItem, error) {
+	args := m.Called(query, ttl)
+	return safeSlice[datastore.ReviewQueueItem](args, 0), args.Error(1)
+}
+
+// ReleaseReviewClaims implements the datastore.Interface ReleaseReviewClaims method
+func (m *MockDataStore) ReleaseReviewClaims(reviewer string, noteIDs []uint) (int64, error) {
+	args := m.Called(reviewer, noteIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ApplyReviewVerdicts implements the datastore.Interface ApplyReviewVerdicts method
+func (m *MockDataStore) ApplyReviewVerdicts(reviewer string, verdicts []datastore.ReviewVerdict) ([]datastore.ReviewVerdictResult, error) {
+	args := m.Called(reviewer, verdicts)
+	return safeSlice[datastore.ReviewVerdictResult](args, 0), args.Error(1)
+}
+
+// GetNoteReviewHistory implements the datastore.Interface GetNoteReviewHistory method
+func (m *MockDataStore) GetNoteReviewHistory(noteID uint) ([]datastore.NoteReviewHistory, error) {
+	args := m.Called(noteID)
+	return safeSlice[datastore.NoteReviewHistory](args, 0), args.Error(1)
+}
+
+// GetReviewProgress implements the datastore.Interface GetReviewProgress method
+func (m *MockDataStore) GetReviewProgress(since time.Time) (*datastore.ReviewProgress, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*datastore.ReviewProgress), args.Error(1)
+}
+
+// SaveSoundLevels implements the datastore.Interface SaveSoundLevels method
+func (m *MockDataStore) SaveSoundLevels(levels []datastore.SoundLevel) error {
+	args := m.Called(levels)
+	return args.Error(0)
+}
+
+// GetSoundLevels implements the datastore.Interface GetSoundLevels method
+func (m *MockDataStore) GetSoundLevels(query *datastore.SoundLevelQuery) ([]datastore.SoundLevel, error) {
+	args := m.Called(query)
+	return safeSlice[datastore.SoundLevel](args, 0), args.Error(1)
+}
+
+// GetSoundLevelSources implements the datastore.Interface GetSoundLevelSources method
+func (m *MockDataStore) GetSoundLevelSources() ([]datastore.SoundLevelSource, error) {
+	args := m.Called()
+	return safeSlice[datastore.SoundLevelSource](args, 0), args.Error(1)
+}
+
+// DeleteSoundLevelsBefore implements the datastore.Interface DeleteSoundLevelsBefore method
+func (m *MockDataStore) DeleteSoundLevelsBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // TestImageProvider implements the imageprovider.Provider interface for testing
 // with a function field for easier test setup.
 // Use this when you need a simple mock with customizable behavior via FetchFunc.
@@ -605,6 +680,75 @@ func (m *MockDataStoreV2) GetSpeciesFirstDetectionInPeriod(startDate, endDate st
 	return safeSlice[datastore.NewSpeciesData](args, 0), args.Error(1)
 }
 
+// GetReviewedDetections implements the datastore.Interface GetReviewedDetections method
+func (m *MockDataStoreV2) GetReviewedDetections(scientificName string) ([]datastore.ReviewedDetection, error) {
+	args := m.Called(scientificName)
+	return safeSlice[datastore.ReviewedDetection](args, 0), args.Error(1)
+}
+
+// GetReviewQueue implements the datastore.Interface GetReviewQueue method
+func (m *MockDataStoreV2) GetReviewQueue(query *datastore.ReviewQueueQuery) ([]datastore.ReviewQueueItem, int64, error) {
+	args := m.Called(query)
+	return safeSlice[datastore.ReviewQueueItem](args, 0), args.Get(1).(int64), args.Error(2)
+}
+
+// ClaimReviews implements the datastore.Interface ClaimReviews method
+func (m *MockDataStoreV2) ClaimReviews(query *datastore.ReviewQueueQuery, ttl time.Duration) ([]datastore.ReviewQueueItem, error) {
+	args := m.Called(query, ttl)
+	return safeSlice[datastore.ReviewQueueItem](args, 0), args.Error(1)
+}
+
+// ReleaseReviewClaims implements the datastore.Interface ReleaseReviewClaims method
+func (m *MockDataStoreV2) ReleaseReviewClaims(reviewer string, noteIDs []uint) (int64, error) {
+	args := m.Called(reviewer, noteIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ApplyReviewVerdicts implements the datastore.Interface ApplyReviewVerdicts method
+func (m *MockDataStoreV2) ApplyReviewVerdicts(reviewer string, verdicts []datastore.ReviewVerdict) ([]datastore.ReviewVerdictResult, error) {
+	args := m.Called(reviewer, verdicts)
+	return safeSlice[datastore.ReviewVerdictResult](args, 0), args.Error(1)
+}
+
+// GetNoteReviewHistory implements the datastore.Interface GetNoteReviewHistory method
+func (m *MockDataStoreV2) GetNoteReviewHistory(noteID uint) ([]datastore.NoteReviewHistory, error) {
+	args := m.Called(noteID)
+	return safeSlice[datastore.NoteReviewHistory](args, 0), args.Error(1)
+}
+
+// GetReviewProgress implements the datastore.Interface GetReviewProgress method
+func (m *MockDataStoreV2) GetReviewProgress(since time.Time) (*datastore.ReviewProgress, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*datastore.ReviewProgress), args.Error(1)
+}
+
+// SaveSoundLevels implements the datastore.Interface SaveSoundLevels method
+func (m *MockDataStoreV2) SaveSoundLevels(levels []datastore.SoundLevel) error {
+	args := m.Called(levels)
+	return args.Error(0)
+}
+
+// GetSoundLevels implements the datastore.Interface GetSoundLevels method
+func (m *MockDataStoreV2) GetSoundLevels(query *datastore.SoundLevelQuery) ([]datastore.SoundLevel, error) {
+	args := m.Called(query)
+	return safeSlice[datastore.SoundLevel](args, 0), args.Error(1)
+}
+
+// GetSoundLevelSources implements the datastore.Interface GetSoundLevelSources method
+func (m *MockDataStoreV2) GetSoundLevelSources() ([]datastore.SoundLevelSource, error) {
+	args := m.Called()
+	return safeSlice[datastore.SoundLevelSource](args, 0), args.Error(1)
+}
+
+// DeleteSoundLevelsBefore implements the datastore.Interface DeleteSoundLevelsBefore method
+func (m *MockDataStoreV2) DeleteSoundLevelsBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // GetDetectionTrends implements the datastore.Interface GetDetectionTrends method
 func (m *MockDataStoreV2) GetDetectionTrends(period string, limit int) ([]datastore.DailyAnalyticsData, error) {
 	args := m.Called(period, limit)
@@ -617,6 +761,12 @@ func (m *MockDataStoreV2) SearchDetections(filters *datastore.SearchFilters) ([]
 	return safeSlice[datastore.DetectionRecord](args, 0), args.Int(1), args.Error(2)
 }
 
+// ExportDetections implements the datastore.Interface ExportDetections method
+func (m *MockDataStoreV2) ExportDetections(filters *datastore.SearchFilters, fn func(note *datastore.Note) error) error {
+	args := m.Called(filters, fn)
+	return args.Error(0)
+}
+
 // MockImageProvider is a mock implementation of imageprovider.ImageProvider interface
 // that uses testify/mock for expectations and verification.
 // Use this when you need to verify specific method calls and arguments.
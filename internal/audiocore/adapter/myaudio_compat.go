@@ -97,7 +97,7 @@ func (a *MyAudioCompatAdapter) CaptureAudio(
 // setupAudioSources configures audio sources based on settings
 func (a *MyAudioCompatAdapter) setupAudioSources() error {
 	// Check if we have RTSP URLs configured
-	if len(a.settings.Realtime.RTSP.URLs) > 0 {
+	if len(a.settings.Realtime.RTSP.AllURLs()) > 0 {
 		// TODO: Implement RTSP source when available
 		log.Printf("RTSP sources not yet implemented in audiocore")
 		return nil
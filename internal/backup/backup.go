@@ -42,6 +42,37 @@ type Source interface {
 	Validate() error
 }
 
+// Restorer is implemented by sources whose backup data is restored by the
+// source itself instead of replacing the SQLite database file, such as
+// database dumps loaded into a server
+type Restorer interface {
+	// VerifyBackupData checks that the file at path is complete backup data of this source
+	VerifyBackupData(path string) error
+	// RestoreBackupData loads the backup data at path. Sources that replace
+	// existing data first save it to previousPath, unless it is empty.
+	RestoreBackupData(ctx context.Context, path, previousPath string) error
+	// RestoreLocation describes where backup data is restored to
+	RestoreLocation() string
+}
+
+// ClipsSourceName is the name of the audio clip source, which can have its
+// own backup schedules
+const ClipsSourceName = "clips"
+
+// ErrNoChanges is returned by incremental sources when nothing was added since
+// their last successful backup
+var ErrNoChanges = errors.NewStd("no changes since the last backup")
+
+// IncrementalSource is implemented by sources that only back up data added
+// since their last successful backup
+type IncrementalSource interface {
+	Source
+	// BackupIncrement returns the data added since the previous state and the
+	// state to save once the backup is stored in all targets. ErrNoChanges is
+	// returned if there is nothing to back up.
+	BackupIncrement(ctx context.Context, previous *SourceState) (io.ReadCloser, *SourceState, error)
+}
+
 // Target represents a destination where backups are stored
 type Target interface {
 	// Name returns the name of the target
@@ -52,6 +83,8 @@ type Target interface {
 	List(ctx context.Context) ([]BackupInfo, error)
 	// Delete deletes a backup from storage
 	Delete(ctx context.Context, id string) error
+	// Restore downloads the archive of the backup with the given ID to destPath
+	Restore(ctx context.Context, id, destPath string) error
 	// Validate validates the target configuration
 	Validate() error
 }
@@ -72,6 +105,7 @@ type Metadata struct {
 	Compressed   bool      `json:"compressed,omitempty"`    // Whether the backup is compressed
 	Encrypted    bool      `json:"encrypted,omitempty"`     // Whether the backup is encrypted
 	OriginalSize int64     `json:"original_size,omitempty"` // Original size before compression/encryption
+	Incremental  bool      `json:"incremental,omitempty"`   // Whether the backup only contains data added since the previous backup of the source
 }
 
 // BackupInfo represents information about a stored backup
@@ -171,7 +205,8 @@ type Manager struct {
 	mu           sync.RWMutex
 	logger       *slog.Logger // Use slog logger
 	stateManager *StateManager
-	appVersion   string // Store app version
+	appVersion   string     // Store app version
+	restoreMu    sync.Mutex // Serializes restores
 }
 
 // NewManager creates a new backup manager
@@ -263,11 +298,52 @@ func (m *Manager) Start() error {
 	return nil
 }
 
-// RunBackup performs an immediate backup of all sources
+// RunBackup performs an immediate backup of all sources, except those that
+// are backed up on their own schedules
 func (m *Manager) RunBackup(ctx context.Context) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	sources := make(map[string]Source, len(m.sources))
+	for name, source := range m.sources {
+		if m.hasOwnSchedule(name) {
+			m.logger.Debug("Skipping source with its own backup schedule", "source_name", name)
+			continue
+		}
+		sources[name] = source
+	}
+	return m.runBackup(ctx, sources)
+}
+
+// RunSourceBackup performs an immediate backup of the named sources
+func (m *Manager) RunSourceBackup(ctx context.Context, names ...string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sources := make(map[string]Source, len(names))
+	for _, name := range names {
+		source, ok := m.sources[name]
+		if !ok {
+			return errors.Newf("backup source %s is not registered", name).
+				Component("backup").
+				Category(errors.CategoryNotFound).
+				Context("operation", "perform_source_backup").
+				Context("source", name).
+				Build()
+		}
+		sources[name] = source
+	}
+	return m.runBackup(ctx, sources)
+}
+
+// hasOwnSchedule reports whether the named source is backed up on its own
+// schedules instead of together with the database
+func (m *Manager) hasOwnSchedule(name string) bool {
+	return name == ClipsSourceName && m.config.Clips.Enabled && len(m.config.Clips.Schedules) > 0
+}
+
+// runBackup backs up the given sources. The caller must hold m.mu.
+func (m *Manager) runBackup(ctx context.Context, sources map[string]Source) error {
 	// Add a timeout for the entire backup operation
 	ctx, cancel := context.WithTimeout(ctx, m.getBackupTimeout())
 	defer cancel()
@@ -293,7 +369,7 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 	var errs []error
 
 	// Process each source
-	for sourceName, source := range m.sources {
+	for sourceName, source := range sources {
 		select {
 		case <-ctx.Done():
 			// Clean up temp dirs before returning
@@ -345,9 +421,23 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 func (m *Manager) processBackupSource(ctx context.Context, sourceName string, source Source, timestamp time.Time, isDaily, isWeekly bool) ([]string, error) {
 	var tempDirs []string // Track temp dirs created in this function
 
-	// 1. Perform the actual backup from the source
+	// 1. Perform the actual backup from the source, only taking what was added
+	// since the last successful backup from incremental sources
 	m.logger.Debug("Starting source backup", "source_name", sourceName)
-	backupReader, err := source.Backup(ctx)
+	var backupReader io.ReadCloser
+	var sourceState *SourceState
+	var err error
+	incremental, isIncremental := source.(IncrementalSource)
+	if isIncremental {
+		previous := m.stateManager.GetSourceState(sourceName)
+		backupReader, sourceState, err = incremental.BackupIncrement(ctx, &previous)
+		if errors.Is(err, ErrNoChanges) {
+			m.logger.Info("No changes since the last backup, skipping source", "source_name", sourceName)
+			return tempDirs, nil
+		}
+	} else {
+		backupReader, err = source.Backup(ctx)
+	}
 	if err != nil {
 		return tempDirs, fmt.Errorf("failed to initiate backup from source: %w", err)
 	}
@@ -373,15 +463,16 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 
 	// 3. Prepare metadata
 	metadata := &Metadata{
-		Version:    1, // Current metadata version
-		ID:         fmt.Sprintf("%s-%s", sourceName, timestamp.Format("20060102-150405")),
-		Timestamp:  timestamp,
-		Type:       sourceName, // Assuming source name is the type for now
-		Source:     sourceName,
-		IsDaily:    isDaily,
-		IsWeekly:   isWeekly, // Add weekly flag
-		AppVersion: m.appVersion,
-		Encrypted:  m.config.Encryption,
+		Version:     1, // Current metadata version
+		ID:          fmt.Sprintf("%s-%s", sourceName, timestamp.Format("20060102-150405")),
+		Timestamp:   timestamp,
+		Type:        sourceName, // Assuming source name is the type for now
+		Source:      sourceName,
+		IsDaily:     isDaily,
+		IsWeekly:    isWeekly, // Add weekly flag
+		AppVersion:  m.appVersion,
+		Encrypted:   m.config.Encryption,
+		Incremental: isIncremental,
 		// Size and checksum will be calculated later
 	}
 
@@ -442,19 +533,28 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 	metadata.Size = fileInfo.Size()
 	m.logger.Debug("Updated metadata with final size", "source_name", sourceName, "size", metadata.Size)
 
-	// Calculate checksum if needed (optional, can be time-consuming)
-	// checksum, err := calculateChecksum(finalArchivePath)
-	// if err == nil {
-	//     metadata.Checksum = checksum
-	// } else {
-	//     m.logger.Warn("Failed to calculate checksum", "path", finalArchivePath, "error", err)
-	// }
+	// Calculate checksum of the stored file so restores can verify the archive
+	checksum, err := calculateChecksum(finalArchivePath)
+	if err == nil {
+		metadata.Checksum = checksum
+	} else {
+		m.logger.Warn("Failed to calculate checksum", "path", finalArchivePath, "error", err)
+	}
 
 	// 8. Store the final archive in all registered targets
 	if err := m.storeBackupInTargets(ctx, finalArchivePath, metadata); err != nil {
 		return tempDirs, fmt.Errorf("failed to store backup in targets: %w", err)
 	}
 
+	// 9. Record the new state of incremental sources now that the backup is stored everywhere
+	if sourceState != nil {
+		sourceState.LastSuccessful = timestamp
+		sourceState.LastBackupID = metadata.ID
+		if err := m.stateManager.UpdateSourceState(sourceName, sourceState); err != nil {
+			m.logger.Warn("Failed to update source state", "source_name", sourceName, "error", err)
+		}
+	}
+
 	m.logger.Debug("Finished processing source", "source_name", sourceName)
 	return tempDirs, nil // Return tempDirs for cleanup by the caller
 }
@@ -675,9 +775,22 @@ func (m *Manager) createArchive(ctx context.Context, archivePath string, reader
 		return fmt.Errorf("failed to add config.yml to archive: %w", err)
 	}
 
-	// 3. Add the actual backup data stream
+	// 3. Add the actual backup data stream. Tar headers need the entry size,
+	// so the stream is spooled to a file next to the archive first.
 	m.logger.Debug("Adding backup data stream to archive", "backup_id", metadata.ID)
-	if err := m.addBackupDataToArchive(ctx, tarWriter, reader, metadata); err != nil {
+	dataFile, dataSize, err := m.spoolBackupData(ctx, archivePath+".data", reader)
+	if err != nil {
+		return fmt.Errorf("failed to spool backup data: %w", err)
+	}
+	defer func() {
+		if err := dataFile.Close(); err != nil {
+			m.logger.Warn("Failed to close spooled backup data", "path", dataFile.Name(), "error", err)
+		}
+		if err := os.Remove(dataFile.Name()); err != nil && !os.IsNotExist(err) {
+			m.logger.Warn("Failed to remove spooled backup data", "path", dataFile.Name(), "error", err)
+		}
+	}()
+	if err := m.addBackupDataToArchive(ctx, tarWriter, dataFile, dataSize, metadata); err != nil {
 		return fmt.Errorf("failed to add backup data to archive: %w", err)
 	}
 
@@ -760,21 +873,49 @@ func (m *Manager) addMetadataToArchive(ctx context.Context, tw *tar.Writer, meta
 	return nil
 }
 
-// addBackupDataToArchive streams data from the source reader into the tar archive.
-func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, reader io.Reader, metadata *Metadata) error {
+// spoolBackupData copies the source stream to a file at path and returns the
+// file rewound to the start together with the number of bytes written.
+func (m *Manager) spoolBackupData(ctx context.Context, path string, reader io.Reader) (*os.File, int64, error) {
+	secureOp := NewSecureFileOp("backup")
+	file, cleanPath, err := secureOp.SecureCreate(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size, err := io.Copy(file, reader)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(cleanPath)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, 0, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "spool_backup_data").
+			Context("bytes_copied", size).
+			Build()
+	}
+
+	return file, size, nil
+}
+
+// addBackupDataToArchive copies size bytes of backup data from reader into the tar archive.
+func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, reader io.Reader, size int64, metadata *Metadata) error {
 	start := time.Now()
 	// Determine the filename within the archive based on source type or name
 	// Example: Use source name with a common extension
 	backupFilename := fmt.Sprintf("backup.%s", strings.ToLower(metadata.Source)) // e.g., backup.sqlite
 
-	// We don't know the size beforehand for streaming backup.
-	// Write the data directly. TAR format supports this.
 	// Create TAR header for the backup data
 	hdr := &tar.Header{
 		Name:    backupFilename,
 		Mode:    0o644, // Standard file permissions
+		Size:    size,
 		ModTime: metadata.Timestamp,
-		// Size is unknown for streaming, tar writer handles this.
 	}
 
 	// Write header
@@ -1134,8 +1275,17 @@ func (m *Manager) cleanupOldBackups(ctx context.Context) error {
 		return fmt.Errorf("failed to list backups for cleanup: %w", err)
 	}
 
+	// Incremental backups depend on each other and are kept until removed
+	// manually, retention only applies to full backups
+	fullBackups := make([]BackupInfo, 0, len(allBackups))
+	for i := range allBackups {
+		if !allBackups[i].Incremental {
+			fullBackups = append(fullBackups, allBackups[i])
+		}
+	}
+
 	// Group backups by target and source type
-	groupedBackups := m.groupBackupsByTargetAndType(allBackups)
+	groupedBackups := m.groupBackupsByTargetAndType(fullBackups)
 
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(groupedBackups)) // Channel size based on number of target/source groups
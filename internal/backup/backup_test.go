@@ -0,0 +1,108 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// incrementSource is an incremental source with a single increment
+type incrementSource struct {
+	previous []SourceState
+}
+
+func (s *incrementSource) Name() string { return ClipsSourceName }
+
+func (s *incrementSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader([]byte("all clips"))), nil
+}
+
+func (s *incrementSource) BackupIncrement(ctx context.Context, previous *SourceState) (io.ReadCloser, *SourceState, error) {
+	s.previous = append(s.previous, *previous)
+	if !previous.Watermark.IsZero() {
+		return nil, nil, ErrNoChanges
+	}
+	state := &SourceState{Watermark: time.Now(), LastFileCount: 2, TotalFiles: 2}
+	return io.NopCloser(bytes.NewReader([]byte("new clips"))), state, nil
+}
+
+func (s *incrementSource) Validate() error { return nil }
+
+func TestRunSourceBackup_IncrementalSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	settings := &conf.Settings{}
+	settings.Backup.Clips.Enabled = true
+	settings.Backup.Clips.Schedules = []conf.BackupScheduleConfig{{Enabled: true, Hour: 3}}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	stateManager, err := NewStateManager(logger)
+	if err != nil {
+		t.Fatalf("NewStateManager() error = %v", err)
+	}
+	manager, err := NewManager(settings, logger, stateManager, "test")
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	source := &incrementSource{}
+	target := &dirTarget{dir: t.TempDir()}
+	if err := manager.RegisterSource(source); err != nil {
+		t.Fatalf("RegisterSource() error = %v", err)
+	}
+	if err := manager.RegisterTarget(target); err != nil {
+		t.Fatalf("RegisterTarget() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// Sources with their own schedules are not part of the regular backup
+	if err := manager.RunBackup(ctx); err != nil {
+		t.Fatalf("RunBackup() error = %v", err)
+	}
+	if len(source.previous) != 0 {
+		t.Fatal("RunBackup() backed up a source with its own schedule")
+	}
+
+	if err := manager.RunSourceBackup(ctx, ClipsSourceName); err != nil {
+		t.Fatalf("RunSourceBackup() error = %v", err)
+	}
+	backups, err := manager.ListBackups(ctx)
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 1 || !backups[0].Incremental {
+		t.Fatalf("ListBackups() = %+v, want one incremental backup", backups)
+	}
+
+	state := stateManager.GetSourceState(ClipsSourceName)
+	if state.LastBackupID != backups[0].ID || state.TotalFiles != 2 || state.Watermark.IsZero() {
+		t.Errorf("source state after backup = %+v", state)
+	}
+
+	// The saved state is passed to the next backup, which has nothing to store
+	if err := manager.RunSourceBackup(ctx, ClipsSourceName); err != nil {
+		t.Fatalf("RunSourceBackup() without changes error = %v", err)
+	}
+	if len(source.previous) != 2 || source.previous[1].LastBackupID != backups[0].ID {
+		t.Errorf("second backup got previous state %+v", source.previous)
+	}
+	if backups, _ := manager.ListBackups(ctx); len(backups) != 1 {
+		t.Errorf("ListBackups() returned %d backups after a backup without changes, want 1", len(backups))
+	}
+
+	// Retention never removes increments, the empty policy would remove any full backup
+	if err := manager.cleanupOldBackups(ctx); err != nil {
+		t.Fatalf("cleanupOldBackups() error = %v", err)
+	}
+	if backups, _ := manager.ListBackups(ctx); len(backups) != 1 {
+		t.Errorf("ListBackups() returned %d backups after cleanup, want 1", len(backups))
+	}
+
+	if err := manager.RunSourceBackup(ctx, "unknown"); err == nil {
+		t.Error("RunSourceBackup() of an unknown source succeeded")
+	}
+}
@@ -98,7 +98,36 @@ func (m *Manager) getEncryptionKey() ([]byte, error) {
 		return key, nil
 	}
 
-	// Decode existing key from hex
+	return decodeEncryptionKey(keyBytes)
+}
+
+// readEncryptionKey reads the existing encryption key without generating a
+// new one. Restores use it to decrypt archives even if encryption has since
+// been disabled.
+func (m *Manager) readEncryptionKey() ([]byte, error) {
+	keyPath, err := m.getEncryptionKeyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	secureOp := NewSecureFileOp("backup")
+	keyBytes, cleanKeyPath, err := secureOp.SecureReadFile(keyPath)
+	if err != nil {
+		if _, statErr := os.Stat(cleanKeyPath); os.IsNotExist(statErr) {
+			return nil, errors.Newf("backup is encrypted but no encryption key found at %s, import the key used to create it", cleanKeyPath).
+				Component("backup").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "read_encryption_key").
+				Build()
+		}
+		return nil, err
+	}
+
+	return decodeEncryptionKey(keyBytes)
+}
+
+// decodeEncryptionKey decodes a hex encoded encryption key and validates its length
+func decodeEncryptionKey(keyBytes []byte) ([]byte, error) {
 	keyStr := strings.TrimSpace(string(keyBytes))
 	key, err := hex.DecodeString(keyStr)
 	if err != nil {
@@ -0,0 +1,655 @@
+// restore.go contains the restore workflow for backup archives
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	// archiveMetadataName is the name of the metadata entry in backup archives
+	archiveMetadataName = "metadata.json"
+	// archiveConfigName is the name of the sanitized configuration entry in backup archives
+	archiveConfigName = "config.yml"
+	// archiveDataPrefix is the name prefix of the backup data entry in backup archives
+	archiveDataPrefix = "backup."
+	// maxArchiveMetadataSize limits the metadata entry read from an archive
+	maxArchiveMetadataSize = 1 << 20
+	// sqliteHeader is the magic header of SQLite database files
+	sqliteHeader = "SQLite format 3\x00"
+	// pendingRestoreSuffix is appended to the database path of a staged restore
+	pendingRestoreSuffix = ".restore-pending"
+)
+
+// sqliteSidecars are the files SQLite keeps next to a database in WAL mode
+var sqliteSidecars = []string{"-wal", "-shm"}
+
+// RestoreOptions controls how a backup is restored
+type RestoreOptions struct {
+	Target        string // Target to restore from, empty to use the target holding the backup
+	DatabasePath  string // Database file to replace, defaults to the configured SQLite path. Not used for backups loaded by a Restorer.
+	RestoreConfig bool   // Also restore the configuration file stored in the archive
+	ConfigPath    string // Configuration file to replace when RestoreConfig is set
+	DryRun        bool   // Download and verify the archive without replacing any files
+	Stage         bool   // Stage a SQLite database to replace the database file at the next start, for restores while the database is open
+}
+
+// RestoreResult describes a completed or simulated restore
+type RestoreResult struct {
+	BackupID         string   `json:"backup_id"`
+	Target           string   `json:"target"`
+	Metadata         Metadata `json:"metadata"`
+	Encrypted        bool     `json:"encrypted"`
+	ChecksumVerified bool     `json:"checksum_verified"` // False if the target has no checksum for the archive
+	DatabaseSize     int64    `json:"database_size"`
+	DatabasePath     string   `json:"database_path,omitempty"`
+	DatabaseBackup   string   `json:"database_backup,omitempty"` // Previous database file or dump
+	ConfigIncluded   bool     `json:"config_included"`
+	ConfigPath       string   `json:"config_path,omitempty"`
+	ConfigBackup     string   `json:"config_backup,omitempty"` // Previous configuration, kept next to the restored one
+	DryRun           bool     `json:"dry_run"`
+	Staged           bool     `json:"staged"` // The database is replaced at the next start, see ApplyPendingRestore
+}
+
+// extractedArchive holds the entries extracted from a backup archive
+type extractedArchive struct {
+	metadata   *Metadata
+	dataPath   string
+	configPath string
+}
+
+// ValidateBackupID checks that a backup ID can be safely used as a file name
+func ValidateBackupID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return NewError(ErrValidation, fmt.Sprintf("invalid backup ID: %q", id), nil)
+	}
+	return nil
+}
+
+// ArchiveFileNames returns the file names the archive of a backup may be
+// stored under, encrypted archive first. Targets that list backups by file
+// name also accept the file name itself as the ID.
+func ArchiveFileNames(id string) []string {
+	if strings.HasSuffix(id, ".tar") || strings.HasSuffix(id, ".tar.enc") {
+		return []string{id}
+	}
+	return []string{id + ".tar.enc", id + ".tar", id}
+}
+
+// matchesBackupID reports whether id refers to the backup with the given metadata ID
+func matchesBackupID(id, metadataID string) bool {
+	for _, name := range ArchiveFileNames(metadataID) {
+		if id == name {
+			return true
+		}
+	}
+	return false
+}
+
+// calculateChecksum returns the hex encoded SHA-256 checksum of a file
+func calculateChecksum(path string) (string, error) {
+	secureOp := NewSecureFileOp("backup")
+	file, cleanPath, err := secureOp.SecureOpen(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "calculate_checksum").
+			Context("path", cleanPath).
+			Build()
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// RestoreBackup downloads a backup, verifies it against its metadata,
+// decrypts it if needed and replaces the database and optionally the
+// configuration file with the archived copies. The replaced files are kept
+// next to the restored ones. Database dumps, such as MySQL backups, are loaded
+// by the registered source implementing Restorer instead, after the current
+// database is dumped to the configuration directory. With DryRun set nothing
+// is replaced.
+//
+// SQLite database files must not be replaced while the database is open. With
+// Stage set the database is copied next to the database file instead and
+// ApplyPendingRestore replaces the file at the next start.
+//
+// The archived configuration is sanitized, so passwords and API keys have to
+// be entered again after restoring it. A running application keeps using the
+// database it has open until it is restarted.
+func (m *Manager) RestoreBackup(ctx context.Context, id string, opts *RestoreOptions) (*RestoreResult, error) {
+	if err := ValidateBackupID(id); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+
+	if !m.restoreMu.TryLock() {
+		return nil, NewError(ErrLocked, "another restore is already in progress", nil)
+	}
+	defer m.restoreMu.Unlock()
+
+	dbPath := opts.DatabasePath
+	if dbPath == "" {
+		dbPath = m.fullConfig.Output.SQLite.Path
+	}
+	if opts.RestoreConfig && opts.ConfigPath == "" && !opts.DryRun {
+		return nil, NewError(ErrConfig, "no configuration file path given to restore to", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.getBackupTimeout())
+	defer cancel()
+
+	start := time.Now()
+	m.logger.Info("Starting restore", "backup_id", id, "target_name", opts.Target, "dry_run", opts.DryRun)
+
+	tempDir, err := os.MkdirTemp("", "birdnet-go-restore-*")
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_restore_temp_directory").
+			Build()
+	}
+	defer m.cleanupTempDirectories([]string{tempDir})
+
+	// 1. Download the archive
+	archivePath := filepath.Join(tempDir, "archive")
+	info, targetName, err := m.downloadBackup(ctx, id, opts.Target, archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{BackupID: id, Target: targetName, DryRun: opts.DryRun}
+
+	// 2. Verify the downloaded archive against the metadata stored with it
+	if info != nil {
+		if result.ChecksumVerified, err = verifyArchive(archivePath, &info.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Decrypt the archive if it is not a plain tar file
+	tarPath := archivePath
+	if !isTarArchive(archivePath) {
+		result.Encrypted = true
+		tarPath = filepath.Join(tempDir, "archive.tar")
+		if err := m.decryptArchive(archivePath, tarPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. Extract and verify the archive contents
+	extracted, err := extractArchive(tarPath, filepath.Join(tempDir, "extracted"))
+	if err != nil {
+		return nil, err
+	}
+	// Targets that list the backup may use their own IDs, others must return the requested backup
+	if info == nil && extracted.metadata.ID != "" && !matchesBackupID(id, extracted.metadata.ID) {
+		return nil, NewError(ErrCorruption, fmt.Sprintf("archive contains backup %q, expected %q", extracted.metadata.ID, id), nil)
+	}
+	restorer, err := m.findRestorer(extracted.metadata.Source, extracted.dataPath)
+	if err != nil {
+		return nil, err
+	}
+	if restorer != nil {
+		dbPath = restorer.RestoreLocation()
+	} else if dbPath == "" && !opts.DryRun {
+		return nil, NewError(ErrConfig, "no database path configured to restore to", nil)
+	}
+
+	result.Metadata = *extracted.metadata
+	if info != nil {
+		result.Metadata.Size = info.Size
+		result.Metadata.Checksum = info.Checksum
+	}
+	result.ConfigIncluded = extracted.configPath != ""
+	if dataInfo, err := os.Stat(extracted.dataPath); err == nil {
+		result.DatabaseSize = dataInfo.Size()
+	}
+	result.DatabasePath = dbPath
+	if opts.RestoreConfig {
+		if !result.ConfigIncluded {
+			return nil, NewError(ErrNotFound, "backup does not contain a configuration file", nil)
+		}
+		result.ConfigPath = opts.ConfigPath
+	}
+
+	if opts.DryRun {
+		m.logger.Info("Restore dry run completed",
+			"backup_id", id,
+			"target_name", targetName,
+			"checksum_verified", result.ChecksumVerified,
+			"duration_ms", time.Since(start).Milliseconds())
+		return result, nil
+	}
+
+	// 5. Replace the database and configuration, keeping the previous files
+	stamp := time.Now().Format("20060102-150405")
+	if restorer != nil {
+		previousPath, err := preRestoreDumpPath(result.Metadata.Source, stamp)
+		if err != nil {
+			return nil, err
+		}
+		if err := restorer.RestoreBackupData(ctx, extracted.dataPath, previousPath); err != nil {
+			return nil, err
+		}
+		// Sources that only add data, such as clips, leave nothing to keep
+		if _, err := os.Stat(previousPath); err == nil {
+			result.DatabaseBackup = previousPath
+		}
+	} else if opts.Stage {
+		if err := stageFile(extracted.dataPath, PendingRestorePath(dbPath)); err != nil {
+			return nil, err
+		}
+		result.Staged = true
+	} else if result.DatabaseBackup, err = replaceFile(extracted.dataPath, dbPath, stamp, sqliteSidecars); err != nil {
+		return nil, err
+	}
+	if result.Staged {
+		m.logger.Info("Database restore staged for the next start", "backup_id", id, "database_path", dbPath)
+	} else {
+		m.logger.Info("Database restored", "backup_id", id, "database_path", dbPath, "previous_database", result.DatabaseBackup)
+	}
+
+	if opts.RestoreConfig {
+		if result.ConfigBackup, err = replaceFile(extracted.configPath, opts.ConfigPath, stamp, nil); err != nil {
+			return result, err
+		}
+		m.logger.Info("Configuration restored", "backup_id", id, "config_path", opts.ConfigPath, "previous_config", result.ConfigBackup)
+	}
+
+	m.logger.Info("Restore completed",
+		"backup_id", id,
+		"target_name", targetName,
+		"duration_ms", time.Since(start).Milliseconds())
+	return result, nil
+}
+
+// downloadBackup downloads the archive of a backup to destPath and returns
+// the listed backup info and the target it was downloaded from. Targets not
+// listing the backup are asked for it by ID, in which case the returned info
+// is nil.
+func (m *Manager) downloadBackup(ctx context.Context, id, targetName, destPath string) (*BackupInfo, string, error) {
+	m.mu.RLock()
+	targets := make(map[string]Target, len(m.targets))
+	for name, t := range m.targets {
+		targets[name] = t
+	}
+	m.mu.RUnlock()
+
+	if targetName != "" {
+		if _, ok := targets[targetName]; !ok {
+			return nil, "", NewError(ErrNotFound, fmt.Sprintf("backup target '%s' not registered", targetName), nil)
+		}
+	}
+
+	backups, err := m.ListBackups(ctx)
+	if err != nil {
+		m.logger.Warn("Failed to list backups from some targets, continuing restore", "error", err)
+	}
+
+	var lastErr error
+	tried := make(map[string]bool)
+	for i := range backups {
+		info := &backups[i]
+		if info.ID != id || (targetName != "" && info.Target != targetName) {
+			continue
+		}
+		t, ok := targets[info.Target]
+		if !ok || tried[info.Target] {
+			continue
+		}
+		tried[info.Target] = true
+		if lastErr = t.Restore(ctx, id, destPath); lastErr == nil {
+			return info, info.Target, nil
+		}
+		m.logger.Warn("Failed to download backup from target", "backup_id", id, "target_name", info.Target, "error", lastErr)
+	}
+
+	// Fall back to asking targets whose listings lack backup IDs
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		if !tried[name] && (targetName == "" || name == targetName) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := targets[name].Restore(ctx, id, destPath); err != nil {
+			m.logger.Debug("Backup not downloaded from target", "backup_id", id, "target_name", name, "error", err)
+			lastErr = err
+			continue
+		}
+		return nil, name, nil
+	}
+
+	return nil, "", NewError(ErrNotFound, fmt.Sprintf("backup with ID '%s' not found", id), lastErr)
+}
+
+// verifyArchive checks the size and checksum of a downloaded archive against
+// its metadata. It reports whether a checksum was available and matched.
+func verifyArchive(path string, metadata *Metadata) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, NewError(ErrIO, "failed to stat downloaded archive", err)
+	}
+	if metadata.Size > 0 && info.Size() != metadata.Size {
+		return false, NewError(ErrCorruption,
+			fmt.Sprintf("archive size mismatch: expected %d bytes, got %d", metadata.Size, info.Size()), nil)
+	}
+
+	if metadata.Checksum == "" {
+		return false, nil
+	}
+	checksum, err := calculateChecksum(path)
+	if err != nil {
+		return false, err
+	}
+	if !strings.EqualFold(checksum, metadata.Checksum) {
+		return false, NewError(ErrCorruption,
+			fmt.Sprintf("archive checksum mismatch: expected %s, got %s", metadata.Checksum, checksum), nil)
+	}
+	return true, nil
+}
+
+// isTarArchive reports whether the file at path starts with a tar header
+func isTarArchive(path string) bool {
+	file, err := os.Open(path) // #nosec G304 -- path is inside the restore temp directory
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, 512)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return bytes.HasPrefix(header[257:], []byte("ustar"))
+}
+
+// decryptArchive decrypts an encrypted archive with the stored encryption key
+func (m *Manager) decryptArchive(sourcePath, destPath string) error {
+	key, err := m.readEncryptionKey()
+	if err != nil {
+		return err
+	}
+
+	secureOp := NewSecureFileOp("backup")
+	ciphertext, _, err := secureOp.SecureReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := decryptData(ciphertext, key)
+	if err != nil {
+		return NewError(ErrEncryption, "failed to decrypt archive, the encryption key may not match the one used for the backup", err)
+	}
+
+	if err := os.WriteFile(destPath, plaintext, 0o600); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_decrypted_archive").
+			Build()
+	}
+	return nil
+}
+
+// extractArchive extracts the metadata, configuration and backup data entries
+// of a tar archive into destDir
+func extractArchive(tarPath, destDir string) (*extractedArchive, error) {
+	secureOp := NewSecureFileOp("backup")
+	file, _, err := secureOp.SecureOpen(tarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if err := os.MkdirAll(destDir, 0o700); err != nil {
+		return nil, NewError(ErrIO, "failed to create extraction directory", err)
+	}
+
+	extracted := &extractedArchive{}
+	tr := tar.NewReader(file)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, NewError(ErrCorruption, "failed to read archive", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Name != filepath.Base(hdr.Name) {
+			continue // Backup archives only contain regular files at the top level
+		}
+
+		switch {
+		case hdr.Name == archiveMetadataName:
+			var metadata Metadata
+			if err := json.NewDecoder(io.LimitReader(tr, maxArchiveMetadataSize)).Decode(&metadata); err != nil {
+				return nil, NewError(ErrCorruption, "invalid metadata in archive", err)
+			}
+			extracted.metadata = &metadata
+		case hdr.Name == archiveConfigName:
+			extracted.configPath = filepath.Join(destDir, archiveConfigName)
+			if err := extractEntry(tr, extracted.configPath); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(hdr.Name, archiveDataPrefix):
+			extracted.dataPath = filepath.Join(destDir, hdr.Name)
+			if err := extractEntry(tr, extracted.dataPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if extracted.metadata == nil {
+		return nil, NewError(ErrCorruption, "archive does not contain metadata", nil)
+	}
+	if extracted.dataPath == "" {
+		return nil, NewError(ErrCorruption, "archive does not contain backup data", nil)
+	}
+	return extracted, nil
+}
+
+// extractEntry writes the current tar entry to path
+func extractEntry(r io.Reader, path string) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304 -- path is inside the restore temp directory
+	if err != nil {
+		return NewError(ErrIO, "failed to create extracted file", err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		return NewError(ErrCorruption, "failed to extract archive entry", err)
+	}
+	if err := out.Close(); err != nil {
+		return NewError(ErrIO, "failed to close extracted file", err)
+	}
+	return nil
+}
+
+// findRestorer returns the registered source that restores the extracted
+// backup data, or nil for SQLite databases that replace the database file.
+// The source that created the backup is preferred.
+func (m *Manager) findRestorer(sourceName, dataPath string) (Restorer, error) {
+	if verifyDatabaseFile(dataPath) == nil {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	sources := make(map[string]Source, len(m.sources))
+	for name, s := range m.sources {
+		sources[name] = s
+	}
+	m.mu.RUnlock()
+
+	if r, ok := sources[sourceName].(Restorer); ok {
+		if err := r.VerifyBackupData(dataPath); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if r, ok := sources[name].(Restorer); ok && r.VerifyBackupData(dataPath) == nil {
+			return r, nil
+		}
+	}
+
+	return nil, NewError(ErrCorruption, "backup data is neither a SQLite database nor data of a registered backup source", nil)
+}
+
+// preRestoreDumpPath returns the file the current database of a Restorer is
+// saved to before it is replaced, in the configuration directory
+func preRestoreDumpPath(sourceName, stamp string) (string, error) {
+	configPaths, err := conf.GetDefaultConfigPaths()
+	if err != nil || len(configPaths) == 0 {
+		return "", NewError(ErrConfig, "no configuration directory available for the pre-restore database dump", err)
+	}
+	return filepath.Join(configPaths[0], fmt.Sprintf("%s.pre-restore-%s.sql", sourceName, stamp)), nil
+}
+
+// verifyDatabaseFile checks that the extracted backup data is a SQLite database
+func verifyDatabaseFile(path string) error {
+	file, err := os.Open(path) // #nosec G304 -- path is inside the restore temp directory
+	if err != nil {
+		return NewError(ErrIO, "failed to open extracted database", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(file, header); err != nil || string(header) != sqliteHeader {
+		return NewError(ErrCorruption, "backup data is not a SQLite database", err)
+	}
+	return nil
+}
+
+// PendingRestorePath returns the file a restore of the SQLite database at
+// dbPath is staged to
+func PendingRestorePath(dbPath string) string {
+	return dbPath + pendingRestoreSuffix
+}
+
+// ApplyPendingRestore replaces the SQLite database at dbPath with a restore
+// staged by RestoreBackup. It must be called before the database is opened.
+// Returns whether a staged restore was applied and the previous database file,
+// which is kept next to the restored one.
+func ApplyPendingRestore(dbPath string) (applied bool, previous string, err error) {
+	pendingPath := PendingRestorePath(dbPath)
+	if _, err := os.Stat(pendingPath); os.IsNotExist(err) {
+		return false, "", nil
+	}
+
+	if err := verifyDatabaseFile(pendingPath); err != nil {
+		return false, "", err
+	}
+	stamp := time.Now().Format("20060102-150405")
+	if previous, err = replaceFile(pendingPath, dbPath, stamp, sqliteSidecars); err != nil {
+		return false, "", err
+	}
+	if err := os.Remove(pendingPath); err != nil {
+		return true, previous, NewError(ErrIO, "failed to remove staged restore", err)
+	}
+	return true, previous, nil
+}
+
+// stageFile copies src to dst through a temporary file, so that an
+// interrupted copy is never applied
+func stageFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return NewError(ErrIO, "failed to create destination directory", err)
+	}
+
+	tempPath := dst + ".tmp"
+	secureOp := NewSecureFileOp("backup")
+	if _, _, err := secureOp.SecureFileCopy(src, tempPath); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, dst); err != nil {
+		_ = os.Remove(tempPath)
+		return NewError(ErrIO, "failed to stage restored file", err)
+	}
+	return nil
+}
+
+// replaceFile replaces dst with a copy of src. An existing dst is renamed to
+// dst.pre-restore-<stamp> and returned; sidecar files such as SQLite WAL
+// files are moved along with it so they are not applied to the new file. If
+// the replacement fails the previous file is put back.
+func replaceFile(src, dst, stamp string, sidecars []string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", NewError(ErrIO, "failed to create destination directory", err)
+	}
+
+	// Copy into the destination directory first so the final rename is atomic
+	tempPath := dst + ".restore-tmp"
+	secureOp := NewSecureFileOp("backup")
+	if _, _, err := secureOp.SecureFileCopy(src, tempPath); err != nil {
+		_ = os.Remove(tempPath)
+		return "", err
+	}
+
+	var previous string
+	if _, err := os.Stat(dst); err == nil {
+		previous = fmt.Sprintf("%s.pre-restore-%s", dst, stamp)
+		if err := os.Rename(dst, previous); err != nil {
+			_ = os.Remove(tempPath)
+			return "", NewError(ErrIO, "failed to move existing file aside", err)
+		}
+		for _, suffix := range sidecars {
+			if err := os.Rename(dst+suffix, previous+suffix); err != nil && !os.IsNotExist(err) {
+				_ = os.Rename(previous, dst)
+				_ = os.Remove(tempPath)
+				return "", NewError(ErrIO, fmt.Sprintf("failed to move %s file aside", suffix), err)
+			}
+		}
+	}
+
+	if err := os.Rename(tempPath, dst); err != nil {
+		if previous != "" {
+			_ = os.Rename(previous, dst)
+			for _, suffix := range sidecars {
+				_ = os.Rename(previous+suffix, dst+suffix)
+			}
+		}
+		_ = os.Remove(tempPath)
+		return "", NewError(ErrIO, "failed to move restored file into place", err)
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,384 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// testDatabase is the content of the database backed up by testSource
+var testDatabase = []byte(sqliteHeader + "test database content")
+
+// testSource is a backup source returning a fixed database
+type testSource struct{}
+
+func (s *testSource) Name() string { return "birdnet" }
+
+func (s *testSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(testDatabase)), nil
+}
+
+func (s *testSource) Validate() error { return nil }
+
+// dirTarget is a backup target storing archives and metadata in a directory
+type dirTarget struct {
+	dir string
+}
+
+func (t *dirTarget) Name() string { return "dir" }
+
+func (t *dirTarget) Store(ctx context.Context, sourcePath string, metadata *Metadata) error {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(t.dir, filepath.Base(sourcePath))
+	if err := os.WriteFile(dst, data, 0o600); err != nil {
+		return err
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst+".meta", metadataBytes, 0o600)
+}
+
+func (t *dirTarget) List(ctx context.Context) ([]BackupInfo, error) {
+	matches, err := filepath.Glob(filepath.Join(t.dir, "*.meta"))
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]BackupInfo, 0, len(matches))
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			return nil, err
+		}
+		var metadata Metadata
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return nil, err
+		}
+		backups = append(backups, BackupInfo{Metadata: metadata, Target: t.Name()})
+	}
+	return backups, nil
+}
+
+func (t *dirTarget) Delete(ctx context.Context, id string) error {
+	return os.Remove(filepath.Join(t.dir, id))
+}
+
+func (t *dirTarget) Restore(ctx context.Context, id, destPath string) error {
+	for _, name := range ArchiveFileNames(id) {
+		data, err := os.ReadFile(filepath.Join(t.dir, name))
+		if err != nil {
+			continue
+		}
+		return os.WriteFile(destPath, data, 0o600)
+	}
+	return NewError(ErrNotFound, "backup not found", nil)
+}
+
+func (t *dirTarget) Validate() error { return nil }
+
+// dumpSource is a backup source producing a database dump that is restored
+// through the Restorer interface
+type dumpSource struct {
+	restored     []byte
+	previousPath string
+}
+
+const testDump = "-- test dump\nINSERT INTO notes VALUES (1);\n"
+
+func (s *dumpSource) Name() string { return "dump" }
+
+func (s *dumpSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(testDump)), nil
+}
+
+func (s *dumpSource) Validate() error { return nil }
+
+func (s *dumpSource) VerifyBackupData(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(data, []byte("-- test dump")) {
+		return NewError(ErrCorruption, "not a test dump", err)
+	}
+	return nil
+}
+
+func (s *dumpSource) RestoreBackupData(ctx context.Context, path, previousPath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s.restored, s.previousPath = data, previousPath
+	return os.WriteFile(previousPath, []byte(testDump), 0o600)
+}
+
+func (s *dumpSource) RestoreLocation() string { return "test://dump" }
+
+// setupRestoreTest creates a manager with a test source and target and runs a backup
+func setupRestoreTest(t *testing.T, encrypted bool) (manager *Manager, target *dirTarget, info BackupInfo) {
+	t.Helper()
+	return setupRestoreTestWithSource(t, encrypted, &testSource{})
+}
+
+// setupRestoreTestWithSource creates a manager with the given source and a test target and runs a backup
+func setupRestoreTestWithSource(t *testing.T, encrypted bool, source Source) (manager *Manager, target *dirTarget, info BackupInfo) {
+	t.Helper()
+
+	// The encryption key and backup state are stored under the home directory
+	t.Setenv("HOME", t.TempDir())
+
+	settings := &conf.Settings{}
+	settings.Output.SQLite.Path = filepath.Join(t.TempDir(), "birdnet.db")
+	settings.Backup.Encryption = encrypted
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	stateManager, err := NewStateManager(logger)
+	if err != nil {
+		t.Fatalf("NewStateManager() error = %v", err)
+	}
+	manager, err = NewManager(settings, logger, stateManager, "test")
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	target = &dirTarget{dir: t.TempDir()}
+	if err := manager.RegisterSource(source); err != nil {
+		t.Fatalf("RegisterSource() error = %v", err)
+	}
+	if err := manager.RegisterTarget(target); err != nil {
+		t.Fatalf("RegisterTarget() error = %v", err)
+	}
+	if err := manager.RunBackup(context.Background()); err != nil {
+		t.Fatalf("RunBackup() error = %v", err)
+	}
+
+	backups, err := manager.ListBackups(context.Background())
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("ListBackups() returned %d backups, want 1", len(backups))
+	}
+	if backups[0].Checksum == "" {
+		t.Fatal("backup metadata has no checksum")
+	}
+
+	// Existing database to be replaced by the restore
+	if err := os.WriteFile(settings.Output.SQLite.Path, []byte(sqliteHeader+"current database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return manager, target, backups[0]
+}
+
+func TestRestoreBackup(t *testing.T) {
+	for _, encrypted := range []bool{false, true} {
+		name := "plain"
+		if encrypted {
+			name = "encrypted"
+		}
+		t.Run(name, func(t *testing.T) {
+			manager, _, info := setupRestoreTest(t, encrypted)
+			dbPath := manager.fullConfig.Output.SQLite.Path
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			result, err := manager.RestoreBackup(context.Background(), info.ID, &RestoreOptions{
+				RestoreConfig: true,
+				ConfigPath:    configPath,
+			})
+			if err != nil {
+				t.Fatalf("RestoreBackup() error = %v", err)
+			}
+
+			if !result.ChecksumVerified {
+				t.Error("checksum was not verified")
+			}
+			if result.Encrypted != encrypted {
+				t.Errorf("Encrypted = %v, want %v", result.Encrypted, encrypted)
+			}
+			if result.Metadata.ID != info.ID {
+				t.Errorf("Metadata.ID = %q, want %q", result.Metadata.ID, info.ID)
+			}
+
+			restored, err := os.ReadFile(dbPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(restored, testDatabase) {
+				t.Errorf("restored database = %q, want %q", restored, testDatabase)
+			}
+
+			previous, err := os.ReadFile(result.DatabaseBackup)
+			if err != nil {
+				t.Fatalf("previous database not kept: %v", err)
+			}
+			if !strings.Contains(string(previous), "current database") {
+				t.Errorf("previous database = %q", previous)
+			}
+
+			if _, err := os.Stat(configPath); err != nil {
+				t.Errorf("configuration not restored: %v", err)
+			}
+		})
+	}
+}
+
+func TestRestoreBackup_DryRun(t *testing.T) {
+	manager, _, info := setupRestoreTest(t, false)
+	dbPath := manager.fullConfig.Output.SQLite.Path
+
+	result, err := manager.RestoreBackup(context.Background(), info.ID, &RestoreOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if !result.DryRun || !result.ChecksumVerified {
+		t.Errorf("DryRun = %v, ChecksumVerified = %v, want both true", result.DryRun, result.ChecksumVerified)
+	}
+	if result.DatabaseSize != int64(len(testDatabase)) {
+		t.Errorf("DatabaseSize = %d, want %d", result.DatabaseSize, len(testDatabase))
+	}
+
+	current, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(current), "current database") {
+		t.Error("dry run replaced the database")
+	}
+}
+
+func TestRestoreBackup_Staged(t *testing.T) {
+	manager, _, info := setupRestoreTest(t, false)
+	dbPath := manager.fullConfig.Output.SQLite.Path
+	if err := os.WriteFile(dbPath+"-wal", []byte("current wal"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := manager.RestoreBackup(context.Background(), info.ID, &RestoreOptions{Stage: true})
+	if err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if !result.Staged || result.DatabaseBackup != "" {
+		t.Errorf("Staged = %v, DatabaseBackup = %q, want staged without a previous database", result.Staged, result.DatabaseBackup)
+	}
+
+	// The open database is left alone until the next start
+	current, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(current), "current database") {
+		t.Error("staged restore replaced the database")
+	}
+
+	applied, previous, err := ApplyPendingRestore(dbPath)
+	if err != nil || !applied {
+		t.Fatalf("ApplyPendingRestore() = %v, %v", applied, err)
+	}
+	restored, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(restored, testDatabase) {
+		t.Errorf("restored database = %q, want %q", restored, testDatabase)
+	}
+	if _, err := os.Stat(previous + "-wal"); err != nil {
+		t.Errorf("WAL file of the previous database not kept: %v", err)
+	}
+	if _, err := os.Stat(PendingRestorePath(dbPath)); !os.IsNotExist(err) {
+		t.Errorf("staged restore not removed: %v", err)
+	}
+
+	// Nothing is applied without a staged restore
+	if applied, _, err := ApplyPendingRestore(dbPath); err != nil || applied {
+		t.Errorf("ApplyPendingRestore() without staged restore = %v, %v", applied, err)
+	}
+}
+
+func TestRestoreBackup_ChecksumMismatch(t *testing.T) {
+	manager, target, info := setupRestoreTest(t, false)
+
+	// Corrupt the stored archive without changing its size
+	archivePath := filepath.Join(target.dir, info.ID+".tar")
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(archivePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = manager.RestoreBackup(context.Background(), info.ID, &RestoreOptions{DryRun: true})
+	if !IsCorruptionError(err) {
+		t.Errorf("RestoreBackup() error = %v, want corruption error", err)
+	}
+}
+
+func TestRestoreBackup_NotFound(t *testing.T) {
+	manager, _, _ := setupRestoreTest(t, false)
+
+	_, err := manager.RestoreBackup(context.Background(), "birdnet-19700101-000000", &RestoreOptions{DryRun: true})
+	if !IsErrorCode(err, ErrNotFound) {
+		t.Errorf("RestoreBackup() error = %v, want not found error", err)
+	}
+
+	_, err = manager.RestoreBackup(context.Background(), "../etc/passwd", &RestoreOptions{DryRun: true})
+	if !IsErrorCode(err, ErrValidation) {
+		t.Errorf("RestoreBackup() error = %v, want validation error", err)
+	}
+}
+
+func TestRestoreBackup_Restorer(t *testing.T) {
+	source := &dumpSource{}
+	manager, _, info := setupRestoreTestWithSource(t, false, source)
+	dbPath := manager.fullConfig.Output.SQLite.Path
+
+	result, err := manager.RestoreBackup(context.Background(), info.ID, &RestoreOptions{})
+	if err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+
+	if string(source.restored) != testDump {
+		t.Errorf("restored data = %q, want %q", source.restored, testDump)
+	}
+	if source.previousPath == "" || result.DatabaseBackup != source.previousPath {
+		t.Errorf("DatabaseBackup = %q, previous path given to source = %q", result.DatabaseBackup, source.previousPath)
+	}
+	if result.DatabasePath != "test://dump" {
+		t.Errorf("DatabasePath = %q, want test://dump", result.DatabasePath)
+	}
+
+	// The SQLite database file is left alone
+	current, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(current), "current database") {
+		t.Error("restore of a dump replaced the SQLite database")
+	}
+}
+
+func TestRestoreBackup_UnknownData(t *testing.T) {
+	manager, _, info := setupRestoreTestWithSource(t, false, &dumpSource{})
+
+	// Without the source that can load the dump the data can't be restored
+	manager.mu.Lock()
+	delete(manager.sources, "dump")
+	manager.mu.Unlock()
+
+	_, err := manager.RestoreBackup(context.Background(), info.ID, &RestoreOptions{DryRun: true})
+	if !IsCorruptionError(err) {
+		t.Errorf("RestoreBackup() error = %v, want corruption error", err)
+	}
+}
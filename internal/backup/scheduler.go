@@ -20,6 +20,7 @@ type BackupSchedule struct {
 	LastRun  time.Time    // Last successful run time
 	NextRun  time.Time    // Next scheduled run time
 	IsWeekly bool         // true for weekly backups, false for daily
+	Sources  []string     // Sources to back up, empty for all sources without their own schedules
 }
 
 // Scheduler manages backup schedules and their execution
@@ -211,7 +212,13 @@ func (s *Scheduler) runBackup(schedule *BackupSchedule) {
 	defer cancel()
 
 	// Run the backup
-	if err := s.manager.RunBackup(ctx); err != nil {
+	var err error
+	if len(schedule.Sources) > 0 {
+		err = s.manager.RunSourceBackup(ctx, schedule.Sources...)
+	} else {
+		err = s.manager.RunBackup(ctx)
+	}
+	if err != nil {
 		duration := time.Since(start)
 		s.logger.Error("Scheduled backup failed", "schedule_type", scheduleType, "error", err, "duration_ms", duration.Milliseconds())
 
@@ -375,108 +382,136 @@ func (s *Scheduler) LoadFromConfig(config *conf.BackupConfig) error {
 	s.schedules = nil // Clear existing schedules before loading
 	s.logger.Info("Loading backup schedules from configuration", "schedule_count", len(config.Schedules))
 
-	for _, scheduleConf := range config.Schedules {
+	if err := s.loadSchedules(config.Schedules, nil); err != nil {
+		return err
+	}
+
+	// Clips are backed up with the database unless they have their own schedules
+	if config.Clips.Enabled && len(config.Clips.Schedules) > 0 {
+		s.logger.Info("Loading clip backup schedules from configuration", "schedule_count", len(config.Clips.Schedules))
+		if err := s.loadSchedules(config.Clips.Schedules, []string{ClipsSourceName}); err != nil {
+			return err
+		}
+	}
+
+	if len(s.schedules) == 0 {
+		s.logger.Warn("No enabled backup schedules loaded from configuration.")
+	}
+
+	return nil
+}
+
+// loadSchedules adds the enabled schedules of the configuration backing up the
+// given sources. The caller must hold s.mu.
+func (s *Scheduler) loadSchedules(configs []conf.BackupScheduleConfig, sources []string) error {
+	for _, scheduleConf := range configs {
 		if !scheduleConf.Enabled {
 			s.logger.Debug("Skipping disabled schedule", "hour", scheduleConf.Hour, "minute", scheduleConf.Minute, "weekday", scheduleConf.Weekday)
 			continue
 		}
 
-		// Validate time
-		if scheduleConf.Hour < 0 || scheduleConf.Hour > 23 {
-			errMsg := fmt.Sprintf("invalid hour %d in schedule config", scheduleConf.Hour)
-			s.logger.Error(errMsg, "config", scheduleConf)
-			return errors.Newf("%s", errMsg).
-				Component("backup").
-				Category(errors.CategoryConfiguration).
-				Context("operation", "parse_schedule_config").
-				Context("hour", scheduleConf.Hour).
-				Build()
-		}
-		if scheduleConf.Minute < 0 || scheduleConf.Minute > 59 {
-			errMsg := fmt.Sprintf("invalid minute %d in schedule config", scheduleConf.Minute)
-			s.logger.Error(errMsg, "config", scheduleConf)
-			return errors.Newf("%s", errMsg).
-				Component("backup").
-				Category(errors.CategoryConfiguration).
-				Context("operation", "parse_schedule_config").
-				Context("minute", scheduleConf.Minute).
-				Build()
+		schedule, err := s.parseScheduleConfig(&scheduleConf, sources)
+		if err != nil {
+			return err
 		}
 
-		var weekday time.Weekday
-		var isWeekly bool
-
-		// Determine weekday and weekly status based on configuration
-		switch {
-		case scheduleConf.IsWeekly:
-			// Explicitly marked as weekly
-			isWeekly = true
-			if scheduleConf.Weekday == "" { // No weekday specified but marked as weekly
-				s.logger.Warn("Schedule marked as weekly but no weekday specified, defaulting to Sunday", "config", scheduleConf)
-				weekday = time.Sunday
-			} else {
-				// Parse the specified weekday
-				parsedDay, err := parseWeekday(scheduleConf.Weekday)
-				if err != nil {
-					errMsg := fmt.Sprintf("invalid weekday '%s' in schedule config", scheduleConf.Weekday)
-					s.logger.Error(errMsg, "config", scheduleConf, "error", err)
-					return errors.New(err).
-						Component("backup").
-						Category(errors.CategoryConfiguration).
-						Context("operation", "parse_weekday_for_weekly_schedule").
-						Context("weekday", scheduleConf.Weekday).
-						Build()
-				}
-				weekday = parsedDay
-			}
-		case scheduleConf.Weekday != "":
-			// If weekday is specified but not explicitly marked as weekly, still make it weekly
-			isWeekly = true
+		s.schedules = append(s.schedules, *schedule)
+		s.logger.Info("Loaded schedule",
+			"hour", schedule.Hour,
+			"minute", schedule.Minute,
+			"weekday", s.formatWeekday(schedule.Weekday),
+			"is_weekly", schedule.IsWeekly,
+			"sources", schedule.Sources,
+			"next_run", schedule.NextRun.Format(time.RFC3339),
+		)
+	}
+	return nil
+}
+
+// parseScheduleConfig validates a schedule configuration and converts it to a schedule
+func (s *Scheduler) parseScheduleConfig(scheduleConf *conf.BackupScheduleConfig, sources []string) (*BackupSchedule, error) {
+	// Validate time
+	if scheduleConf.Hour < 0 || scheduleConf.Hour > 23 {
+		errMsg := fmt.Sprintf("invalid hour %d in schedule config", scheduleConf.Hour)
+		s.logger.Error(errMsg, "config", scheduleConf)
+		return nil, errors.Newf("%s", errMsg).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "parse_schedule_config").
+			Context("hour", scheduleConf.Hour).
+			Build()
+	}
+	if scheduleConf.Minute < 0 || scheduleConf.Minute > 59 {
+		errMsg := fmt.Sprintf("invalid minute %d in schedule config", scheduleConf.Minute)
+		s.logger.Error(errMsg, "config", scheduleConf)
+		return nil, errors.Newf("%s", errMsg).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "parse_schedule_config").
+			Context("minute", scheduleConf.Minute).
+			Build()
+	}
+
+	var weekday time.Weekday
+	var isWeekly bool
+
+	// Determine weekday and weekly status based on configuration
+	switch {
+	case scheduleConf.IsWeekly:
+		// Explicitly marked as weekly
+		isWeekly = true
+		if scheduleConf.Weekday == "" { // No weekday specified but marked as weekly
+			s.logger.Warn("Schedule marked as weekly but no weekday specified, defaulting to Sunday", "config", scheduleConf)
+			weekday = time.Sunday
+		} else {
+			// Parse the specified weekday
 			parsedDay, err := parseWeekday(scheduleConf.Weekday)
 			if err != nil {
 				errMsg := fmt.Sprintf("invalid weekday '%s' in schedule config", scheduleConf.Weekday)
 				s.logger.Error(errMsg, "config", scheduleConf, "error", err)
-				return errors.New(err).
+				return nil, errors.New(err).
 					Component("backup").
 					Category(errors.CategoryConfiguration).
-					Context("operation", "parse_weekday_for_schedule").
+					Context("operation", "parse_weekday_for_weekly_schedule").
 					Context("weekday", scheduleConf.Weekday).
 					Build()
 			}
 			weekday = parsedDay
-		default:
-			// Neither IsWeekly nor Weekday specified, it's a daily schedule
-			isWeekly = false
-			weekday = -1 // Explicitly set weekday to -1 for daily schedules
 		}
-
-		// Calculate next run time based on current time
-		now := time.Now()
-		nextRun := s.calculateNextRun(now, scheduleConf.Hour, scheduleConf.Minute, weekday, isWeekly)
-
-		schedule := BackupSchedule{
-			Hour:     scheduleConf.Hour,
-			Minute:   scheduleConf.Minute,
-			Weekday:  weekday,
-			IsWeekly: isWeekly,
-			NextRun:  nextRun,
+	case scheduleConf.Weekday != "":
+		// If weekday is specified but not explicitly marked as weekly, still make it weekly
+		isWeekly = true
+		parsedDay, err := parseWeekday(scheduleConf.Weekday)
+		if err != nil {
+			errMsg := fmt.Sprintf("invalid weekday '%s' in schedule config", scheduleConf.Weekday)
+			s.logger.Error(errMsg, "config", scheduleConf, "error", err)
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "parse_weekday_for_schedule").
+				Context("weekday", scheduleConf.Weekday).
+				Build()
 		}
-
-		s.schedules = append(s.schedules, schedule)
-		s.logger.Info("Loaded schedule",
-			"hour", schedule.Hour,
-			"minute", schedule.Minute,
-			"weekday", s.formatWeekday(schedule.Weekday),
-			"is_weekly", schedule.IsWeekly,
-			"next_run", schedule.NextRun.Format(time.RFC3339),
-		)
+		weekday = parsedDay
+	default:
+		// Neither IsWeekly nor Weekday specified, it's a daily schedule
+		isWeekly = false
+		weekday = -1 // Explicitly set weekday to -1 for daily schedules
 	}
 
-	if len(s.schedules) == 0 {
-		s.logger.Warn("No enabled backup schedules loaded from configuration.")
-	}
+	// Calculate next run time based on current time
+	now := time.Now()
+	nextRun := s.calculateNextRun(now, scheduleConf.Hour, scheduleConf.Minute, weekday, isWeekly)
+
+	return &BackupSchedule{
+		Hour:     scheduleConf.Hour,
+		Minute:   scheduleConf.Minute,
+		Weekday:  weekday,
+		IsWeekly: isWeekly,
+		NextRun:  nextRun,
+		Sources:  sources,
+	}, nil
 
-	return nil
 }
 
 // GetMissedBackups returns all missed backups
@@ -515,10 +550,14 @@ func (s *Scheduler) TriggerBackup(ctx context.Context) error {
 
 // getScheduleType returns a string representation of the schedule type
 func (s *Scheduler) getScheduleType(schedule *BackupSchedule) string {
+	scheduleType := "Daily"
 	if schedule.IsWeekly {
-		return fmt.Sprintf("Weekly (%s)", s.formatWeekday(schedule.Weekday))
+		scheduleType = fmt.Sprintf("Weekly (%s)", s.formatWeekday(schedule.Weekday))
+	}
+	if len(schedule.Sources) > 0 {
+		scheduleType += fmt.Sprintf(" [%s]", strings.Join(schedule.Sources, ", "))
 	}
-	return "Daily"
+	return scheduleType
 }
 
 // formatWeekday returns a string representation of the weekday
@@ -0,0 +1,503 @@
+package sources
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const (
+	// clipsManifestName is the first entry of every clip backup
+	clipsManifestName = ".birdnet-go-clips.json"
+	// clipsManifestVersion is the current version of the manifest format
+	clipsManifestVersion = 1
+	// clipsTempExt is the extension of clips that are still being exported
+	clipsTempExt = ".temp"
+	// maxClipsManifestSize limits the size of the manifest read from a backup
+	maxClipsManifestSize = 1 << 20
+)
+
+// LockedClipsProvider returns the clip paths of locked notes
+type LockedClipsProvider interface {
+	GetLockedNotesClipPaths() ([]string, error)
+}
+
+// clipsManifest describes the clips contained in a clip backup
+type clipsManifest struct {
+	Version    int       `json:"version"`
+	Created    time.Time `json:"created"`
+	Since      time.Time `json:"since,omitempty"` // Clips modified before this were included in earlier backups
+	Files      int       `json:"files"`
+	LockedOnly bool      `json:"locked_only,omitempty"`
+}
+
+// clipFile is a clip selected for backup
+type clipFile struct {
+	path string // Path on disk
+	name string // Slash separated path relative to the export directory
+}
+
+// ClipsSource implements the backup.IncrementalSource interface for the
+// audio clips under the export path. Each backup only contains the clips
+// added since the last successful backup.
+type ClipsSource struct {
+	config *conf.Settings
+	store  LockedClipsProvider
+	logger *slog.Logger
+}
+
+// NewClipsSource creates a new audio clip backup source. The store is used to
+// find clips of locked notes, it may be nil if only restoring backups.
+func NewClipsSource(config *conf.Settings, store LockedClipsProvider, logger *slog.Logger) *ClipsSource {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ClipsSource{
+		config: config,
+		store:  store,
+		logger: logger.With("backup_source", backup.ClipsSourceName),
+	}
+}
+
+// Name returns the name of this source
+func (s *ClipsSource) Name() string {
+	return backup.ClipsSourceName
+}
+
+// RestoreLocation returns the clip directory backups are restored to
+func (s *ClipsSource) RestoreLocation() string {
+	return s.config.Realtime.Audio.Export.Path
+}
+
+// validateConfig checks that the export path is configured
+func (s *ClipsSource) validateConfig() error {
+	if s.config.Realtime.Audio.Export.Path == "" {
+		return backup.NewError(backup.ErrConfig, "audio export path not configured", nil)
+	}
+	return nil
+}
+
+// Validate checks if the source configuration is valid
+func (s *ClipsSource) Validate() error {
+	if err := s.validateConfig(); err != nil {
+		return err
+	}
+	info, err := os.Stat(s.config.Realtime.Audio.Export.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The directory is created when the first clip is exported
+			s.logger.Info("Audio export directory does not exist yet", "path", s.config.Realtime.Audio.Export.Path)
+			return nil
+		}
+		return backup.NewError(backup.ErrIO, "failed to access audio export directory", err)
+	}
+	if !info.IsDir() {
+		return backup.NewError(backup.ErrValidation, "audio export path is not a directory", nil)
+	}
+	return nil
+}
+
+// Backup returns an archive of all clips
+func (s *ClipsSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	files, _, err := s.selectClips(ctx, &backup.SourceState{})
+	if err != nil {
+		return nil, err
+	}
+	return s.stream(ctx, files, time.Time{}), nil
+}
+
+// BackupIncrement returns an archive of the clips added since the previous
+// backup. Clips are new if they were modified after the watermark of the
+// previous state. When only locked clips are backed up, clips of notes locked
+// after an earlier backup are included as well.
+func (s *ClipsSource) BackupIncrement(ctx context.Context, previous *backup.SourceState) (io.ReadCloser, *backup.SourceState, error) {
+	if previous == nil {
+		previous = &backup.SourceState{}
+	}
+	files, state, err := s.selectClips(ctx, previous)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, backup.ErrNoChanges
+	}
+	s.logger.Info("Selected clips for backup", "files", len(files), "since", previous.Watermark)
+	return s.stream(ctx, files, previous.Watermark), state, nil
+}
+
+// lockedClips returns the base names of clips of locked notes
+func (s *ClipsSource) lockedClips() (map[string]bool, error) {
+	locked := make(map[string]bool)
+	if s.store == nil {
+		if s.config.Backup.Clips.LockedOnly {
+			return nil, backup.NewError(backup.ErrConfig, "locked clips can not be backed up without a datastore", nil)
+		}
+		return locked, nil
+	}
+	paths, err := s.store.GetLockedNotesClipPaths()
+	if err != nil {
+		return nil, backup.NewError(backup.ErrDatabase, "failed to get clips of locked notes", err)
+	}
+	for _, p := range paths {
+		if p != "" {
+			// Clip paths may be stored relative to different roots, match them by name
+			locked[filepath.Base(p)] = true
+		}
+	}
+	return locked, nil
+}
+
+// selectClips walks the export directory and returns the clips to back up
+// together with the state to save once they are stored
+func (s *ClipsSource) selectClips(ctx context.Context, previous *backup.SourceState) ([]clipFile, *backup.SourceState, error) {
+	if err := s.validateConfig(); err != nil {
+		return nil, nil, err
+	}
+	locked, err := s.lockedClips()
+	if err != nil {
+		return nil, nil, err
+	}
+	lockedOnly := s.config.Backup.Clips.LockedOnly
+	archivedLocked := make(map[string]bool, len(previous.ArchivedLocked))
+	for _, name := range previous.ArchivedLocked {
+		archivedLocked[name] = true
+	}
+
+	// Clips written while walking are newer than the scan start and are
+	// picked up by the next backup
+	scanStart := time.Now()
+	root := s.config.Realtime.Audio.Export.Path
+	var files []clipFile
+	var stillLocked []string
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && os.IsNotExist(err) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != root {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(d.Name(), clipsTempExt) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		isLocked := locked[d.Name()]
+		if lockedOnly && !isLocked {
+			return nil
+		}
+		if isLocked && archivedLocked[name] {
+			stillLocked = append(stillLocked, name)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // Removed by clip cleanup while walking
+			}
+			return err
+		}
+		isNew := previous.Watermark.IsZero() || !info.ModTime().Before(previous.Watermark)
+		if isNew || (lockedOnly && !archivedLocked[name]) {
+			files = append(files, clipFile{path: p, name: name})
+		}
+		return nil
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return nil, nil, backup.NewError(backup.ErrIO, "failed to scan audio export directory", err)
+	}
+
+	state := &backup.SourceState{
+		Watermark:     scanStart,
+		LastFileCount: len(files),
+		TotalFiles:    previous.TotalFiles + len(files),
+	}
+	if lockedOnly {
+		// Only clips that are still locked are remembered, so the list does
+		// not grow with clips that were unlocked or removed
+		for i := range files {
+			if !archivedLocked[files[i].name] {
+				stillLocked = append(stillLocked, files[i].name)
+			}
+		}
+		sort.Strings(stillLocked)
+		state.ArchivedLocked = stillLocked
+	}
+	return files, state, nil
+}
+
+// stream writes a tar archive of the files, preceded by the manifest, to the
+// returned reader
+func (s *ClipsSource) stream(ctx context.Context, files []clipFile, since time.Time) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		start := time.Now()
+		err := s.writeArchive(ctx, pw, files, since)
+		if err != nil {
+			s.logger.Error("Clip backup failed", "error", err, "duration_ms", time.Since(start).Milliseconds())
+			if closeErr := pw.CloseWithError(err); closeErr != nil {
+				s.logger.Warn("Error closing pipe writer with error", "error", closeErr)
+			}
+			return
+		}
+		if err := pw.Close(); err != nil {
+			s.logger.Warn("Error closing pipe writer", "error", err)
+		}
+		s.logger.Info("Clip backup completed", "files", len(files), "duration_ms", time.Since(start).Milliseconds())
+	}()
+
+	return pr
+}
+
+// writeArchive writes the manifest and files as a tar archive to w
+func (s *ClipsSource) writeArchive(ctx context.Context, w io.Writer, files []clipFile, since time.Time) error {
+	tw := tar.NewWriter(w)
+
+	manifest, err := json.Marshal(&clipsManifest{
+		Version:    clipsManifestVersion,
+		Created:    time.Now().UTC(),
+		Since:      since,
+		Files:      len(files),
+		LockedOnly: s.config.Backup.Clips.LockedOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal clip manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    clipsManifestName,
+		Mode:    0o644,
+		Size:    int64(len(manifest)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return backup.NewError(backup.ErrIO, "failed to write clip manifest", err)
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return backup.NewError(backup.ErrIO, "failed to write clip manifest", err)
+	}
+
+	for i := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.writeClip(tw, &files[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return backup.NewError(backup.ErrIO, "failed to finish clip archive", err)
+	}
+	return nil
+}
+
+// writeClip adds a single clip to the archive
+func (s *ClipsSource) writeClip(tw *tar.Writer, clip *clipFile) error {
+	file, err := os.Open(clip.path) // #nosec G304 -- path was found by walking the export directory
+	if err != nil {
+		return backup.NewError(backup.ErrIO, fmt.Sprintf("failed to open clip %s", clip.name), err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return backup.NewError(backup.ErrIO, fmt.Sprintf("failed to stat clip %s", clip.name), err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    clip.name,
+		Mode:    0o644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return backup.NewError(backup.ErrIO, fmt.Sprintf("failed to write header of clip %s", clip.name), err)
+	}
+	if _, err := io.CopyN(tw, file, info.Size()); err != nil {
+		return backup.NewError(backup.ErrIO, fmt.Sprintf("failed to archive clip %s", clip.name), err)
+	}
+	return nil
+}
+
+// validClipName reports whether name is a safe relative path for a restored clip
+func validClipName(name string) bool {
+	return name != "" && name != clipsManifestName && !strings.Contains(name, `\`) &&
+		path.Clean(name) == name && filepath.IsLocal(filepath.FromSlash(name))
+}
+
+// readClipsManifest reads the manifest from the first entry of a clip archive
+func readClipsManifest(tr *tar.Reader) (*clipsManifest, error) {
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != clipsManifestName {
+		return nil, backup.NewError(backup.ErrCorruption, "backup data is not a clip archive", err)
+	}
+	var manifest clipsManifest
+	if err := json.NewDecoder(io.LimitReader(tr, maxClipsManifestSize)).Decode(&manifest); err != nil {
+		return nil, backup.NewError(backup.ErrCorruption, "invalid clip manifest", err)
+	}
+	if manifest.Version > clipsManifestVersion {
+		return nil, backup.NewError(backup.ErrValidation, fmt.Sprintf("unsupported clip manifest version %d", manifest.Version), nil)
+	}
+	return &manifest, nil
+}
+
+// VerifyBackupData checks that the file at path is a complete clip archive
+// created by this source
+func (s *ClipsSource) VerifyBackupData(dataPath string) error {
+	secureOp := backup.NewSecureFileOp("backup")
+	file, _, err := secureOp.SecureOpen(dataPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	tr := tar.NewReader(file)
+	manifest, err := readClipsManifest(tr)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return backup.NewError(backup.ErrCorruption, "invalid clip archive", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !validClipName(hdr.Name) {
+			return backup.NewError(backup.ErrCorruption, fmt.Sprintf("unexpected entry %q in clip archive", hdr.Name), nil)
+		}
+		if _, err := io.Copy(io.Discard, tr); err != nil {
+			return backup.NewError(backup.ErrCorruption, "clip archive is incomplete", err)
+		}
+		count++
+	}
+	if count != manifest.Files {
+		return backup.NewError(backup.ErrCorruption,
+			fmt.Sprintf("clip archive contains %d files, manifest lists %d", count, manifest.Files), nil)
+	}
+	return nil
+}
+
+// RestoreBackupData extracts the clips at dataPath into the export directory.
+// Existing clips are kept, so increments can be restored in any order. Clips
+// only add data, previousPath is not used.
+func (s *ClipsSource) RestoreBackupData(ctx context.Context, dataPath, previousPath string) error {
+	if err := s.validateConfig(); err != nil {
+		return err
+	}
+	if err := s.VerifyBackupData(dataPath); err != nil {
+		return err
+	}
+
+	secureOp := backup.NewSecureFileOp("backup")
+	file, _, err := secureOp.SecureOpen(dataPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	start := time.Now()
+	root := s.config.Realtime.Audio.Export.Path
+	tr := tar.NewReader(file)
+	if _, err := readClipsManifest(tr); err != nil {
+		return err
+	}
+
+	restored, skipped := 0, 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return backup.NewError(backup.ErrCorruption, "invalid clip archive", err)
+		}
+
+		dest := filepath.Join(root, filepath.FromSlash(hdr.Name))
+		if _, err := os.Lstat(dest); err == nil {
+			skipped++
+			continue
+		}
+		if err := restoreClip(tr, dest, hdr.ModTime); err != nil {
+			return err
+		}
+		restored++
+	}
+
+	s.logger.Info("Clips restored",
+		"path", root,
+		"restored", restored,
+		"skipped_existing", skipped,
+		"duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+// restoreClip writes the current tar entry to dest through a temporary file,
+// keeping the original modification time so the clip is not backed up again
+func restoreClip(r io.Reader, dest string, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return backup.NewError(backup.ErrIO, "failed to create clip directory", err)
+	}
+
+	tempPath := dest + clipsTempExt
+	out, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644) // #nosec G304 -- name was validated to be inside the export directory
+	if err != nil {
+		return backup.NewError(backup.ErrIO, "failed to create restored clip", err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tempPath)
+		return backup.NewError(backup.ErrCorruption, "failed to extract clip", err)
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return backup.NewError(backup.ErrIO, "failed to close restored clip", err)
+	}
+	if err := os.Chtimes(tempPath, modTime, modTime); err != nil {
+		_ = os.Remove(tempPath)
+		return backup.NewError(backup.ErrIO, "failed to set clip modification time", err)
+	}
+	if err := os.Rename(tempPath, dest); err != nil {
+		_ = os.Remove(tempPath)
+		return backup.NewError(backup.ErrIO, "failed to move restored clip into place", err)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package sources
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// lockedClips is a LockedClipsProvider returning a fixed list of clips
+type lockedClips []string
+
+func (l lockedClips) GetLockedNotesClipPaths() ([]string, error) { return l, nil }
+
+// newClipsTestSource creates a clip source over a temporary export directory
+// containing the given files, all modified an hour ago
+func newClipsTestSource(t *testing.T, store LockedClipsProvider, lockedOnly bool, files ...string) (source *ClipsSource, root string) {
+	t.Helper()
+	root = t.TempDir()
+	past := time.Now().Add(-time.Hour)
+	for _, name := range files {
+		writeClipFile(t, root, name, past)
+	}
+
+	settings := &conf.Settings{}
+	settings.Realtime.Audio.Export.Path = root
+	settings.Backup.Clips.Enabled = true
+	settings.Backup.Clips.LockedOnly = lockedOnly
+	return NewClipsSource(settings, store, nil), root
+}
+
+func writeClipFile(t *testing.T, root, name string, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("clip "+name), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readClipArchive returns the sorted names of the clips in a clip archive
+func readClipArchive(t *testing.T, r io.ReadCloser) []string {
+	t.Helper()
+	defer r.Close()
+	tr := tar.NewReader(r)
+	if _, err := readClipsManifest(tr); err != nil {
+		t.Fatalf("readClipsManifest() error = %v", err)
+	}
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading clip archive: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestClipsSource_BackupIncrement(t *testing.T) {
+	source, root := newClipsTestSource(t, nil, false,
+		"Turdus merula/a.wav", "Parus major/b.wav", "c.wav.temp", ".hidden/d.wav")
+
+	r, state, err := source.BackupIncrement(context.Background(), &backup.SourceState{})
+	if err != nil {
+		t.Fatalf("BackupIncrement() error = %v", err)
+	}
+	want := []string{"Parus major/b.wav", "Turdus merula/a.wav"}
+	if got := readClipArchive(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("first backup contains %v, want %v", got, want)
+	}
+	if state.LastFileCount != 2 || state.TotalFiles != 2 || state.Watermark.IsZero() {
+		t.Errorf("unexpected state after first backup: %+v", state)
+	}
+
+	if _, _, err := source.BackupIncrement(context.Background(), state); !errors.Is(err, backup.ErrNoChanges) {
+		t.Fatalf("BackupIncrement() without new clips error = %v, want ErrNoChanges", err)
+	}
+
+	writeClipFile(t, root, "Parus major/e.wav", time.Now().Add(time.Second))
+	r, next, err := source.BackupIncrement(context.Background(), state)
+	if err != nil {
+		t.Fatalf("BackupIncrement() error = %v", err)
+	}
+	if got := readClipArchive(t, r); !reflect.DeepEqual(got, []string{"Parus major/e.wav"}) {
+		t.Errorf("second backup contains %v, want only the new clip", got)
+	}
+	if next.TotalFiles != 3 {
+		t.Errorf("TotalFiles = %d, want 3", next.TotalFiles)
+	}
+}
+
+func TestClipsSource_LockedOnly(t *testing.T) {
+	store := lockedClips{"clips/a/locked.wav"}
+	source, _ := newClipsTestSource(t, &store, true, "a/locked.wav", "a/unlocked.wav", "b/later.wav")
+
+	r, state, err := source.BackupIncrement(context.Background(), &backup.SourceState{})
+	if err != nil {
+		t.Fatalf("BackupIncrement() error = %v", err)
+	}
+	if got := readClipArchive(t, r); !reflect.DeepEqual(got, []string{"a/locked.wav"}) {
+		t.Errorf("first backup contains %v, want only the locked clip", got)
+	}
+	if !reflect.DeepEqual(state.ArchivedLocked, []string{"a/locked.wav"}) {
+		t.Errorf("ArchivedLocked = %v", state.ArchivedLocked)
+	}
+
+	// A clip locked after the first backup is included although it is older
+	store = append(store, "later.wav")
+	r, state, err = source.BackupIncrement(context.Background(), state)
+	if err != nil {
+		t.Fatalf("BackupIncrement() error = %v", err)
+	}
+	if got := readClipArchive(t, r); !reflect.DeepEqual(got, []string{"b/later.wav"}) {
+		t.Errorf("second backup contains %v, want the newly locked clip", got)
+	}
+	if !reflect.DeepEqual(state.ArchivedLocked, []string{"a/locked.wav", "b/later.wav"}) {
+		t.Errorf("ArchivedLocked = %v", state.ArchivedLocked)
+	}
+
+	if _, _, err := source.BackupIncrement(context.Background(), state); !errors.Is(err, backup.ErrNoChanges) {
+		t.Errorf("BackupIncrement() error = %v, want ErrNoChanges", err)
+	}
+
+	withoutStore, _ := newClipsTestSource(t, nil, true, "a/locked.wav")
+	if _, _, err := withoutStore.BackupIncrement(context.Background(), &backup.SourceState{}); err == nil {
+		t.Error("BackupIncrement() of locked clips without a datastore succeeded")
+	}
+}
+
+func TestClipsSource_Restore(t *testing.T) {
+	source, _ := newClipsTestSource(t, nil, false, "a/one.wav", "b/two.wav")
+	r, err := source.Backup(context.Background())
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	dataPath := filepath.Join(t.TempDir(), "backup.clips")
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dataPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.VerifyBackupData(dataPath); err != nil {
+		t.Fatalf("VerifyBackupData() error = %v", err)
+	}
+
+	// Restore into a directory that already contains one of the clips
+	restored, restoreRoot := newClipsTestSource(t, nil, false)
+	if err := os.MkdirAll(filepath.Join(restoreRoot, "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(restoreRoot, "a", "one.wav")
+	if err := os.WriteFile(existing, []byte("existing"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(existing, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.RestoreBackupData(context.Background(), dataPath, ""); err != nil {
+		t.Fatalf("RestoreBackupData() error = %v", err)
+	}
+
+	if got, _ := os.ReadFile(existing); string(got) != "existing" {
+		t.Errorf("existing clip was overwritten with %q", got)
+	}
+	got, err := os.ReadFile(filepath.Join(restoreRoot, "b", "two.wav"))
+	if err != nil || string(got) != "clip b/two.wav" {
+		t.Errorf("restored clip = %q, %v", got, err)
+	}
+
+	// Restored clips keep their time and are not backed up again
+	if _, _, err := restored.BackupIncrement(context.Background(), &backup.SourceState{Watermark: time.Now().Add(-time.Minute)}); !errors.Is(err, backup.ErrNoChanges) {
+		t.Errorf("BackupIncrement() after restore error = %v, want ErrNoChanges", err)
+	}
+}
+
+func TestClipsSource_VerifyBackupData(t *testing.T) {
+	source, _ := newClipsTestSource(t, nil, false)
+
+	writeArchive := func(t *testing.T, manifest bool, names ...string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "backup.clips")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		tw := tar.NewWriter(f)
+		if manifest {
+			body := []byte(`{"version":1,"files":1}`)
+			if err := tw.WriteHeader(&tar.Header{Name: clipsManifestName, Mode: 0o644, Size: int64(len(body))}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, name := range names {
+			if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 1}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte("x")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	testCases := []struct {
+		name    string
+		path    func(t *testing.T) string
+		wantErr bool
+	}{
+		{name: "Valid archive", path: func(t *testing.T) string { return writeArchive(t, true, "a/one.wav") }},
+		{name: "Missing manifest", path: func(t *testing.T) string { return writeArchive(t, false, "a/one.wav") }, wantErr: true},
+		{name: "Path traversal", path: func(t *testing.T) string { return writeArchive(t, true, "../one.wav") }, wantErr: true},
+		{name: "Absolute path", path: func(t *testing.T) string { return writeArchive(t, true, "/tmp/one.wav") }, wantErr: true},
+		{name: "Missing file", path: func(t *testing.T) string { return writeArchive(t, true) }, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := source.VerifyBackupData(tc.path(t))
+			if tc.wantErr && !backup.IsCorruptionError(err) {
+				t.Errorf("VerifyBackupData() error = %v, want corruption error", err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("VerifyBackupData() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package sources
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// RegisterConfigured registers a backup source for each enabled database
+// output, and the clip source if clip backups are enabled, with the manager.
+// The store provides the clips of locked notes and may be nil when backups are
+// only restored. Sources that fail to register are returned as errors and
+// skipped.
+func RegisterConfigured(manager *backup.Manager, settings *conf.Settings, store LockedClipsProvider, logger *slog.Logger) []error {
+	var sources []backup.Source
+	if settings.Output.SQLite.Enabled {
+		sources = append(sources, NewSQLiteSource(settings, logger))
+	}
+	if settings.Output.MySQL.Enabled {
+		sources = append(sources, NewMySQLSource(settings, logger))
+	}
+	if settings.Backup.Clips.Enabled {
+		sources = append(sources, NewClipsSource(settings, store, logger))
+	}
+
+	var errs []error
+	for _, source := range sources {
+		if err := manager.RegisterSource(source); err != nil {
+			errs = append(errs, fmt.Errorf("%s source: %w", source.Name(), err))
+		}
+	}
+	return errs
+}
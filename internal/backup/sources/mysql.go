@@ -0,0 +1,700 @@
+package sources
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	// mysqlDumpHeader is the first line of dumps created by MySQLSource
+	mysqlDumpHeader = "-- BirdNET-Go MySQL dump"
+	// mysqlDumpTrailer is the last line of complete dumps
+	mysqlDumpTrailer = "-- Dump completed"
+	// mysqlMaxInsertSize limits the size of INSERT statements, well below the
+	// 4MB max_allowed_packet default of older servers
+	mysqlMaxInsertSize = 512 * 1024
+	// mysqlMaxStatementSize limits statements read from a dump
+	mysqlMaxStatementSize = 64 * 1024 * 1024
+)
+
+// mysqlBackupTables lists the BirdNET-Go tables in dump order, the tables
+// migrated by the datastore. Tables that don't exist yet are skipped.
+var mysqlBackupTables = datastore.TableNames()
+
+// mysqlDumpSettings are the session settings written before the tables of a dump
+var mysqlDumpSettings = []string{
+	"SET NAMES utf8mb4",
+	"SET SESSION sql_mode = 'NO_AUTO_VALUE_ON_ZERO'",
+	"SET SESSION time_zone = '+00:00'",
+	"SET FOREIGN_KEY_CHECKS = 0",
+	"SET UNIQUE_CHECKS = 0",
+}
+
+// mysqlDumpResetSettings are the session settings written after the tables of a dump
+var mysqlDumpResetSettings = []string{
+	"SET FOREIGN_KEY_CHECKS = 1",
+	"SET UNIQUE_CHECKS = 1",
+}
+
+// mysqlDumpStatements lists the statement prefixes allowed in a dump besides
+// the exact session settings, so a tampered backup can't run arbitrary SQL
+var mysqlDumpStatements = []string{
+	"DROP TABLE IF EXISTS `",
+	"CREATE TABLE `",
+	"INSERT INTO `",
+}
+
+// MySQLSource implements the backup.Source and backup.Restorer interfaces for
+// MySQL databases. Backups are logical SQL dumps taken from a consistent
+// snapshot, so the database stays usable while they are created.
+type MySQLSource struct {
+	config *conf.Settings
+	logger *slog.Logger
+}
+
+// NewMySQLSource creates a new MySQL backup source
+func NewMySQLSource(config *conf.Settings, logger *slog.Logger) *MySQLSource {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MySQLSource{
+		config: config,
+		logger: logger.With("backup_source", "mysql"),
+	}
+}
+
+// Name returns the name of this source
+func (s *MySQLSource) Name() string {
+	return s.config.Output.MySQL.Database
+}
+
+// RestoreLocation returns the database backups are restored to
+func (s *MySQLSource) RestoreLocation() string {
+	mysqlConfig := s.config.Output.MySQL
+	return fmt.Sprintf("mysql://%s/%s", net.JoinHostPort(mysqlConfig.Host, mysqlConfig.Port), mysqlConfig.Database)
+}
+
+// validateConfig checks if MySQL output is enabled and properly configured
+func (s *MySQLSource) validateConfig() error {
+	mysqlConfig := s.config.Output.MySQL
+	if !mysqlConfig.Enabled {
+		return errors.Newf("mysql is not enabled").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_config").
+			Build()
+	}
+	if mysqlConfig.Database == "" || mysqlConfig.Host == "" {
+		return errors.Newf("mysql host and database must be configured").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_config").
+			Build()
+	}
+	return nil
+}
+
+// openDatabase opens and verifies a connection to the configured database
+func (s *MySQLSource) openDatabase(ctx context.Context) (*sql.DB, error) {
+	mysqlConfig := s.config.Output.MySQL
+
+	cfg := mysql.NewConfig()
+	cfg.User = mysqlConfig.Username
+	cfg.Passwd = mysqlConfig.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(mysqlConfig.Host, mysqlConfig.Port)
+	cfg.DBName = mysqlConfig.Database
+	// Values are read as text without time parsing so they are dumped as the server formats them
+	cfg.Params = map[string]string{"charset": "utf8mb4"}
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "open_database").
+			Context("database", mysqlConfig.Database).
+			Build()
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		// Best effort close on error path
+		_ = db.Close()
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "verify_database_connection").
+			Context("database", mysqlConfig.Database).
+			Build()
+	}
+
+	return db, nil
+}
+
+// withDatabase executes a function with a connection to the configured database
+func (s *MySQLSource) withDatabase(ctx context.Context, fn func(*sql.Conn) error) error {
+	db, err := s.openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			s.logger.Warn("Failed to close database connection", "error", err)
+		}
+	}()
+
+	// Session settings of the dump and restore only apply to a single connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "get_database_connection").
+			Build()
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			s.logger.Warn("Failed to close database connection", "error", err)
+		}
+	}()
+
+	return fn(conn)
+}
+
+// Backup performs a streaming dump of the MySQL database
+func (s *MySQLSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	start := time.Now()
+	s.logger.Info("Starting MySQL streaming backup operation")
+
+	if err := s.validateConfig(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	// Verify the database is reachable before streaming
+	db, err := s.openDatabase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database verification failed: %w", err)
+	}
+	if err := db.Close(); err != nil {
+		s.logger.Warn("Failed to close database connection", "error", err)
+	}
+
+	// Create pipe for streaming
+	pr, pw := io.Pipe()
+
+	// Start backup in a goroutine
+	go func() {
+		backupErr := s.withDatabase(ctx, func(conn *sql.Conn) error {
+			return s.dump(ctx, conn, pw)
+		})
+
+		if backupErr != nil {
+			s.logger.Error("MySQL backup failed in goroutine", "error", backupErr, "duration_ms", time.Since(start).Milliseconds())
+			if closeErr := pw.CloseWithError(backupErr); closeErr != nil {
+				s.logger.Warn("Error closing pipe writer with error", "error", closeErr)
+			}
+			return
+		}
+
+		if err := pw.Close(); err != nil {
+			s.logger.Warn("Error closing pipe writer in goroutine", "error", err)
+		}
+		s.logger.Info("MySQL backup completed successfully", "duration_ms", time.Since(start).Milliseconds())
+	}()
+
+	return pr, nil
+}
+
+// Validate checks if the source configuration is valid
+func (s *MySQLSource) Validate() error {
+	if err := s.validateConfig(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db, err := s.openDatabase(ctx)
+	if err != nil {
+		s.logger.Error("MySQL source validation failed", "database", s.config.Output.MySQL.Database, "error", err)
+		return err
+	}
+	if err := db.Close(); err != nil {
+		s.logger.Warn("Failed to close database connection", "error", err)
+	}
+
+	s.logger.Info("MySQL source validation successful", "database", s.config.Output.MySQL.Database)
+	return nil
+}
+
+// dump writes a logical dump of the BirdNET-Go tables to w. All tables are
+// read in a single read-only transaction with a consistent snapshot.
+func (s *MySQLSource) dump(ctx context.Context, conn *sql.Conn, w io.Writer) error {
+	// TIMESTAMP values are dumped in UTC, the dump sets the same time zone when loaded
+	if _, err := conn.ExecContext(ctx, "SET SESSION time_zone = '+00:00'"); err != nil {
+		return dumpError(err, "set_time_zone", "")
+	}
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return dumpError(err, "set_isolation_level", "")
+	}
+	if _, err := conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY"); err != nil {
+		return dumpError(err, "start_snapshot", "")
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "ROLLBACK"); err != nil {
+			s.logger.Warn("Failed to end snapshot transaction", "error", err)
+		}
+	}()
+
+	tables, err := existingTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(w, 64*1024)
+	fmt.Fprintf(bw, "%s\n-- Database: %s\n-- Created: %s\n\n", mysqlDumpHeader,
+		s.config.Output.MySQL.Database, time.Now().UTC().Format(time.RFC3339))
+	for _, setting := range mysqlDumpSettings {
+		bw.WriteString(setting + ";\n")
+	}
+
+	for _, table := range mysqlBackupTables {
+		if !tables[table] {
+			s.logger.Debug("Skipping missing table", "table", table)
+			continue
+		}
+		rowCount, err := s.dumpTable(ctx, conn, bw, table)
+		if err != nil {
+			return err
+		}
+		s.logger.Debug("Dumped table", "table", table, "rows", rowCount)
+	}
+
+	bw.WriteString("\n")
+	for _, setting := range mysqlDumpResetSettings {
+		bw.WriteString(setting + ";\n")
+	}
+	bw.WriteString(mysqlDumpTrailer + "\n")
+
+	if err := bw.Flush(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_dump").
+			Build()
+	}
+	return nil
+}
+
+// existingTables returns the base tables of the current database
+func existingTables(ctx context.Context, conn *sql.Conn) (map[string]bool, error) {
+	rows, err := conn.QueryContext(ctx,
+		"SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'")
+	if err != nil {
+		return nil, dumpError(err, "list_tables", "")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tables := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, dumpError(err, "list_tables", "")
+		}
+		tables[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dumpError(err, "list_tables", "")
+	}
+	return tables, nil
+}
+
+// dumpTable writes the definition and rows of a table and returns the number of rows
+func (s *MySQLSource) dumpTable(ctx context.Context, conn *sql.Conn, w *bufio.Writer, table string) (int, error) {
+	var name, createStatement string
+	if err := conn.QueryRowContext(ctx, "SHOW CREATE TABLE `"+table+"`").Scan(&name, &createStatement); err != nil {
+		return 0, dumpError(err, "show_create_table", table)
+	}
+	fmt.Fprintf(w, "\n-- Table `%s`\nDROP TABLE IF EXISTS `%s`;\n%s;\n", table, table, createStatement)
+
+	rows, err := conn.QueryContext(ctx, "SELECT * FROM `"+table+"`")
+	if err != nil {
+		return 0, dumpError(err, "select_rows", table)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, dumpError(err, "get_column_types", table)
+	}
+	columns := make([]string, len(columnTypes))
+	kinds := make([]mysqlValueKind, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = "`" + strings.ReplaceAll(ct.Name(), "`", "``") + "`"
+		kinds[i] = mysqlKindOf(ct.DatabaseTypeName())
+	}
+	insertPrefix := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES ", table, strings.Join(columns, ", "))
+
+	values := make([]sql.RawBytes, len(columns))
+	scanArgs := make([]any, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	var statement bytes.Buffer
+	rowCount := 0
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return rowCount, dumpError(err, "scan_row", table)
+		}
+
+		if statement.Len() == 0 {
+			statement.WriteString(insertPrefix)
+		} else {
+			statement.WriteString(",")
+		}
+		statement.WriteString("(")
+		for i, value := range values {
+			if i > 0 {
+				statement.WriteString(",")
+			}
+			writeMySQLValue(&statement, value, kinds[i])
+		}
+		statement.WriteString(")")
+		rowCount++
+
+		if statement.Len() >= mysqlMaxInsertSize {
+			statement.WriteString(";\n")
+			if _, err := w.Write(statement.Bytes()); err != nil {
+				return rowCount, dumpError(err, "write_rows", table)
+			}
+			statement.Reset()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return rowCount, dumpError(err, "read_rows", table)
+	}
+	if statement.Len() > 0 {
+		statement.WriteString(";\n")
+		if _, err := w.Write(statement.Bytes()); err != nil {
+			return rowCount, dumpError(err, "write_rows", table)
+		}
+	}
+
+	return rowCount, nil
+}
+
+// dumpError builds the error of a failed dump operation
+func dumpError(err error, operation, table string) error {
+	builder := errors.New(err).
+		Component("backup").
+		Category(errors.CategoryDatabase).
+		Context("operation", operation)
+	if table != "" {
+		builder = builder.Context("table", table)
+	}
+	return builder.Build()
+}
+
+// mysqlValueKind determines how column values are written to a dump
+type mysqlValueKind int
+
+const (
+	mysqlString mysqlValueKind = iota
+	mysqlNumber
+	mysqlBinary
+)
+
+// mysqlKindOf returns the value kind of a column database type name
+func mysqlKindOf(typeName string) mysqlValueKind {
+	switch {
+	case strings.Contains(typeName, "INT"), typeName == "DECIMAL", typeName == "FLOAT", typeName == "DOUBLE", typeName == "YEAR":
+		return mysqlNumber
+	case strings.Contains(typeName, "BLOB"), strings.Contains(typeName, "BINARY"), typeName == "BIT", typeName == "GEOMETRY":
+		return mysqlBinary
+	default:
+		return mysqlString
+	}
+}
+
+// writeMySQLValue writes a column value as an SQL literal
+func writeMySQLValue(buf *bytes.Buffer, value sql.RawBytes, kind mysqlValueKind) {
+	switch {
+	case value == nil:
+		buf.WriteString("NULL")
+	case kind == mysqlNumber:
+		buf.Write(value)
+	case kind == mysqlBinary:
+		if len(value) == 0 {
+			buf.WriteString("''")
+			return
+		}
+		buf.WriteString("X'")
+		buf.WriteString(hex.EncodeToString(value))
+		buf.WriteString("'")
+	default:
+		writeMySQLString(buf, value)
+	}
+}
+
+// writeMySQLString writes a quoted and escaped string literal. Line breaks are
+// escaped, so literals never span lines.
+func writeMySQLString(buf *bytes.Buffer, value []byte) {
+	buf.WriteByte('\'')
+	for _, c := range value {
+		switch c {
+		case 0:
+			buf.WriteString(`\0`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\'':
+			buf.WriteString(`\'`)
+		case '"':
+			buf.WriteString(`\"`)
+		case 0x1a:
+			buf.WriteString(`\Z`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('\'')
+}
+
+// mysqlStatementReader splits a dump into statements, ignoring semicolons
+// in quoted strings and identifiers and skipping comment lines
+type mysqlStatementReader struct {
+	r *bufio.Reader
+}
+
+func newMySQLStatementReader(r io.Reader) *mysqlStatementReader {
+	return &mysqlStatementReader{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Next returns the next statement without the terminating semicolon, or
+// io.EOF after the last statement
+func (sr *mysqlStatementReader) Next() (string, error) {
+	var stmt bytes.Buffer
+	var quote byte
+	escaped := false
+	for {
+		c, err := sr.r.ReadByte()
+		if err == io.EOF {
+			if strings.TrimSpace(stmt.String()) != "" {
+				return "", fmt.Errorf("unterminated statement at end of dump")
+			}
+			return "", io.EOF
+		}
+		if err != nil {
+			return "", err
+		}
+
+		// Comment lines between statements
+		if quote == 0 && c == '-' && strings.TrimSpace(stmt.String()) == "" {
+			if next, err := sr.r.Peek(1); err == nil && next[0] == '-' {
+				if _, err := sr.r.ReadString('\n'); err != nil && err != io.EOF {
+					return "", err
+				}
+				stmt.Reset()
+				continue
+			}
+		}
+
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && c == '\\' && quote != '`':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"' || c == '`'):
+			quote = c
+		case quote == 0 && c == ';':
+			return strings.TrimSpace(stmt.String()), nil
+		}
+
+		if stmt.Len() >= mysqlMaxStatementSize {
+			return "", fmt.Errorf("statement exceeds %d bytes", mysqlMaxStatementSize)
+		}
+		stmt.WriteByte(c)
+	}
+}
+
+// validateDumpStatement checks that a statement may appear in a dump
+func validateDumpStatement(stmt string) error {
+	if slices.Contains(mysqlDumpSettings, stmt) || slices.Contains(mysqlDumpResetSettings, stmt) {
+		return nil
+	}
+	for _, prefix := range mysqlDumpStatements {
+		if strings.HasPrefix(stmt, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected statement in dump: %.40q", stmt)
+}
+
+// VerifyBackupData checks that the file at path is a complete dump created by this source
+func (s *MySQLSource) VerifyBackupData(path string) error {
+	secureOp := backup.NewSecureFileOp("backup")
+	file, _, err := secureOp.SecureOpen(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, len(mysqlDumpHeader))
+	if _, err := io.ReadFull(file, header); err != nil || string(header) != mysqlDumpHeader {
+		return backup.NewError(backup.ErrCorruption, "backup data is not a MySQL dump", err)
+	}
+
+	// Every statement is checked and the dump must end with the trailer
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return backup.NewError(backup.ErrIO, "failed to read MySQL dump", err)
+	}
+	sr := newMySQLStatementReader(file)
+	count := 0
+	for {
+		stmt, err := sr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return backup.NewError(backup.ErrCorruption, "invalid MySQL dump", err)
+		}
+		if err := validateDumpStatement(stmt); err != nil {
+			return backup.NewError(backup.ErrCorruption, "invalid MySQL dump", err)
+		}
+		count++
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return backup.NewError(backup.ErrIO, "failed to read MySQL dump", err)
+	}
+	tail := make([]byte, min(info.Size(), int64(len(mysqlDumpTrailer)+1)))
+	if _, err := file.ReadAt(tail, info.Size()-int64(len(tail))); err != nil {
+		return backup.NewError(backup.ErrIO, "failed to read MySQL dump", err)
+	}
+	if strings.TrimSpace(string(tail)) != mysqlDumpTrailer || count == 0 {
+		return backup.NewError(backup.ErrCorruption, "MySQL dump is incomplete", nil)
+	}
+
+	return nil
+}
+
+// RestoreBackupData saves the current database to previousPath and loads the
+// dump at path. If loading fails the saved database is loaded back.
+func (s *MySQLSource) RestoreBackupData(ctx context.Context, path, previousPath string) error {
+	if err := s.validateConfig(); err != nil {
+		return err
+	}
+	if err := s.VerifyBackupData(path); err != nil {
+		return err
+	}
+
+	return s.withDatabase(ctx, func(conn *sql.Conn) error {
+		if previousPath != "" {
+			if err := s.dumpToFile(ctx, conn, previousPath); err != nil {
+				return err
+			}
+			s.logger.Info("Saved current database before restore", "path", previousPath)
+		}
+
+		start := time.Now()
+		restoreErr := s.loadDump(ctx, conn, path)
+		if restoreErr == nil {
+			s.logger.Info("MySQL database restored", "duration_ms", time.Since(start).Milliseconds())
+			return nil
+		}
+		if previousPath == "" {
+			return restoreErr
+		}
+
+		s.logger.Error("MySQL restore failed, loading previous database", "error", restoreErr)
+		if err := s.loadDump(context.Background(), conn, previousPath); err != nil {
+			return backup.NewError(backup.ErrDatabase,
+				fmt.Sprintf("restore failed and the previous database could not be loaded back, it is saved in %s", previousPath), err)
+		}
+		return restoreErr
+	})
+}
+
+// dumpToFile writes a dump of the database to path
+func (s *MySQLSource) dumpToFile(ctx context.Context, conn *sql.Conn, path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600) // #nosec G304 -- path is built by the backup manager
+	if err != nil {
+		return backup.NewError(backup.ErrIO, "failed to create pre-restore dump", err)
+	}
+
+	dumpErr := s.dump(ctx, conn, file)
+	if err := file.Close(); err != nil && dumpErr == nil {
+		dumpErr = backup.NewError(backup.ErrIO, "failed to close pre-restore dump", err)
+	}
+	if dumpErr != nil {
+		_ = os.Remove(path)
+		return dumpErr
+	}
+	return nil
+}
+
+// loadDump executes the statements of a dump
+func (s *MySQLSource) loadDump(ctx context.Context, conn *sql.Conn, path string) error {
+	secureOp := backup.NewSecureFileOp("backup")
+	file, _, err := secureOp.SecureOpen(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// Restore session defaults even if loading stops halfway
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SET FOREIGN_KEY_CHECKS = 1"); err != nil {
+			s.logger.Warn("Failed to re-enable foreign key checks", "error", err)
+		}
+	}()
+
+	sr := newMySQLStatementReader(file)
+	for {
+		stmt, err := sr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return backup.NewError(backup.ErrCorruption, "invalid MySQL dump", err)
+		}
+		if err := validateDumpStatement(stmt); err != nil {
+			return backup.NewError(backup.ErrCorruption, "invalid MySQL dump", err)
+		}
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return backup.NewError(backup.ErrDatabase, fmt.Sprintf("failed to execute dump statement %.40q", stmt), err)
+		}
+	}
+}
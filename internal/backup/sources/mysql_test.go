@@ -0,0 +1,132 @@
+package sources
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestWriteMySQLValue(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value sql.RawBytes
+		kind  mysqlValueKind
+		want  string
+	}{
+		{name: "NULL", value: nil, kind: mysqlString, want: "NULL"},
+		{name: "Number", value: sql.RawBytes("0.75"), kind: mysqlNumber, want: "0.75"},
+		{name: "Empty string", value: sql.RawBytes(""), kind: mysqlString, want: "''"},
+		{name: "Escaped string", value: sql.RawBytes("it's a\n\"test\";\\\x00"), kind: mysqlString, want: `'it\'s a\n\"test\";\\\0'`},
+		{name: "Binary", value: sql.RawBytes{0x00, 0xff}, kind: mysqlBinary, want: "X'00ff'"},
+		{name: "Empty binary", value: sql.RawBytes{}, kind: mysqlBinary, want: "''"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeMySQLValue(&buf, tc.value, tc.kind)
+			if buf.String() != tc.want {
+				t.Errorf("writeMySQLValue() = %s, want %s", buf.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestMySQLKindOf(t *testing.T) {
+	for typeName, want := range map[string]mysqlValueKind{
+		"UNSIGNED BIGINT": mysqlNumber,
+		"DOUBLE":          mysqlNumber,
+		"DECIMAL":         mysqlNumber,
+		"VARCHAR":         mysqlString,
+		"DATETIME":        mysqlString,
+		"LONGTEXT":        mysqlString,
+		"JSON":            mysqlString,
+		"LONGBLOB":        mysqlBinary,
+		"VARBINARY":       mysqlBinary,
+		"BIT":             mysqlBinary,
+	} {
+		if got := mysqlKindOf(typeName); got != want {
+			t.Errorf("mysqlKindOf(%q) = %d, want %d", typeName, got, want)
+		}
+	}
+}
+
+func TestMySQLStatementReader(t *testing.T) {
+	dump := mysqlDumpHeader + "\n-- Table `notes`\n" +
+		"DROP TABLE IF EXISTS `notes`;\n" +
+		"CREATE TABLE `notes` (\n  `id` bigint NOT NULL,\n  `name` varchar(10) DEFAULT ';'\n) ENGINE=InnoDB;\n" +
+		"INSERT INTO `notes` (`id`, `name`) VALUES (1,'a;b'),(2,'it\\'s; -- not a comment');\n" +
+		mysqlDumpTrailer + "\n"
+
+	sr := newMySQLStatementReader(strings.NewReader(dump))
+	var statements []string
+	for {
+		stmt, err := sr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		statements = append(statements, stmt)
+	}
+
+	want := []string{
+		"DROP TABLE IF EXISTS `notes`",
+		"CREATE TABLE `notes` (\n  `id` bigint NOT NULL,\n  `name` varchar(10) DEFAULT ';'\n) ENGINE=InnoDB",
+		"INSERT INTO `notes` (`id`, `name`) VALUES (1,'a;b'),(2,'it\\'s; -- not a comment')",
+	}
+	if len(statements) != len(want) {
+		t.Fatalf("got %d statements %q, want %d", len(statements), statements, len(want))
+	}
+	for i := range want {
+		if statements[i] != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, statements[i], want[i])
+		}
+	}
+
+	if _, err := newMySQLStatementReader(strings.NewReader("INSERT INTO `notes` VALUES ('unterminated")).Next(); err == nil || err == io.EOF {
+		t.Errorf("Next() of unterminated statement error = %v, want error", err)
+	}
+}
+
+func TestMySQLSource_VerifyBackupData(t *testing.T) {
+	source := NewMySQLSource(&conf.Settings{}, nil)
+	valid := mysqlDumpHeader + "\nSET NAMES utf8mb4;\nINSERT INTO `notes` (`id`) VALUES (1);\n" + mysqlDumpTrailer + "\n"
+
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "Valid dump", data: valid},
+		{name: "SQLite database", data: "SQLite format 3\x00", wantErr: true},
+		{name: "Truncated dump", data: strings.TrimSuffix(valid, mysqlDumpTrailer+"\n"), wantErr: true},
+		{name: "Unexpected statement", data: mysqlDumpHeader + "\nDROP DATABASE birdnet;\n" + mysqlDumpTrailer + "\n", wantErr: true},
+		{name: "Unexpected setting", data: mysqlDumpHeader + "\nSET GLOBAL general_log = 1;\n" + mysqlDumpTrailer + "\n", wantErr: true},
+		{name: "Password change", data: mysqlDumpHeader + "\nSET PASSWORD = 'x';\n" + mysqlDumpTrailer + "\n", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "backup.birdnet")
+			if err := os.WriteFile(path, []byte(tc.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			err := source.VerifyBackupData(path)
+			if tc.wantErr && !backup.IsCorruptionError(err) {
+				t.Errorf("VerifyBackupData() error = %v, want corruption error", err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("VerifyBackupData() error = %v", err)
+			}
+		})
+	}
+}
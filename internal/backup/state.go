@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,10 +16,21 @@ import (
 // BackupState represents the persistent state of the backup system
 type BackupState struct {
 	LastUpdate time.Time                `json:"last_update"`
-	Schedules  map[string]ScheduleState `json:"schedules"` // Key is "daily" or "weekly-{weekday}"
+	Schedules  map[string]ScheduleState `json:"schedules"` // Key is "daily" or "weekly-{weekday}", prefixed with "{sources}-" for source schedules
 	Targets    map[string]TargetState   `json:"targets"`   // Key is target name
 	MissedRuns []MissedBackup           `json:"missed_runs"`
-	Stats      map[string]BackupStats   `json:"stats"` // Key is target name
+	Stats      map[string]BackupStats   `json:"stats"`             // Key is target name
+	Sources    map[string]SourceState   `json:"sources,omitempty"` // Key is the name of an incremental source
+}
+
+// SourceState represents the state of an incremental backup source
+type SourceState struct {
+	LastSuccessful time.Time `json:"last_successful"`
+	LastBackupID   string    `json:"last_backup_id"`
+	Watermark      time.Time `json:"watermark"`                 // Files modified before this were included in earlier backups
+	LastFileCount  int       `json:"last_file_count"`           // Files in the last backup
+	TotalFiles     int       `json:"total_files"`               // Files in all backups
+	ArchivedLocked []string  `json:"archived_locked,omitempty"` // Clips of locked notes included in earlier backups
 }
 
 // ScheduleState represents the state of a backup schedule
@@ -47,6 +59,7 @@ type MissedBackup struct {
 	Reason        string    `json:"reason"`
 	IsWeekly      bool      `json:"is_weekly"`
 	Weekday       string    `json:"weekday,omitempty"`
+	Sources       []string  `json:"sources,omitempty"`
 }
 
 // StateManager handles persistence of backup states
@@ -82,6 +95,7 @@ func NewStateManager(logger *slog.Logger) (*StateManager, error) {
 			Schedules:  make(map[string]ScheduleState),
 			Targets:    make(map[string]TargetState),
 			Stats:      make(map[string]BackupStats),
+			Sources:    make(map[string]SourceState),
 			MissedRuns: make([]MissedBackup, 0),
 		},
 		logger: logger.With("service", "backup_statemanager"),
@@ -115,14 +129,12 @@ func (sm *StateManager) loadState() error {
 	return json.Unmarshal(data, sm.state)
 }
 
-// saveState saves the current backup state to disk
+// saveState saves the current backup state to disk. Callers must hold sm.mu.
 func (sm *StateManager) saveState() error {
 	start := time.Now()
 
-	// Copy state under lock
-	sm.mu.Lock()
+	// Copy state, the caller holds the lock
 	stateSnapshot := *sm.state
-	sm.mu.Unlock()
 
 	// Update last update time (on the snapshot)
 	stateSnapshot.LastUpdate = time.Now()
@@ -168,11 +180,7 @@ func (sm *StateManager) UpdateScheduleState(schedule *BackupSchedule, successful
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	// Generate schedule key
-	key := "daily"
-	if schedule.IsWeekly {
-		key = fmt.Sprintf("weekly-%s", schedule.Weekday)
-	}
+	key := scheduleKey(schedule)
 	sm.logger.Debug("Updating schedule state", "schedule_key", key, "successful", successful)
 
 	state := sm.state.Schedules[key]
@@ -204,6 +212,7 @@ func (sm *StateManager) AddMissedBackup(schedule *BackupSchedule, reason string)
 		ScheduledTime: schedule.NextRun,
 		Reason:        reason,
 		IsWeekly:      schedule.IsWeekly,
+		Sources:       schedule.Sources,
 	}
 	if schedule.IsWeekly {
 		missed.Weekday = schedule.Weekday.String()
@@ -244,6 +253,25 @@ func (sm *StateManager) UpdateTargetState(targetName string, metadata *Metadata,
 	return nil
 }
 
+// UpdateSourceState stores the state of an incremental source after a successful backup
+func (sm *StateManager) UpdateSourceState(sourceName string, state *SourceState) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.logger.Debug("Updating source state", "source_name", sourceName, "backup_id", state.LastBackupID, "watermark", state.Watermark)
+
+	// State files written before incremental sources existed have no sources map
+	if sm.state.Sources == nil {
+		sm.state.Sources = make(map[string]SourceState)
+	}
+	sm.state.Sources[sourceName] = *state
+
+	if err := sm.saveState(); err != nil {
+		sm.logger.Error("Failed to save state after updating source state", "source_name", sourceName, "error", err)
+		return err
+	}
+	return nil
+}
+
 // UpdateStats updates the backup statistics
 func (sm *StateManager) UpdateStats(stats map[string]BackupStats) error {
 	sm.mu.Lock()
@@ -264,12 +292,20 @@ func (sm *StateManager) GetScheduleState(schedule *BackupSchedule) ScheduleState
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
+	return sm.state.Schedules[scheduleKey(schedule)]
+}
+
+// scheduleKey returns the key of a schedule in the state file, prefixed with
+// the sources of schedules that only back up some sources
+func scheduleKey(schedule *BackupSchedule) string {
 	key := "daily"
 	if schedule.IsWeekly {
 		key = fmt.Sprintf("weekly-%s", schedule.Weekday)
 	}
-
-	return sm.state.Schedules[key]
+	if len(schedule.Sources) > 0 {
+		key = strings.Join(schedule.Sources, "+") + "-" + key
+	}
+	return key
 }
 
 // GetTargetState returns the state of a specific target
@@ -280,6 +316,17 @@ func (sm *StateManager) GetTargetState(targetName string) TargetState {
 	return sm.state.Targets[targetName]
 }
 
+// GetSourceState returns the state of an incremental source. The zero state
+// is returned if the source has no successful backup yet.
+func (sm *StateManager) GetSourceState(sourceName string) SourceState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	state := sm.state.Sources[sourceName]
+	state.ArchivedLocked = append([]string(nil), state.ArchivedLocked...)
+	return state
+}
+
 // GetMissedBackups returns all missed backups
 func (sm *StateManager) GetMissedBackups() []MissedBackup {
 	sm.mu.RLock()
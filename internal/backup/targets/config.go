@@ -0,0 +1,61 @@
+package targets
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// FromConfig creates the backup target described by a configured backup target
+func FromConfig(target *conf.BackupTarget, debug bool, logger *slog.Logger) (backup.Target, error) {
+	settings := make(map[string]any, len(target.Settings)+1)
+	for k, v := range target.Settings {
+		settings[k] = v
+	}
+	if _, ok := settings["debug"]; !ok {
+		settings["debug"] = debug
+	}
+
+	switch strings.ToLower(target.Type) {
+	case "local":
+		path, _ := settings["path"].(string)
+		localDebug, _ := settings["debug"].(bool)
+		return NewLocalTarget(LocalTargetConfig{Path: path, Debug: localDebug}, nil)
+	case "ftp":
+		return NewFTPTargetFromMap(settings)
+	case "sftp":
+		return NewSFTPTarget(settings, logger)
+	case "rsync":
+		return NewRsyncTarget(settings)
+	case "gdrive":
+		return NewGDriveTargetFromMap(settings)
+	case "s3":
+		return NewS3TargetFromMap(settings, logger)
+	default:
+		return nil, backup.NewError(backup.ErrConfig, fmt.Sprintf("unsupported backup target type: %s", target.Type), nil)
+	}
+}
+
+// RegisterConfigured registers all enabled targets from the backup configuration
+// with the manager. Targets that fail to initialize are returned as errors and
+// skipped, so one unreachable target does not disable the others.
+func RegisterConfigured(manager *backup.Manager, config *conf.BackupConfig, logger *slog.Logger) []error {
+	var errs []error
+	for i := range config.Targets {
+		if !config.Targets[i].Enabled {
+			continue
+		}
+		target, err := FromConfig(&config.Targets[i], config.Debug, logger)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s target: %w", config.Targets[i].Type, err))
+			continue
+		}
+		if err := manager.RegisterTarget(target); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -502,6 +502,43 @@ func (t *FTPTarget) Delete(ctx context.Context, target string) error {
 	})
 }
 
+// Restore downloads the archive of a backup from the FTP server to destPath
+func (t *FTPTarget) Restore(ctx context.Context, id, destPath string) error {
+	if err := backup.ValidateBackupID(id); err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		t.logger.Printf("🔄 FTP: Restoring backup %s from %s", id, t.config.Host)
+	}
+
+	return t.withRetry(ctx, func(conn *ftp.ServerConn) error {
+		var lastErr error
+		for _, name := range backup.ArchiveFileNames(id) {
+			resp, err := conn.Retr(path.Join(t.config.BasePath, name))
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			err = writeDownloadedFile(destPath, resp)
+			if closeErr := resp.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return backup.NewError(backup.ErrIO, "ftp: failed to download backup", err)
+			}
+
+			if t.config.Debug {
+				t.logger.Printf("✅ FTP: Successfully restored backup %s", id)
+			}
+			return nil
+		}
+
+		return backup.NewError(backup.ErrNotFound, fmt.Sprintf("ftp: backup %s not found", id), lastErr)
+	})
+}
+
 // Validate performs comprehensive validation of the FTP target
 func (t *FTPTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
@@ -731,6 +731,62 @@ func (t *GDriveTarget) Delete(ctx context.Context, id string) error {
 	})
 }
 
+// Restore downloads the archive of a backup to destPath. The ID may be either
+// the backup ID or the Drive file ID reported by List.
+func (t *GDriveTarget) Restore(ctx context.Context, id, destPath string) error {
+	if err := backup.ValidateBackupID(id); err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		t.logger.Printf("🔄 GDrive: Restoring backup %s", id)
+	}
+
+	return t.withRetry(ctx, func() error {
+		folderId, err := t.ensureFolder(ctx, t.config.BasePath)
+		if err != nil {
+			return err
+		}
+
+		// Look up the archive by name, falling back to the ID as a Drive file ID
+		fileId := id
+		for _, name := range backup.ArchiveFileNames(id) {
+			query := fmt.Sprintf("name='%s' and '%s' in parents and trashed=false", name, folderId)
+			files, err := t.service.Files.List().Q(query).Fields("files(id)").Context(ctx).Do()
+			if err != nil {
+				return backup.NewError(backup.ErrIO, "gdrive: failed to find backup", err)
+			}
+			if len(files.Files) > 0 {
+				fileId = files.Files[0].Id
+				break
+			}
+		}
+
+		resp, err := t.service.Files.Get(fileId).Context(ctx).Download()
+		if err != nil {
+			if isAPIErr, apiErr := t.isAPIError(err); isAPIErr {
+				return apiErr
+			}
+			return backup.NewError(backup.ErrIO, "gdrive: failed to download backup", err)
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.logger.Printf("gdrive: failed to close download: %v", err)
+			}
+		}()
+
+		if err := writeDownloadedFile(destPath, resp.Body); err != nil {
+			return backup.NewError(backup.ErrIO, "gdrive: failed to write backup", err)
+		}
+
+		if t.config.Debug {
+			t.logger.Printf("✅ GDrive: Successfully restored backup %s", id)
+		}
+
+		return nil
+	})
+}
+
 // Validate performs comprehensive validation of the Google Drive target
 func (t *GDriveTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
@@ -159,6 +159,15 @@ func copyFile(dst, src *os.File) error {
 	return err
 }
 
+// writeDownloadedFile atomically writes a downloaded backup archive to destPath
+func writeDownloadedFile(destPath string, r io.Reader) error {
+	return atomicWriteFile(destPath, "restore-*.tmp", filePermissions, func(tempFile *os.File) error {
+		buf := make([]byte, copyBufferSize)
+		_, err := io.CopyBuffer(tempFile, r, buf)
+		return err
+	})
+}
+
 // validatePath performs comprehensive path validation
 func validatePath(path string) error {
 	if path == "" {
@@ -602,6 +611,58 @@ func (t *LocalTarget) Delete(ctx context.Context, backupID string) error {
 	return nil
 }
 
+// Restore copies the archive of a backup to destPath
+func (t *LocalTarget) Restore(ctx context.Context, backupID, destPath string) error {
+	if err := backup.ValidateBackupID(backupID); err != nil {
+		return err
+	}
+
+	if t.debug {
+		t.logger.Printf("🔄 Restoring backup %s from local target", backupID)
+	}
+
+	for _, name := range backup.ArchiveFileNames(backupID) {
+		backupPath := filepath.Join(t.path, name)
+		if _, err := os.Stat(backupPath); err != nil {
+			continue
+		}
+
+		err := atomicWriteFile(destPath, "restore-*.tmp", filePermissions, func(tempFile *os.File) error {
+			secureOp := backup.NewSecureFileOp("backup")
+			srcFile, cleanPath, err := secureOp.SecureOpen(backupPath)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := srcFile.Close(); err != nil {
+					t.logger.Printf("local: failed to close backup file %s: %v", cleanPath, err)
+				}
+			}()
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return copyFile(tempFile, srcFile)
+		})
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "restore_backup").
+				Context("backup_id", backupID).
+				Context("path", backupPath).
+				Build()
+		}
+
+		if t.debug {
+			t.logger.Printf("✅ Successfully restored backup %s", backupID)
+		}
+		return nil
+	}
+
+	return backup.NewError(backup.ErrNotFound, fmt.Sprintf("local: backup %s not found", backupID), nil)
+}
+
 // Validate checks if the target configuration is valid
 func (t *LocalTarget) Validate() error {
 	// Check if path is absolute
@@ -38,6 +38,9 @@ type RsyncMetadataV1 struct {
 	ConfigHash  string    `json:"config_hash,omitempty"`
 	AppVersion  string    `json:"app_version,omitempty"`
 	Compression string    `json:"compression,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	Checksum    string    `json:"checksum,omitempty"`
+	Encrypted   bool      `json:"encrypted,omitempty"`
 }
 
 // RsyncTargetConfig holds configuration for the rsync target
@@ -345,6 +348,9 @@ func (t *RsyncTarget) Store(ctx context.Context, sourcePath string, metadata *ba
 		IsDaily:    metadata.IsDaily,
 		ConfigHash: metadata.ConfigHash,
 		AppVersion: metadata.AppVersion,
+		ID:         metadata.ID,
+		Checksum:   metadata.Checksum,
+		Encrypted:  metadata.Encrypted,
 	}
 
 	// Marshal metadata
@@ -691,6 +697,9 @@ func (t *RsyncTarget) List(ctx context.Context) ([]backup.BackupInfo, error) {
 				IsDaily:    metadata.IsDaily,
 				ConfigHash: metadata.ConfigHash,
 				AppVersion: metadata.AppVersion,
+				ID:         metadata.ID,
+				Checksum:   metadata.Checksum,
+				Encrypted:  metadata.Encrypted,
 			},
 			Target: t.Name(),
 		}
@@ -785,6 +794,62 @@ func (t *RsyncTarget) Delete(ctx context.Context, target string) error {
 	return nil
 }
 
+// Restore downloads the archive of a backup from the remote server to destPath
+func (t *RsyncTarget) Restore(ctx context.Context, id, destPath string) error {
+	if err := backup.ValidateBackupID(id); err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		fmt.Printf("🔄 Rsync: Restoring backup %s from %s\n", id, t.config.Host)
+	}
+
+	cleanBasePath, err := t.sanitizePath(t.config.BasePath)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, name := range backup.ArchiveFileNames(id) {
+		cleanName, err := t.sanitizePath(name)
+		if err != nil {
+			return err
+		}
+
+		err = t.withRetry(ctx, func() error {
+			args := []string{
+				"-a",                  // Archive mode
+				"--protect-args",      // Protect special characters
+				"--timeout=300",       // Connection timeout
+				"--checksum",          // Verify checksums
+				"-e", t.buildSSHCmd(), // SSH command with custom port and security options
+			}
+
+			source := fmt.Sprintf("%s@%s:%s/%s",
+				t.config.Username,
+				t.config.Host,
+				cleanBasePath,
+				cleanName)
+			args = append(args, source, destPath)
+
+			// #nosec G204 - rsyncPath is validated during initialization, args are constructed safely
+			cmd := exec.CommandContext(ctx, t.rsyncPath, args...)
+			return t.executeCommand(ctx, cmd)
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if t.config.Debug {
+			fmt.Printf("✅ Rsync: Successfully restored backup %s\n", id)
+		}
+		return nil
+	}
+
+	return backup.NewError(backup.ErrNotFound, fmt.Sprintf("rsync: backup %s not found", id), lastErr)
+}
+
 // Validate checks if the target configuration is valid
 func (t *RsyncTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
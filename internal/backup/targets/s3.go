@@ -0,0 +1,1007 @@
+package targets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" // #nosec G501 -- MD5 is required by the S3 API for Content-MD5 and ETags, not used for security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	defaultS3Region       = "us-east-1"
+	defaultS3Timeout      = 5 * time.Minute  // Timeout of a single request
+	defaultS3PartSize     = 16 * 1024 * 1024 // 16MB multipart upload parts
+	minS3PartSize         = 5 * 1024 * 1024  // Minimum part size accepted by S3
+	maxS3Parts            = 10000            // Maximum number of parts in a multipart upload
+	defaultS3MaxRetries   = 3
+	defaultS3RetryBackoff = time.Second
+	s3MetadataFileExt     = ".meta"
+	s3ValidateObjectName  = "birdnet-go-write-test"
+	s3EmptyPayloadHash    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" // SHA-256 of an empty payload
+	s3SigningAlgorithm    = "AWS4-HMAC-SHA256"
+	s3TimeFormat          = "20060102T150405Z"
+	s3DateFormat          = "20060102"
+)
+
+// S3TargetConfig holds configuration for the S3 target
+type S3TargetConfig struct {
+	Endpoint        string        // Endpoint host or URL of an S3-compatible service, empty for AWS
+	Region          string        // Region used for request signing
+	Bucket          string        // Bucket storing the backups
+	AccessKeyID     string        // Access key, empty for anonymous access
+	SecretAccessKey string        // Secret key
+	Prefix          string        // Object key prefix, e.g. "birdnet-go/backups"
+	UseSSL          bool          // Use HTTPS if the endpoint has no scheme
+	PathStyle       bool          // Put the bucket in the URL path instead of the host name, needed by most MinIO and Garage setups
+	PartSize        int64         // Size of multipart upload parts in bytes, larger archives are uploaded in parts
+	Timeout         time.Duration // Timeout of a single request
+	Debug           bool
+	MaxRetries      int
+	RetryBackoff    time.Duration
+}
+
+// S3Target implements the backup.Target interface for Amazon S3 and
+// S3-compatible object storage such as MinIO and Garage
+type S3Target struct {
+	config  S3TargetConfig
+	baseURL *url.URL // Scheme and host of the endpoint, including the bucket with virtual-hosted addressing
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// s3Error is an error response of the S3 API
+type s3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("s3: %s (HTTP %d): %s", e.Code, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("s3: HTTP %d", e.StatusCode)
+}
+
+// s3Request describes a request to the S3 API
+type s3Request struct {
+	method  string
+	key     string // Object key, empty for bucket requests
+	query   url.Values
+	body    []byte
+	headers map[string]string
+}
+
+// s3ListResult is the response of a ListObjectsV2 request
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+// s3CompletedPart is a part of a completed multipart upload
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// NewS3Target creates a new S3 target with the given configuration
+func NewS3Target(config *S3TargetConfig, logger *slog.Logger) (*S3Target, error) {
+	if config.Bucket == "" {
+		return nil, errors.Newf("s3: bucket is required").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_s3_target").
+			Build()
+	}
+	if (config.AccessKeyID == "") != (config.SecretAccessKey == "") {
+		return nil, errors.Newf("s3: access key ID and secret access key must be set together").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_s3_target").
+			Build()
+	}
+
+	// Set defaults for optional fields
+	if config.Region == "" {
+		config.Region = defaultS3Region
+	}
+	if config.PartSize == 0 {
+		config.PartSize = defaultS3PartSize
+	}
+	if config.PartSize < minS3PartSize {
+		return nil, errors.Newf("s3: part size must be at least %d bytes", minS3PartSize).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_s3_target").
+			Context("part_size", config.PartSize).
+			Build()
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultS3Timeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaultS3MaxRetries
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = defaultS3RetryBackoff
+	}
+	config.Prefix = strings.Trim(config.Prefix, "/")
+
+	baseURL, err := s3BaseURL(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &S3Target{
+		config:  *config,
+		baseURL: baseURL,
+		client:  &http.Client{},
+		logger:  logger.With("backup_target", "s3"),
+	}, nil
+}
+
+// NewS3TargetFromMap creates a new S3 target from a map of settings. The
+// keys match conf.S3BackupSettings.
+func NewS3TargetFromMap(settings map[string]any, logger *slog.Logger) (*S3Target, error) {
+	config := S3TargetConfig{UseSSL: true}
+
+	config.Endpoint, _ = settings["endpoint"].(string)
+	config.Region, _ = settings["region"].(string)
+	config.Bucket, _ = settings["bucket"].(string)
+	config.AccessKeyID, _ = settings["accesskeyid"].(string)
+	config.SecretAccessKey, _ = settings["secretaccesskey"].(string)
+	config.Prefix, _ = settings["prefix"].(string)
+	config.Debug, _ = settings["debug"].(bool)
+
+	if useSSL, ok := settings["usessl"].(bool); ok {
+		config.UseSSL = useSSL
+	}
+
+	// Custom endpoints rarely have wildcard DNS for virtual-hosted buckets
+	config.PathStyle = config.Endpoint != ""
+	if pathStyle, ok := settings["pathstyle"].(bool); ok {
+		config.PathStyle = pathStyle
+	}
+
+	if partSizeMB, ok := intSetting(settings, "partsizemb"); ok {
+		config.PartSize = int64(partSizeMB) * 1024 * 1024
+	}
+
+	if timeout, ok := settings["timeout"].(string); ok {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryValidation).
+				Context("operation", "parse_timeout").
+				Build()
+		}
+		config.Timeout = duration
+	}
+
+	return NewS3Target(&config, logger)
+}
+
+// intSetting returns an integer setting, accepting the number types produced
+// by YAML and JSON decoding
+func intSetting(settings map[string]any, key string) (int, bool) {
+	switch v := settings[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+// s3BaseURL returns the scheme and host requests are sent to
+func s3BaseURL(config *S3TargetConfig) (*url.URL, error) {
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("s3.%s.amazonaws.com", config.Region)
+	}
+	if !strings.Contains(endpoint, "://") {
+		scheme := "http"
+		if config.UseSSL {
+			scheme = "https"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		if err == nil {
+			err = fmt.Errorf("missing host")
+		}
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "parse_s3_endpoint").
+			Context("endpoint", config.Endpoint).
+			Build()
+	}
+
+	base := &url.URL{Scheme: u.Scheme, Host: u.Host}
+	if !config.PathStyle {
+		base.Host = config.Bucket + "." + u.Host
+	}
+	return base, nil
+}
+
+// Name returns the name of this target
+func (t *S3Target) Name() string {
+	return "s3"
+}
+
+// objectKey returns the object key of a file name
+func (t *S3Target) objectKey(name string) string {
+	if t.config.Prefix == "" {
+		return name
+	}
+	return t.config.Prefix + "/" + name
+}
+
+// objectURL returns the URL of an object, or of the bucket if key is empty
+func (t *S3Target) objectURL(key string, query url.Values) *url.URL {
+	u := *t.baseURL
+	p := "/" + key
+	if t.config.PathStyle {
+		p = "/" + t.config.Bucket + "/" + key
+	}
+	u.Path = p
+	u.RawPath = s3EscapePath(p)
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+// newRequest creates a signed HTTP request
+func (t *S3Target) newRequest(ctx context.Context, r *s3Request) (*http.Request, error) {
+	var body io.Reader
+	payloadHash := s3EmptyPayloadHash
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+		sum := sha256.Sum256(r.body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, t.objectURL(r.key, r.query).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.headers {
+		req.Header.Set(k, v)
+	}
+	if r.body != nil {
+		// The server rejects the upload if the data does not match
+		sum := md5.Sum(r.body) // #nosec G401 -- Content-MD5 is defined by the S3 API
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	signS3Request(req, t.config.AccessKeyID, t.config.SecretAccessKey, t.config.Region, payloadHash, time.Now())
+	return req, nil
+}
+
+// do sends a request with retries and returns the headers and body of a
+// successful response
+func (t *S3Target) do(ctx context.Context, r *s3Request) (http.Header, []byte, error) {
+	var header http.Header
+	var body []byte
+	err := t.withRetry(ctx, func() error {
+		reqCtx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+		defer cancel()
+
+		req, err := t.newRequest(reqCtx, r)
+		if err != nil {
+			return err
+		}
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.logger.Debug("S3: Failed to close response body", "error", err)
+			}
+		}()
+
+		if resp.StatusCode >= 300 {
+			return parseS3Error(resp)
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		// Some S3 operations report errors in the body of a 200 response
+		if len(data) > 0 && bytes.Contains(data[:min(len(data), 128)], []byte("<Error>")) {
+			apiErr := &s3Error{StatusCode: resp.StatusCode}
+			if xml.Unmarshal(data, apiErr) == nil && apiErr.Code != "" {
+				return apiErr
+			}
+		}
+
+		header, body = resp.Header, data
+		return nil
+	})
+	return header, body, err
+}
+
+// open sends a GET request for an object and returns the response body for streaming
+func (t *S3Target) open(ctx context.Context, key string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := t.withRetry(ctx, func() error {
+		req, err := t.newRequest(ctx, &s3Request{method: http.MethodGet, key: key})
+		if err != nil {
+			return err
+		}
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 300 {
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			return parseS3Error(resp)
+		}
+		body = resp.Body
+		return nil
+	})
+	return body, err
+}
+
+// parseS3Error reads the error of a failed response
+func parseS3Error(resp *http.Response) error {
+	apiErr := &s3Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = xml.Unmarshal(data, apiErr) // HEAD responses have no body
+	return apiErr
+}
+
+// isS3NotFound reports whether err is a not found response
+func isS3NotFound(err error) bool {
+	var apiErr *s3Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isTransientError determines if an error is likely transient
+func (t *S3Target) isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *s3Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 ||
+			apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.Code == "SlowDown" ||
+			apiErr.Code == "RequestTimeout"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	errStr := err.Error()
+	return strings.Contains(errStr, "connection reset") ||
+		strings.Contains(errStr, "connection refused") ||
+		strings.Contains(errStr, "broken pipe") ||
+		strings.Contains(errStr, "EOF")
+}
+
+// withRetry executes an operation with retry logic
+func (t *S3Target) withRetry(ctx context.Context, op func() error) error {
+	var lastErr error
+	for i := 0; i < t.config.MaxRetries; i++ {
+		if err := ctx.Err(); err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategorySystem).
+				Context("operation", "s3_retry").
+				Context("error_type", "cancelled").
+				Build()
+		}
+
+		err := op()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+		if !t.isTransientError(err) {
+			return err
+		}
+
+		if t.config.Debug {
+			t.logger.Debug("S3: Retrying operation after error",
+				"error", err,
+				"attempt", i+1,
+				"max_retries", t.config.MaxRetries)
+		}
+		time.Sleep(t.config.RetryBackoff * time.Duration(i+1))
+	}
+	return lastErr
+}
+
+// Store uploads a backup archive and its metadata to the bucket
+func (t *S3Target) Store(ctx context.Context, sourcePath string, metadata *backup.Metadata) error {
+	key := t.objectKey(filepath.Base(sourcePath))
+	if t.config.Debug {
+		t.logger.Debug("S3: Storing backup", "key", key, "bucket", t.config.Bucket)
+	}
+
+	secureOp := backup.NewSecureFileOp("backup")
+	file, cleanPath, err := secureOp.SecureOpen(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			t.logger.Debug("S3: Failed to close source file", "path", cleanPath, "error", err)
+		}
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_source_file").
+			Context("source_path", cleanPath).
+			Build()
+	}
+
+	headers := map[string]string{"Content-Type": "application/octet-stream"}
+	if metadata != nil && metadata.Checksum != "" {
+		headers["x-amz-meta-sha256"] = metadata.Checksum
+	}
+
+	if info.Size() > t.config.PartSize {
+		err = t.multipartUpload(ctx, key, file, info.Size(), headers)
+	} else {
+		err = t.putFile(ctx, key, file, headers)
+	}
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "upload_backup").
+			Context("key", key).
+			Build()
+	}
+
+	// Verify the stored object before writing the metadata that makes it visible to List
+	respHeader, _, err := t.do(ctx, &s3Request{method: http.MethodHead, key: key})
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "verify_backup").
+			Context("key", key).
+			Build()
+	}
+	if size, _ := strconv.ParseInt(respHeader.Get("Content-Length"), 10, 64); size != info.Size() {
+		return errors.Newf("s3: stored object size mismatch: expected %d, got %d", info.Size(), size).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "verify_backup_size").
+			Context("key", key).
+			Build()
+	}
+
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "marshal_metadata").
+			Build()
+	}
+	if _, _, err := t.do(ctx, &s3Request{
+		method:  http.MethodPut,
+		key:     key + s3MetadataFileExt,
+		body:    metadataBytes,
+		headers: map[string]string{"Content-Type": "application/json"},
+	}); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "upload_metadata").
+			Context("key", key).
+			Build()
+	}
+
+	if t.config.Debug {
+		t.logger.Debug("S3: Successfully stored backup", "key", key, "size", info.Size())
+	}
+	return nil
+}
+
+// putFile uploads a file in a single request
+func (t *S3Target) putFile(ctx context.Context, key string, file *os.File, headers map[string]string) error {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	respHeader, _, err := t.do(ctx, &s3Request{method: http.MethodPut, key: key, body: data, headers: headers})
+	if err != nil {
+		return err
+	}
+
+	sum := md5.Sum(data) // #nosec G401 -- ETags of single part uploads are MD5 checksums
+	return verifyETag(respHeader.Get("ETag"), hex.EncodeToString(sum[:]))
+}
+
+// multipartUpload uploads a file in parts. The upload is aborted on failure
+// so no incomplete parts are left behind.
+func (t *S3Target) multipartUpload(ctx context.Context, key string, file *os.File, size int64, headers map[string]string) error {
+	partSize := t.config.PartSize
+	if size > partSize*maxS3Parts {
+		partSize = (size + maxS3Parts - 1) / maxS3Parts
+	}
+
+	_, body, err := t.do(ctx, &s3Request{
+		method:  http.MethodPost,
+		key:     key,
+		query:   url.Values{"uploads": {""}},
+		headers: headers,
+	})
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &initiated); err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("s3: invalid response to multipart upload request: %w", err)
+	}
+	uploadID := initiated.UploadID
+
+	success := false
+	defer func() {
+		if success {
+			return
+		}
+		// Abort with a fresh context, ctx may already be canceled
+		abortCtx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
+		defer cancel()
+		if _, _, err := t.do(abortCtx, &s3Request{
+			method: http.MethodDelete,
+			key:    key,
+			query:  url.Values{"uploadId": {uploadID}},
+		}); err != nil {
+			t.logger.Warn("S3: Failed to abort multipart upload", "key", key, "upload_id", uploadID, "error", err)
+		}
+	}()
+
+	buf := make([]byte, partSize)
+	parts := make([]s3CompletedPart, 0, (size+partSize-1)/partSize)
+	partSums := make([]byte, 0, cap(parts)*md5.Size)
+	for partNumber := 1; ; partNumber++ {
+		n, err := io.ReadFull(file, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		part := buf[:n]
+
+		respHeader, _, err := t.do(ctx, &s3Request{
+			method: http.MethodPut,
+			key:    key,
+			query:  url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}},
+			body:   part,
+		})
+		if err != nil {
+			return fmt.Errorf("s3: failed to upload part %d: %w", partNumber, err)
+		}
+
+		sum := md5.Sum(part) // #nosec G401 -- part ETags are MD5 checksums
+		etag := respHeader.Get("ETag")
+		if err := verifyETag(etag, hex.EncodeToString(sum[:])); err != nil {
+			return fmt.Errorf("s3: part %d: %w", partNumber, err)
+		}
+		parts = append(parts, s3CompletedPart{PartNumber: partNumber, ETag: etag})
+		partSums = append(partSums, sum[:]...)
+
+		if t.config.Debug {
+			t.logger.Debug("S3: Uploaded part", "key", key, "part", partNumber, "size", n)
+		}
+	}
+
+	completeBody, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	_, body, err = t.do(ctx, &s3Request{
+		method:  http.MethodPost,
+		key:     key,
+		query:   url.Values{"uploadId": {uploadID}},
+		body:    completeBody,
+		headers: map[string]string{"Content-Type": "application/xml"},
+	})
+	if err != nil {
+		return err
+	}
+	success = true
+
+	// The ETag of a multipart object is the MD5 of the part MD5s and the part count
+	var completed struct {
+		ETag string `xml:"ETag"`
+	}
+	if err := xml.Unmarshal(body, &completed); err != nil {
+		return fmt.Errorf("s3: invalid response to complete multipart upload: %w", err)
+	}
+	sum := md5.Sum(partSums) // #nosec G401 -- multipart ETags are MD5 checksums
+	return verifyETag(completed.ETag, fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), len(parts)))
+}
+
+// verifyETag compares an ETag with the expected MD5 based value. ETags that
+// are not MD5 based, as with KMS encrypted objects, are not compared.
+func verifyETag(etag, expected string) error {
+	etag = strings.Trim(etag, `"`)
+	md5Hex := etag
+	if i := strings.IndexByte(etag, '-'); i >= 0 {
+		md5Hex = etag[:i]
+	}
+	if len(md5Hex) != 2*md5.Size || strings.Trim(strings.ToLower(md5Hex), "0123456789abcdef") != "" {
+		return nil
+	}
+	if !strings.EqualFold(etag, expected) {
+		return backup.NewError(backup.ErrCorruption, fmt.Sprintf("s3: checksum mismatch: expected ETag %s, got %s", expected, etag), nil)
+	}
+	return nil
+}
+
+// List returns the backups stored in the bucket
+func (t *S3Target) List(ctx context.Context) ([]backup.BackupInfo, error) {
+	if t.config.Debug {
+		t.logger.Debug("S3: Listing backups", "bucket", t.config.Bucket, "prefix", t.config.Prefix)
+	}
+
+	listPrefix := ""
+	if t.config.Prefix != "" {
+		listPrefix = t.config.Prefix + "/"
+	}
+
+	objects := make(map[string]bool)
+	var metadataKeys []string
+	continuationToken := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {listPrefix},
+			"delimiter": {"/"},
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		_, body, err := t.do(ctx, &s3Request{method: http.MethodGet, query: query})
+		if err != nil {
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryNetwork).
+				Context("operation", "list_backups").
+				Context("bucket", t.config.Bucket).
+				Build()
+		}
+
+		var result s3ListResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, backup.NewError(backup.ErrIO, "s3: invalid list response", err)
+		}
+		for _, obj := range result.Contents {
+			objects[obj.Key] = true
+			if strings.HasSuffix(obj.Key, s3MetadataFileExt) {
+				metadataKeys = append(metadataKeys, obj.Key)
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	backups := make([]backup.BackupInfo, 0, len(metadataKeys))
+	for _, metadataKey := range metadataKeys {
+		backupKey := strings.TrimSuffix(metadataKey, s3MetadataFileExt)
+		if !objects[backupKey] {
+			if t.config.Debug {
+				t.logger.Debug("S3: Skipping orphaned metadata file", "key", metadataKey)
+			}
+			continue
+		}
+
+		_, body, err := t.do(ctx, &s3Request{method: http.MethodGet, key: metadataKey})
+		if err != nil {
+			t.logger.Warn("S3: Skipping backup with unreadable metadata", "key", metadataKey, "error", err)
+			continue
+		}
+		var metadata backup.Metadata
+		if err := json.Unmarshal(body, &metadata); err != nil {
+			t.logger.Warn("S3: Skipping backup with invalid metadata", "key", metadataKey, "error", err)
+			continue
+		}
+
+		backups = append(backups, backup.BackupInfo{
+			Metadata: metadata,
+			Target:   t.Name(),
+		})
+	}
+
+	// Sort backups by timestamp (newest first)
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp.After(backups[j].Timestamp)
+	})
+
+	return backups, nil
+}
+
+// Delete removes a backup and its metadata from the bucket
+func (t *S3Target) Delete(ctx context.Context, id string) error {
+	if err := backup.ValidateBackupID(id); err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		t.logger.Debug("S3: Deleting backup", "id", id, "bucket", t.config.Bucket)
+	}
+
+	// Deleting a missing object succeeds, so all possible names are removed
+	for _, name := range backup.ArchiveFileNames(id) {
+		key := t.objectKey(name)
+		for _, k := range []string{key, key + s3MetadataFileExt} {
+			if _, _, err := t.do(ctx, &s3Request{method: http.MethodDelete, key: k}); err != nil {
+				return errors.New(err).
+					Component("backup").
+					Category(errors.CategoryNetwork).
+					Context("operation", "delete_backup").
+					Context("key", k).
+					Build()
+			}
+		}
+	}
+
+	if t.config.Debug {
+		t.logger.Debug("S3: Successfully deleted backup", "id", id)
+	}
+	return nil
+}
+
+// Restore downloads the archive of a backup to destPath
+func (t *S3Target) Restore(ctx context.Context, id, destPath string) error {
+	if err := backup.ValidateBackupID(id); err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		t.logger.Debug("S3: Restoring backup", "id", id, "bucket", t.config.Bucket)
+	}
+
+	var lastErr error
+	for _, name := range backup.ArchiveFileNames(id) {
+		key := t.objectKey(name)
+		body, err := t.open(ctx, key)
+		if err != nil {
+			if isS3NotFound(err) {
+				lastErr = err
+				continue
+			}
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryNetwork).
+				Context("operation", "restore_backup").
+				Context("key", key).
+				Build()
+		}
+
+		err = writeDownloadedFile(destPath, body)
+		if closeErr := body.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryNetwork).
+				Context("operation", "download_backup").
+				Context("key", key).
+				Build()
+		}
+
+		if t.config.Debug {
+			t.logger.Debug("S3: Successfully restored backup", "id", id, "key", key)
+		}
+		return nil
+	}
+
+	return backup.NewError(backup.ErrNotFound, fmt.Sprintf("s3: backup %s not found", id), lastErr)
+}
+
+// Validate checks that the bucket exists and is writable
+func (t *S3Target) Validate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
+	defer cancel()
+
+	if _, _, err := t.do(ctx, &s3Request{method: http.MethodHead}); err != nil {
+		if isS3NotFound(err) {
+			return backup.NewError(backup.ErrConfig, fmt.Sprintf("s3: bucket %s does not exist", t.config.Bucket), err)
+		}
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "validate_bucket").
+			Context("bucket", t.config.Bucket).
+			Build()
+	}
+
+	// Write and remove a small object to check permissions
+	key := t.objectKey(s3ValidateObjectName)
+	if _, _, err := t.do(ctx, &s3Request{method: http.MethodPut, key: key, body: []byte("test")}); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "validate_write_permission").
+			Context("bucket", t.config.Bucket).
+			Build()
+	}
+	if _, _, err := t.do(ctx, &s3Request{method: http.MethodDelete, key: key}); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryNetwork).
+			Context("operation", "validate_delete_permission").
+			Context("bucket", t.config.Bucket).
+			Build()
+	}
+
+	return nil
+}
+
+// signS3Request signs a request with AWS Signature Version 4. Requests
+// without credentials are sent anonymously.
+func signS3Request(req *http.Request, accessKeyID, secretAccessKey, region, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(s3TimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if accessKeyID == "" {
+		return
+	}
+
+	// Canonical headers: host and the content and amz headers that are set
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-md5" || lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", now.Format(s3DateFormat), region)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, accessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape percent-encodes a string as required by Signature Version 4
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', keepSlash && c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3EscapePath percent-encodes an URL path, keeping the slashes. S3 does not
+// normalize paths, so the path is not cleaned.
+func s3EscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return s3Escape(p, true)
+}
+
+// s3CanonicalQuery returns the query string sorted and encoded as required by Signature Version 4
+func s3CanonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, s3Escape(k, false)+"="+s3Escape(v, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
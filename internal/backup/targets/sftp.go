@@ -835,6 +835,52 @@ func (t *SFTPTarget) Delete(ctx context.Context, target string) error {
 	})
 }
 
+// Restore downloads the archive of a backup from the SFTP server to destPath
+func (t *SFTPTarget) Restore(ctx context.Context, id, destPath string) error {
+	if err := backup.ValidateBackupID(id); err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		t.logger.Debug("SFTP: Restoring backup",
+			"id", id,
+			"host", t.config.Host)
+	}
+
+	return t.withRetry(ctx, func(client *sftp.Client) error {
+		var lastErr error
+		for _, name := range backup.ArchiveFileNames(id) {
+			backupPath := path.Join(t.config.BasePath, name)
+			file, err := client.Open(backupPath)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			err = writeDownloadedFile(destPath, file)
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return errors.New(err).
+					Component("backup").
+					Category(errors.CategoryNetwork).
+					Context("operation", "restore_backup").
+					Context("backup_id", id).
+					Build()
+			}
+
+			if t.config.Debug {
+				t.logger.Debug("SFTP: Successfully restored backup",
+					"id", id)
+			}
+			return nil
+		}
+
+		return backup.NewError(backup.ErrNotFound, fmt.Sprintf("sftp: backup %s not found", id), lastErr)
+	})
+}
+
 // Validate checks if the target configuration is valid
 func (t *SFTPTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
@@ -110,12 +110,33 @@ func (bn *BirdNET) PredictWithContext(ctx context.Context, sample [][]float32) (
 	// Use optimized sigmoid function with buffer reuse
 	confidence := applySigmoidToPredictionsReuse(predictions, bn.Settings.BirdNET.Sensitivity, bn.confidenceBuffer)
 
+	// Fuse the outputs of additional models into one prediction vector
+	labels, resultsBuffer := bn.Settings.BirdNET.Labels, bn.resultsBuffer
+	if bn.ensemble != nil {
+		fused, err := bn.ensemble.predict(sample[0], confidence, bn.Settings.BirdNET.Sensitivity)
+		if err != nil {
+			span.SetTag("error", "true")
+			span.SetData("error_type", "ensemble_failed")
+
+			if globalMetrics != nil {
+				globalMetrics.RecordPrediction(bn.ModelInfo.ID, time.Since(start).Seconds(), err)
+			}
+
+			return nil, err
+		}
+		confidence = fused
+		labels, resultsBuffer = bn.ensemble.labels, bn.ensemble.resultsBuffer
+	}
+
+	// Archive the full prediction vector before it is reduced to the top results
+	bn.archivePrediction(ctx, labels, confidence)
+
 	// Use the pre-allocated buffer to reduce memory allocations
-	results, err := pairLabelsAndConfidenceReuse(bn.Settings.BirdNET.Labels, confidence, bn.resultsBuffer)
+	results, err := pairLabelsAndConfidenceReuse(labels, confidence, resultsBuffer)
 	if err != nil {
 		err = errors.New(err).
 			Category(errors.CategoryValidation).
-			Context("label_count", len(bn.Settings.BirdNET.Labels)).
+			Context("label_count", len(labels)).
 			Context("confidence_count", len(confidence)).
 			Timing("prediction-total", time.Since(start)).
 			Build()
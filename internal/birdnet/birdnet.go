@@ -19,6 +19,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/cpuspec"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/predictionarchive"
 	"github.com/tphakala/birdnet-go/internal/telemetry"
 	tflite "github.com/tphakala/go-tflite"
 	"github.com/tphakala/go-tflite/delegates/xnnpack"
@@ -53,8 +54,10 @@ type BirdNET struct {
 	ScientificIndex     ScientificNameIndex // Index for fast scientific name lookups
 	TaxonomyPath        string              // Path to custom taxonomy file, if used
 	mu                  sync.Mutex
-	resultsBuffer       []datastore.Results // Pre-allocated buffer for results to reduce allocations
-	confidenceBuffer    []float32           // Pre-allocated buffer for confidence values to reduce allocations
+	resultsBuffer       []datastore.Results       // Pre-allocated buffer for results to reduce allocations
+	confidenceBuffer    []float32                 // Pre-allocated buffer for confidence values to reduce allocations
+	predictionArchive   *predictionarchive.Writer // Optional archive for full prediction vectors
+	ensemble            *ensemble                 // Optional additional models fused with the primary model
 }
 
 // NewBirdNET initializes a new BirdNET instance with given settings.
@@ -145,6 +148,15 @@ func NewBirdNET(settings *conf.Settings) (*BirdNET, error) {
 			Build()
 	}
 
+	// Load additional models of the ensemble, if configured
+	bn.ensemble, err = bn.initializeEnsemble()
+	if err != nil {
+		return nil, errors.New(fmt.Errorf("BirdNET: failed to initialize model ensemble: %w", err)).
+			Component("birdnet").
+			Category(errors.CategoryModelInit).
+			Build()
+	}
+
 	return bn, nil
 }
 
@@ -176,28 +188,7 @@ func (bn *BirdNET) initializeModel() error {
 	threads := bn.determineThreadCount(bn.Settings.BirdNET.Threads)
 
 	// Configure interpreter options.
-	options := tflite.NewInterpreterOptions()
-
-	// Try to use XNNPACK delegate if enabled in settings
-	if bn.Settings.BirdNET.UseXNNPACK {
-		delegate := xnnpack.New(xnnpack.DelegateOptions{NumThreads: int32(max(1, threads-1))}) //nolint:gosec // G115: thread count bounded by CPU count, safe conversion
-		if delegate == nil {
-			fmt.Println("⚠️ Failed to create XNNPACK delegate, falling back to default CPU")
-			fmt.Println("Please download updated tensorflow lite C API library from:")
-			fmt.Println("https://github.com/tphakala/tflite_c/releases/tag/v2.17.1")
-			fmt.Println("and install it to enable use of XNNPACK delegate")
-			options.SetNumThread(threads)
-		} else {
-			options.AddDelegate(delegate)
-			options.SetNumThread(1)
-		}
-	} else {
-		options.SetNumThread(threads)
-	}
-
-	options.SetErrorReporter(func(msg string, user_data interface{}) {
-		fmt.Println(msg)
-	}, nil)
+	options := bn.newAnalysisInterpreterOptions(threads)
 
 	// Create and allocate the TensorFlow Lite interpreter.
 	bn.AnalysisInterpreter = tflite.NewInterpreter(model, options)
@@ -240,6 +231,35 @@ func (bn *BirdNET) initializeModel() error {
 	return nil
 }
 
+// newAnalysisInterpreterOptions returns interpreter options for analysis models,
+// using the XNNPACK delegate if it is enabled in settings.
+func (bn *BirdNET) newAnalysisInterpreterOptions(threads int) *tflite.InterpreterOptions {
+	options := tflite.NewInterpreterOptions()
+
+	// Try to use XNNPACK delegate if enabled in settings
+	if bn.Settings.BirdNET.UseXNNPACK {
+		delegate := xnnpack.New(xnnpack.DelegateOptions{NumThreads: int32(max(1, threads-1))}) //nolint:gosec // G115: thread count bounded by CPU count, safe conversion
+		if delegate == nil {
+			fmt.Println("⚠️ Failed to create XNNPACK delegate, falling back to default CPU")
+			fmt.Println("Please download updated tensorflow lite C API library from:")
+			fmt.Println("https://github.com/tphakala/tflite_c/releases/tag/v2.17.1")
+			fmt.Println("and install it to enable use of XNNPACK delegate")
+			options.SetNumThread(threads)
+		} else {
+			options.AddDelegate(delegate)
+			options.SetNumThread(1)
+		}
+	} else {
+		options.SetNumThread(threads)
+	}
+
+	options.SetErrorReporter(func(msg string, user_data interface{}) {
+		fmt.Println(msg)
+	}, nil)
+
+	return options
+}
+
 // getMetaModelData returns the appropriate meta model data based on the settings.
 func (bn *BirdNET) getMetaModelData() []byte {
 	if bn.Settings.BirdNET.RangeFilter.Model == "legacy" {
@@ -487,6 +507,7 @@ func (bn *BirdNET) Delete() {
 	if bn.RangeInterpreter != nil {
 		bn.RangeInterpreter.Delete()
 	}
+	bn.ensemble.delete()
 }
 
 // loadModel loads either the embedded model or an external model file
@@ -638,6 +659,24 @@ func (bn *BirdNET) ReloadModel() error {
 		return fmt.Errorf("\033[31m❌ model validation failed: %w\033[0m", err)
 	}
 
+	// Reload additional models of the ensemble
+	newEnsemble, err := bn.initializeEnsemble()
+	if err != nil {
+		// Clean up the newly created interpreters if the ensemble fails to load
+		if bn.AnalysisInterpreter != nil {
+			bn.AnalysisInterpreter.Delete()
+		}
+		if bn.RangeInterpreter != nil {
+			bn.RangeInterpreter.Delete()
+		}
+		// Restore the old interpreters
+		bn.AnalysisInterpreter = oldAnalysisInterpreter
+		bn.RangeInterpreter = oldRangeInterpreter
+		return fmt.Errorf("\033[31m❌ failed to reload model ensemble: %w\033[0m", err)
+	}
+	bn.ensemble.delete()
+	bn.ensemble = newEnsemble
+
 	// Clean up old interpreters after successful reload
 	if oldAnalysisInterpreter != nil {
 		oldAnalysisInterpreter.Delete()
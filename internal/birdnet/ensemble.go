@@ -0,0 +1,327 @@
+// ensemble.go runs additional analysis models and fuses their outputs with the primary model
+package birdnet
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	tflite "github.com/tphakala/go-tflite"
+)
+
+// Supported ensemble fusion methods
+const (
+	FusionMax      = "max"
+	FusionMean     = "mean"
+	FusionWeighted = "weighted"
+)
+
+// ensembleMember is an additional analysis model of the ensemble.
+type ensembleMember struct {
+	name        string
+	interpreter *tflite.Interpreter
+	labels      []string
+	weight      float32
+	indexMap    []int // position of each member label in the fused label list
+}
+
+// ensemble fuses the output of the primary model with additional models.
+// Labels of all models are merged into one list: the primary labels come
+// first in their original order, species only known to additional models
+// are appended after them.
+type ensemble struct {
+	fusion        string
+	primaryWeight float32
+	members       []*ensembleMember
+	labels        []string            // fused label list
+	fused         []float32           // fused confidence buffer
+	weightSum     []float32           // per label sum of weights of the models reporting it
+	resultsBuffer []datastore.Results // pre-allocated results buffer for the fused labels
+}
+
+// initializeEnsemble loads the additional models configured in the ensemble
+// settings. It returns nil if the ensemble is disabled or has no models.
+func (bn *BirdNET) initializeEnsemble() (*ensemble, error) {
+	settings := bn.Settings.BirdNET.Ensemble
+	if !settings.Enabled || len(settings.Models) == 0 {
+		return nil, nil
+	}
+
+	e := &ensemble{
+		fusion:        settings.Fusion,
+		primaryWeight: float32(settings.PrimaryWeight),
+		labels:        append([]string(nil), bn.Settings.BirdNET.Labels...),
+	}
+	if e.fusion == "" {
+		e.fusion = FusionMax
+	}
+
+	primaryInputSize := 0
+	if input := bn.AnalysisInterpreter.GetInputTensor(0); input != nil {
+		primaryInputSize = input.Dim(input.NumDims() - 1)
+	}
+
+	for i, model := range settings.Models {
+		name := model.Name
+		if name == "" {
+			name = fmt.Sprintf("model %d", i+1)
+		}
+
+		member, err := bn.loadEnsembleMember(name, model.ModelPath, model.LabelPath, primaryInputSize)
+		if err != nil {
+			e.delete()
+			return nil, err
+		}
+		member.weight = float32(model.Weight)
+		e.labels, member.indexMap = mapEnsembleLabels(e.labels, member.labels, bn.ScientificIndex)
+		e.members = append(e.members, member)
+
+		fmt.Printf("Ensemble model %s initialized with %d labels\n", name, len(member.labels))
+	}
+
+	e.fused = make([]float32, len(e.labels))
+	e.weightSum = make([]float32, len(e.labels))
+	e.resultsBuffer = make([]datastore.Results, len(e.labels))
+
+	bn.Debug("Model ensemble initialized: %d additional models, %d fused labels, fusion %s",
+		len(e.members), len(e.labels), e.fusion)
+	return e, nil
+}
+
+// loadEnsembleMember loads an additional model and its labels and checks that
+// the model accepts the same input as the primary model.
+func (bn *BirdNET) loadEnsembleMember(name, modelPath, labelPath string, inputSize int) (*ensembleMember, error) {
+	start := time.Now()
+
+	data, err := os.ReadFile(modelPath)
+	if err != nil {
+		return nil, errors.New(err).
+			Category(errors.CategoryFileIO).
+			ModelContext(modelPath, name).
+			Context("operation", "read_ensemble_model").
+			Build()
+	}
+
+	model := tflite.NewModel(data)
+	if model == nil {
+		return nil, errors.Newf("cannot load ensemble model %s", name).
+			Category(errors.CategoryModelInit).
+			ModelContext(modelPath, name).
+			Timing("ensemble-model-load", time.Since(start)).
+			Build()
+	}
+
+	threads := bn.determineThreadCount(bn.Settings.BirdNET.Threads)
+	interpreter := tflite.NewInterpreter(model, bn.newAnalysisInterpreterOptions(threads))
+	if interpreter == nil {
+		return nil, errors.Newf("cannot create interpreter for ensemble model %s", name).
+			Category(errors.CategoryModelInit).
+			ModelContext(modelPath, name).
+			Build()
+	}
+
+	// From here on the interpreter must be released on failure
+	fail := func(err error) (*ensembleMember, error) {
+		interpreter.Delete()
+		return nil, err
+	}
+
+	if status := interpreter.AllocateTensors(); status != tflite.OK {
+		return fail(errors.Newf("tensor allocation failed for ensemble model %s: %v", name, status).
+			Category(errors.CategoryModelInit).
+			ModelContext(modelPath, name).
+			Build())
+	}
+
+	input := interpreter.GetInputTensor(0)
+	output := interpreter.GetOutputTensor(0)
+	if input == nil || output == nil {
+		return fail(errors.Newf("cannot get tensors of ensemble model %s", name).
+			Category(errors.CategoryModelInit).
+			ModelContext(modelPath, name).
+			Build())
+	}
+	if memberInput := input.Dim(input.NumDims() - 1); inputSize > 0 && memberInput != inputSize {
+		return fail(errors.Newf("ensemble model %s expects %d input samples, primary model expects %d", name, memberInput, inputSize).
+			Category(errors.CategoryValidation).
+			ModelContext(modelPath, name).
+			Build())
+	}
+
+	labels, err := readLabelFile(labelPath)
+	if err != nil {
+		return fail(errors.New(err).
+			Category(errors.CategoryLabelLoad).
+			Context("label_path", labelPath).
+			Context("model", name).
+			Build())
+	}
+
+	if outputSize := output.Dim(output.NumDims() - 1); outputSize != len(labels) {
+		return fail(errors.Newf("label count mismatch: ensemble model %s expects %d classes but label file has %d labels",
+			name, outputSize, len(labels)).
+			Category(errors.CategoryValidation).
+			ModelContext(modelPath, name).
+			Context("label_path", labelPath).
+			Build())
+	}
+
+	return &ensembleMember{name: name, interpreter: interpreter, labels: labels}, nil
+}
+
+// readLabelFile reads a label file with one label per line.
+func readLabelFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var labels []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			labels = append(labels, line)
+		}
+	}
+	return labels, scanner.Err()
+}
+
+// speciesKey returns the identity used to match labels between models: the
+// eBird code when the species is in the taxonomy, otherwise the lower case
+// scientific name.
+func speciesKey(label string, scientificIndex ScientificNameIndex) string {
+	scientific, _ := SplitSpeciesName(label)
+	if scientific == "" {
+		scientific = label
+	}
+	scientific = strings.TrimSpace(scientific)
+	if code, exists := scientificIndex[scientific]; exists {
+		return "code:" + code
+	}
+	return "name:" + strings.ToLower(scientific)
+}
+
+// mapEnsembleLabels maps the labels of an ensemble member onto the fused label
+// list using the taxonomy. Labels that do not match an existing entry are
+// appended. It returns the extended label list and the position of each
+// member label in it.
+func mapEnsembleLabels(fused, member []string, scientificIndex ScientificNameIndex) (labels []string, indexMap []int) {
+	positions := make(map[string]int, len(fused))
+	for i, label := range fused {
+		key := speciesKey(label, scientificIndex)
+		if _, exists := positions[key]; !exists {
+			positions[key] = i
+		}
+	}
+
+	labels = fused
+	indexMap = make([]int, len(member))
+	for i, label := range member {
+		key := speciesKey(label, scientificIndex)
+		pos, exists := positions[key]
+		if !exists {
+			pos = len(labels)
+			labels = append(labels, label)
+			positions[key] = pos
+		}
+		indexMap[i] = pos
+	}
+	return labels, indexMap
+}
+
+// predict runs the additional models on the sample and fuses their outputs
+// with the primary model confidences. The returned slice is reused between
+// calls, callers must hold the BirdNET mutex.
+func (e *ensemble) predict(sample []float32, primary []float32, sensitivity float64) ([]float32, error) {
+	for i := range e.fused {
+		e.fused[i] = 0
+		e.weightSum[i] = 0
+	}
+	e.accumulate(primary, nil, e.primaryWeight)
+
+	for _, m := range e.members {
+		input := m.interpreter.GetInputTensor(0)
+		if input == nil {
+			return nil, errors.Newf("cannot get input tensor of ensemble model %s", m.name).
+				Category(errors.CategoryModelInit).
+				Build()
+		}
+		copy(input.Float32s(), sample)
+
+		if status := m.interpreter.Invoke(); status != tflite.OK {
+			return nil, errors.Newf("tensor invoke failed for ensemble model %s: %v", m.name, status).
+				Category(errors.CategoryAudio).
+				Context("status_code", status).
+				Build()
+		}
+
+		confidence := applySigmoidToPredictions(extractPredictions(m.interpreter.GetOutputTensor(0)), sensitivity)
+		e.accumulate(confidence, m.indexMap, m.weight)
+	}
+
+	e.normalize()
+	return e.fused, nil
+}
+
+// normalize divides the accumulated confidences by the summed weights for
+// mean and weighted fusion.
+func (e *ensemble) normalize() {
+	if e.fusion == FusionMax {
+		return
+	}
+	for i, sum := range e.weightSum {
+		if sum > 0 {
+			e.fused[i] /= sum
+		}
+	}
+}
+
+// accumulate adds the confidences of one model to the fused buffer. A nil
+// indexMap means the confidences are already in fused label order.
+func (e *ensemble) accumulate(confidence []float32, indexMap []int, weight float32) {
+	for i, c := range confidence {
+		pos := i
+		if indexMap != nil {
+			pos = indexMap[i]
+		}
+
+		switch e.fusion {
+		case FusionMax:
+			if c > e.fused[pos] {
+				e.fused[pos] = c
+			}
+		case FusionWeighted:
+			e.fused[pos] += c * weight
+			e.weightSum[pos] += weight
+		default: // FusionMean
+			e.fused[pos] += c
+			e.weightSum[pos]++
+		}
+	}
+}
+
+// ensembleOnlyLabels returns the labels of species that are only known to
+// additional ensemble models and not to the primary model.
+func (bn *BirdNET) ensembleOnlyLabels() []string {
+	if bn.ensemble == nil {
+		return nil
+	}
+	return bn.ensemble.labels[len(bn.Settings.BirdNET.Labels):]
+}
+
+// delete releases the interpreters of the additional models.
+func (e *ensemble) delete() {
+	if e == nil {
+		return
+	}
+	for _, m := range e.members {
+		if m.interpreter != nil {
+			m.interpreter.Delete()
+		}
+	}
+}
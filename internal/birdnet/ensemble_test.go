@@ -0,0 +1,76 @@
+package birdnet
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestMapEnsembleLabels tests mapping of ensemble member labels onto the fused label list
+func TestMapEnsembleLabels(t *testing.T) {
+	t.Parallel()
+
+	index := ScientificNameIndex{
+		"Turdus merula": "eurbla",
+		"Parus major":   "gretit1",
+	}
+	primary := []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit", "Dog_Dog"}
+	member := []string{
+		"Parus major_Talitiainen",    // Same species, different locale
+		"Strix uralensis_Ural Owl",   // Not in primary labels
+		"Turdus merula_Mustarastas",  // Same species, different locale
+		"dog_Dog",                    // Non-bird class matched by name
+		"Strix uralensis_Viirupöllö", // Same as an appended label
+	}
+
+	labels, indexMap := mapEnsembleLabels(append([]string(nil), primary...), member, index)
+
+	wantLabels := append(append([]string(nil), primary...), "Strix uralensis_Ural Owl")
+	if !reflect.DeepEqual(labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", labels, wantLabels)
+	}
+	if want := []int{1, 3, 0, 2, 3}; !reflect.DeepEqual(indexMap, want) {
+		t.Errorf("indexMap = %v, want %v", indexMap, want)
+	}
+}
+
+// TestEnsembleFusion tests the max, mean and weighted fusion methods
+func TestEnsembleFusion(t *testing.T) {
+	t.Parallel()
+
+	primary := []float32{0.8, 0.2, 0.0}
+	member := []float32{0.4, 0.6}
+	indexMap := []int{0, 2} // member only knows labels 0 and 2
+
+	tests := []struct {
+		fusion string
+		want   []float32
+	}{
+		{FusionMax, []float32{0.8, 0.2, 0.6}},
+		{FusionMean, []float32{0.6, 0.2, 0.3}},
+		// primary weight 1, member weight 3
+		{FusionWeighted, []float32{0.5, 0.2, 0.45}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fusion, func(t *testing.T) {
+			t.Parallel()
+
+			e := &ensemble{
+				fusion:        tt.fusion,
+				primaryWeight: 1,
+				fused:         make([]float32, 3),
+				weightSum:     make([]float32, 3),
+			}
+			e.accumulate(primary, nil, e.primaryWeight)
+			e.accumulate(member, indexMap, 3)
+			e.normalize()
+
+			for i, want := range tt.want {
+				if math.Abs(float64(e.fused[i]-want)) > 1e-6 {
+					t.Errorf("fused[%d] = %v, want %v", i, e.fused[i], want)
+				}
+			}
+		})
+	}
+}
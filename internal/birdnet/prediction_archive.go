@@ -0,0 +1,64 @@
+package birdnet
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/predictionarchive"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// predictionMetaKey is the context key for PredictionMeta
+type predictionMetaKey struct{}
+
+// PredictionMeta describes the audio chunk a prediction is made for. It is
+// carried in the context passed to PredictWithContext so that the prediction
+// archive can attribute full prediction vectors to their source.
+type PredictionMeta struct {
+	Source    string    // Audio source of the chunk, RTSP URL or audio card name
+	StartTime time.Time // Start time of the audio chunk
+}
+
+// WithPredictionMeta returns a context carrying metadata of the analysed audio chunk.
+func WithPredictionMeta(ctx context.Context, meta PredictionMeta) context.Context {
+	return context.WithValue(ctx, predictionMetaKey{}, meta)
+}
+
+// predictionMetaFromContext returns the chunk metadata stored in ctx, if any.
+func predictionMetaFromContext(ctx context.Context) (PredictionMeta, bool) {
+	meta, ok := ctx.Value(predictionMetaKey{}).(PredictionMeta)
+	return meta, ok
+}
+
+// SetPredictionArchive attaches a prediction archive writer. Every subsequent
+// prediction is archived with its full confidence vector. Passing nil disables
+// archiving. The caller remains responsible for closing the writer.
+func (bn *BirdNET) SetPredictionArchive(w *predictionarchive.Writer) {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	bn.predictionArchive = w
+}
+
+// archivePrediction hands the full confidence vector to the prediction archive.
+// RTSP credentials are stripped from the source before it is archived. Must be
+// called with bn.mu held, the writer copies the vector so the shared confidence
+// buffer can be reused immediately afterwards.
+func (bn *BirdNET) archivePrediction(ctx context.Context, labels []string, confidence []float32) {
+	if bn.predictionArchive == nil {
+		return
+	}
+
+	meta, ok := predictionMetaFromContext(ctx)
+	if !ok {
+		meta.StartTime = time.Now()
+	}
+
+	bn.predictionArchive.Write(&predictionarchive.Record{
+		Timestamp:   meta.StartTime,
+		Source:      privacy.SanitizeRTSPUrl(meta.Source),
+		Sensitivity: float32(bn.Settings.BirdNET.Sensitivity),
+		Overlap:     float32(bn.Settings.BirdNET.Overlap),
+		Labels:      labels,
+		Confidences: confidence,
+	})
+}
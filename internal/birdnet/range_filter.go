@@ -80,25 +80,74 @@ func BuildRangeFilter(bn *BirdNET) error {
 
 	conf.Setting().UpdateIncludedSpecies(includedSpecies)
 
+	// Build species lists for named sources with their own location or species lists
+	return BuildSourceRangeFilters(bn, conf.Setting(), today)
+}
+
+// BuildSourceRangeFilters updates the included species lists of named RTSP
+// sources that override the location, range filter threshold or species lists.
+func BuildSourceRangeFilters(bn *BirdNET, settings *conf.Settings, date time.Time) error {
+	for i := range settings.Realtime.RTSP.Sources {
+		src := &settings.Realtime.RTSP.Sources[i]
+		if !src.HasOwnRangeFilter() {
+			continue
+		}
+
+		source := settings.ResolveSourceSettings(src.URL)
+		speciesScores, err := bn.GetProbableSpeciesForSource(date, 0.0, &source)
+		if err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("date", date.Format("2006-01-02")).
+				Context("source", source.Name).
+				Context("latitude", source.Latitude).
+				Context("longitude", source.Longitude).
+				Build()
+		}
+
+		includedSpecies := make([]string, 0, len(speciesScores))
+		for _, speciesScore := range speciesScores {
+			includedSpecies = append(includedSpecies, speciesScore.Label)
+		}
+		settings.UpdateSourceIncludedSpecies(src.URL, includedSpecies)
+	}
+
 	return nil
 }
 
 // GetProbableSpecies filters and sorts bird species based on their scores.
 // It also updates the scores for species that have custom actions defined in the speciesConfigCSV.
 func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesScore, error) {
+	// check bn.Settings.BirdNET.LocationFilterThreshold for valid value
+	if bn.Settings.BirdNET.RangeFilter.Threshold < 0 ||
+		bn.Settings.BirdNET.RangeFilter.Threshold > 1 {
+		fmt.Println("Invalid LocationFilterThreshold value, using default value of 0.01")
+		bn.Settings.BirdNET.RangeFilter.Threshold = 0.01
+	}
+
+	source := bn.Settings.ResolveSourceSettings("")
+	return bn.GetProbableSpeciesForSource(date, week, &source)
+}
+
+// GetProbableSpeciesForSource filters and sorts bird species for the location,
+// range filter threshold and species lists of a single audio source.
+func (bn *BirdNET) GetProbableSpeciesForSource(date time.Time, week float32, source *conf.SourceSettings) ([]SpeciesScore, error) {
 	bn.Debug("Applying range filter")
 	// Skip filtering if location is not set
-	if bn.Settings.BirdNET.Latitude == 0 && bn.Settings.BirdNET.Longitude == 0 {
+	if source.Latitude == 0 && source.Longitude == 0 {
 		bn.Debug("Latitude and longitude not set, not using location based prediction filter")
 		var speciesScores []SpeciesScore
 		for _, label := range bn.Settings.BirdNET.Labels {
 			speciesScores = append(speciesScores, SpeciesScore{Score: 0.0, Label: label})
 		}
+		for _, label := range bn.ensembleOnlyLabels() {
+			speciesScores = append(speciesScores, SpeciesScore{Score: 0.0, Label: label})
+		}
 		return speciesScores, nil
 	}
 
 	// Apply prediction filter based on the context
-	filters, err := bn.predictFilter(date, week)
+	filters, err := bn.predictFilter(date, week, source.Latitude, source.Longitude, source.RangeFilterThreshold)
 	if err != nil {
 		return nil, errors.New(err).
 			Category(errors.CategoryValidation).
@@ -108,19 +157,12 @@ func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesSc
 			Build()
 	}
 
-	// check bn.Settings.BirdNET.LocationFilterThreshold for valid value
-	if bn.Settings.BirdNET.RangeFilter.Threshold < 0 ||
-		bn.Settings.BirdNET.RangeFilter.Threshold > 1 {
-		fmt.Println("Invalid LocationFilterThreshold value, using default value of 0.01")
-		bn.Settings.BirdNET.RangeFilter.Threshold = 0.01
-	}
-
 	// Collect species scores above a certain threshold
 	var speciesScores []SpeciesScore
 	for _, filter := range filters {
-		if filter.Score >= bn.Settings.BirdNET.RangeFilter.Threshold {
+		if filter.Score >= source.RangeFilterThreshold {
 			// Check if species is in exclude list before adding
-			if !isSpeciesExcluded(filter.Label, bn.Settings.Realtime.Species.Exclude) {
+			if !isSpeciesExcluded(filter.Label, source.Exclude) {
 				speciesScores = append(speciesScores, SpeciesScore{Score: float64(filter.Score), Label: filter.Label})
 			} else {
 				bn.Debug("Excluding species from range filter: %s", filter.Label)
@@ -128,11 +170,19 @@ func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesSc
 		}
 	}
 
+	// Species only known to ensemble models are not covered by the range
+	// filter model, include them unless excluded
+	for _, label := range bn.ensembleOnlyLabels() {
+		if !isSpeciesExcluded(label, source.Exclude) {
+			speciesScores = append(speciesScores, SpeciesScore{Score: 1.0, Label: label})
+		}
+	}
+
 	// Add included species and species with actions with maximum score
 	processedSpecies := make(map[string]bool)
 
 	// Process explicitly included species
-	for _, includedSpecies := range bn.Settings.Realtime.Species.Include {
+	for _, includedSpecies := range source.Include {
 		bn.Debug("Processing included species: %s", includedSpecies)
 		addSpeciesWithMaxScore(bn, &speciesScores, includedSpecies, processedSpecies)
 	}
@@ -187,7 +237,7 @@ func matchesSpecies(label, speciesName string) bool {
 }
 
 // predictFilter applies a TensorFlow Lite model to predict species based on the context.
-func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error) {
+func (bn *BirdNET) predictFilter(date time.Time, week float32, latitude, longitude float64, threshold float32) ([]Filter, error) {
 	start := time.Now()
 
 	input := bn.RangeInterpreter.GetInputTensor(0)
@@ -205,7 +255,7 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	}
 
 	// Prepare the input data
-	data := []float32{float32(bn.Settings.BirdNET.Latitude), float32(bn.Settings.BirdNET.Longitude), week}
+	data := []float32{float32(latitude), float32(longitude), week}
 
 	// Retrieve the input tensor's underlying data slice
 	float32s := input.Float32s()
@@ -229,8 +279,8 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 			Category(errors.CategoryModelInit).
 			Context("model_type", "range_filter").
 			Context("status_code", status).
-			Context("latitude", bn.Settings.BirdNET.Latitude).
-			Context("longitude", bn.Settings.BirdNET.Longitude).
+			Context("latitude", latitude).
+			Context("longitude", longitude).
 			Context("week", week).
 			Timing("range-filter-invoke", time.Since(start)).
 			Build()
@@ -247,7 +297,7 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	// Filter and label the results, but only for indices that exist in bn.Labels
 	var results []Filter
 	for i, score := range filter {
-		if score >= bn.Settings.BirdNET.RangeFilter.Threshold && i < len(bn.Settings.BirdNET.Labels) {
+		if score >= threshold && i < len(bn.Settings.BirdNET.Labels) {
 			results = append(results, Filter{Score: score, Label: bn.Settings.BirdNET.Labels[i]})
 		}
 	}
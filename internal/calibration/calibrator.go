@@ -0,0 +1,167 @@
+package calibration
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// ReviewSource provides reviewed detections, implemented by datastore.Interface.
+type ReviewSource interface {
+	GetReviewedDetections(scientificName string) ([]datastore.ReviewedDetection, error)
+}
+
+// Calibrator keeps the calibration curves of all species with enough reviewed
+// detections and refits them periodically.
+type Calibrator struct {
+	source     ReviewSource
+	method     string
+	minSamples int
+
+	mu      sync.RWMutex
+	curves  map[string]*Curve // keyed by lower case common and scientific name
+	list    []*Curve          // sorted by scientific name
+	updated time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// New creates a calibrator. Species with fewer than minSamples reviewed
+// detections are not calibrated.
+func New(source ReviewSource, method string, minSamples int) *Calibrator {
+	if minSamples < 2 {
+		minSamples = 2
+	}
+	return &Calibrator{
+		source:     source,
+		method:     method,
+		minSamples: minSamples,
+		curves:     make(map[string]*Curve),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Refresh refits all calibration curves from the reviewed detections.
+func (c *Calibrator) Refresh() error {
+	reviewed, err := c.source.GetReviewedDetections("")
+	if err != nil {
+		return err
+	}
+
+	// Group samples by species
+	type speciesSamples struct {
+		commonName string
+		samples    []Sample
+	}
+	bySpecies := make(map[string]*speciesSamples)
+	for _, r := range reviewed {
+		s, ok := bySpecies[r.ScientificName]
+		if !ok {
+			s = &speciesSamples{commonName: r.CommonName}
+			bySpecies[r.ScientificName] = s
+		}
+		s.samples = append(s.samples, Sample{Confidence: r.Confidence, Correct: r.Verified == "correct"})
+	}
+
+	curves := make(map[string]*Curve, len(bySpecies)*2)
+	list := make([]*Curve, 0, len(bySpecies))
+	for scientificName, s := range bySpecies {
+		if len(s.samples) < c.minSamples {
+			continue
+		}
+		curve, err := Fit(c.method, s.samples)
+		if err != nil {
+			return err
+		}
+		curve.ScientificName = scientificName
+		curve.CommonName = s.commonName
+
+		curves[strings.ToLower(scientificName)] = curve
+		if s.commonName != "" {
+			curves[strings.ToLower(s.commonName)] = curve
+		}
+		list = append(list, curve)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ScientificName < list[j].ScientificName
+	})
+
+	c.mu.Lock()
+	c.curves = curves
+	c.list = list
+	c.updated = time.Now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Curve returns the calibration curve of a species by common or scientific name.
+func (c *Calibrator) Curve(species string) (*Curve, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	curve, ok := c.curves[strings.ToLower(species)]
+	return curve, ok
+}
+
+// Curves returns all calibration curves sorted by scientific name.
+func (c *Calibrator) Curves() []*Curve {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	list := make([]*Curve, len(c.list))
+	copy(list, c.list)
+	return list
+}
+
+// LastUpdated returns the time the curves were last refitted.
+func (c *Calibrator) LastUpdated() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.updated
+}
+
+// Threshold returns the raw confidence threshold at which detections of the
+// species reach the target calibrated probability. It returns false if the
+// species has no calibration curve or the curve never reaches the target.
+func (c *Calibrator) Threshold(species string, target float64) (float64, bool) {
+	curve, ok := c.Curve(species)
+	if !ok {
+		return 0, false
+	}
+	return curve.ThresholdFor(target)
+}
+
+// Start refits the curves immediately and then at the given interval until
+// Stop is called.
+func (c *Calibrator) Start(interval time.Duration) {
+	go func() {
+		if err := c.Refresh(); err != nil {
+			log.Printf("⚠️ Failed to fit confidence calibration curves: %v", err)
+		}
+		if interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.Refresh(); err != nil {
+					log.Printf("⚠️ Failed to refit confidence calibration curves: %v", err)
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops periodic refitting.
+func (c *Calibrator) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
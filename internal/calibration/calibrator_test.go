@@ -0,0 +1,66 @@
+package calibration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// fakeReviewSource returns a fixed list of reviewed detections
+type fakeReviewSource struct {
+	reviewed []datastore.ReviewedDetection
+}
+
+func (f *fakeReviewSource) GetReviewedDetections(scientificName string) ([]datastore.ReviewedDetection, error) {
+	return f.reviewed, nil
+}
+
+func TestCalibratorRefresh(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeReviewSource{}
+	for _, s := range syntheticSamples() {
+		verified := "false_positive"
+		if s.Correct {
+			verified = "correct"
+		}
+		source.reviewed = append(source.reviewed, datastore.ReviewedDetection{
+			ScientificName: "Turdus merula",
+			CommonName:     "Eurasian Blackbird",
+			Confidence:     s.Confidence,
+			Verified:       verified,
+		})
+	}
+	// Too few reviews to calibrate
+	source.reviewed = append(source.reviewed, datastore.ReviewedDetection{
+		ScientificName: "Parus major",
+		CommonName:     "Great Tit",
+		Confidence:     0.9,
+		Verified:       "correct",
+	})
+
+	c := New(source, MethodLogistic, 20)
+	require.NoError(t, c.Refresh())
+	assert.False(t, c.LastUpdated().IsZero())
+
+	curves := c.Curves()
+	require.Len(t, curves, 1)
+	assert.Equal(t, "Turdus merula", curves[0].ScientificName)
+	assert.Equal(t, 57, curves[0].Samples)
+
+	// Lookup by either name, case insensitive
+	byCommon, ok := c.Curve("eurasian blackbird")
+	require.True(t, ok)
+	byScientific, ok := c.Curve("Turdus Merula")
+	require.True(t, ok)
+	assert.Same(t, byCommon, byScientific)
+
+	_, ok = c.Threshold("great tit", 0.8)
+	assert.False(t, ok)
+
+	threshold, ok := c.Threshold("eurasian blackbird", 0.8)
+	require.True(t, ok)
+	assert.InDelta(t, 0.8, byCommon.Probability(threshold), 0.0001)
+}
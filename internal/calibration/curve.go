@@ -0,0 +1,226 @@
+// Package calibration fits per-species calibration curves that map raw
+// BirdNET confidence values to the probability that a detection is correct,
+// based on detections the user has reviewed as correct or false positive.
+package calibration
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Supported calibration methods
+const (
+	MethodLogistic = "logistic" // Platt scaling, smooth two parameter sigmoid
+	MethodIsotonic = "isotonic" // Monotonic step function, needs more samples
+)
+
+// Sample is a single reviewed detection.
+type Sample struct {
+	Confidence float64 // Raw model confidence
+	Correct    bool    // True if the detection was reviewed as correct
+}
+
+// Point is a step of an isotonic calibration curve. Confidence values from
+// Confidence up to the next point map to Probability.
+type Point struct {
+	Confidence  float64 `json:"confidence"`
+	Probability float64 `json:"probability"`
+}
+
+// Curve maps raw confidence values of a species to calibrated probabilities.
+type Curve struct {
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Method         string  `json:"method"`
+	Samples        int     `json:"samples"`             // Number of reviewed detections
+	Positives      int     `json:"positives"`           // Number of detections reviewed as correct
+	MinConfidence  float64 `json:"minConfidence"`       // Lowest reviewed confidence, the curve is unsupported below it
+	Slope          float64 `json:"slope,omitempty"`     // Logistic curve slope
+	Intercept      float64 `json:"intercept,omitempty"` // Logistic curve intercept
+	Points         []Point `json:"points,omitempty"`    // Isotonic curve steps, ascending by confidence
+}
+
+// Fit fits a calibration curve to the given samples.
+func Fit(method string, samples []Sample) (*Curve, error) {
+	if len(samples) < 2 {
+		return nil, errors.Newf("at least 2 reviewed detections are required for calibration, got %d", len(samples)).
+			Component("calibration").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	curve := &Curve{Method: method, Samples: len(samples), MinConfidence: samples[0].Confidence}
+	for _, s := range samples {
+		if s.Correct {
+			curve.Positives++
+		}
+		curve.MinConfidence = math.Min(curve.MinConfidence, s.Confidence)
+	}
+
+	switch method {
+	case MethodLogistic, "":
+		curve.Method = MethodLogistic
+		curve.Slope, curve.Intercept = fitLogistic(samples)
+	case MethodIsotonic:
+		curve.Points = fitIsotonic(samples)
+	default:
+		return nil, errors.Newf("unsupported calibration method: %s", method).
+			Component("calibration").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	return curve, nil
+}
+
+// Probability returns the calibrated probability for a raw confidence value.
+func (c *Curve) Probability(confidence float64) float64 {
+	if c.Method == MethodIsotonic {
+		if len(c.Points) == 0 {
+			return 0
+		}
+		// Index of the first step above the confidence, the step before applies
+		i := sort.Search(len(c.Points), func(i int) bool {
+			return c.Points[i].Confidence > confidence
+		})
+		if i == 0 {
+			return c.Points[0].Probability
+		}
+		return c.Points[i-1].Probability
+	}
+	return sigmoid(c.Slope*confidence + c.Intercept)
+}
+
+// ThresholdFor returns the lowest raw confidence whose calibrated probability
+// reaches the target. It returns false if the curve never reaches the target
+// within the valid confidence range. The threshold is never below the lowest
+// reviewed confidence, as there are no reviews supporting the curve below it.
+func (c *Curve) ThresholdFor(target float64) (float64, bool) {
+	if c.Method == MethodIsotonic {
+		for _, p := range c.Points {
+			if p.Probability >= target {
+				return math.Max(p.Confidence, c.MinConfidence), true
+			}
+		}
+		return 0, false
+	}
+
+	if c.Slope <= 0 || target <= 0 || target >= 1 {
+		return 0, false
+	}
+	threshold := (math.Log(target/(1-target)) - c.Intercept) / c.Slope
+	if threshold > 1 {
+		return 0, false
+	}
+	return math.Max(threshold, c.MinConfidence), true
+}
+
+// sigmoid is the logistic function.
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// fitLogistic fits p = sigmoid(a*x + b) with Newton's method using Platt's
+// smoothed targets, which keeps the fit finite when all samples share a label.
+func fitLogistic(samples []Sample) (slope, intercept float64) {
+	const (
+		maxIterations  = 100
+		regularization = 1e-6
+		tolerance      = 1e-9
+	)
+
+	positives := 0
+	for _, s := range samples {
+		if s.Correct {
+			positives++
+		}
+	}
+	negatives := len(samples) - positives
+	hiTarget := (float64(positives) + 1) / (float64(positives) + 2)
+	loTarget := 1 / (float64(negatives) + 2)
+
+	a, b := 0.0, math.Log((float64(positives)+1)/(float64(negatives)+1))
+	for iter := 0; iter < maxIterations; iter++ {
+		// Gradient and Hessian of the negative log likelihood
+		var ga, gb, haa, hab, hbb float64
+		for _, s := range samples {
+			y := loTarget
+			if s.Correct {
+				y = hiTarget
+			}
+			p := sigmoid(a*s.Confidence + b)
+			d := p - y
+			w := p * (1 - p)
+			ga += d * s.Confidence
+			gb += d
+			haa += w * s.Confidence * s.Confidence
+			hab += w * s.Confidence
+			hbb += w
+		}
+		haa += regularization
+		hbb += regularization
+
+		det := haa*hbb - hab*hab
+		if det <= 0 {
+			break
+		}
+		da := (hbb*ga - hab*gb) / det
+		db := (haa*gb - hab*ga) / det
+		a -= da
+		b -= db
+
+		if math.Abs(da) < tolerance && math.Abs(db) < tolerance {
+			break
+		}
+	}
+
+	return a, b
+}
+
+// fitIsotonic fits a non-decreasing step function with the pool adjacent
+// violators algorithm.
+func fitIsotonic(samples []Sample) []Point {
+	sorted := make([]Sample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Confidence < sorted[j].Confidence
+	})
+
+	type block struct {
+		start float64 // lowest confidence in the block
+		end   float64 // highest confidence in the block
+		sum   float64 // number of correct detections
+		count float64
+	}
+
+	blocks := make([]block, 0, len(sorted))
+	for _, s := range sorted {
+		b := block{start: s.Confidence, end: s.Confidence, count: 1}
+		if s.Correct {
+			b.sum = 1
+		}
+		// Equal confidence values always share a block
+		if n := len(blocks); n > 0 && blocks[n-1].end == s.Confidence {
+			blocks[n-1].sum += b.sum
+			blocks[n-1].count++
+		} else {
+			blocks = append(blocks, b)
+		}
+
+		// Merge blocks while they violate monotonicity
+		for n := len(blocks); n > 1 && blocks[n-2].sum/blocks[n-2].count > blocks[n-1].sum/blocks[n-1].count; n = len(blocks) {
+			blocks[n-2].sum += blocks[n-1].sum
+			blocks[n-2].count += blocks[n-1].count
+			blocks[n-2].end = blocks[n-1].end
+			blocks = blocks[:n-1]
+		}
+	}
+
+	points := make([]Point, 0, len(blocks))
+	for _, b := range blocks {
+		points = append(points, Point{Confidence: b.start, Probability: b.sum / b.count})
+	}
+	return points
+}
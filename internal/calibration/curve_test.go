@@ -0,0 +1,123 @@
+package calibration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticSamples returns samples where detections above 0.6 are mostly
+// correct and detections below it mostly false positives.
+func syntheticSamples() []Sample {
+	var samples []Sample
+	for i := 1; i <= 19; i++ {
+		confidence := float64(i) / 20
+		correct := confidence > 0.6
+		samples = append(samples,
+			Sample{Confidence: confidence, Correct: correct},
+			Sample{Confidence: confidence, Correct: correct},
+			Sample{Confidence: confidence, Correct: correct != (i%4 == 0)}, // every 4th level has a mislabeled review
+		)
+	}
+	return samples
+}
+
+func TestFitLogistic(t *testing.T) {
+	t.Parallel()
+
+	curve, err := Fit(MethodLogistic, syntheticSamples())
+	require.NoError(t, err)
+	assert.Equal(t, MethodLogistic, curve.Method)
+	assert.Equal(t, 57, curve.Samples)
+	assert.Positive(t, curve.Slope)
+
+	// Probability increases with confidence
+	assert.Less(t, curve.Probability(0.2), curve.Probability(0.5))
+	assert.Less(t, curve.Probability(0.5), curve.Probability(0.9))
+	assert.Greater(t, curve.Probability(0.95), 0.8)
+	assert.Less(t, curve.Probability(0.05), 0.2)
+
+	threshold, ok := curve.ThresholdFor(0.8)
+	require.True(t, ok)
+	assert.InDelta(t, 0.8, curve.Probability(threshold), 0.0001)
+	assert.Greater(t, threshold, 0.4)
+	assert.Less(t, threshold, 0.9)
+
+	// Targets outside (0, 1) are never reached
+	_, ok = curve.ThresholdFor(1)
+	assert.False(t, ok)
+}
+
+func TestFitIsotonic(t *testing.T) {
+	t.Parallel()
+
+	samples := []Sample{
+		{0.1, false}, {0.2, false}, {0.3, true}, {0.4, false},
+		{0.5, true}, {0.6, true}, {0.7, false}, {0.8, true}, {0.9, true},
+	}
+	curve, err := Fit(MethodIsotonic, samples)
+	require.NoError(t, err)
+	assert.Equal(t, 5, curve.Positives)
+
+	// Steps are non-decreasing
+	for i := 1; i < len(curve.Points); i++ {
+		assert.GreaterOrEqual(t, curve.Points[i].Probability, curve.Points[i-1].Probability)
+	}
+
+	assert.InDelta(t, 0.0, curve.Probability(0.15), 0.0001)
+	assert.InDelta(t, 0.5, curve.Probability(0.35), 0.0001)
+	assert.InDelta(t, 1.0, curve.Probability(0.95), 0.0001)
+
+	threshold, ok := curve.ThresholdFor(0.9)
+	require.True(t, ok)
+	assert.InDelta(t, 0.8, threshold, 0.0001)
+}
+
+func TestFitErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Fit(MethodLogistic, []Sample{{0.5, true}})
+	require.Error(t, err)
+
+	_, err = Fit("spline", syntheticSamples())
+	require.Error(t, err)
+}
+
+func TestFitLogisticSingleLabel(t *testing.T) {
+	t.Parallel()
+
+	// All detections correct: the fit must stay finite and never reach certainty
+	samples := []Sample{{0.5, true}, {0.6, true}, {0.7, true}, {0.8, true}}
+	curve, err := Fit(MethodLogistic, samples)
+	require.NoError(t, err)
+	p := curve.Probability(0.9)
+	assert.Greater(t, p, 0.5)
+	assert.Less(t, p, 1.0)
+}
+
+func TestThresholdForClampsToReviewedRange(t *testing.T) {
+	t.Parallel()
+
+	// Reviews only exist above the threshold in use, the low end of the curve is extrapolated
+	samples := []Sample{
+		{0.6, false}, {0.65, true}, {0.7, false}, {0.75, true},
+		{0.8, true}, {0.85, false}, {0.9, true}, {0.95, true},
+	}
+	for _, method := range []string{MethodLogistic, MethodIsotonic} {
+		curve, err := Fit(method, samples)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.6, curve.MinConfidence, 0.0001)
+
+		threshold, ok := curve.ThresholdFor(0.01)
+		require.True(t, ok, method)
+		assert.GreaterOrEqual(t, threshold, 0.6, "%s threshold is below the lowest reviewed confidence", method)
+	}
+
+	// The logistic curve reaches low targets far below the reviews
+	curve, err := Fit(MethodLogistic, samples)
+	require.NoError(t, err)
+	threshold, ok := curve.ThresholdFor(0.01)
+	require.True(t, ok)
+	assert.InDelta(t, 0.6, threshold, 0.0001)
+}
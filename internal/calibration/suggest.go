@@ -0,0 +1,204 @@
+// suggest.go computes per-species threshold suggestions from reviewed detections
+package calibration
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// Default threshold suggestion options
+const (
+	DefaultSuggestMinReviews       = 20
+	DefaultSuggestTargetPrecision  = 0.9
+	defaultSuggestCandidateStep    = 0.05
+	defaultSuggestCandidateMinimum = 0.1
+)
+
+// ThresholdStats is the precision and recall of reviewed detections at a
+// candidate threshold. Recall is relative to the detections reviewed as
+// correct, detections below the threshold in use at the time were never
+// stored and cannot be accounted for.
+type ThresholdStats struct {
+	Threshold      float64 `json:"threshold"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+	TruePositives  int     `json:"true_positives"`  // Correct detections at or above the threshold
+	FalsePositives int     `json:"false_positives"` // False positives at or above the threshold
+}
+
+// Suggestion is the threshold suggestion for a single species.
+type Suggestion struct {
+	ScientificName     string           `json:"scientific_name"`
+	CommonName         string           `json:"common_name"`
+	Reviews            int              `json:"reviews"`
+	Correct            int              `json:"correct"`
+	FalsePositives     int              `json:"false_positives"`
+	CurrentThreshold   float64          `json:"current_threshold"`
+	CustomThreshold    bool             `json:"custom_threshold"` // True if the current threshold is set in species config
+	SuggestedThreshold float64          `json:"suggested_threshold"`
+	TargetReached      bool             `json:"target_reached"` // False if no candidate reaches the target precision
+	Current            ThresholdStats   `json:"current"`        // Stats at the current threshold
+	Suggested          ThresholdStats   `json:"suggested"`      // Stats at the suggested threshold
+	Candidates         []ThresholdStats `json:"candidates"`
+}
+
+// Changed reports whether the suggested threshold differs from the current one.
+func (s *Suggestion) Changed() bool {
+	const epsilon = 1e-9
+	diff := s.SuggestedThreshold - s.CurrentThreshold
+	return diff > epsilon || diff < -epsilon
+}
+
+// SuggestOptions controls how thresholds are suggested.
+type SuggestOptions struct {
+	MinReviews      int       // Species with fewer reviews are skipped
+	TargetPrecision float64   // Precision the suggested threshold should reach
+	Candidates      []float64 // Candidate thresholds, defaults to 0.10 to 0.95 in 0.05 steps
+	Source          string    // Only use reviews of this audio source and its threshold, empty for all sources
+	ChangedOnly     bool      // Skip species whose current threshold is kept
+}
+
+// defaultCandidates returns the default candidate thresholds.
+func defaultCandidates() []float64 {
+	var candidates []float64
+	for t := defaultSuggestCandidateMinimum; t < 1-defaultSuggestCandidateStep/2; t += defaultSuggestCandidateStep {
+		// Round to avoid accumulated floating point error in the step
+		candidates = append(candidates, float64(int(t*100+0.5))/100)
+	}
+	return candidates
+}
+
+// CurrentThreshold returns the threshold in use for a species on an audio
+// source and whether it is a custom threshold from the species config. Sources
+// without a threshold override, or an empty source, use the global threshold.
+func CurrentThreshold(settings *conf.Settings, commonName, source string) (threshold float64, custom bool) {
+	if config, exists := settings.Realtime.Species.Config[strings.ToLower(commonName)]; exists {
+		return config.Threshold, true
+	}
+	return settings.ResolveSourceSettings(source).Threshold, false
+}
+
+// SuggestThresholds computes precision and recall at the candidate thresholds
+// for every species with enough reviews and proposes the lowest threshold that
+// reaches the target precision. The suggestion never goes below the current
+// threshold because detections below it were not stored and have no reviews.
+// When no candidate reaches the target the candidate with the best F1 score
+// is suggested. Results are sorted by number of false positives, most first.
+func SuggestThresholds(settings *conf.Settings, reviewed []datastore.ReviewedDetection, opts SuggestOptions) []Suggestion {
+	if opts.MinReviews <= 0 {
+		opts.MinReviews = DefaultSuggestMinReviews
+	}
+	if opts.TargetPrecision <= 0 || opts.TargetPrecision > 1 {
+		opts.TargetPrecision = DefaultSuggestTargetPrecision
+	}
+	if len(opts.Candidates) == 0 {
+		opts.Candidates = defaultCandidates()
+	}
+	sort.Float64s(opts.Candidates)
+
+	// Group reviews by species
+	sources := settings.StoredSources(opts.Source)
+	bySpecies := make(map[string][]datastore.ReviewedDetection)
+	var order []string
+	for i := range reviewed {
+		if opts.Source != "" && !slices.Contains(sources, reviewed[i].Source) {
+			continue
+		}
+		name := reviewed[i].ScientificName
+		if _, exists := bySpecies[name]; !exists {
+			order = append(order, name)
+		}
+		bySpecies[name] = append(bySpecies[name], reviewed[i])
+	}
+
+	suggestions := make([]Suggestion, 0, len(order))
+	for _, scientificName := range order {
+		reviews := bySpecies[scientificName]
+		if len(reviews) < opts.MinReviews {
+			continue
+		}
+
+		s := Suggestion{
+			ScientificName: scientificName,
+			CommonName:     reviews[0].CommonName,
+			Reviews:        len(reviews),
+		}
+		for i := range reviews {
+			if reviews[i].Verified == "correct" {
+				s.Correct++
+			} else {
+				s.FalsePositives++
+			}
+		}
+		s.CurrentThreshold, s.CustomThreshold = CurrentThreshold(settings, s.CommonName, opts.Source)
+		s.Current = thresholdStats(reviews, s.CurrentThreshold, s.Correct)
+
+		s.Candidates = make([]ThresholdStats, 0, len(opts.Candidates))
+		for _, candidate := range opts.Candidates {
+			s.Candidates = append(s.Candidates, thresholdStats(reviews, candidate, s.Correct))
+		}
+
+		// Keep the current threshold if it already reaches the target
+		s.Suggested = s.Current
+		s.TargetReached = s.Current.Precision >= opts.TargetPrecision
+		if !s.TargetReached {
+			best := s.Current
+			for _, stats := range s.Candidates {
+				if stats.Threshold < s.CurrentThreshold {
+					continue
+				}
+				if stats.Precision >= opts.TargetPrecision && stats.TruePositives > 0 {
+					best = stats
+					s.TargetReached = true
+					break
+				}
+				if stats.F1 > best.F1 {
+					best = stats
+				}
+			}
+			s.Suggested = best
+		}
+		s.SuggestedThreshold = s.Suggested.Threshold
+
+		if opts.ChangedOnly && !s.Changed() {
+			continue
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].FalsePositives > suggestions[j].FalsePositives
+	})
+	return suggestions
+}
+
+// thresholdStats computes the stats of the reviews at a threshold.
+func thresholdStats(reviews []datastore.ReviewedDetection, threshold float64, correct int) ThresholdStats {
+	stats := ThresholdStats{Threshold: threshold}
+	for i := range reviews {
+		if reviews[i].Confidence < threshold {
+			continue
+		}
+		if reviews[i].Verified == "correct" {
+			stats.TruePositives++
+		} else {
+			stats.FalsePositives++
+		}
+	}
+
+	if accepted := stats.TruePositives + stats.FalsePositives; accepted > 0 {
+		stats.Precision = float64(stats.TruePositives) / float64(accepted)
+	}
+	if correct > 0 {
+		stats.Recall = float64(stats.TruePositives) / float64(correct)
+	}
+	if stats.Precision+stats.Recall > 0 {
+		stats.F1 = 2 * stats.Precision * stats.Recall / (stats.Precision + stats.Recall)
+	}
+	return stats
+}
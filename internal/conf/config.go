@@ -63,6 +63,14 @@ type SoundLevelSettings struct {
 	Interval             int  `yaml:"interval" mapstructure:"interval" json:"interval"`                             // measurement interval in seconds (default: 10)
 	Debug                bool `yaml:"debug" mapstructure:"debug" json:"debug"`                                   // true to enable debug logging for sound level monitoring
 	DebugRealtimeLogging bool `yaml:"debug_realtime_logging" mapstructure:"debug_realtime_logging" json:"debugRealtimeLogging"` // true to log debug messages for every realtime update, false to log only at configured interval
+	Storage              SoundLevelStorageSettings `yaml:"storage" mapstructure:"storage" json:"storage"`             // persistence of downsampled sound levels in the database
+}
+
+// SoundLevelStorageSettings contains settings for storing sound level history
+type SoundLevelStorageSettings struct {
+	Enabled       bool `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                   // true to store sound levels in the database
+	Interval      int  `yaml:"interval" mapstructure:"interval" json:"interval"`                // aggregation period of stored levels in seconds (default: 60)
+	RetentionDays int  `yaml:"retentiondays" mapstructure:"retentiondays" json:"retentionDays"` // days to keep stored levels, 0 keeps all
 }
 
 type AudioSettings struct {
@@ -102,6 +110,16 @@ type DynamicThresholdSettings struct {
 	ValidHours int     `json:"validHours"` // number of hours to consider for dynamic threshold
 }
 
+// CalibrationSettings contains settings for per-species confidence calibration
+// fitted from reviewed detections.
+type CalibrationSettings struct {
+	Enabled         bool    `json:"enabled"`         // true to use calibrated thresholds for species with enough reviews
+	Method          string  `json:"method"`          // calibration method: logistic or isotonic
+	Target          float64 `json:"target"`          // calibrated probability of being correct a detection must reach
+	MinSamples      int     `json:"minSamples"`      // minimum number of reviewed detections to calibrate a species
+	RefreshInterval int     `json:"refreshInterval"` // hours between refitting curves from reviews
+}
+
 // RetrySettings contains common settings for retry mechanisms
 type RetrySettings struct {
 	Enabled           bool    `json:"enabled"`           // true to enable retry mechanism
@@ -162,6 +180,60 @@ type DogBarkFilterSettings struct {
 	Species    []string `json:"species"`    // species list for filtering
 }
 
+// DetectionFilterSettings contains settings for the filter chain deciding
+// whether pending detections are discarded before their actions run.
+type DetectionFilterSettings struct {
+	Order       []string                  `json:"order"`       // filter order, unlisted filters run after in default order
+	Consecutive ConsecutiveFilterSettings `json:"consecutive"` // minimum matches filter settings
+	Noise       NoiseFilterSettings       `json:"noise"`       // rain and wind noise filter settings
+	Weather     WeatherFilterSettings     `json:"weather"`     // weather data filter settings
+	Cooldown    CooldownFilterSettings    `json:"cooldown"`    // species cooldown filter settings
+}
+
+// ConsecutiveFilterSettings contains settings for the filter discarding
+// detections matched too few times before they are flushed.
+type ConsecutiveFilterSettings struct {
+	MinDetections int `json:"minDetections"` // minimum matches, 0 to derive from the analysis overlap
+}
+
+// NoiseFilterSettings contains settings for the filter discarding detections
+// made while rain, wind or other noise is detected.
+type NoiseFilterSettings struct {
+	Enabled    bool     `json:"enabled"`    // true to enable noise filter
+	Confidence float32  `json:"confidence"` // confidence threshold for noise detection
+	Labels     []string `json:"labels"`     // labels counted as noise, matched case-insensitively as substrings
+	Remember   int      `json:"remember"`   // seconds before a detection that noise still discards it
+	Species    []string `json:"species"`    // species list for filtering, empty for all species
+}
+
+// WeatherFilterSettings contains settings for the filter raising thresholds
+// or holding detections for review during heavy rain or strong wind, based on
+// the latest weather data. Speeds are in the units of the weather provider.
+type WeatherFilterSettings struct {
+	Enabled           bool     `json:"enabled"`           // true to enable weather filter
+	Action            string   `json:"action"`            // threshold to raise the confidence threshold, review to hold detections for review
+	WindSpeed         float64  `json:"windSpeed"`         // wind speed at or above which the filter applies, 0 to ignore wind speed
+	WindGust          float64  `json:"windGust"`          // wind gust speed at or above which the filter applies, 0 to ignore gusts
+	Conditions        []string `json:"conditions"`        // weather descriptions counted as heavy rain, matched as substrings ignoring case, spaces and underscores
+	ThresholdIncrease float64  `json:"thresholdIncrease"` // added to the confidence threshold when action is threshold
+	MaxAge            int      `json:"maxAge"`            // minutes weather data is considered current
+	Species           []string `json:"species"`           // species list for filtering, empty for all species
+}
+
+// CooldownFilterSettings contains settings for the filter allowing a species
+// to be detected only once in an interval.
+type CooldownFilterSettings struct {
+	Enabled  bool `json:"enabled"`  // true to enable cooldown for all species
+	Interval int  `json:"interval"` // minutes after an approved detection the species is discarded
+}
+
+// SpeciesFilterSettings contains per-species detection filter overrides
+type SpeciesFilterSettings struct {
+	Disable       []string `yaml:"disable,omitempty" json:"disable,omitempty"`             // filters not run for this species
+	MinDetections int      `yaml:"mindetections,omitempty" json:"minDetections,omitempty"` // minimum matches, 0 for the global setting
+	Cooldown      int      `yaml:"cooldown,omitempty" json:"cooldown,omitempty"`           // cooldown minutes, 0 for the global setting
+}
+
 // RTSPHealthSettings contains settings for RTSP stream health monitoring.
 type RTSPHealthSettings struct {
 	HealthyDataThreshold int `json:"healthyDataThreshold"` // seconds before stream considered unhealthy (default: 60)
@@ -170,10 +242,32 @@ type RTSPHealthSettings struct {
 
 // RTSPSettings contains settings for RTSP streaming.
 type RTSPSettings struct {
-	Transport        string              `json:"transport"`        // RTSP Transport Protocol
-	URLs             []string            `json:"urls"`             // RTSP stream URL
-	Health           RTSPHealthSettings  `json:"health"`           // health monitoring settings
-	FFmpegParameters []string            `json:"ffmpegParameters"` // optional custom FFmpeg parameters
+	Transport        string               `json:"transport"`        // RTSP Transport Protocol
+	URLs             []string             `json:"urls"`             // RTSP stream URL
+	Sources          []RTSPSourceSettings `json:"sources"`          // named RTSP sources with per-source settings
+	Health           RTSPHealthSettings   `json:"health"`           // health monitoring settings
+	FFmpegParameters []string             `json:"ffmpegParameters"` // optional custom FFmpeg parameters
+}
+
+// RTSPSourceSettings defines a named RTSP source. Location, threshold and
+// species list fields are optional, unset values fall back to the global
+// BirdNET and species settings.
+type RTSPSourceSettings struct {
+	Name        string                    `json:"name"`                                           // display name of the source
+	URL         string                    `json:"url"`                                            // RTSP stream URL
+	Latitude    *float64                  `yaml:"latitude,omitempty" json:"latitude,omitempty"`   // latitude of the source location
+	Longitude   *float64                  `yaml:"longitude,omitempty" json:"longitude,omitempty"` // longitude of the source location
+	Threshold   *float64                  `yaml:"threshold,omitempty" json:"threshold,omitempty"` // threshold for prediction confidence to report
+	RangeFilter SourceRangeFilterSettings `json:"rangeFilter"`                                    // per-source range filter settings
+	Include     []string                  `yaml:"include" json:"include"`                         // species always included for this source
+	Exclude     []string                  `yaml:"exclude" json:"exclude"`                         // species always excluded for this source
+}
+
+// SourceRangeFilterSettings contains per-source range filter settings.
+type SourceRangeFilterSettings struct {
+	Threshold   *float32  `yaml:"threshold,omitempty" json:"threshold,omitempty"` // rangefilter species occurrence threshold
+	Species     []string  `yaml:"-" json:"species,omitempty"`                     // list of included species, runtime value
+	LastUpdated time.Time `yaml:"-" json:"lastUpdated,omitempty"`                 // last time the species list was updated, runtime value
 }
 
 // MQTTSettings contains settings for MQTT integration.
@@ -198,6 +292,26 @@ type MQTTTLSSettings struct {
 	ClientKey          string `yaml:"clientkey,omitempty" json:"clientKey,omitempty"`   // path to client key file (managed internally)
 }
 
+// WebhookSettings contains settings for posting detections to an HTTP endpoint.
+type WebhookSettings struct {
+	Name          string            `json:"name"`          // name of the webhook used in logs
+	Enabled       bool              `json:"enabled"`       // true to enable the webhook
+	URL           string            `json:"url"`           // URL of the endpoint
+	Method        string            `json:"method"`        // HTTP method, default POST
+	Headers       map[string]string `json:"headers"`       // additional HTTP headers
+	Template      string            `json:"template"`      // Go template for the request body, default is the note as JSON
+	Secret        string            `json:"secret"`        // secret for HMAC-SHA256 signing of the request body
+	Timeout       int               `json:"timeout"`       // request timeout in seconds, default 10
+	RetrySettings RetrySettings     `json:"retrySettings"` // settings for retry mechanism
+}
+
+// JobQueueSettings contains settings of the queue running BirdWeather, MQTT and webhook jobs
+type JobQueueSettings struct {
+	Persist        bool   `json:"persist"`        // true to journal pending and failed jobs to disk so they survive restarts
+	JournalPath    string `json:"journalPath"`    // path to the job journal file
+	MaxDeadLetters int    `json:"maxDeadLetters"` // maximum number of failed jobs kept for replay, oldest are discarded; 0 uses the default
+}
+
 // TelemetrySettings contains settings for telemetry.
 type TelemetrySettings struct {
 	Enabled bool   `json:"enabled"` // true to enable Prometheus compatible telemetry endpoint
@@ -236,6 +350,95 @@ type SentrySettings struct {
 	Debug   bool `json:"debug"`   // true to enable transparent telemetry logging
 }
 
+// NotificationSettings contains settings for storing notifications and delivering them outside the web interface
+type NotificationSettings struct {
+	Store NotificationStoreSettings `json:"store"` // notification history storage
+	Rules []DetectionRuleSettings   `json:"rules"` // detection notification rules
+	Push  PushSettings              `json:"push"`  // external push notification channels
+}
+
+// DetectionRuleSettings contains a user-defined rule creating notifications of
+// detections, all configured conditions must match
+type DetectionRuleSettings struct {
+	Name          string                `json:"name"`          // name of the rule, shown in notifications
+	Enabled       bool                  `json:"enabled"`       // true to enable the rule
+	Species       []string              `json:"species"`       // watchlist of common or scientific names, empty for all species
+	Sources       []string              `json:"sources"`       // audio sources, empty for all sources
+	MinConfidence float64               `json:"minConfidence"` // minimum confidence 0-1, 0 for no limit
+	MaxConfidence float64               `json:"maxConfidence"` // maximum confidence 0-1, 0 for no limit
+	NotSeenDays   int                   `json:"notSeenDays"`   // match species not detected in this many days, 0 to disable
+	TimeOfDay     RuleTimeOfDaySettings `json:"timeOfDay"`     // time of day the rule applies
+	Burst         RuleBurstSettings     `json:"burst"`         // match bursts of detections of a species
+	Priority      string                `json:"priority"`      // critical, high, medium or low, medium if empty
+	Expiry        int                   `json:"expiry"`        // seconds until notifications expire, 0 for no expiry
+	Cooldown      int                   `json:"cooldown"`      // seconds before notifying again of the same species, 0 for no cooldown
+}
+
+// RuleTimeOfDaySettings limits a detection rule to a time of day, the range may span midnight
+type RuleTimeOfDaySettings struct {
+	Start string `json:"start"` // local start time HH:MM, empty for all day
+	End   string `json:"end"`   // local end time HH:MM, exclusive
+}
+
+// RuleBurstSettings matches when a species is detected more than Count times within Window
+type RuleBurstSettings struct {
+	Count  int `json:"count"`  // detections to exceed, 0 to disable
+	Window int `json:"window"` // window in seconds
+}
+
+// NotificationStoreSettings contains settings for the notification history storage
+type NotificationStoreSettings struct {
+	Type          string `json:"type"`          // memory or database
+	RetentionDays int    `json:"retentionDays"` // days to keep notifications in the database, 0 to keep until deleted
+}
+
+// PushSettings contains the external channels notifications are delivered to
+type PushSettings struct {
+	Enabled  bool                  `json:"enabled"`  // true to enable push notifications
+	Channels []PushChannelSettings `json:"channels"` // delivery channels
+}
+
+// PushChannelSettings contains settings for one push notification channel
+type PushChannelSettings struct {
+	Name      string                `json:"name"`      // name of the channel used in logs
+	Enabled   bool                  `json:"enabled"`   // true to enable the channel
+	Type      string                `json:"type"`      // smtp, webhook, ntfy, gotify or pushover
+	URL       string                `json:"url"`       // webhook URL, ntfy or Gotify server URL, optional Pushover API URL
+	Method    string                `json:"method"`    // HTTP method of webhooks, default POST
+	Headers   map[string]string     `json:"headers"`   // additional HTTP headers of webhook and ntfy requests
+	Topic     string                `json:"topic"`     // ntfy topic
+	Token     string                `json:"token"`     // ntfy access token, Gotify or Pushover application token
+	UserKey   string                `json:"userKey"`   // Pushover user or group key
+	SMTP      SMTPSettings          `json:"smtp"`      // email settings of smtp channels
+	Filter    PushFilterSettings    `json:"filter"`    // notifications delivered to the channel
+	RateLimit PushRateLimitSettings `json:"rateLimit"` // limit of notifications delivered to the channel
+	Timeout   int                   `json:"timeout"`   // delivery timeout in seconds, default 10
+}
+
+// SMTPSettings contains settings for sending notifications by email
+type SMTPSettings struct {
+	Host       string   `json:"host"`       // SMTP server host
+	Port       int      `json:"port"`       // SMTP server port, default 587
+	Username   string   `json:"username"`   // SMTP username, empty to send without authentication
+	Password   string   `json:"password"`   // SMTP password
+	From       string   `json:"from"`       // sender address
+	To         []string `json:"to"`         // recipient addresses
+	Encryption string   `json:"encryption"` // starttls (default), tls or none
+}
+
+// PushFilterSettings selects the notifications delivered to a channel, empty lists match all
+type PushFilterSettings struct {
+	Types      []string `json:"types"`      // notification types: error, warning, info, detection, system
+	Priorities []string `json:"priorities"` // notification priorities: critical, high, medium, low
+	Components []string `json:"components"` // components the notifications originate from, e.g. detection, diskmanager
+}
+
+// PushRateLimitSettings limits the notifications delivered to a channel
+type PushRateLimitSettings struct {
+	MaxEvents int `json:"maxEvents"` // maximum notifications per window, 0 for no limit
+	Window    int `json:"window"`    // window in seconds, default 60
+}
+
 // RealtimeSettings contains all settings related to realtime processing.
 type RealtimeSettings struct {
 	Interval         int                      `json:"interval"`         // minimum interval between log messages in seconds
@@ -243,17 +446,22 @@ type RealtimeSettings struct {
 	Audio            AudioSettings            `json:"audio"`            // Audio processing settings
 	Dashboard        Dashboard                `json:"dashboard"`        // Dashboard settings
 	DynamicThreshold DynamicThresholdSettings `json:"dynamicThreshold"` // Dynamic threshold settings
+	Calibration      CalibrationSettings      `json:"calibration"`      // Confidence calibration settings
+	Schedule         ScheduleSettings         `json:"schedule"`         // Action schedule rules
 	Log              struct {
 		Enabled bool   `json:"enabled"` // true to enable OBS chat log
 		Path    string `json:"path"`    // path to OBS chat log
 	} `json:"log"`
-	Birdweather   BirdweatherSettings   `json:"birdweather"`   // Birdweather integration settings
-	EBird         EBirdSettings         `json:"ebird"`         // eBird integration settings
-	OpenWeather   OpenWeatherSettings   `yaml:"-" json:"-"`    // OpenWeather integration settings
-	PrivacyFilter PrivacyFilterSettings `json:"privacyFilter"` // Privacy filter settings
-	DogBarkFilter DogBarkFilterSettings `json:"dogBarkFilter"` // Dog bark filter settings
-	RTSP          RTSPSettings          `json:"rtsp"`          // RTSP settings
+	Birdweather     BirdweatherSettings     `json:"birdweather"`     // Birdweather integration settings
+	EBird           EBirdSettings           `json:"ebird"`           // eBird integration settings
+	OpenWeather     OpenWeatherSettings     `yaml:"-" json:"-"`      // OpenWeather integration settings
+	PrivacyFilter   PrivacyFilterSettings   `json:"privacyFilter"`   // Privacy filter settings
+	DogBarkFilter   DogBarkFilterSettings   `json:"dogBarkFilter"`   // Dog bark filter settings
+	Filters         DetectionFilterSettings `json:"filters"`         // Detection filter chain settings
+	RTSP            RTSPSettings            `json:"rtsp"`            // RTSP settings
 	MQTT            MQTTSettings            `json:"mqtt"`            // MQTT settings
+	Webhooks        []WebhookSettings       `json:"webhooks"`        // Webhooks called for every detection
+	JobQueue        JobQueueSettings        `json:"jobQueue"`        // Integration job queue settings
 	Telemetry       TelemetrySettings       `json:"telemetry"`       // Telemetry settings
 	Monitoring      MonitoringSettings      `json:"monitoring"`      // System resource monitoring settings
 	Species         SpeciesSettings         `json:"species"`         // Custom thresholds and actions for species
@@ -263,17 +471,39 @@ type RealtimeSettings struct {
 
 // SpeciesAction represents a single action configuration
 type SpeciesAction struct {
-	Type            string   `yaml:"type" json:"type"`                        // Type of action (ExecuteCommand, etc)
-	Command         string   `yaml:"command" json:"command"`                  // Path to the command to execute
-	Parameters      []string `yaml:"parameters" json:"parameters"`            // Action parameters
-	ExecuteDefaults bool     `yaml:"executeDefaults" json:"executeDefaults"` // Whether to also execute default actions
+	Type            string           `yaml:"type" json:"type"`                           // Type of action (ExecuteCommand, Webhook)
+	Command         string           `yaml:"command" json:"command"`                     // Path to the command to execute
+	Parameters      []string         `yaml:"parameters" json:"parameters"`               // Action parameters
+	Webhook         *WebhookSettings `yaml:"webhook,omitempty" json:"webhook,omitempty"` // Webhook settings for Webhook actions
+	ExecuteDefaults bool             `yaml:"executeDefaults" json:"executeDefaults"`     // Whether to also execute default actions
 }
 
 // SpeciesConfig represents configuration for a specific species
 type SpeciesConfig struct {
-	Threshold float64         `yaml:"threshold" json:"threshold"`                    // Confidence threshold
-	Interval  int             `yaml:"interval,omitempty" json:"interval,omitempty"` // New field: Custom interval in seconds
-	Actions   []SpeciesAction `yaml:"actions" json:"actions"`                      // List of actions to execute
+	Threshold float64               `yaml:"threshold" json:"threshold"`                   // Confidence threshold
+	Interval  int                   `yaml:"interval,omitempty" json:"interval,omitempty"` // New field: Custom interval in seconds
+	Actions   []SpeciesAction       `yaml:"actions" json:"actions"`                       // List of actions to execute
+	Schedule  []ScheduleRule        `yaml:"schedule,omitempty" json:"schedule,omitempty"` // Schedule rules for this species
+	Filters   SpeciesFilterSettings `yaml:"filters,omitempty" json:"filters,omitempty"`   // Detection filter overrides for this species
+}
+
+// ScheduleRule mutes or allows action types during a time window. Times are
+// "HH:MM" or relative to a sun event, e.g. "sunset+30m", at the location of
+// the audio source. A window whose end is before its start spans midnight.
+type ScheduleRule struct {
+	Name     string   `json:"name"`     // name of the rule used in logs
+	Mode     string   `json:"mode"`     // mute to suppress actions inside the window, allow to run them only inside it
+	Actions  []string `json:"actions"`  // action types: database, log, birdweather, mqtt, sse, webhook, command; empty for all
+	Species  []string `json:"species"`  // common or scientific names, empty for all species
+	Weekdays []string `json:"weekdays"` // weekdays the window starts on, e.g. mon, tue; empty for every day
+	Start    string   `json:"start"`    // window start time
+	End      string   `json:"end"`      // window end time
+}
+
+// ScheduleSettings contains schedule rules gating detection actions
+type ScheduleSettings struct {
+	Enabled bool           `json:"enabled"` // true to enable schedule rules, including species schedules
+	Rules   []ScheduleRule `json:"rules"`   // schedule rules for all species
 }
 
 // RealtimeSpeciesSettings contains all species-specific settings
@@ -510,6 +740,7 @@ type InputConfig struct {
 	Path      string `yaml:"-" json:"-"` // path to input file or directory
 	Recursive bool   `yaml:"-" json:"-"` // true for recursive directory analysis
 	Watch     bool   `yaml:"-" json:"-"` // true to watch directory for new files
+	Manifest  string `yaml:"-" json:"-"` // path to directory analysis manifest, defaults to the output directory
 }
 
 type BirdNETConfig struct {
@@ -526,6 +757,36 @@ type BirdNETConfig struct {
 	LabelPath   string              `json:"labelPath"`   // path to external label file (empty for embedded)
 	Labels      []string            `yaml:"-" json:"-"` // list of available species labels, runtime value
 	UseXNNPACK  bool                `json:"useXnnpack"`  // true to use XNNPACK delegate for inference acceleration
+
+	PredictionArchive PredictionArchiveSettings `json:"predictionArchive"` // archive of full prediction vectors
+	Ensemble          EnsembleSettings          `json:"ensemble"`          // additional models fused with the primary model
+}
+
+// EnsembleSettings contains settings for running additional analysis models
+// on every audio chunk and fusing their outputs with the primary model.
+type EnsembleSettings struct {
+	Enabled       bool                    `json:"enabled"`       // true to enable the model ensemble
+	Fusion        string                  `json:"fusion"`        // fusion method: max, mean or weighted
+	PrimaryWeight float64                 `json:"primaryWeight"` // weight of the primary model in weighted fusion
+	Models        []EnsembleModelSettings `json:"models"`        // additional models
+}
+
+// EnsembleModelSettings defines an additional model of the ensemble.
+type EnsembleModelSettings struct {
+	Name      string  `json:"name"`      // display name of the model
+	ModelPath string  `json:"modelPath"` // path to the model file
+	LabelPath string  `json:"labelPath"` // path to the label file
+	Weight    float64 `json:"weight"`    // weight of the model in weighted fusion
+}
+
+// PredictionArchiveSettings contains settings for archiving the full prediction
+// vector (all labels) of every analysed audio chunk for offline threshold tuning.
+type PredictionArchiveSettings struct {
+	Enabled       bool   `json:"enabled"`       // true to archive every prediction vector
+	Path          string `json:"path"`          // directory for archive files
+	BlockSize     int    `json:"blockSize"`     // number of predictions per compressed block
+	FlushInterval int    `json:"flushInterval"` // maximum seconds predictions are buffered before being written
+	RetentionDays int    `json:"retentionDays"` // number of days of archive files to keep, 0 keeps all
 }
 
 // RangeFilterSettings contains settings for the range filter
@@ -744,6 +1005,13 @@ type BackupScheduleConfig struct {
 	IsWeekly bool   `yaml:"isweekly" json:"isWeekly"` // If true, this schedule is weekly (runs on the specified Weekday at Hour:Minute). If false, it's a daily schedule (runs every day at Hour:Minute). (Valid: true or false)
 }
 
+// BackupClipsConfig contains settings for the incremental audio clip backup
+type BackupClipsConfig struct {
+	Enabled    bool                   `yaml:"enabled" json:"enabled"`       // If true, audio clips under the export path are backed up. Each backup only contains clips added since the last successful clip backup.
+	LockedOnly bool                   `yaml:"lockedonly" json:"lockedOnly"` // If true, only clips of locked notes are backed up. Clips of notes locked after an earlier backup are included in the next one.
+	Schedules  []BackupScheduleConfig `yaml:"schedules" json:"schedules"`   // Schedules of the clip backup. If empty, clips are backed up together with the database on the regular schedules.
+}
+
 // BackupConfig contains backup-related configuration
 type BackupConfig struct {
 	Enabled        bool                   `yaml:"enabled" json:"enabled"`                 // Global flag to enable or disable the entire backup system. If false, no backups (manual or scheduled) will occur.
@@ -754,6 +1022,7 @@ type BackupConfig struct {
 	Retention      BackupRetention        `yaml:"retention" json:"retention"`             // Defines policies for how long and how many backups are kept.
 	Targets        []BackupTarget         `yaml:"targets" json:"targets"`                 // A list of configured backup targets (destinations) where backup archives will be stored.
 	Schedules      []BackupScheduleConfig `yaml:"schedules" json:"schedules"`             // A list of schedules (e.g., daily, weekly) that define when automatic backups should run.
+	Clips          BackupClipsConfig      `yaml:"clips" json:"clips"`                     // Incremental backup of exported audio clips.
 
 	// OperationTimeouts defines timeouts for various backup operations
 	OperationTimeouts struct {
@@ -764,6 +1033,25 @@ type BackupConfig struct {
 	} `json:"operationTimeouts"`
 }
 
+// DarwinCoreSettings contains the dataset metadata of Darwin Core archive exports
+type DarwinCoreSettings struct {
+	Title              string `json:"title"`              // dataset title
+	Description        string `json:"description"`        // dataset abstract
+	Publisher          string `json:"publisher"`          // organization publishing the dataset
+	ContactName        string `json:"contactName"`        // name of the dataset contact
+	ContactEmail       string `json:"contactEmail"`       // email address of the dataset contact
+	License            string `json:"license"`            // CC0-1.0, CC-BY-4.0 or CC-BY-NC-4.0
+	BaseURL            string `json:"baseUrl"`            // public URL of this node, used to link audio clips
+	OccurrenceIDPrefix string `json:"occurrenceIdPrefix"` // prefix of occurrence identifiers, derived from the node name if empty
+}
+
+// DarwinCoreLicenses maps the dataset licenses accepted by GBIF to their legal code URLs
+var DarwinCoreLicenses = map[string]string{
+	"CC0-1.0":      "http://creativecommons.org/publicdomain/zero/1.0/legalcode",
+	"CC-BY-4.0":    "http://creativecommons.org/licenses/by/4.0/legalcode",
+	"CC-BY-NC-4.0": "http://creativecommons.org/licenses/by-nc/4.0/legalcode",
+}
+
 // Settings contains all configuration options for the BirdNET-Go application.
 type Settings struct {
 	Debug bool `json:"debug"` // true to enable debug mode
@@ -789,11 +1077,14 @@ type Settings struct {
 	Security  Security          `json:"security"`  // security configuration
 	Sentry    SentrySettings    `json:"sentry"`    // Sentry error tracking configuration
 
+	Notification NotificationSettings `json:"notification"` // notification storage and external delivery
+
 	Output struct {
 		File struct {
 			Enabled bool   `yaml:"-" json:"-"` // true to enable file output
 			Path    string `yaml:"-" json:"-"` // directory to output results
-			Type    string `yaml:"-" json:"-"` // table, csv
+			Type    string `yaml:"-" json:"-"` // table, csv, raven, audacity
+			PerFile bool   `yaml:"-" json:"-"` // true to write one output per input file in directory analysis
 		} `json:"file"`
 
 		SQLite struct {
@@ -809,6 +1100,8 @@ type Settings struct {
 			Host     string `json:"host"`     // host for mysql database
 			Port     string `json:"port"`     // port for mysql database
 		} `json:"mysql"`
+
+		DarwinCore DarwinCoreSettings `json:"darwinCore"` // Darwin Core archive export metadata
 	} `json:"output"`
 
 	Backup BackupConfig `json:"backup"` // Backup configuration
@@ -33,6 +33,16 @@ func setDefaultConfig() {
 	viper.SetDefault("birdnet.labelpath", "")
 	viper.SetDefault("birdnet.usexnnpack", true)
 
+	// Prediction archive configuration
+	viper.SetDefault("birdnet.predictionarchive.enabled", false)
+	viper.SetDefault("birdnet.predictionarchive.path", "predictions/")
+	viper.SetDefault("birdnet.predictionarchive.blocksize", 256)
+	viper.SetDefault("birdnet.predictionarchive.flushinterval", 60)
+	viper.SetDefault("birdnet.predictionarchive.retentiondays", 30)
+	viper.SetDefault("birdnet.ensemble.enabled", false)
+	viper.SetDefault("birdnet.ensemble.fusion", "max")
+	viper.SetDefault("birdnet.ensemble.primaryweight", 1.0)
+
 	// Range filter configuration
 	viper.SetDefault("birdnet.rangefilter.debug", false)
 	viper.SetDefault("birdnet.rangefilter.model", "latest")
@@ -50,6 +60,9 @@ func setDefaultConfig() {
 	// Sound level monitoring configuration
 	viper.SetDefault("realtime.audio.soundlevel.enabled", false)
 	viper.SetDefault("realtime.audio.soundlevel.interval", 10)
+	viper.SetDefault("realtime.audio.soundlevel.storage.enabled", false)
+	viper.SetDefault("realtime.audio.soundlevel.storage.interval", 60)
+	viper.SetDefault("realtime.audio.soundlevel.storage.retentiondays", 30)
 
 	// Audio export configuration
 	viper.SetDefault("realtime.audio.export.debug", false)
@@ -101,6 +114,16 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.dynamicthreshold.min", 0.20)
 	viper.SetDefault("realtime.dynamicthreshold.validhours", 24)
 
+	// Action schedule configuration
+	viper.SetDefault("realtime.schedule.enabled", false)
+
+	// Confidence calibration configuration
+	viper.SetDefault("realtime.calibration.enabled", false)
+	viper.SetDefault("realtime.calibration.method", "logistic")
+	viper.SetDefault("realtime.calibration.target", 0.9)
+	viper.SetDefault("realtime.calibration.minsamples", 20)
+	viper.SetDefault("realtime.calibration.refreshinterval", 24)
+
 	// Log configuration
 	viper.SetDefault("realtime.log.enabled", false)
 	viper.SetDefault("realtime.log.path", "birdnet.txt")
@@ -166,6 +189,11 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.mqtt.retrysettings.maxdelay", 3600)
 	viper.SetDefault("realtime.mqtt.retrysettings.backoffmultiplier", 2.0)
 
+	// Job queue configuration
+	viper.SetDefault("realtime.jobqueue.persist", false)
+	viper.SetDefault("realtime.jobqueue.journalpath", "jobs.journal")
+	viper.SetDefault("realtime.jobqueue.maxdeadletters", 100)
+
 	// Privacy filter configuration
 	viper.SetDefault("realtime.privacyfilter.enabled", true)
 	viper.SetDefault("realtime.privacyfilter.debug", false)
@@ -178,6 +206,25 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.dogbarkfilter.confidence", 0.1)
 	viper.SetDefault("realtime.dogbarkfilter.species", []string{})
 
+	// Detection filter chain configuration
+	viper.SetDefault("realtime.filters.order", []string{})
+	viper.SetDefault("realtime.filters.consecutive.mindetections", 0)
+	viper.SetDefault("realtime.filters.noise.enabled", false)
+	viper.SetDefault("realtime.filters.noise.confidence", 0.5)
+	viper.SetDefault("realtime.filters.noise.labels", []string{"environmental", "noise"})
+	viper.SetDefault("realtime.filters.noise.remember", 0)
+	viper.SetDefault("realtime.filters.noise.species", []string{})
+	viper.SetDefault("realtime.filters.weather.enabled", false)
+	viper.SetDefault("realtime.filters.weather.action", "threshold")
+	viper.SetDefault("realtime.filters.weather.windspeed", 10.0)
+	viper.SetDefault("realtime.filters.weather.windgust", 15.0)
+	viper.SetDefault("realtime.filters.weather.conditions", []string{"heavy rain", "heavy intensity rain", "very heavy rain", "extreme rain", "thunder"})
+	viper.SetDefault("realtime.filters.weather.thresholdincrease", 0.15)
+	viper.SetDefault("realtime.filters.weather.maxage", 90)
+	viper.SetDefault("realtime.filters.weather.species", []string{})
+	viper.SetDefault("realtime.filters.cooldown.enabled", false)
+	viper.SetDefault("realtime.filters.cooldown.interval", 10)
+
 	// Telemetry configuration
 	viper.SetDefault("realtime.telemetry.enabled", false)
 	viper.SetDefault("realtime.telemetry.listen", "0.0.0.0:8090")
@@ -249,6 +296,7 @@ func setDefaultConfig() {
 	viper.SetDefault("output.file.enabled", true)
 	viper.SetDefault("output.file.path", "output/")
 	viper.SetDefault("output.file.type", "table")
+	viper.SetDefault("output.file.perfile", true)
 
 	// SQLite output configuration
 	viper.SetDefault("output.sqlite.enabled", true)
@@ -262,6 +310,16 @@ func setDefaultConfig() {
 	viper.SetDefault("output.mysql.host", "localhost")
 	viper.SetDefault("output.mysql.port", 3306)
 
+	// Darwin Core archive export configuration
+	viper.SetDefault("output.darwincore.title", "BirdNET-Go acoustic detections")
+	viper.SetDefault("output.darwincore.description", "")
+	viper.SetDefault("output.darwincore.publisher", "")
+	viper.SetDefault("output.darwincore.contactname", "")
+	viper.SetDefault("output.darwincore.contactemail", "")
+	viper.SetDefault("output.darwincore.license", "CC-BY-4.0")
+	viper.SetDefault("output.darwincore.baseurl", "")
+	viper.SetDefault("output.darwincore.occurrenceidprefix", "")
+
 	// Security configuration
 	viper.SetDefault("security.debug", false)
 	viper.SetDefault("security.host", "")
@@ -298,4 +356,11 @@ func setDefaultConfig() {
 	viper.SetDefault("sentry.dsn", "")
 	viper.SetDefault("sentry.samplerate", 1.0)
 	viper.SetDefault("sentry.debug", false)
+
+	// Notification store configuration
+	viper.SetDefault("notification.store.type", "memory")
+	viper.SetDefault("notification.store.retentiondays", 90)
+
+	// Push notification configuration
+	viper.SetDefault("notification.push.enabled", false)
 }
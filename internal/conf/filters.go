@@ -0,0 +1,22 @@
+// conf/filters.go contains the names of the detection filters
+package conf
+
+// Detection filter names used in the filter order and species filter settings
+const (
+	FilterConsecutive = "consecutive" // minimum number of matches before a detection is approved
+	FilterPrivacy     = "privacy"     // human voice detected during the detection
+	FilterDogBark     = "dogbark"     // dog bark detected shortly before the detection
+	FilterNoise       = "noise"       // rain or wind noise detected during the detection
+	FilterWeather     = "weather"     // heavy rain or strong wind in recent weather data
+	FilterCooldown    = "cooldown"    // species approved within the cooldown interval
+)
+
+// DefaultFilterOrder is the order detection filters run in. Filters missing
+// from a configured order run after the configured ones in this order.
+var DefaultFilterOrder = []string{FilterConsecutive, FilterPrivacy, FilterDogBark, FilterNoise, FilterWeather, FilterCooldown}
+
+// Weather filter actions
+const (
+	WeatherFilterActionThreshold = "threshold" // raise the confidence threshold
+	WeatherFilterActionReview    = "review"    // save the detection for review without running other actions
+)
@@ -41,3 +41,37 @@ func (s *Settings) IsSpeciesIncluded(result string) bool {
 	}
 	return false
 }
+
+// UpdateSourceIncludedSpecies updates the included species list of a named
+// RTSP source that has its own range filter
+func (s *Settings) UpdateSourceIncludedSpecies(source string, species []string) {
+	speciesListMutex.Lock()
+	defer speciesListMutex.Unlock()
+	src := s.FindRTSPSource(source)
+	if src == nil {
+		return
+	}
+	src.RangeFilter.Species = make([]string, len(species))
+	copy(src.RangeFilter.Species, species)
+	src.RangeFilter.LastUpdated = time.Now()
+}
+
+// IsSpeciesIncludedForSource checks if a species is included in the range
+// filter of the given audio source. Sources without their own range filter,
+// or whose list has not been built yet, use the global species list.
+func (s *Settings) IsSpeciesIncludedForSource(source, result string) bool {
+	speciesListMutex.RLock()
+	src := s.FindRTSPSource(source)
+	if src == nil || !src.HasOwnRangeFilter() || src.RangeFilter.LastUpdated.IsZero() {
+		speciesListMutex.RUnlock()
+		return s.IsSpeciesIncluded(result)
+	}
+	defer speciesListMutex.RUnlock()
+
+	for _, fullSpeciesString := range src.RangeFilter.Species {
+		if strings.HasPrefix(fullSpeciesString, result) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// conf/schedule.go contains parsing of action schedule rule times
+package conf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule rule modes
+const (
+	ScheduleModeMute  = "mute"  // actions are muted inside the window
+	ScheduleModeAllow = "allow" // actions only run inside the window
+)
+
+// Sun events schedule times can be relative to
+const (
+	SunEventSunrise = "sunrise"
+	SunEventSunset  = "sunset"
+	SunEventDawn    = "dawn" // civil dawn
+	SunEventDusk    = "dusk" // civil dusk
+)
+
+// ScheduleActionTypes are the action types schedule rules can gate
+var ScheduleActionTypes = []string{"database", "log", "birdweather", "mqtt", "sse", "webhook", "command"}
+
+// ScheduleTime is a parsed schedule rule start or end time, either a fixed
+// time of day or an offset from a sun event.
+type ScheduleTime struct {
+	SunEvent string        // empty for a fixed time of day
+	Offset   time.Duration // time of day for fixed times, offset from the sun event otherwise
+}
+
+// scheduleTimeRegex matches sun relative times such as "sunset+30m" or "sunrise - 1h"
+var scheduleTimeRegex = regexp.MustCompile(`^(sunrise|sunset|dawn|dusk)\s*(?:([+-])\s*([0-9hms.]+))?$`)
+
+// ParseScheduleTime parses "HH:MM" or a sun event with an optional offset,
+// e.g. "sunset+30m" or "sunrise-1h30m".
+func ParseScheduleTime(value string) (ScheduleTime, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if m := scheduleTimeRegex.FindStringSubmatch(value); m != nil {
+		st := ScheduleTime{SunEvent: m[1]}
+		if m[3] != "" {
+			offset, err := time.ParseDuration(m[3])
+			if err != nil {
+				return ScheduleTime{}, fmt.Errorf("invalid offset in schedule time %q: %w", value, err)
+			}
+			if m[2] == "-" {
+				offset = -offset
+			}
+			st.Offset = offset
+		}
+		return st, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) == 2 {
+		hour, errHour := strconv.Atoi(parts[0])
+		minute, errMinute := strconv.Atoi(parts[1])
+		if errHour == nil && errMinute == nil && hour >= 0 && hour <= 24 && minute >= 0 && minute < 60 &&
+			(hour < 24 || minute == 0) {
+			return ScheduleTime{Offset: time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute}, nil
+		}
+	}
+
+	return ScheduleTime{}, fmt.Errorf("invalid schedule time %q, use HH:MM or a sun event like sunset+30m", value)
+}
+
+// ParseScheduleWeekday parses a weekday name or its three letter abbreviation.
+func ParseScheduleWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if value == strings.ToLower(day.String()[:3]) {
+			return day, nil
+		}
+	}
+	return ParseWeekday(value)
+}
@@ -0,0 +1,134 @@
+// conf/source_settings.go
+
+package conf
+
+import "slices"
+
+// SourceSettings holds the effective analysis settings of a single audio
+// source, with the per-source overrides of a named RTSP source applied on
+// top of the global BirdNET and species settings.
+type SourceSettings struct {
+	Name                 string   // display name of the source, empty for unnamed sources
+	URL                  string   // RTSP stream URL or audio card name
+	Latitude             float64  // latitude of the source location
+	Longitude            float64  // longitude of the source location
+	Threshold            float64  // threshold for prediction confidence to report
+	RangeFilterThreshold float32  // rangefilter species occurrence threshold
+	Include              []string // species always included
+	Exclude              []string // species always excluded
+}
+
+// FindRTSPSource returns the named RTSP source matching the given source,
+// which may be either the stream URL or the source name. It returns nil if
+// the source is not defined in realtime.rtsp.sources.
+func (s *Settings) FindRTSPSource(source string) *RTSPSourceSettings {
+	if source == "" {
+		return nil
+	}
+	for i := range s.Realtime.RTSP.Sources {
+		src := &s.Realtime.RTSP.Sources[i]
+		if src.URL == source || (src.Name != "" && src.Name == source) {
+			return src
+		}
+	}
+	return nil
+}
+
+// StoredSources returns the source values detections of an audio source are
+// stored with. The source may be the name of a named RTSP source, a configured
+// RTSP URL with or without its credentials, or an audio card name. Other values
+// are returned as is, so they only match detections stored with exactly that source.
+func (s *Settings) StoredSources(source string) []string {
+	if source == "" {
+		return nil
+	}
+	if src := s.FindRTSPSource(source); src != nil {
+		return []string{src.URL}
+	}
+
+	sources := []string{source}
+	for _, url := range s.Realtime.RTSP.AllURLs() {
+		if url != source && SanitizeRTSPUrl(url) == source {
+			sources = append(sources, url)
+		}
+	}
+	return sources
+}
+
+// ResolveSourceSettings returns the effective settings for an audio source.
+// Sources without a named RTSP source definition use the global settings.
+func (s *Settings) ResolveSourceSettings(source string) SourceSettings {
+	resolved := SourceSettings{
+		URL:                  source,
+		Latitude:             s.BirdNET.Latitude,
+		Longitude:            s.BirdNET.Longitude,
+		Threshold:            s.BirdNET.Threshold,
+		RangeFilterThreshold: s.BirdNET.RangeFilter.Threshold,
+		Include:              s.Realtime.Species.Include,
+		Exclude:              s.Realtime.Species.Exclude,
+	}
+
+	src := s.FindRTSPSource(source)
+	if src == nil {
+		return resolved
+	}
+
+	resolved.Name = src.Name
+	resolved.URL = src.URL
+	if src.Latitude != nil {
+		resolved.Latitude = *src.Latitude
+	}
+	if src.Longitude != nil {
+		resolved.Longitude = *src.Longitude
+	}
+	if src.Threshold != nil {
+		resolved.Threshold = *src.Threshold
+	}
+	if src.RangeFilter.Threshold != nil {
+		resolved.RangeFilterThreshold = *src.RangeFilter.Threshold
+	}
+	// Per-source species lists extend the global lists
+	if len(src.Include) > 0 {
+		resolved.Include = mergeSpeciesLists(s.Realtime.Species.Include, src.Include)
+	}
+	if len(src.Exclude) > 0 {
+		resolved.Exclude = mergeSpeciesLists(s.Realtime.Species.Exclude, src.Exclude)
+	}
+
+	return resolved
+}
+
+// HasOwnRangeFilter reports whether the source overrides any setting that
+// affects the range filter and therefore needs its own species list.
+func (src *RTSPSourceSettings) HasOwnRangeFilter() bool {
+	return src.Latitude != nil || src.Longitude != nil ||
+		src.RangeFilter.Threshold != nil ||
+		len(src.Include) > 0 || len(src.Exclude) > 0
+}
+
+// AllURLs returns the streams to capture: the plain URL list followed by the
+// URLs of named RTSP sources missing from it. The settings are left as they
+// are, so the URLs of named sources are never saved to the plain list.
+func (r *RTSPSettings) AllURLs() []string {
+	urls := slices.Clone(r.URLs)
+	for _, src := range r.Sources {
+		if src.URL == "" || slices.Contains(urls, src.URL) {
+			continue
+		}
+		urls = append(urls, src.URL)
+	}
+	return urls
+}
+
+// mergeSpeciesLists returns a new list holding the entries of both lists
+// without duplicates.
+func mergeSpeciesLists(global, source []string) []string {
+	merged := make([]string, 0, len(global)+len(source))
+	merged = append(merged, global...)
+	for _, species := range source {
+		if !slices.Contains(merged, species) {
+			merged = append(merged, species)
+		}
+	}
+	return merged
+}
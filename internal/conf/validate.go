@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/url"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/errors"
 )
@@ -86,6 +89,26 @@ func ValidateSettings(settings *Settings) error {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
 
+	// Validate Darwin Core export settings
+	if err := validateDarwinCoreSettings(&settings.Output.DarwinCore); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate notification store settings
+	if err := validateNotificationStoreSettings(&settings.Notification.Store); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate detection notification rules
+	if err := validateDetectionRules(settings.Notification.Rules); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate push notification settings
+	if err := validatePushSettings(&settings.Notification.Push); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
 	// If there are any errors, return the ValidationError
 	if len(ve.Errors) > 0 {
 		return ve
@@ -137,6 +160,46 @@ func validateBirdNETSettings(birdnetSettings *BirdNETConfig, settings *Settings)
 		errs = append(errs, "RangeFilter threshold must be between 0 and 1")
 	}
 
+	// Validate prediction archive settings
+	if birdnetSettings.PredictionArchive.Enabled {
+		if birdnetSettings.PredictionArchive.Path == "" {
+			errs = append(errs, "Prediction archive path must not be empty when enabled")
+		}
+		if birdnetSettings.PredictionArchive.BlockSize < 0 {
+			errs = append(errs, "Prediction archive block size must be at least 0")
+		}
+		if birdnetSettings.PredictionArchive.FlushInterval < 0 {
+			errs = append(errs, "Prediction archive flush interval must be at least 0")
+		}
+		if birdnetSettings.PredictionArchive.RetentionDays < 0 {
+			errs = append(errs, "Prediction archive retention days must be at least 0")
+		}
+	}
+
+	// Check model ensemble settings
+	if birdnetSettings.Ensemble.Enabled {
+		switch birdnetSettings.Ensemble.Fusion {
+		case "max", "mean", "weighted":
+		default:
+			errs = append(errs, fmt.Sprintf("Ensemble fusion method '%s' is not supported, use max, mean or weighted", birdnetSettings.Ensemble.Fusion))
+		}
+		if birdnetSettings.Ensemble.PrimaryWeight < 0 {
+			errs = append(errs, "Ensemble primary model weight must be at least 0")
+		}
+		for i, model := range birdnetSettings.Ensemble.Models {
+			if model.ModelPath == "" || model.LabelPath == "" {
+				errs = append(errs, fmt.Sprintf("Ensemble model %d: model path and label path are required", i+1))
+			}
+			switch {
+			case model.Weight < 0:
+				errs = append(errs, fmt.Sprintf("Ensemble model %d: weight must be at least 0", i+1))
+			case model.Weight == 0 && birdnetSettings.Ensemble.Fusion == "weighted":
+				// An omitted weight would silently drop the model from weighted fusion
+				errs = append(errs, fmt.Sprintf("Ensemble model %d: weight must be greater than 0 with weighted fusion", i+1))
+			}
+		}
+	}
+
 	// Validate locale setting
 	if birdnetSettings.Locale != "" {
 		normalizedLocale, err := NormalizeLocale(birdnetSettings.Locale)
@@ -282,10 +345,350 @@ func validateRealtimeSettings(settings *RealtimeSettings) error {
 		return err
 	}
 
+	// Validate confidence calibration settings
+	if err := validateCalibrationSettings(&settings.Calibration); err != nil {
+		return err
+	}
+
+	// Validate named RTSP sources
+	if err := validateRTSPSourceSettings(settings.RTSP.Sources); err != nil {
+		return err
+	}
+
+	// Validate default and species webhooks
+	if err := validateWebhooks(settings); err != nil {
+		return err
+	}
+
+	// Validate job queue persistence
+	if err := validateJobQueueSettings(&settings.JobQueue); err != nil {
+		return err
+	}
+
+	// Validate action schedule rules
+	if err := validateScheduleSettings(settings); err != nil {
+		return err
+	}
+
+	// Validate detection filter chain
+	if err := validateDetectionFilterSettings(settings); err != nil {
+		return err
+	}
+
 	// Add more realtime settings validation as needed
 	return nil
 }
 
+// validateCalibrationSettings validates the confidence calibration settings
+func validateCalibrationSettings(settings *CalibrationSettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	var errs []string
+	if settings.Method != "logistic" && settings.Method != "isotonic" {
+		errs = append(errs, fmt.Sprintf("calibration method '%s' is not supported, use logistic or isotonic", settings.Method))
+	}
+	if settings.Target <= 0 || settings.Target >= 1 {
+		errs = append(errs, "calibration target must be between 0 and 1")
+	}
+	if settings.MinSamples < 2 {
+		errs = append(errs, "calibration requires at least 2 samples per species")
+	}
+	if settings.RefreshInterval < 0 {
+		errs = append(errs, "calibration refresh interval must be non-negative")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("calibration settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "calibration").
+			Build()
+	}
+	return nil
+}
+
+// validateRTSPSourceSettings validates the named RTSP source definitions
+func validateRTSPSourceSettings(sources []RTSPSourceSettings) error {
+	var errs []string
+	names := make(map[string]bool)
+	urls := make(map[string]bool)
+
+	for i := range sources {
+		src := &sources[i]
+		label := src.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+
+		if src.URL == "" {
+			errs = append(errs, fmt.Sprintf("RTSP source %s: url is required", label))
+		} else if urls[src.URL] {
+			errs = append(errs, fmt.Sprintf("RTSP source %s: duplicate url", label))
+		}
+		urls[src.URL] = true
+
+		if src.Name != "" {
+			if names[src.Name] {
+				errs = append(errs, fmt.Sprintf("RTSP source name %q is used more than once", src.Name))
+			}
+			names[src.Name] = true
+		}
+
+		if src.Latitude != nil && (*src.Latitude < -90 || *src.Latitude > 90) {
+			errs = append(errs, fmt.Sprintf("RTSP source %s: latitude must be between -90 and 90", label))
+		}
+		if src.Longitude != nil && (*src.Longitude < -180 || *src.Longitude > 180) {
+			errs = append(errs, fmt.Sprintf("RTSP source %s: longitude must be between -180 and 180", label))
+		}
+		if src.Threshold != nil && (*src.Threshold < 0 || *src.Threshold > 1) {
+			errs = append(errs, fmt.Sprintf("RTSP source %s: threshold must be between 0 and 1", label))
+		}
+		if src.RangeFilter.Threshold != nil && (*src.RangeFilter.Threshold < 0 || *src.RangeFilter.Threshold > 1) {
+			errs = append(errs, fmt.Sprintf("RTSP source %s: range filter threshold must be between 0 and 1", label))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("RTSP source settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "rtsp-sources").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
+// validateJobQueueSettings validates the job journal and dead-letter settings
+func validateJobQueueSettings(settings *JobQueueSettings) error {
+	var errs []string
+	if settings.Persist && strings.TrimSpace(settings.JournalPath) == "" {
+		errs = append(errs, "journal path is required when persist is enabled")
+	}
+	if settings.MaxDeadLetters < 0 {
+		errs = append(errs, fmt.Sprintf("max dead letters must not be negative, got %d", settings.MaxDeadLetters))
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("job queue settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "job-queue").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
+// validateWebhooks validates the default webhooks and webhook species actions
+func validateWebhooks(settings *RealtimeSettings) error {
+	var errs []string
+
+	for i := range settings.Webhooks {
+		webhook := &settings.Webhooks[i]
+		if !webhook.Enabled {
+			continue
+		}
+		label := webhook.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		errs = append(errs, validateWebhookSettings("webhook "+label, webhook)...)
+	}
+
+	for species, config := range settings.Species.Config {
+		for i := range config.Actions {
+			action := &config.Actions[i]
+			if action.Type != "Webhook" {
+				continue
+			}
+			label := fmt.Sprintf("species %s webhook action", species)
+			if action.Webhook == nil {
+				errs = append(errs, label+": webhook settings are required")
+				continue
+			}
+			errs = append(errs, validateWebhookSettings(label, action.Webhook)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("webhook settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "webhooks").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
+// validateWebhookSettings validates a single webhook and returns the problems found
+func validateWebhookSettings(label string, webhook *WebhookSettings) []string {
+	var errs []string
+
+	if webhook.URL == "" {
+		errs = append(errs, label+": url is required")
+	} else if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, label+": url must be a valid http or https URL")
+	}
+
+	switch strings.ToUpper(webhook.Method) {
+	case "", "POST", "PUT", "PATCH", "GET":
+	default:
+		errs = append(errs, fmt.Sprintf("%s: unsupported method %s, use POST, PUT, PATCH or GET", label, webhook.Method))
+	}
+
+	if webhook.Timeout < 0 {
+		errs = append(errs, label+": timeout must be non-negative")
+	}
+
+	if strings.TrimSpace(webhook.Template) != "" {
+		if _, err := ParseWebhookTemplate(webhook.Template); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid template: %v", label, err))
+		}
+	}
+
+	if webhook.RetrySettings.Enabled {
+		if webhook.RetrySettings.MaxRetries < 0 || webhook.RetrySettings.InitialDelay < 0 {
+			errs = append(errs, label+": retry count and initial delay must be non-negative")
+		}
+		if webhook.RetrySettings.MaxDelay < webhook.RetrySettings.InitialDelay {
+			errs = append(errs, label+": retry max delay must be greater than or equal to initial delay")
+		}
+	}
+
+	return errs
+}
+
+// validateScheduleSettings validates the global and species schedule rules
+func validateScheduleSettings(settings *RealtimeSettings) error {
+	var errs []string
+
+	for i := range settings.Schedule.Rules {
+		errs = append(errs, validateScheduleRule(fmt.Sprintf("schedule rule #%d", i+1), &settings.Schedule.Rules[i])...)
+	}
+	for species, config := range settings.Species.Config {
+		for i := range config.Schedule {
+			errs = append(errs, validateScheduleRule(fmt.Sprintf("species %s schedule rule #%d", species, i+1), &config.Schedule[i])...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("schedule settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "schedule").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
+// validateDetectionFilterSettings validates the filter order and species filter overrides
+func validateDetectionFilterSettings(settings *RealtimeSettings) error {
+	var errs []string
+
+	seen := make(map[string]bool)
+	for _, name := range settings.Filters.Order {
+		name = strings.ToLower(name)
+		if !slices.Contains(DefaultFilterOrder, name) {
+			errs = append(errs, fmt.Sprintf("unknown filter %q in filter order, use one of %v", name, DefaultFilterOrder))
+		} else if seen[name] {
+			errs = append(errs, fmt.Sprintf("filter %q listed more than once in filter order", name))
+		}
+		seen[name] = true
+	}
+
+	if settings.Filters.Consecutive.MinDetections < 0 {
+		errs = append(errs, "consecutive filter minimum detections must be non-negative")
+	}
+	if settings.Filters.Noise.Confidence < 0 || settings.Filters.Noise.Confidence > 1 {
+		errs = append(errs, "noise filter confidence must be between 0 and 1")
+	}
+	if settings.Filters.Noise.Remember < 0 {
+		errs = append(errs, "noise filter remember must be non-negative")
+	}
+	if settings.Filters.Noise.Enabled && len(settings.Filters.Noise.Labels) == 0 {
+		errs = append(errs, "noise filter requires at least one label when enabled")
+	}
+	switch strings.ToLower(settings.Filters.Weather.Action) {
+	case "", WeatherFilterActionThreshold, WeatherFilterActionReview:
+	default:
+		errs = append(errs, fmt.Sprintf("weather filter action must be %s or %s", WeatherFilterActionThreshold, WeatherFilterActionReview))
+	}
+	if settings.Filters.Weather.WindSpeed < 0 || settings.Filters.Weather.WindGust < 0 {
+		errs = append(errs, "weather filter wind speeds must be non-negative")
+	}
+	if settings.Filters.Weather.ThresholdIncrease < 0 || settings.Filters.Weather.ThresholdIncrease > 1 {
+		errs = append(errs, "weather filter threshold increase must be between 0 and 1")
+	}
+	if settings.Filters.Weather.MaxAge < 0 {
+		errs = append(errs, "weather filter max age must be non-negative")
+	}
+	if settings.Filters.Cooldown.Interval < 0 {
+		errs = append(errs, "cooldown filter interval must be non-negative")
+	}
+
+	for species, config := range settings.Species.Config {
+		for _, name := range config.Filters.Disable {
+			if !slices.Contains(DefaultFilterOrder, strings.ToLower(name)) {
+				errs = append(errs, fmt.Sprintf("species %s: unknown filter %q, use one of %v", species, name, DefaultFilterOrder))
+			}
+		}
+		if config.Filters.MinDetections < 0 {
+			errs = append(errs, fmt.Sprintf("species %s: filter minimum detections must be non-negative", species))
+		}
+		if config.Filters.Cooldown < 0 {
+			errs = append(errs, fmt.Sprintf("species %s: filter cooldown must be non-negative", species))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("detection filter settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "detection-filters").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
+// validateScheduleRule validates a single schedule rule and returns the problems found
+func validateScheduleRule(label string, rule *ScheduleRule) []string {
+	var errs []string
+	if rule.Name != "" {
+		label = fmt.Sprintf("%s (%s)", label, rule.Name)
+	}
+
+	switch strings.ToLower(rule.Mode) {
+	case "", ScheduleModeMute, ScheduleModeAllow:
+	default:
+		errs = append(errs, fmt.Sprintf("%s: mode must be %s or %s", label, ScheduleModeMute, ScheduleModeAllow))
+	}
+
+	for _, action := range rule.Actions {
+		if !slices.Contains(ScheduleActionTypes, strings.ToLower(action)) {
+			errs = append(errs, fmt.Sprintf("%s: unknown action type %q, use one of %v", label, action, ScheduleActionTypes))
+		}
+	}
+
+	for _, day := range rule.Weekdays {
+		if _, err := ParseScheduleWeekday(day); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+		}
+	}
+
+	if rule.Start == "" || rule.End == "" {
+		errs = append(errs, label+": start and end are required")
+	} else {
+		if _, err := ParseScheduleTime(rule.Start); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+		}
+		if _, err := ParseScheduleTime(rule.End); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+		}
+	}
+
+	return errs
+}
+
 // validateMQTTSettings validates the MQTT-specific settings
 func validateMQTTSettings(settings *MQTTSettings) error {
 	if settings.Enabled {
@@ -352,6 +755,23 @@ func validateSoundLevelSettings(settings *SoundLevelSettings) error {
 				Context("minimum_interval", MinSoundLevelInterval).
 				Build()
 		}
+
+		// Stored levels aggregate whole measurement intervals
+		if settings.Storage.Enabled && settings.Storage.Interval < settings.Interval {
+			return errors.New(fmt.Errorf("sound level storage interval must be at least the measurement interval of %d seconds, got %d", settings.Interval, settings.Storage.Interval)).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "sound-level-storage-interval").
+				Context("storage_interval", settings.Storage.Interval).
+				Context("interval", settings.Interval).
+				Build()
+		}
+	}
+	if settings.Storage.RetentionDays < 0 {
+		return errors.New(fmt.Errorf("sound level retention days must not be negative, got %d", settings.Storage.RetentionDays)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "sound-level-retention").
+			Context("retention_days", settings.Storage.RetentionDays).
+			Build()
 	}
 	return nil
 }
@@ -512,6 +932,217 @@ func validateWeatherSettings(settings *WeatherSettings) error {
 	return nil
 }
 
+// validateDarwinCoreSettings validates the Darwin Core archive export settings
+func validateDarwinCoreSettings(settings *DarwinCoreSettings) error {
+	if _, ok := DarwinCoreLicenses[settings.License]; settings.License != "" && !ok {
+		return errors.New(fmt.Errorf("darwin core license must be CC0-1.0, CC-BY-4.0 or CC-BY-NC-4.0, got %q", settings.License)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "darwin-core-license").
+			Context("license", settings.License).
+			Build()
+	}
+
+	if settings.BaseURL != "" {
+		u, err := url.Parse(settings.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New(fmt.Errorf("darwin core base URL must be an http or https URL, got %q", settings.BaseURL)).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "darwin-core-base-url").
+				Build()
+		}
+	}
+	return nil
+}
+
+// validateNotificationStoreSettings validates the notification store type and retention
+func validateNotificationStoreSettings(settings *NotificationStoreSettings) error {
+	switch settings.Type {
+	case "", "memory", "database":
+	default:
+		return errors.New(fmt.Errorf("notification store type must be memory or database, got %q", settings.Type)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-store").
+			Context("store_type", settings.Type).
+			Build()
+	}
+	if settings.RetentionDays < 0 {
+		return errors.New(fmt.Errorf("notification retention days must not be negative, got %d", settings.RetentionDays)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-store").
+			Context("retention_days", settings.RetentionDays).
+			Build()
+	}
+	return nil
+}
+
+// validatePushSettings validates the enabled push notification channels
+func validatePushSettings(settings *PushSettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	var errs []string
+	for i := range settings.Channels {
+		channel := &settings.Channels[i]
+		if !channel.Enabled {
+			continue
+		}
+		label := channel.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		errs = append(errs, validatePushChannelSettings("push channel "+label, channel)...)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("push notification settings errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "push-notifications").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
+// validatePushChannelSettings validates a single push channel and returns the problems found
+func validatePushChannelSettings(label string, channel *PushChannelSettings) []string {
+	var errs []string
+
+	validURL := func(raw string) bool {
+		u, err := url.Parse(raw)
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	}
+
+	switch channel.Type {
+	case "webhook", "ntfy", "gotify":
+		if !validURL(channel.URL) {
+			errs = append(errs, label+": url must be a valid http or https URL")
+		}
+	case "pushover":
+		if channel.URL != "" && !validURL(channel.URL) {
+			errs = append(errs, label+": url must be a valid http or https URL")
+		}
+	case "smtp":
+	default:
+		errs = append(errs, fmt.Sprintf("%s: unsupported type %q, use smtp, webhook, ntfy, gotify or pushover", label, channel.Type))
+	}
+
+	switch channel.Type {
+	case "webhook":
+		switch strings.ToUpper(channel.Method) {
+		case "", "POST", "PUT", "PATCH":
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unsupported method %s, use POST, PUT or PATCH", label, channel.Method))
+		}
+	case "ntfy":
+		if channel.Topic == "" {
+			errs = append(errs, label+": ntfy topic is required")
+		}
+	case "gotify":
+		if channel.Token == "" {
+			errs = append(errs, label+": gotify application token is required")
+		}
+	case "pushover":
+		if channel.Token == "" || channel.UserKey == "" {
+			errs = append(errs, label+": pushover application token and user key are required")
+		}
+	case "smtp":
+		if channel.SMTP.Host == "" {
+			errs = append(errs, label+": smtp host is required")
+		}
+		if channel.SMTP.Port < 0 || channel.SMTP.Port > 65535 {
+			errs = append(errs, label+": smtp port must be between 0 and 65535")
+		}
+		if channel.SMTP.From == "" || len(channel.SMTP.To) == 0 {
+			errs = append(errs, label+": smtp sender and recipients are required")
+		}
+		switch channel.SMTP.Encryption {
+		case "", "starttls", "tls", "none":
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unsupported smtp encryption %q, use starttls, tls or none", label, channel.SMTP.Encryption))
+		}
+	}
+
+	for _, t := range channel.Filter.Types {
+		switch t {
+		case "error", "warning", "info", "detection", "system":
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unknown notification type %q in filter", label, t))
+		}
+	}
+	for _, p := range channel.Filter.Priorities {
+		switch p {
+		case "critical", "high", "medium", "low":
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unknown notification priority %q in filter", label, p))
+		}
+	}
+
+	if channel.RateLimit.MaxEvents < 0 || channel.RateLimit.Window < 0 {
+		errs = append(errs, label+": rate limit must be non-negative")
+	}
+	if channel.Timeout < 0 {
+		errs = append(errs, label+": timeout must be non-negative")
+	}
+
+	return errs
+}
+
+// validateDetectionRules validates the enabled detection notification rules
+func validateDetectionRules(rules []DetectionRuleSettings) error {
+	var errs []string
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Enabled {
+			continue
+		}
+		label := "detection rule " + rule.Name
+		if rule.Name == "" {
+			label = fmt.Sprintf("detection rule #%d", i+1)
+		}
+
+		if rule.MinConfidence < 0 || rule.MinConfidence > 1 || rule.MaxConfidence < 0 || rule.MaxConfidence > 1 {
+			errs = append(errs, label+": confidence limits must be between 0 and 1")
+		} else if rule.MaxConfidence > 0 && rule.MinConfidence > rule.MaxConfidence {
+			errs = append(errs, label+": minimum confidence must not exceed maximum confidence")
+		}
+		if rule.NotSeenDays < 0 {
+			errs = append(errs, label+": not seen days must be non-negative")
+		}
+		if (rule.TimeOfDay.Start == "") != (rule.TimeOfDay.End == "") {
+			errs = append(errs, label+": time of day requires both start and end")
+		} else if rule.TimeOfDay.Start != "" {
+			for _, clock := range []string{rule.TimeOfDay.Start, rule.TimeOfDay.End} {
+				if _, err := time.Parse("15:04", clock); err != nil {
+					errs = append(errs, fmt.Sprintf("%s: invalid time of day %q, use HH:MM", label, clock))
+				}
+			}
+		}
+		if rule.Burst.Count < 0 || rule.Burst.Window < 0 {
+			errs = append(errs, label+": burst count and window must be non-negative")
+		} else if rule.Burst.Count > 0 && rule.Burst.Window == 0 {
+			errs = append(errs, label+": burst window is required with burst count")
+		}
+		switch rule.Priority {
+		case "", "critical", "high", "medium", "low":
+		default:
+			errs = append(errs, fmt.Sprintf("%s: unknown notification priority %q", label, rule.Priority))
+		}
+		if rule.Expiry < 0 || rule.Cooldown < 0 {
+			errs = append(errs, label+": expiry and cooldown must be non-negative")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Errorf("detection rule errors: %v", errs)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "detection-rules").
+			Context("error_count", len(errs)).
+			Build()
+	}
+	return nil
+}
+
 // validateSpeciesTrackingSettings validates the species tracking settings
 func validateSpeciesTrackingSettings(settings *SpeciesTrackingSettings) error {
 	if settings.Enabled {
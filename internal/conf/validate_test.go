@@ -2,6 +2,7 @@ package conf
 
 import (
 	stderrors "errors"
+	"strings"
 	"testing"
 
 	"github.com/tphakala/birdnet-go/internal/errors"
@@ -90,6 +91,35 @@ func TestValidateSoundLevelSettings(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "storage interval shorter than measurement interval - should fail",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 10,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 5},
+			},
+			wantErr: true,
+			errType: "sound-level-storage-interval",
+		},
+		{
+			name: "storage with one minute interval - should pass",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 10,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 60, RetentionDays: 30},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative storage retention - should fail",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 10,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 60, RetentionDays: -1},
+			},
+			wantErr: true,
+			errType: "sound-level-retention",
+		},
 	}
 
 	// Run test cases
@@ -220,4 +250,236 @@ func BenchmarkValidateSoundLevelSettingsWithError(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = validateSoundLevelSettings(settings)
 	}
-}
\ No newline at end of file
+}
+
+func TestValidateDarwinCoreSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings DarwinCoreSettings
+		errType  string
+	}{
+		{name: "defaults - should pass", settings: DarwinCoreSettings{License: "CC-BY-4.0"}},
+		{name: "empty license - should pass", settings: DarwinCoreSettings{}},
+		{name: "https base URL - should pass", settings: DarwinCoreSettings{License: "CC0-1.0", BaseURL: "https://birdnet.example.org"}},
+		{name: "unsupported license - should fail", settings: DarwinCoreSettings{License: "GPL-3.0"}, errType: "darwin-core-license"},
+		{name: "base URL without scheme - should fail", settings: DarwinCoreSettings{BaseURL: "birdnet.example.org"}, errType: "darwin-core-base-url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDarwinCoreSettings(&tt.settings)
+			if tt.errType == "" {
+				if err != nil {
+					t.Errorf("validateDarwinCoreSettings() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var enhancedErr *errors.EnhancedError
+			if !stderrors.As(err, &enhancedErr) {
+				t.Fatalf("expected EnhancedError, got %v", err)
+			}
+			if enhancedErr.Context["validation_type"] != tt.errType {
+				t.Errorf("expected validation_type = %s, got %v", tt.errType, enhancedErr.Context["validation_type"])
+			}
+		})
+	}
+}
+
+func TestValidateEnsembleSettings(t *testing.T) {
+	model := EnsembleModelSettings{Name: "regional", ModelPath: "regional.tflite", LabelPath: "regional.txt"}
+	weighted := model
+	weighted.Weight = 0.5
+
+	tests := []struct {
+		name    string
+		fusion  string
+		models  []EnsembleModelSettings
+		wantErr string
+	}{
+		{name: "max without weight - should pass", fusion: "max", models: []EnsembleModelSettings{model}},
+		{name: "weighted with weight - should pass", fusion: "weighted", models: []EnsembleModelSettings{weighted}},
+		{name: "weighted without weight - should fail", fusion: "weighted", models: []EnsembleModelSettings{weighted, model}, wantErr: "model 2: weight must be greater than 0"},
+		{name: "negative weight - should fail", fusion: "mean", models: []EnsembleModelSettings{{ModelPath: "a", LabelPath: "b", Weight: -1}}, wantErr: "weight must be at least 0"},
+		{name: "unknown fusion - should fail", fusion: "median", models: []EnsembleModelSettings{model}, wantErr: "fusion method 'median' is not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			birdnet := BirdNETConfig{RangeFilter: RangeFilterSettings{Model: "latest"}}
+			birdnet.Ensemble = EnsembleSettings{Enabled: true, Fusion: tt.fusion, PrimaryWeight: 1, Models: tt.models}
+
+			err := validateBirdNETSettings(&birdnet, &Settings{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateBirdNETSettings() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatePushSettings(t *testing.T) {
+	ntfy := PushChannelSettings{Name: "phone", Enabled: true, Type: "ntfy", URL: "https://ntfy.sh", Topic: "birds"}
+	mail := PushChannelSettings{Enabled: true, Type: "smtp", SMTP: SMTPSettings{Host: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}}}
+
+	tests := []struct {
+		name     string
+		settings PushSettings
+		wantErr  string
+	}{
+		{name: "disabled - should pass", settings: PushSettings{Channels: []PushChannelSettings{{Enabled: true, Type: "pager"}}}},
+		{name: "ntfy and smtp - should pass", settings: PushSettings{Enabled: true, Channels: []PushChannelSettings{ntfy, mail}}},
+		{name: "disabled channel - should pass", settings: PushSettings{Enabled: true, Channels: []PushChannelSettings{{Type: "pager"}}}},
+		{name: "unknown type - should fail", settings: PushSettings{Enabled: true, Channels: []PushChannelSettings{{Enabled: true, Type: "pager"}}}, wantErr: "unsupported type"},
+		{name: "ntfy without topic - should fail", settings: PushSettings{Enabled: true, Channels: []PushChannelSettings{{Enabled: true, Type: "ntfy", URL: "https://ntfy.sh"}}}, wantErr: "topic is required"},
+		{name: "pushover without user key - should fail", settings: PushSettings{Enabled: true, Channels: []PushChannelSettings{{Enabled: true, Type: "pushover", Token: "app"}}}, wantErr: "user key are required"},
+		{name: "unknown priority filter - should fail", settings: PushSettings{Enabled: true, Channels: []PushChannelSettings{{
+			Enabled: true, Type: "gotify", URL: "https://gotify.example.com", Token: "app",
+			Filter: PushFilterSettings{Priorities: []string{"urgent"}},
+		}}}, wantErr: "unknown notification priority"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePushSettings(&tt.settings)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validatePushSettings() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var enhancedErr *errors.EnhancedError
+			if !stderrors.As(err, &enhancedErr) {
+				t.Fatalf("expected EnhancedError, got %v", err)
+			}
+			if enhancedErr.Context["validation_type"] != "push-notifications" {
+				t.Errorf("expected validation_type = push-notifications, got %v", enhancedErr.Context["validation_type"])
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "default body - should pass"},
+		{name: "valid template - should pass", template: `{"species": {{json .Note.CommonName}}}`},
+		{name: "unclosed action - should fail", template: `{"species": {{.Note.CommonName}`, wantErr: "webhook #1: invalid template"},
+		{name: "unknown function - should fail", template: `{{yaml .Note}}`, wantErr: "function \"yaml\" not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := WebhookSettings{URL: "https://example.com/hook", Template: tt.template}
+			errs := validateWebhookSettings("webhook #1", &webhook)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validateWebhookSettings() unexpected errors = %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateJobQueueSettings(t *testing.T) {
+	if err := validateJobQueueSettings(&JobQueueSettings{JournalPath: ""}); err != nil {
+		t.Errorf("journal path is not required without persist, got %v", err)
+	}
+	if err := validateJobQueueSettings(&JobQueueSettings{Persist: true, JournalPath: "jobs.journal"}); err != nil {
+		t.Errorf("expected valid settings, got %v", err)
+	}
+
+	for _, settings := range []JobQueueSettings{
+		{Persist: true, JournalPath: " "},
+		{MaxDeadLetters: -1},
+	} {
+		err := validateJobQueueSettings(&settings)
+		var enhancedErr *errors.EnhancedError
+		if !stderrors.As(err, &enhancedErr) || enhancedErr.Context["validation_type"] != "job-queue" {
+			t.Errorf("expected job-queue validation error for %+v, got %v", settings, err)
+		}
+	}
+}
+
+func TestValidateNotificationStoreSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings NotificationStoreSettings
+		wantErr  bool
+	}{
+		{name: "memory - should pass", settings: NotificationStoreSettings{Type: "memory", RetentionDays: 90}},
+		{name: "database - should pass", settings: NotificationStoreSettings{Type: "database"}},
+		{name: "unknown type - should fail", settings: NotificationStoreSettings{Type: "redis"}, wantErr: true},
+		{name: "negative retention - should fail", settings: NotificationStoreSettings{Type: "database", RetentionDays: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNotificationStoreSettings(&tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateNotificationStoreSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var enhancedErr *errors.EnhancedError
+			if tt.wantErr && (!stderrors.As(err, &enhancedErr) || enhancedErr.Context["validation_type"] != "notification-store") {
+				t.Errorf("expected notification-store validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateDetectionRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    DetectionRuleSettings
+		wantErr string
+	}{
+		{name: "watchlist - should pass", rule: DetectionRuleSettings{Enabled: true, Species: []string{"Tyto alba"}, Priority: "high", Cooldown: 3600}},
+		{name: "disabled - should pass", rule: DetectionRuleSettings{Priority: "urgent"}},
+		{name: "night - should pass", rule: DetectionRuleSettings{Enabled: true, TimeOfDay: RuleTimeOfDaySettings{Start: "22:00", End: "05:00"}}},
+		{name: "confidence out of range - should fail", rule: DetectionRuleSettings{Enabled: true, MinConfidence: 1.5}, wantErr: "between 0 and 1"},
+		{name: "inverted confidence - should fail", rule: DetectionRuleSettings{Enabled: true, MinConfidence: 0.9, MaxConfidence: 0.5}, wantErr: "must not exceed"},
+		{name: "start without end - should fail", rule: DetectionRuleSettings{Enabled: true, TimeOfDay: RuleTimeOfDaySettings{Start: "22:00"}}, wantErr: "both start and end"},
+		{name: "invalid time - should fail", rule: DetectionRuleSettings{Enabled: true, TimeOfDay: RuleTimeOfDaySettings{Start: "10pm", End: "05:00"}}, wantErr: "invalid time of day"},
+		{name: "burst without window - should fail", rule: DetectionRuleSettings{Enabled: true, Burst: RuleBurstSettings{Count: 20}}, wantErr: "burst window is required"},
+		{name: "unknown priority - should fail", rule: DetectionRuleSettings{Enabled: true, Priority: "urgent"}, wantErr: "unknown notification priority"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDetectionRules([]DetectionRuleSettings{tt.rule})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateDetectionRules() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var enhancedErr *errors.EnhancedError
+			if !stderrors.As(err, &enhancedErr) {
+				t.Fatalf("expected EnhancedError, got %v", err)
+			}
+			if enhancedErr.Context["validation_type"] != "detection-rules" {
+				t.Errorf("expected validation_type = detection-rules, got %v", enhancedErr.Context["validation_type"])
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// conf/webhook.go contains parsing of webhook body templates
+package conf
+
+import (
+	"encoding/json"
+	"text/template"
+)
+
+// webhookTemplateFuncs are the functions available in webhook body templates
+var webhookTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. {{json .Note.CommonName}} for a quoted string
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseWebhookTemplate parses a webhook body template. Missing keys are
+// errors when the template is executed.
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(text)
+}
@@ -55,6 +55,16 @@ type NewSpeciesData struct {
 	CountInPeriod  int    `json:"count_in_period"` // Optional: How many times seen in the query period
 }
 
+// ReviewedDetection is a detection that has been reviewed by the user as
+// either correct or a false positive
+type ReviewedDetection struct {
+	ScientificName string  `json:"scientific_name"`
+	CommonName     string  `json:"common_name"`
+	Confidence     float64 `json:"confidence"`
+	Verified       string  `json:"verified"` // "correct" or "false_positive"
+	Source         string  `json:"-"`        // Audio source, may contain RTSP credentials
+}
+
 // GetSpeciesSummaryData retrieves overall statistics for all bird species
 // Optional date range filtering with startDate and endDate parameters in YYYY-MM-DD format
 func (ds *DataStore) GetSpeciesSummaryData(startDate, endDate string) ([]SpeciesSummaryData, error) {
@@ -597,3 +607,24 @@ func (ds *DataStore) GetNewSpeciesDetections(startDate, endDate string, limit, o
 
 	return finalResults, nil
 }
+
+// GetReviewedDetections retrieves the confidence and review status of all
+// reviewed detections, optionally limited to a single species by scientific name
+func (ds *DataStore) GetReviewedDetections(scientificName string) ([]ReviewedDetection, error) {
+	var detections []ReviewedDetection
+
+	query := ds.DB.Table("notes").
+		Select("notes.scientific_name, notes.common_name, notes.confidence, note_reviews.verified, notes.source").
+		Joins("JOIN note_reviews ON note_reviews.note_id = notes.id").
+		Where("note_reviews.verified IN ?", []string{"correct", "false_positive"})
+	if scientificName != "" {
+		query = query.Where("notes.scientific_name = ?", scientificName)
+	}
+
+	if err := query.Scan(&detections).Error; err != nil {
+		return nil, dbError(err, "get_reviewed_detections", errors.PriorityMedium,
+			"scientific_name", scientificName)
+	}
+
+	return detections, nil
+}
@@ -642,3 +642,39 @@ func TestDatabasePerformance(t *testing.T) {
 	duration = time.Since(start)
 	assert.Less(t, duration.Milliseconds(), int64(paginationThresholdMs), "Paginated queries should complete within %dms", paginationThresholdMs)
 }
+
+// TestGetReviewedDetections tests retrieval of reviewed detections for calibration
+func TestGetReviewedDetections(t *testing.T) {
+	ds := setupTestDB(t)
+	seedTestData(t, ds)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}))
+
+	reviews := []NoteReview{
+		{NoteID: 1, Verified: "correct"},
+		{NoteID: 2, Verified: "false_positive"},
+		{NoteID: 3, Verified: "correct"},
+		{NoteID: 5, Verified: "unverified"}, // Not a final review status, ignored
+	}
+	for i := range reviews {
+		require.NoError(t, ds.DB.Create(&reviews[i]).Error)
+	}
+	require.NoError(t, ds.DB.Model(&Note{}).Where("id = ?", 1).Update("source", "rtsp://garden/stream").Error)
+
+	all, err := ds.GetReviewedDetections("")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	robins, err := ds.GetReviewedDetections("Turdus migratorius")
+	require.NoError(t, err)
+	require.Len(t, robins, 2)
+	verified := map[string]float64{}
+	for _, r := range robins {
+		assert.Equal(t, "American Robin", r.CommonName)
+		verified[r.Verified] = r.Confidence
+		if r.Verified == "correct" {
+			assert.Equal(t, "rtsp://garden/stream", r.Source)
+		}
+	}
+	assert.InDelta(t, 0.85, verified["correct"], 0.001)
+	assert.InDelta(t, 0.90, verified["false_positive"], 0.001)
+}
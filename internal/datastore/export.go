@@ -0,0 +1,60 @@
+// export.go contains batched iteration of detections for bulk exports
+package datastore
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// exportBatchSize limits the number of notes loaded into memory at once by ExportDetections
+const exportBatchSize = 500
+
+// ExportDetections calls fn for every note matching the search filters in
+// ascending ID order. Notes are read in batches so that memory use does not
+// depend on the number of matching detections. Paging and sorting of the
+// filters are ignored. Iteration stops at the first error returned by fn.
+func (ds *DataStore) ExportDetections(filters *SearchFilters, fn func(note *Note) error) error {
+	if err := filters.sanitise(); err != nil {
+		return errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryValidation).
+			Context("operation", "export_detections").
+			Build()
+	}
+
+	query := ds.DB.Model(&Note{}).
+		Select("notes.*").
+		Joins("LEFT JOIN note_reviews ON notes.id = note_reviews.note_id").
+		Joins("LEFT JOIN note_locks ON notes.id = note_locks.note_id").
+		Preload("Review").
+		Preload("Lock")
+	query = applyCommonFilters(query, filters, ds)
+	if filters.Ctx != nil {
+		query = query.WithContext(filters.Ctx)
+	}
+
+	var fnErr error
+	var batch []Note
+	result := query.FindInBatches(&batch, exportBatchSize, func(_ *gorm.DB, _ int) error {
+		for i := range batch {
+			note := &batch[i]
+			if note.Review != nil {
+				note.Verified = note.Review.Verified
+			}
+			note.Locked = note.Lock != nil
+			if err := fn(note); err != nil {
+				fnErr = err
+				return err
+			}
+		}
+		return nil
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if result.Error != nil {
+		return dbError(result.Error, "export_detections", errors.PriorityMedium)
+	}
+	return nil
+}
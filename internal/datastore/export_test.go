@@ -0,0 +1,70 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDetections(t *testing.T) {
+	t.Parallel()
+
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}, &NoteLock{}))
+
+	// More notes than one batch to cover batched iteration
+	notes := make([]Note, 0, exportBatchSize+10)
+	for i := range exportBatchSize + 10 {
+		source := "rtsp://garden"
+		if i%2 == 1 {
+			source = "malgo"
+		}
+		notes = append(notes, Note{
+			Date: "2024-05-01", Time: fmt.Sprintf("06:%02d:%02d", i/60%60, i%60),
+			Source: source, ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.9,
+		})
+	}
+	require.NoError(t, ds.DB.CreateInBatches(&notes, 100).Error)
+	require.NoError(t, ds.DB.Create(&NoteReview{NoteID: notes[0].ID, Verified: "correct"}).Error)
+	require.NoError(t, ds.DB.Create(&NoteLock{NoteID: notes[2].ID}).Error)
+
+	var ids []uint
+	err := ds.ExportDetections(&SearchFilters{Ctx: context.Background(), Sources: []string{"rtsp://garden"}}, func(note *Note) error {
+		ids = append(ids, note.ID)
+		assert.Equal(t, "rtsp://garden", note.Source)
+		if note.ID == notes[0].ID {
+			assert.Equal(t, "correct", note.Verified)
+		}
+		assert.Equal(t, note.ID == notes[2].ID, note.Locked)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, (exportBatchSize+10)/2)
+	assert.IsIncreasing(t, ids)
+
+	// Review filters use the joined review table
+	count := 0
+	err = ds.ExportDetections(&SearchFilters{VerifiedOnly: true}, func(note *Note) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Errors of the callback stop the export
+	errStop := errors.New("stop")
+	count = 0
+	err = ds.ExportDetections(&SearchFilters{}, func(note *Note) error {
+		count++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, count)
+
+	err = ds.ExportDetections(&SearchFilters{VerifiedOnly: true, UnverifiedOnly: true}, func(*Note) error { return nil })
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package datastore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"log/slog"
@@ -90,8 +91,22 @@ type Interface interface {
 	GetHourlyDistribution(startDate, endDate string, species string) ([]HourlyDistributionData, error)
 	GetNewSpeciesDetections(startDate, endDate string, limit, offset int) ([]NewSpeciesData, error)
 	GetSpeciesFirstDetectionInPeriod(startDate, endDate string, limit, offset int) ([]NewSpeciesData, error)
+	GetReviewedDetections(scientificName string) ([]ReviewedDetection, error)
+	// Review queue
+	GetReviewQueue(query *ReviewQueueQuery) ([]ReviewQueueItem, int64, error)
+	ClaimReviews(query *ReviewQueueQuery, ttl time.Duration) ([]ReviewQueueItem, error)
+	ReleaseReviewClaims(reviewer string, noteIDs []uint) (int64, error)
+	ApplyReviewVerdicts(reviewer string, verdicts []ReviewVerdict) ([]ReviewVerdictResult, error)
+	GetNoteReviewHistory(noteID uint) ([]NoteReviewHistory, error)
+	GetReviewProgress(since time.Time) (*ReviewProgress, error)
+	// Sound level history
+	SaveSoundLevels(levels []SoundLevel) error
+	GetSoundLevels(query *SoundLevelQuery) ([]SoundLevel, error)
+	GetSoundLevelSources() ([]SoundLevelSource, error)
+	DeleteSoundLevelsBefore(before time.Time) (int64, error)
 	// Search functionality
 	SearchDetections(filters *SearchFilters) ([]DetectionRecord, int, error)
+	ExportDetections(filters *SearchFilters, fn func(note *Note) error) error
 }
 
 // DataStore implements StoreInterface using a GORM database.
@@ -273,6 +288,20 @@ func (ds *DataStore) Delete(id string) error {
 				"table", "results",
 				"action", "delete_detection_results")
 		}
+		// Delete the review history and claims, Note has no association
+		// to them so the cascade constraints don't apply
+		if err := tx.Where("note_id = ?", noteID).Delete(&NoteReviewHistory{}).Error; err != nil {
+			return dbError(err, "delete_review_history", errors.PriorityMedium,
+				"note_id", fmt.Sprintf("%d", noteID),
+				"table", "note_review_histories",
+				"action", "delete_detection_review_history")
+		}
+		if err := tx.Where("note_id = ?", noteID).Delete(&ReviewClaim{}).Error; err != nil {
+			return dbError(err, "delete_review_claims", errors.PriorityMedium,
+				"note_id", fmt.Sprintf("%d", noteID),
+				"table", "review_claims",
+				"action", "delete_detection_review_claims")
+		}
 		// Delete the note itself
 		if err := tx.Delete(&Note{}, noteID).Error; err != nil {
 			return dbError(err, "delete_note", errors.PriorityMedium,
@@ -855,15 +884,14 @@ func (ds *DataStore) GetNoteReview(noteID string) (*NoteReview, error) {
 	return &review, nil
 }
 
-// SaveNoteReview saves or updates a note review
+// SaveNoteReview saves or updates a note review and records the verdict in the review history
 func (ds *DataStore) SaveNoteReview(review *NoteReview) error {
-	// Use upsert operation to either create or update the review
-	result := ds.DB.Where("note_id = ?", review.NoteID).
-		Assign(*review).
-		FirstOrCreate(review)
+	err := ds.DB.Transaction(func(tx *gorm.DB) error {
+		return saveNoteReview(tx, review)
+	})
 
-	if result.Error != nil {
-		return errors.New(result.Error).
+	if err != nil {
+		return errors.New(err).
 			Component("datastore").
 			Category(errors.CategoryDatabase).
 			Context("operation", "save_note_review").
@@ -1425,7 +1453,10 @@ type SearchFilters struct {
 	LockedOnly     bool
 	UnlockedOnly   bool
 	Device         string
-	TimeOfDay      string // "any", "day", "night", "sunrise", "sunset"
+	Sources        []string // Stored audio sources, matched exactly
+	TimeOfDay      string   // "any", "day", "night", "sunrise", "sunset"
+	NoiseMin       *float64 // Minimum broadband noise level in dB, nil for no limit
+	NoiseMax       *float64 // Maximum broadband noise level in dB, nil for no limit
 	Page           int
 	PerPage        int
 	SortBy         string
@@ -1466,6 +1497,13 @@ func (f *SearchFilters) sanitise() error {
 			Category(errors.CategoryValidation).
 			Build()
 	}
+	// Validate noise level range
+	if f.NoiseMin != nil && f.NoiseMax != nil && *f.NoiseMin > *f.NoiseMax {
+		return errors.Newf("noise_min must be <= noise_max").
+			Component("datastore").
+			Category(errors.CategoryValidation).
+			Build()
+	}
 	// Validate TimeOfDay
 	switch f.TimeOfDay {
 	case "", "any", "day", "night", "sunrise", "sunset": // Add sunrise/sunset
@@ -1543,6 +1581,17 @@ func applyCommonFilters(query *gorm.DB, filters *SearchFilters, ds *DataStore) *
 	if filters.Device != "" {
 		query = query.Where("notes.source_node LIKE ?", "%"+filters.Device+"%")
 	}
+	if len(filters.Sources) > 0 {
+		query = query.Where("notes.source IN ?", filters.Sources)
+	}
+
+	// Detections without a measured noise level are excluded by noise filters
+	if filters.NoiseMin != nil {
+		query = query.Where("notes.noise_level >= ?", *filters.NoiseMin)
+	}
+	if filters.NoiseMax != nil {
+		query = query.Where("notes.noise_level <= ?", *filters.NoiseMax)
+	}
 
 	return query
 }
@@ -1739,6 +1788,7 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 	// Select necessary fields, including potentially null fields from joins
 	query = query.Select("notes.id, notes.date, notes.time, notes.scientific_name, notes.common_name, notes.confidence, " +
 		"notes.latitude, notes.longitude, notes.clip_name, notes.source, notes.source_node, " +
+		"notes.noise_level, notes.noise_bands, " +
 		"note_reviews.verified AS review_verified, " + // Select review status
 		"note_locks.id IS NOT NULL AS is_locked") // Select lock status as boolean
 
@@ -1800,8 +1850,10 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 		ClipName       string
 		Source         string
 		SourceNode     string
-		ReviewVerified *string // Use pointer to handle NULL for review status
-		IsLocked       bool    // Boolean result from IS NOT NULL
+		NoiseLevel     *float64 // Use pointer to handle NULL for detections without noise context
+		NoiseBands     *string  // JSON encoded octave band levels, NULL if not measured
+		ReviewVerified *string  // Use pointer to handle NULL for review status
+		IsLocked       bool     // Boolean result from IS NOT NULL
 	}
 
 	// Execute the query
@@ -1892,6 +1944,12 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 			Device:         scanned.SourceNode,
 			Source:         scanned.Source,
 			TimeOfDay:      timeOfDay, // Include calculated time of day
+			NoiseLevel:     scanned.NoiseLevel,
+		}
+		if scanned.NoiseBands != nil && *scanned.NoiseBands != "" {
+			if err := json.Unmarshal([]byte(*scanned.NoiseBands), &record.NoiseBands); err != nil {
+				log.Printf("Warning: Failed to parse noise bands for note ID %d: %v", scanned.ID, err)
+			}
 		}
 
 		results = append(results, record)
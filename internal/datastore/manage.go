@@ -447,22 +447,37 @@ func validateAndFixSchema(db *gorm.DB, dbType, connectionInfo string, debug bool
 	return nil
 }
 
+// tableMappings lists the migrated models and their tables in migration order
+var tableMappings = []struct {
+	model interface{}
+	name  string
+}{
+	{&Note{}, "notes"},
+	{&Results{}, "results"},
+	{&NoteReview{}, "note_reviews"},
+	{&NoteReviewHistory{}, "note_review_histories"},
+	{&ReviewClaim{}, "review_claims"},
+	{&NoteComment{}, "note_comments"},
+	{&DailyEvents{}, "daily_events"},
+	{&HourlyWeather{}, "hourly_weathers"},
+	{&SoundLevel{}, "sound_levels"},
+	{&NoteLock{}, "note_locks"},
+	{&ImageCache{}, "image_caches"},
+	{&NotificationRecord{}, "notifications"},
+}
+
+// TableNames returns the names of all tables migrated by the datastore in
+// migration order
+func TableNames() []string {
+	names := make([]string, 0, len(tableMappings))
+	for _, table := range tableMappings {
+		names = append(names, table.name)
+	}
+	return names
+}
+
 // migrateTables performs the actual table migrations
 func migrateTables(db *gorm.DB, dbType string, lgr *slog.Logger) (int, error) {
-	tableMappings := []struct {
-		model interface{}
-		name  string
-	}{
-		{&Note{}, "notes"},
-		{&Results{}, "results"},
-		{&NoteReview{}, "note_reviews"},
-		{&NoteComment{}, "note_comments"},
-		{&DailyEvents{}, "daily_events"},
-		{&HourlyWeather{}, "hourly_weather"},
-		{&NoteLock{}, "note_locks"},
-		{&ImageCache{}, "image_caches"},
-	}
-	
 	lgr.Info("Starting table migrations",
 		"table_count", len(tableMappings))
 	
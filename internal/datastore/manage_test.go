@@ -0,0 +1,33 @@
+package datastore
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestTableNames tests that the listed table names are the tables of the migrated models
+func TestTableNames(t *testing.T) {
+	t.Parallel()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	count, err := migrateTables(db, "sqlite", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	names := TableNames()
+	assert.Len(t, names, count)
+	assert.Contains(t, names, "sound_levels")
+	assert.Contains(t, names, "notifications")
+	for i, table := range tableMappings {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(table.model))
+		assert.Equal(t, stmt.Schema.Table, names[i])
+		assert.True(t, db.Migrator().HasTable(names[i]), "table %s is migrated", names[i])
+	}
+}
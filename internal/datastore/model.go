@@ -29,6 +29,16 @@ type Note struct {
 	Comments       []NoteComment `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-many relationship with cascade delete
 	Lock           *NoteLock     `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-one relationship with cascade delete
 
+	// WeatherAdjustment describes the weather filter adjustment applied to the detection, empty if none
+	WeatherAdjustment string
+	// HeldForReview is true if a filter held the detection for review instead of reporting it
+	HeldForReview bool `gorm:"index"`
+
+	// NoiseLevel is the broadband ambient sound level in dB around the detection, nil if not measured
+	NoiseLevel *float64 `gorm:"index"`
+	// NoiseBands are the ambient octave band levels in dB around the detection, keyed by band
+	NoiseBands map[string]float64 `gorm:"type:text;serializer:json"`
+
 	// Virtual fields to maintain compatibility with templates
 	Verified string `gorm:"-"` // This will be populated from Review.Verified
 	Locked   bool   `gorm:"-"` // This will be populated from Lock presence
@@ -42,6 +52,24 @@ type Results struct {
 	Confidence float32
 }
 
+// DefaultDetectionDuration is the duration of detections stored without an end time
+const DefaultDetectionDuration = 3 * time.Second
+
+// DetectionTimes returns the begin and end time of the note, falling back to
+// its date and time for detections stored without them
+func (n *Note) DetectionTimes() (begin, end time.Time) {
+	begin, end = n.BeginTime, n.EndTime
+	if begin.IsZero() {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", n.Date+" "+n.Time, time.Local); err == nil {
+			begin = t
+		}
+	}
+	if !end.After(begin) {
+		end = begin.Add(DefaultDetectionDuration)
+	}
+	return begin, end
+}
+
 // Copy creates a deep copy of the Results struct
 func (r Results) Copy() Results {
 	return Results{
@@ -58,10 +86,31 @@ type NoteReview struct {
 	ID        uint      `gorm:"primaryKey"`
 	NoteID    uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
 	Verified  string    `gorm:"type:varchar(20)"`                                                                                  // Values: "correct", "false_positive"
+	Reviewer  string    `gorm:"type:varchar(100);index"`                                                                           // Identifier of the reviewer, empty if unknown
 	CreatedAt time.Time `gorm:"index"`                                                                                             // When the review was created
 	UpdatedAt time.Time // When the review was last updated
 }
 
+// NoteReviewHistory records every verdict given for a Note, including verdicts later changed
+// GORM will automatically create table name as 'note_review_histories'
+type NoteReviewHistory struct {
+	ID        uint      `gorm:"primaryKey"`
+	NoteID    uint      `gorm:"index;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
+	Verified  string    `gorm:"type:varchar(20)"`                                                                            // Values: "correct", "false_positive"
+	Reviewer  string    `gorm:"type:varchar(100);index"`                                                                     // Identifier of the reviewer, empty if unknown
+	CreatedAt time.Time `gorm:"index"`                                                                                       // When the verdict was given
+}
+
+// ReviewClaim reserves an unreviewed Note for a reviewer until the claim expires
+// GORM will automatically create table name as 'review_claims'
+type ReviewClaim struct {
+	ID        uint      `gorm:"primaryKey"`
+	NoteID    uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note, one claim per note
+	Reviewer  string    `gorm:"type:varchar(100);index;not null"`                                                                  // Identifier of the reviewer holding the claim
+	ClaimedAt time.Time `gorm:"not null"`                                                                                          // When the note was claimed
+	ExpiresAt time.Time `gorm:"index;not null"`                                                                                    // When the claim expires and the note returns to the queue
+}
+
 // NoteComment represents user comments on a detection
 // GORM will automatically create table name as 'note_comments'
 type NoteComment struct {
@@ -111,6 +160,22 @@ type HourlyWeather struct {
 	WeatherIcon   string
 }
 
+// SoundLevel represents the aggregated sound level of one octave band over one storage period
+// GORM will automatically create table name as 'sound_levels'
+type SoundLevel struct {
+	ID         uint      `gorm:"primaryKey"`
+	Timestamp  time.Time `gorm:"index:idx_soundlevels_source_timestamp,priority:2;index;not null"`             // UTC start of the aggregation period
+	Source     string    `gorm:"type:varchar(255);index:idx_soundlevels_source_timestamp,priority:1;not null"` // Audio source identifier
+	Name       string    `gorm:"type:varchar(255)"`                                                            // Display name of the audio source
+	Band       string    `gorm:"type:varchar(20);not null"`                                                    // Octave band key, e.g. "1.0_kHz"
+	CenterFreq float64   // Center frequency of the band in Hz
+	Duration   int       // Seconds of measurements in the period
+	Leq        float64   // Equivalent continuous level in dB over the period
+	Lmax       float64   // Maximum level in dB
+	Lmin       float64   // Minimum level in dB
+	Samples    int       // Number of 1-second samples in the period
+}
+
 // ImageCache represents cached image metadata for species
 type ImageCache struct {
 	ID             uint      `gorm:"primaryKey"`
@@ -133,19 +198,21 @@ type ImageCacheQuery struct {
 
 // DetectionRecord represents a bird detection record for search results
 type DetectionRecord struct {
-	ID             string    `json:"id"`
-	Timestamp      time.Time `json:"timestamp"`
-	ScientificName string    `json:"scientificName,omitempty"`
-	CommonName     string    `json:"commonName,omitempty"`
-	Confidence     float64   `json:"confidence,omitempty"`
-	Latitude       float64   `json:"latitude,omitempty"`
-	Longitude      float64   `json:"longitude,omitempty"`
-	Week           int       `json:"week,omitempty"`
-	AudioFilePath  string    `json:"audioFilePath,omitempty"`
-	Verified       string    `json:"verified,omitempty"`
-	Locked         bool      `json:"locked,omitempty"`
-	HasAudio       bool      `json:"hasAudio,omitempty"`
-	Device         string    `json:"device,omitempty"`
-	Source         string    `json:"source,omitempty"`
-	TimeOfDay      string    `json:"timeOfDay,omitempty"`
+	ID             string             `json:"id"`
+	Timestamp      time.Time          `json:"timestamp"`
+	ScientificName string             `json:"scientificName,omitempty"`
+	CommonName     string             `json:"commonName,omitempty"`
+	Confidence     float64            `json:"confidence,omitempty"`
+	Latitude       float64            `json:"latitude,omitempty"`
+	Longitude      float64            `json:"longitude,omitempty"`
+	Week           int                `json:"week,omitempty"`
+	AudioFilePath  string             `json:"audioFilePath,omitempty"`
+	Verified       string             `json:"verified,omitempty"`
+	Locked         bool               `json:"locked,omitempty"`
+	HasAudio       bool               `json:"hasAudio,omitempty"`
+	Device         string             `json:"device,omitempty"`
+	Source         string             `json:"source,omitempty"`
+	TimeOfDay      string             `json:"timeOfDay,omitempty"`
+	NoiseLevel     *float64           `json:"noiseLevel,omitempty"` // Broadband ambient noise in dB around the detection
+	NoiseBands     map[string]float64 `json:"noiseBands,omitempty"` // Ambient octave band levels in dB around the detection
 }
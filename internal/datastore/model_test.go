@@ -0,0 +1,24 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteDetectionTimes(t *testing.T) {
+	t.Parallel()
+
+	begin := time.Date(2024, 6, 1, 6, 30, 0, 0, time.Local)
+	note := Note{BeginTime: begin, EndTime: begin.Add(2 * time.Second)}
+	start, end := note.DetectionTimes()
+	assert.Equal(t, begin, start)
+	assert.Equal(t, begin.Add(2*time.Second), end)
+
+	// Detections stored without begin and end times use the detection time
+	note = Note{Date: "2024-06-01", Time: "06:30:00"}
+	start, end = note.DetectionTimes()
+	assert.True(t, begin.Equal(start))
+	assert.True(t, begin.Add(DefaultDetectionDuration).Equal(end))
+}
@@ -0,0 +1,267 @@
+// notification.go contains the database backed notification store
+package datastore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// NotificationRecord represents a persisted notification
+type NotificationRecord struct {
+	ID        string     `gorm:"primaryKey;type:varchar(36)"`     // Notification UUID
+	Type      string     `gorm:"type:varchar(20);index;not null"` // notification.Type
+	Priority  string     `gorm:"type:varchar(20);index;not null"` // notification.Priority
+	Status    string     `gorm:"type:varchar(20);index;not null"` // notification.Status
+	Component string     `gorm:"type:varchar(100);index"`         // Source component
+	Title     string     `gorm:"type:varchar(255)"`               // Short summary
+	Message   string     `gorm:"type:text"`                       // Detailed message
+	Metadata  string     `gorm:"type:text"`                       // JSON encoded metadata
+	Timestamp time.Time  `gorm:"index;not null"`                  // UTC creation time
+	ExpiresAt *time.Time `gorm:"index"`                           // UTC expiry time, nil if the notification doesn't expire
+}
+
+// TableName returns the table name of persisted notifications
+func (NotificationRecord) TableName() string {
+	return "notifications"
+}
+
+// NotificationStore persists notifications in the database. It implements
+// notification.NotificationStore so that notifications, and their read and
+// acknowledged state, survive restarts. The notification package owns the
+// store interface and doesn't depend on the datastore, only the datastore
+// depends on it.
+type NotificationStore struct {
+	ds        Interface
+	retention time.Duration // notifications older than this are deleted, 0 keeps them
+}
+
+// Compile-time check that NotificationStore implements notification.NotificationStore
+var _ notification.NotificationStore = (*NotificationStore)(nil)
+
+// NewNotificationStore creates a notification store using the datastore.
+// Notifications older than retention are deleted with expired notifications,
+// a zero retention keeps notifications until they expire or are deleted.
+func NewNotificationStore(ds Interface, retention time.Duration) *NotificationStore {
+	return &NotificationStore{ds: ds, retention: retention}
+}
+
+// Save stores a notification. Toast notifications are ephemeral and only
+// delivered to subscribers, they are not stored.
+func (s *NotificationStore) Save(n *notification.Notification) error {
+	if isToast(n) {
+		return nil
+	}
+	record, err := newNotificationRecord(n)
+	if err != nil {
+		return err
+	}
+	return s.ds.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return dbError(err, "save_notification", errors.PriorityLow, "notification_id", n.ID)
+		}
+		return nil
+	})
+}
+
+// Get returns the notification with the given ID or notification.ErrNotificationNotFound
+func (s *NotificationStore) Get(id string) (*notification.Notification, error) {
+	var record NotificationRecord
+	err := s.ds.Transaction(func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id).First(&record).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, notification.ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, dbError(err, "get_notification", errors.PriorityLow, "notification_id", id)
+	}
+	return record.toNotification(), nil
+}
+
+// List returns the notifications matching the filter, newest first
+func (s *NotificationStore) List(filter *notification.FilterOptions) ([]*notification.Notification, error) {
+	var records []NotificationRecord
+	err := s.ds.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&NotificationRecord{})
+		if filter != nil {
+			query = applyNotificationFilter(query, filter)
+			if filter.Offset > 0 {
+				query = query.Offset(filter.Offset)
+			}
+			if filter.Limit > 0 {
+				query = query.Limit(filter.Limit)
+			}
+		}
+		return query.Order("timestamp DESC").Find(&records).Error
+	})
+	if err != nil {
+		return nil, dbError(err, "list_notifications", errors.PriorityLow)
+	}
+
+	notifications := make([]*notification.Notification, 0, len(records))
+	for i := range records {
+		notifications = append(notifications, records[i].toNotification())
+	}
+	return notifications, nil
+}
+
+// Update replaces a stored notification
+func (s *NotificationStore) Update(n *notification.Notification) error {
+	record, err := newNotificationRecord(n)
+	if err != nil {
+		return err
+	}
+	return s.ds.Transaction(func(tx *gorm.DB) error {
+		// RowsAffected of an update is not reliable for unchanged rows on MySQL,
+		// check that the notification exists first
+		var count int64
+		if err := tx.Model(&NotificationRecord{}).Where("id = ?", n.ID).Count(&count).Error; err != nil {
+			return dbError(err, "update_notification", errors.PriorityLow, "notification_id", n.ID)
+		}
+		if count == 0 {
+			return fmt.Errorf("notification not found: %s", n.ID)
+		}
+		if err := tx.Save(record).Error; err != nil {
+			return dbError(err, "update_notification", errors.PriorityLow, "notification_id", n.ID)
+		}
+		return nil
+	})
+}
+
+// Delete removes a notification
+func (s *NotificationStore) Delete(id string) error {
+	return s.ds.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).Delete(&NotificationRecord{}).Error; err != nil {
+			return dbError(err, "delete_notification", errors.PriorityLow, "notification_id", id)
+		}
+		return nil
+	})
+}
+
+// DeleteExpired removes expired notifications and notifications older than the retention
+func (s *NotificationStore) DeleteExpired() error {
+	now := time.Now().UTC()
+	return s.ds.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at IS NOT NULL AND expires_at < ?", now).Delete(&NotificationRecord{}).Error; err != nil {
+			return dbError(err, "delete_expired_notifications", errors.PriorityLow)
+		}
+		if s.retention > 0 {
+			cutoff := now.Add(-s.retention)
+			if err := tx.Where("timestamp < ?", cutoff).Delete(&NotificationRecord{}).Error; err != nil {
+				return dbError(err, "delete_expired_notifications", errors.PriorityLow,
+					"before", cutoff.Format(time.RFC3339))
+			}
+		}
+		return nil
+	})
+}
+
+// GetUnreadCount returns the number of unread notifications
+func (s *NotificationStore) GetUnreadCount() (int, error) {
+	var count int64
+	err := s.ds.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&NotificationRecord{}).Where("status = ?", string(notification.StatusUnread)).Count(&count).Error
+	})
+	if err != nil {
+		return 0, dbError(err, "count_unread_notifications", errors.PriorityLow)
+	}
+	return int(count), nil
+}
+
+// applyNotificationFilter adds the conditions of the filter to the query
+func applyNotificationFilter(query *gorm.DB, filter *notification.FilterOptions) *gorm.DB {
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		query = query.Where("type IN ?", types)
+	}
+	if len(filter.Priorities) > 0 {
+		priorities := make([]string, len(filter.Priorities))
+		for i, p := range filter.Priorities {
+			priorities[i] = string(p)
+		}
+		query = query.Where("priority IN ?", priorities)
+	}
+	if len(filter.Status) > 0 {
+		statuses := make([]string, len(filter.Status))
+		for i, st := range filter.Status {
+			statuses[i] = string(st)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+	if filter.Component != "" {
+		query = query.Where("component = ?", filter.Component)
+	}
+	if filter.Since != nil {
+		query = query.Where("timestamp >= ?", filter.Since.UTC())
+	}
+	if filter.Until != nil {
+		query = query.Where("timestamp <= ?", filter.Until.UTC())
+	}
+	return query
+}
+
+// newNotificationRecord converts a notification to its database record
+func newNotificationRecord(n *notification.Notification) (*NotificationRecord, error) {
+	record := &NotificationRecord{
+		ID:        n.ID,
+		Type:      string(n.Type),
+		Priority:  string(n.Priority),
+		Status:    string(n.Status),
+		Component: n.Component,
+		Title:     n.Title,
+		Message:   n.Message,
+		Timestamp: n.Timestamp.UTC(),
+	}
+	if n.ExpiresAt != nil {
+		expiresAt := n.ExpiresAt.UTC()
+		record.ExpiresAt = &expiresAt
+	}
+	if len(n.Metadata) > 0 {
+		metadata, err := json.Marshal(n.Metadata)
+		if err != nil {
+			return nil, validationError(err.Error(), "metadata", n.ID)
+		}
+		record.Metadata = string(metadata)
+	}
+	return record, nil
+}
+
+// toNotification converts the record back to a notification. Metadata values
+// are decoded from JSON, so numbers are returned as float64.
+func (r *NotificationRecord) toNotification() *notification.Notification {
+	n := &notification.Notification{
+		ID:        r.ID,
+		Type:      notification.Type(r.Type),
+		Priority:  notification.Priority(r.Priority),
+		Status:    notification.Status(r.Status),
+		Title:     r.Title,
+		Message:   r.Message,
+		Component: r.Component,
+		Timestamp: r.Timestamp,
+		ExpiresAt: r.ExpiresAt,
+		Metadata:  make(map[string]any),
+	}
+	if r.Metadata != "" {
+		if err := json.Unmarshal([]byte(r.Metadata), &n.Metadata); err != nil {
+			getLogger().Warn("Failed to decode notification metadata",
+				"notification_id", r.ID,
+				"error", err)
+		}
+	}
+	return n
+}
+
+// isToast reports whether the notification is an ephemeral toast
+func isToast(n *notification.Notification) bool {
+	toast, ok := n.Metadata[notification.MetadataKeyIsToast].(bool)
+	return ok && toast
+}
@@ -0,0 +1,138 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// setupNotificationTestStore creates a notification store on a test database
+func setupNotificationTestStore(t *testing.T, retention time.Duration) *NotificationStore {
+	t.Helper()
+
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NotificationRecord{}))
+	return NewNotificationStore(&SQLiteStore{DataStore: DataStore{DB: ds.DB}}, retention)
+}
+
+func TestNotificationStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationTestStore(t, 0)
+
+	n := notification.NewNotification(notification.TypeDetection, notification.PriorityHigh, "New species", "Robin").
+		WithComponent("detection").
+		WithMetadata("species", "Turdus migratorius").
+		WithMetadata("confidence", 0.92).
+		WithExpiry(time.Hour)
+	require.NoError(t, store.Save(n))
+
+	got, err := store.Get(n.ID)
+	require.NoError(t, err)
+	assert.Equal(t, n.Title, got.Title)
+	assert.Equal(t, "detection", got.Component)
+	assert.Equal(t, notification.StatusUnread, got.Status)
+	assert.Equal(t, "Turdus migratorius", got.Metadata["species"])
+	assert.InDelta(t, 0.92, got.Metadata["confidence"], 0.0001)
+	assert.True(t, got.Timestamp.Equal(n.Timestamp))
+	require.NotNil(t, got.ExpiresAt)
+	assert.True(t, got.ExpiresAt.Equal(*n.ExpiresAt))
+
+	count, err := store.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	got.MarkAsAcknowledged()
+	require.NoError(t, store.Update(got))
+	// Updating without changes must not report a missing notification
+	require.NoError(t, store.Update(got))
+	got, err = store.Get(n.ID)
+	require.NoError(t, err)
+	assert.Equal(t, notification.StatusAcknowledged, got.Status)
+	count, err = store.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	require.NoError(t, store.Delete(n.ID))
+	_, err = store.Get(n.ID)
+	require.ErrorIs(t, err, notification.ErrNotificationNotFound)
+	assert.Error(t, store.Update(n), "updating a deleted notification should fail")
+}
+
+func TestNotificationStoreList(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationTestStore(t, 0)
+
+	now := time.Now()
+	for i, n := range []*notification.Notification{
+		notification.NewNotification(notification.TypeError, notification.PriorityCritical, "disk", "m").WithComponent("diskmanager"),
+		notification.NewNotification(notification.TypeDetection, notification.PriorityHigh, "robin", "m").WithComponent("detection"),
+		notification.NewNotification(notification.TypeDetection, notification.PriorityMedium, "crow", "m").WithComponent("detection"),
+		notification.NewNotification(notification.TypeInfo, notification.PriorityLow, "update", "m"),
+	} {
+		n.Timestamp = now.Add(-time.Duration(i) * time.Hour)
+		require.NoError(t, store.Save(n))
+	}
+	toast := notification.NewNotification(notification.TypeInfo, notification.PriorityLow, "toast", "m").
+		WithMetadata(notification.MetadataKeyIsToast, true)
+	require.NoError(t, store.Save(toast))
+
+	titles := func(filter *notification.FilterOptions) []string {
+		t.Helper()
+		list, err := store.List(filter)
+		require.NoError(t, err)
+		result := make([]string, 0, len(list))
+		for _, n := range list {
+			result = append(result, n.Title)
+		}
+		return result
+	}
+
+	// Toasts are not stored, results are newest first
+	assert.Equal(t, []string{"disk", "robin", "crow", "update"}, titles(nil))
+	assert.Equal(t, []string{"robin", "crow"}, titles(&notification.FilterOptions{
+		Types: []notification.Type{notification.TypeDetection},
+	}))
+	assert.Equal(t, []string{"disk", "robin"}, titles(&notification.FilterOptions{
+		Priorities: []notification.Priority{notification.PriorityCritical, notification.PriorityHigh},
+	}))
+	assert.Equal(t, []string{"robin", "crow"}, titles(&notification.FilterOptions{Component: "detection"}))
+
+	since := now.Add(-150 * time.Minute)
+	until := now.Add(-30 * time.Minute)
+	assert.Equal(t, []string{"robin", "crow"}, titles(&notification.FilterOptions{Since: &since, Until: &until}))
+	assert.Equal(t, []string{"robin", "crow"}, titles(&notification.FilterOptions{Offset: 1, Limit: 2}))
+
+	count, err := store.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []string{"disk", "robin", "crow", "update"}, titles(&notification.FilterOptions{
+		Status: []notification.Status{notification.StatusUnread},
+	}))
+}
+
+func TestNotificationStoreDeleteExpired(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationTestStore(t, 7*24*time.Hour)
+
+	expired := notification.NewNotification(notification.TypeInfo, notification.PriorityLow, "expired", "m")
+	past := time.Now().Add(-time.Minute)
+	expired.ExpiresAt = &past
+	old := notification.NewNotification(notification.TypeError, notification.PriorityHigh, "old", "m")
+	old.Timestamp = time.Now().Add(-8 * 24 * time.Hour)
+	recent := notification.NewNotification(notification.TypeError, notification.PriorityHigh, "recent", "m").
+		WithExpiry(time.Hour)
+	for _, n := range []*notification.Notification{expired, old, recent} {
+		require.NoError(t, store.Save(n))
+	}
+
+	require.NoError(t, store.DeleteExpired())
+
+	list, err := store.List(nil)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "recent", list[0].Title)
+}
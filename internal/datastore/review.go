@@ -0,0 +1,458 @@
+// review.go contains the review queue, review claims and reviewer progress
+package datastore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Review queue sort orders
+const (
+	ReviewSortPriority   = "priority"   // rarest species first, then lowest confidence
+	ReviewSortConfidence = "confidence" // lowest confidence first, then rarest species
+	ReviewSortNewest     = "newest"     // most recent detections first
+)
+
+// Review verdict outcomes
+const (
+	ReviewVerdictApplied  = "applied"   // verdict saved
+	ReviewVerdictNotFound = "not_found" // detection does not exist
+	ReviewVerdictLocked   = "locked"    // detection is locked
+	ReviewVerdictClaimed  = "claimed"   // detection is claimed by another reviewer
+	ReviewVerdictInvalid  = "invalid"   // verdict is not correct or false_positive
+)
+
+// ReviewQueueQuery selects the unreviewed detections served by the review queue
+type ReviewQueueQuery struct {
+	Reviewer      string  // reviewer requesting the queue, their own claims are included
+	Species       string  // common or scientific name, empty for all species
+	MinConfidence float64 // minimum confidence, 0 for no limit
+	MaxConfidence float64 // maximum confidence, 0 for no limit
+	StartDate     string  // first date in YYYY-MM-DD format, empty for no limit
+	EndDate       string  // last date in YYYY-MM-DD format, empty for no limit
+	Sort          string  // ReviewSortPriority, ReviewSortConfidence or ReviewSortNewest
+	ClaimedOnly   bool    // true to only return detections claimed by the reviewer
+	HeldOnly      bool    // true to only return detections held for review by a filter
+	Limit         int
+	Offset        int
+}
+
+// ReviewQueueItem is an unreviewed detection in the review queue
+type ReviewQueueItem struct {
+	ID             uint
+	Date           string
+	Time           string
+	Source         string
+	ScientificName string
+	CommonName     string
+	Confidence     float64
+	ClipName       string
+	HeldForReview  bool       // true if a filter held the detection for review
+	SpeciesCount   int64      // detections of the species, lower is rarer
+	ClaimedBy      string     // reviewer holding an active claim, empty if unclaimed
+	ClaimExpiresAt *time.Time // expiry of the active claim, nil if unclaimed
+}
+
+// ReviewVerdict is a verdict for a single detection
+type ReviewVerdict struct {
+	NoteID   uint
+	Verified string // "correct" or "false_positive"
+}
+
+// ReviewVerdictResult is the outcome of a single verdict in a batch
+type ReviewVerdictResult struct {
+	NoteID    uint
+	Status    string // one of the ReviewVerdict outcome constants
+	ClaimedBy string // reviewer holding the claim if Status is ReviewVerdictClaimed
+}
+
+// ReviewerProgress summarizes the verdicts of a single reviewer
+type ReviewerProgress struct {
+	Reviewer      string     // reviewer identifier, empty for unattributed reviews
+	Verdicts      int64      // verdicts given, including verdicts later changed
+	Correct       int64      // verdicts marking detections correct
+	FalsePositive int64      // verdicts marking detections false positives
+	Detections    int64      // distinct detections reviewed
+	ActiveClaims  int64      // detections currently claimed
+	LastReviewAt  *time.Time // time of the latest verdict, nil if none
+}
+
+// ReviewProgress summarizes the review queue and reviewer progress
+type ReviewProgress struct {
+	Unreviewed int64 // detections not reviewed or locked
+	Claimed    int64 // unreviewed detections with an active claim
+	Reviewers  []ReviewerProgress
+}
+
+// GetReviewQueue returns a page of unreviewed, unlocked detections not
+// claimed by other reviewers, and the total number of matching detections.
+func (ds *DataStore) GetReviewQueue(query *ReviewQueueQuery) ([]ReviewQueueItem, int64, error) {
+	now := time.Now()
+
+	var total int64
+	if err := reviewQueueBase(ds.DB, query, now).Count(&total).Error; err != nil {
+		return nil, 0, dbError(err, "count_review_queue", errors.PriorityMedium)
+	}
+
+	items, err := reviewQueueItems(ds.DB, query, now)
+	if err != nil {
+		return nil, 0, dbError(err, "get_review_queue", errors.PriorityMedium)
+	}
+	return items, total, nil
+}
+
+// ClaimReviews claims up to query.Limit detections from the review queue for
+// query.Reviewer until ttl has passed, refreshing claims the reviewer already
+// holds. It returns the claimed detections.
+func (ds *DataStore) ClaimReviews(query *ReviewQueueQuery, ttl time.Duration) ([]ReviewQueueItem, error) {
+	if strings.TrimSpace(query.Reviewer) == "" {
+		return nil, validationError("reviewer is required", "reviewer", query.Reviewer)
+	}
+	if ttl <= 0 {
+		return nil, validationError("claim duration must be positive", "ttl", ttl)
+	}
+
+	var claimed []ReviewQueueItem
+	err := ds.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		expiresAt := now.Add(ttl)
+
+		// Expired claims return their detections to the queue
+		if err := tx.Where("expires_at <= ?", now).Delete(&ReviewClaim{}).Error; err != nil {
+			return err
+		}
+
+		items, err := reviewQueueItems(tx, query, now)
+		if err != nil || len(items) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(items))
+		claims := make([]ReviewClaim, len(items))
+		for i := range items {
+			ids[i] = items[i].ID
+			claims[i] = ReviewClaim{NoteID: items[i].ID, Reviewer: query.Reviewer, ClaimedAt: now, ExpiresAt: expiresAt}
+		}
+
+		// Detections claimed concurrently by another reviewer are skipped
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&claims).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&ReviewClaim{}).
+			Where("reviewer = ? AND note_id IN ?", query.Reviewer, ids).
+			Update("expires_at", expiresAt).Error; err != nil {
+			return err
+		}
+
+		var owned []uint
+		if err := tx.Model(&ReviewClaim{}).
+			Where("reviewer = ? AND note_id IN ?", query.Reviewer, ids).
+			Pluck("note_id", &owned).Error; err != nil {
+			return err
+		}
+		ownedSet := make(map[uint]bool, len(owned))
+		for _, id := range owned {
+			ownedSet[id] = true
+		}
+
+		for i := range items {
+			if ownedSet[items[i].ID] {
+				items[i].ClaimedBy = query.Reviewer
+				items[i].ClaimExpiresAt = &expiresAt
+				claimed = append(claimed, items[i])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "claim_reviews", errors.PriorityMedium, "reviewer", query.Reviewer)
+	}
+
+	return claimed, nil
+}
+
+// ReleaseReviewClaims releases claims held by a reviewer, all of them if
+// noteIDs is empty, and returns the number of claims released.
+func (ds *DataStore) ReleaseReviewClaims(reviewer string, noteIDs []uint) (int64, error) {
+	if strings.TrimSpace(reviewer) == "" {
+		return 0, validationError("reviewer is required", "reviewer", reviewer)
+	}
+
+	query := ds.DB.Where("reviewer = ?", reviewer)
+	if len(noteIDs) > 0 {
+		query = query.Where("note_id IN ?", noteIDs)
+	}
+	result := query.Delete(&ReviewClaim{})
+	if result.Error != nil {
+		return 0, dbError(result.Error, "release_review_claims", errors.PriorityMedium, "reviewer", reviewer)
+	}
+	return result.RowsAffected, nil
+}
+
+// ApplyReviewVerdicts saves a batch of verdicts by a reviewer in a single
+// transaction. Verdicts for missing, locked or detections claimed by other
+// reviewers are skipped and reported in the results.
+func (ds *DataStore) ApplyReviewVerdicts(reviewer string, verdicts []ReviewVerdict) ([]ReviewVerdictResult, error) {
+	results := make([]ReviewVerdictResult, len(verdicts))
+	if len(verdicts) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, len(verdicts))
+	for i := range verdicts {
+		ids[i] = verdicts[i].NoteID
+	}
+
+	err := ds.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var existing, locked []uint
+		if err := tx.Model(&Note{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&NoteLock{}).Where("note_id IN ?", ids).Pluck("note_id", &locked).Error; err != nil {
+			return err
+		}
+		var claims []ReviewClaim
+		if err := tx.Where("note_id IN ? AND reviewer <> ? AND expires_at > ?", ids, reviewer, now).
+			Find(&claims).Error; err != nil {
+			return err
+		}
+
+		existingSet := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			existingSet[id] = true
+		}
+		lockedSet := make(map[uint]bool, len(locked))
+		for _, id := range locked {
+			lockedSet[id] = true
+		}
+		claimedBy := make(map[uint]string, len(claims))
+		for i := range claims {
+			claimedBy[claims[i].NoteID] = claims[i].Reviewer
+		}
+
+		for i, verdict := range verdicts {
+			results[i] = ReviewVerdictResult{NoteID: verdict.NoteID}
+			switch {
+			case verdict.Verified != "correct" && verdict.Verified != "false_positive":
+				results[i].Status = ReviewVerdictInvalid
+				continue
+			case !existingSet[verdict.NoteID]:
+				results[i].Status = ReviewVerdictNotFound
+				continue
+			case lockedSet[verdict.NoteID]:
+				results[i].Status = ReviewVerdictLocked
+				continue
+			case claimedBy[verdict.NoteID] != "":
+				results[i].Status = ReviewVerdictClaimed
+				results[i].ClaimedBy = claimedBy[verdict.NoteID]
+				continue
+			}
+
+			review := &NoteReview{
+				NoteID:    verdict.NoteID,
+				Verified:  verdict.Verified,
+				Reviewer:  reviewer,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := saveNoteReview(tx, review); err != nil {
+				return err
+			}
+			if err := tx.Where("note_id = ?", verdict.NoteID).Delete(&ReviewClaim{}).Error; err != nil {
+				return err
+			}
+			results[i].Status = ReviewVerdictApplied
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "apply_review_verdicts", errors.PriorityMedium,
+			"reviewer", reviewer,
+			"verdict_count", fmt.Sprintf("%d", len(verdicts)))
+	}
+
+	return results, nil
+}
+
+// GetNoteReviewHistory returns all verdicts given for a note, oldest first
+func (ds *DataStore) GetNoteReviewHistory(noteID uint) ([]NoteReviewHistory, error) {
+	var history []NoteReviewHistory
+	if err := ds.DB.Where("note_id = ?", noteID).Order("created_at ASC, id ASC").Find(&history).Error; err != nil {
+		return nil, dbError(err, "get_note_review_history", errors.PriorityLow,
+			"note_id", fmt.Sprintf("%d", noteID))
+	}
+	return history, nil
+}
+
+// GetReviewProgress returns the size of the review queue and the progress of
+// each reviewer from verdicts given since the given time, all if zero.
+func (ds *DataStore) GetReviewProgress(since time.Time) (*ReviewProgress, error) {
+	now := time.Now()
+	progress := &ReviewProgress{}
+
+	if err := reviewQueueBase(ds.DB, &ReviewQueueQuery{}, now).Count(&progress.Unreviewed).Error; err != nil {
+		return nil, dbError(err, "count_unreviewed", errors.PriorityLow)
+	}
+	if err := reviewQueueBase(ds.DB, &ReviewQueueQuery{}, now).
+		Where("review_claims.id IS NOT NULL").
+		Count(&progress.Claimed).Error; err != nil {
+		return nil, dbError(err, "count_claimed", errors.PriorityLow)
+	}
+
+	history := ds.DB.Model(&NoteReviewHistory{}).
+		Select("reviewer, COUNT(*) AS verdicts, " +
+			"SUM(CASE WHEN verified = 'correct' THEN 1 ELSE 0 END) AS correct, " +
+			"SUM(CASE WHEN verified = 'false_positive' THEN 1 ELSE 0 END) AS false_positive, " +
+			"COUNT(DISTINCT note_id) AS detections").
+		Group("reviewer").
+		Order("verdicts DESC")
+	if !since.IsZero() {
+		history = history.Where("created_at >= ?", since)
+	}
+	if err := history.Scan(&progress.Reviewers).Error; err != nil {
+		return nil, dbError(err, "get_reviewer_progress", errors.PriorityLow)
+	}
+
+	var claims []struct {
+		Reviewer string
+		Count    int64
+	}
+	if err := ds.DB.Model(&ReviewClaim{}).
+		Select("reviewer, COUNT(*) AS count").
+		Where("expires_at > ?", now).
+		Group("reviewer").
+		Scan(&claims).Error; err != nil {
+		return nil, dbError(err, "count_review_claims", errors.PriorityLow)
+	}
+
+	index := make(map[string]int, len(progress.Reviewers))
+	for i := range progress.Reviewers {
+		index[progress.Reviewers[i].Reviewer] = i
+	}
+	for _, claim := range claims {
+		i, ok := index[claim.Reviewer]
+		if !ok {
+			progress.Reviewers = append(progress.Reviewers, ReviewerProgress{Reviewer: claim.Reviewer})
+			i = len(progress.Reviewers) - 1
+			index[claim.Reviewer] = i
+		}
+		progress.Reviewers[i].ActiveClaims = claim.Count
+	}
+
+	// Latest verdict per reviewer, queried as a row so timestamps scan on all databases
+	for i := range progress.Reviewers {
+		var latest NoteReviewHistory
+		err := ds.DB.Where("reviewer = ?", progress.Reviewers[i].Reviewer).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&latest).Error
+		if err != nil {
+			return nil, dbError(err, "get_last_review", errors.PriorityLow)
+		}
+		if latest.ID != 0 {
+			progress.Reviewers[i].LastReviewAt = &latest.CreatedAt
+		}
+	}
+
+	return progress, nil
+}
+
+// saveNoteReview upserts a review and records the verdict in the review history
+func saveNoteReview(tx *gorm.DB, review *NoteReview) error {
+	if err := tx.Where("note_id = ?", review.NoteID).
+		Assign(*review).
+		FirstOrCreate(review).Error; err != nil {
+		return err
+	}
+
+	createdAt := review.UpdatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	return tx.Create(&NoteReviewHistory{
+		NoteID:    review.NoteID,
+		Verified:  review.Verified,
+		Reviewer:  review.Reviewer,
+		CreatedAt: createdAt,
+	}).Error
+}
+
+// reviewQueueBase builds the query for unreviewed, unlocked detections not
+// claimed by reviewers other than query.Reviewer
+func reviewQueueBase(db *gorm.DB, query *ReviewQueueQuery, now time.Time) *gorm.DB {
+	q := db.Table("notes").
+		Joins("LEFT JOIN note_reviews ON note_reviews.note_id = notes.id").
+		Joins("LEFT JOIN note_locks ON note_locks.note_id = notes.id").
+		Joins("LEFT JOIN review_claims ON review_claims.note_id = notes.id AND review_claims.expires_at > ?", now).
+		Where("(note_reviews.id IS NULL OR note_reviews.verified = '')").
+		Where("note_locks.id IS NULL")
+
+	switch {
+	case query.ClaimedOnly:
+		q = q.Where("review_claims.reviewer = ?", query.Reviewer)
+	case query.Reviewer != "":
+		q = q.Where("(review_claims.id IS NULL OR review_claims.reviewer = ?)", query.Reviewer)
+	}
+
+	if query.HeldOnly {
+		q = q.Where("notes.held_for_review = ?", true)
+	}
+	if query.Species != "" {
+		q = q.Where("(LOWER(notes.common_name) = LOWER(?) OR LOWER(notes.scientific_name) = LOWER(?))", query.Species, query.Species)
+	}
+	if query.MinConfidence > 0 {
+		q = q.Where("notes.confidence >= ?", query.MinConfidence)
+	}
+	if query.MaxConfidence > 0 {
+		q = q.Where("notes.confidence <= ?", query.MaxConfidence)
+	}
+	if query.StartDate != "" {
+		q = q.Where("notes.date >= ?", query.StartDate)
+	}
+	if query.EndDate != "" {
+		q = q.Where("notes.date <= ?", query.EndDate)
+	}
+	return q
+}
+
+// reviewQueueItems returns a page of the review queue in the requested order
+func reviewQueueItems(db *gorm.DB, query *ReviewQueueQuery, now time.Time) ([]ReviewQueueItem, error) {
+	speciesCounts := db.Table("notes").
+		Select("scientific_name, COUNT(*) AS species_count").
+		Group("scientific_name")
+
+	q := reviewQueueBase(db, query, now).
+		Select("notes.id, notes.date, notes.time, notes.source, notes.scientific_name, notes.common_name, "+
+			"notes.confidence, notes.clip_name, notes.held_for_review, species_counts.species_count, "+
+			"review_claims.reviewer AS claimed_by, review_claims.expires_at AS claim_expires_at").
+		Joins("JOIN (?) AS species_counts ON species_counts.scientific_name = notes.scientific_name", speciesCounts)
+
+	switch query.Sort {
+	case ReviewSortConfidence:
+		q = q.Order("notes.confidence ASC, species_counts.species_count ASC, notes.id DESC")
+	case ReviewSortNewest:
+		q = q.Order("notes.id DESC")
+	default:
+		q = q.Order("species_counts.species_count ASC, notes.confidence ASC, notes.id DESC")
+	}
+
+	if query.Limit > 0 {
+		q = q.Limit(query.Limit)
+	}
+	if query.Offset > 0 {
+		q = q.Offset(query.Offset)
+	}
+
+	var items []ReviewQueueItem
+	if err := q.Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
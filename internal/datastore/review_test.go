@@ -0,0 +1,181 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupReviewTestDB creates a test database with notes of a common and a rare species
+func setupReviewTestDB(t *testing.T) *DataStore {
+	t.Helper()
+
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}, &NoteReviewHistory{}, &ReviewClaim{}, &NoteLock{}))
+
+	notes := []Note{
+		{ID: 1, Date: "2024-05-01", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.9},
+		{ID: 2, Date: "2024-05-01", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.6, HeldForReview: true},
+		{ID: 3, Date: "2024-05-02", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.7},
+		{ID: 4, Date: "2024-05-02", ScientificName: "Bubo bubo", CommonName: "Eurasian Eagle-Owl", Confidence: 0.8},
+		{ID: 5, Date: "2024-05-03", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.5},
+	}
+	require.NoError(t, ds.DB.Create(&notes).Error)
+
+	// Note 5 is already reviewed and note 3 is locked
+	require.NoError(t, ds.SaveNoteReview(&NoteReview{NoteID: 5, Verified: "correct"}))
+	require.NoError(t, ds.DB.Create(&NoteLock{NoteID: 3, LockedAt: time.Now()}).Error)
+
+	return ds
+}
+
+func queueIDs(items []ReviewQueueItem) []uint {
+	ids := make([]uint, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	return ids
+}
+
+func TestGetReviewQueue(t *testing.T) {
+	t.Parallel()
+	ds := setupReviewTestDB(t)
+
+	items, total, err := ds.GetReviewQueue(&ReviewQueueQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []uint{4, 2, 1}, queueIDs(items), "rarest species first, then lowest confidence")
+	assert.Equal(t, int64(1), items[0].SpeciesCount)
+	assert.Equal(t, int64(4), items[1].SpeciesCount)
+
+	items, _, err = ds.GetReviewQueue(&ReviewQueueQuery{Sort: ReviewSortConfidence})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{2, 4, 1}, queueIDs(items))
+
+	items, total, err = ds.GetReviewQueue(&ReviewQueueQuery{Species: "great tit", MaxConfidence: 0.8, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []uint{2}, queueIDs(items))
+
+	// Detections held for review by the weather filter
+	items, total, err = ds.GetReviewQueue(&ReviewQueueQuery{HeldOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Equal(t, []uint{2}, queueIDs(items))
+	assert.True(t, items[0].HeldForReview)
+}
+
+func TestClaimReviews(t *testing.T) {
+	t.Parallel()
+	ds := setupReviewTestDB(t)
+
+	claimed, err := ds.ClaimReviews(&ReviewQueueQuery{Reviewer: "alice", Limit: 2}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{4, 2}, queueIDs(claimed))
+	assert.Equal(t, "alice", claimed[0].ClaimedBy)
+	require.NotNil(t, claimed[0].ClaimExpiresAt)
+
+	// Claimed detections are hidden from other reviewers
+	claimed, err = ds.ClaimReviews(&ReviewQueueQuery{Reviewer: "bob", Limit: 5}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1}, queueIDs(claimed))
+
+	items, _, err := ds.GetReviewQueue(&ReviewQueueQuery{Reviewer: "alice", ClaimedOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{4, 2}, queueIDs(items))
+
+	released, err := ds.ReleaseReviewClaims("alice", []uint{4})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), released)
+
+	items, _, err = ds.GetReviewQueue(&ReviewQueueQuery{Reviewer: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{4, 1}, queueIDs(items))
+
+	_, err = ds.ClaimReviews(&ReviewQueueQuery{}, time.Hour)
+	require.Error(t, err, "reviewer is required")
+}
+
+func TestDeleteRemovesReviewHistoryAndClaims(t *testing.T) {
+	t.Parallel()
+	ds := setupReviewTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&Results{}))
+
+	_, err := ds.ClaimReviews(&ReviewQueueQuery{Reviewer: "alice", Limit: 2}, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, ds.SaveNoteReview(&NoteReview{NoteID: 4, Verified: "correct", Reviewer: "alice"}))
+
+	require.NoError(t, ds.Delete("4"))
+
+	var count int64
+	require.NoError(t, ds.DB.Model(&NoteReviewHistory{}).Where("note_id = ?", 4).Count(&count).Error)
+	assert.Zero(t, count, "review history of the deleted note")
+	require.NoError(t, ds.DB.Model(&ReviewClaim{}).Where("note_id = ?", 4).Count(&count).Error)
+	assert.Zero(t, count, "claims of the deleted note")
+
+	// Claims of other notes are kept
+	require.NoError(t, ds.DB.Model(&ReviewClaim{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestApplyReviewVerdicts(t *testing.T) {
+	t.Parallel()
+	ds := setupReviewTestDB(t)
+
+	_, err := ds.ClaimReviews(&ReviewQueueQuery{Reviewer: "bob", Species: "Bubo bubo"}, time.Hour)
+	require.NoError(t, err)
+
+	results, err := ds.ApplyReviewVerdicts("alice", []ReviewVerdict{
+		{NoteID: 1, Verified: "correct"},
+		{NoteID: 2, Verified: "false_positive"},
+		{NoteID: 3, Verified: "correct"},
+		{NoteID: 4, Verified: "correct"},
+		{NoteID: 99, Verified: "correct"},
+		{NoteID: 1, Verified: "maybe"},
+	})
+	require.NoError(t, err)
+
+	statuses := make([]string, len(results))
+	for i := range results {
+		statuses[i] = results[i].Status
+	}
+	assert.Equal(t, []string{
+		ReviewVerdictApplied, ReviewVerdictApplied, ReviewVerdictLocked,
+		ReviewVerdictClaimed, ReviewVerdictNotFound, ReviewVerdictInvalid,
+	}, statuses)
+	assert.Equal(t, "bob", results[3].ClaimedBy)
+
+	review, err := ds.GetNoteReview("2")
+	require.NoError(t, err)
+	assert.Equal(t, "false_positive", review.Verified)
+	assert.Equal(t, "alice", review.Reviewer)
+
+	// Changing a verdict keeps the earlier one in the history
+	_, err = ds.ApplyReviewVerdicts("bob", []ReviewVerdict{{NoteID: 2, Verified: "correct"}})
+	require.NoError(t, err)
+	history, err := ds.GetNoteReviewHistory(2)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "alice", history[0].Reviewer)
+	assert.Equal(t, "bob", history[1].Reviewer)
+	assert.Equal(t, "correct", history[1].Verified)
+
+	progress, err := ds.GetReviewProgress(time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), progress.Unreviewed, "only the claimed owl remains")
+	assert.Equal(t, int64(1), progress.Claimed)
+
+	byReviewer := make(map[string]ReviewerProgress)
+	for _, p := range progress.Reviewers {
+		byReviewer[p.Reviewer] = p
+	}
+	assert.Equal(t, int64(2), byReviewer["alice"].Verdicts)
+	assert.Equal(t, int64(1), byReviewer["alice"].Correct)
+	assert.Equal(t, int64(1), byReviewer["alice"].FalsePositive)
+	assert.NotNil(t, byReviewer["alice"].LastReviewAt)
+	assert.Equal(t, int64(1), byReviewer["bob"].Verdicts)
+	assert.Equal(t, int64(1), byReviewer["bob"].ActiveClaims)
+	assert.Equal(t, int64(1), byReviewer[""].Verdicts, "unattributed review from setup")
+}
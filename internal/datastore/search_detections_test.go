@@ -0,0 +1,59 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDetectionsNoiseContext(t *testing.T) {
+	t.Parallel()
+
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}, &NoteLock{}))
+
+	quiet, loud := -52.5, -21.25
+	notes := []Note{
+		{ID: 1, Date: "2024-05-01", Time: "06:00:00", CommonName: "Great Tit", Confidence: 0.9,
+			NoiseLevel: &quiet, NoiseBands: map[string]float64{"125_Hz": -55, "1.0_kHz": -56}},
+		{ID: 2, Date: "2024-05-01", Time: "07:00:00", CommonName: "Great Tit", Confidence: 0.9,
+			NoiseLevel: &loud, NoiseBands: map[string]float64{"125_Hz": -22}},
+		{ID: 3, Date: "2024-05-01", Time: "08:00:00", CommonName: "Great Tit", Confidence: 0.9},
+	}
+	require.NoError(t, ds.DB.Create(&notes).Error)
+
+	// Noise context is stored with the note
+	var stored Note
+	require.NoError(t, ds.DB.First(&stored, 1).Error)
+	require.NotNil(t, stored.NoiseLevel)
+	assert.InDelta(t, quiet, *stored.NoiseLevel, 1e-9)
+	assert.Equal(t, notes[0].NoiseBands, stored.NoiseBands)
+
+	all, total, err := ds.SearchDetections(&SearchFilters{Ctx: context.Background(), SortBy: "date_asc"})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.NotNil(t, all[0].NoiseLevel)
+	assert.InDelta(t, quiet, *all[0].NoiseLevel, 1e-9)
+	assert.Equal(t, notes[0].NoiseBands, all[0].NoiseBands)
+	assert.Nil(t, all[2].NoiseLevel)
+	assert.Nil(t, all[2].NoiseBands)
+
+	// Noise filters exclude detections without a measured noise level
+	maxNoise := -40.0
+	results, total, err := ds.SearchDetections(&SearchFilters{Ctx: context.Background(), NoiseMax: &maxNoise})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "1", results[0].ID)
+
+	results, total, err = ds.SearchDetections(&SearchFilters{Ctx: context.Background(), NoiseMin: &maxNoise})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "2", results[0].ID)
+
+	_, _, err = ds.SearchDetections(&SearchFilters{Ctx: context.Background(), NoiseMin: &quiet, NoiseMax: &maxNoise})
+	require.NoError(t, err)
+	_, _, err = ds.SearchDetections(&SearchFilters{Ctx: context.Background(), NoiseMin: &maxNoise, NoiseMax: &quiet})
+	assert.Error(t, err)
+}
@@ -0,0 +1,201 @@
+// soundlevel.go contains storage and aggregation of sound level history
+package datastore
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// soundLevelBatchSize limits the number of rows inserted per statement
+const soundLevelBatchSize = 500
+
+// SoundLevelQuery selects stored sound levels
+type SoundLevelQuery struct {
+	Source string    // audio source identifier, empty for all sources
+	Bands  []string  // octave band keys, empty for all bands
+	Start  time.Time // first period start to include, zero for no limit
+	End    time.Time // periods starting at or after End are excluded, zero for no limit
+	Limit  int       // maximum number of rows, 0 for no limit
+}
+
+// SoundLevelSource describes an audio source with stored sound levels
+type SoundLevelSource struct {
+	Source string
+	Name   string
+	First  time.Time // start of the oldest stored period
+	Last   time.Time // start of the newest stored period
+}
+
+// SaveSoundLevels stores aggregated sound levels
+func (ds *DataStore) SaveSoundLevels(levels []SoundLevel) error {
+	if len(levels) == 0 {
+		return nil
+	}
+	if err := ds.DB.CreateInBatches(levels, soundLevelBatchSize).Error; err != nil {
+		return dbError(err, "save_sound_levels", errors.PriorityLow, "count", len(levels))
+	}
+	return nil
+}
+
+// GetSoundLevels returns stored sound levels ordered by period start and band
+func (ds *DataStore) GetSoundLevels(query *SoundLevelQuery) ([]SoundLevel, error) {
+	if query.Limit < 0 {
+		return nil, validationError("limit must not be negative", "limit", query.Limit)
+	}
+	if !query.Start.IsZero() && !query.End.IsZero() && !query.End.After(query.Start) {
+		return nil, validationError("end must be after start", "end", query.End)
+	}
+
+	db := ds.DB.Model(&SoundLevel{})
+	if query.Source != "" {
+		db = db.Where("source = ?", query.Source)
+	}
+	if len(query.Bands) > 0 {
+		db = db.Where("band IN ?", query.Bands)
+	}
+	if !query.Start.IsZero() {
+		db = db.Where("timestamp >= ?", query.Start.UTC())
+	}
+	if !query.End.IsZero() {
+		db = db.Where("timestamp < ?", query.End.UTC())
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var levels []SoundLevel
+	if err := db.Order("timestamp ASC, source ASC, center_freq ASC").Find(&levels).Error; err != nil {
+		return nil, dbError(err, "get_sound_levels", errors.PriorityLow, "source", query.Source)
+	}
+	return levels, nil
+}
+
+// GetSoundLevelSources returns the audio sources with stored sound levels and
+// the range of their stored history
+func (ds *DataStore) GetSoundLevelSources() ([]SoundLevelSource, error) {
+	var sources []SoundLevelSource
+	if err := ds.DB.Model(&SoundLevel{}).
+		Distinct("source", "name").
+		Order("source ASC").
+		Scan(&sources).Error; err != nil {
+		return nil, dbError(err, "get_sound_level_sources", errors.PriorityLow)
+	}
+
+	// Sources renamed over time are reported once with their latest name
+	result := make([]SoundLevelSource, 0, len(sources))
+	seen := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		if seen[source.Source] {
+			continue
+		}
+		seen[source.Source] = true
+
+		var first, last SoundLevel
+		if err := ds.DB.Where("source = ?", source.Source).Order("timestamp ASC").First(&first).Error; err != nil {
+			return nil, dbError(err, "get_sound_level_sources", errors.PriorityLow, "source", source.Source)
+		}
+		if err := ds.DB.Where("source = ?", source.Source).Order("timestamp DESC").First(&last).Error; err != nil {
+			return nil, dbError(err, "get_sound_level_sources", errors.PriorityLow, "source", source.Source)
+		}
+		result = append(result, SoundLevelSource{
+			Source: source.Source,
+			Name:   last.Name,
+			First:  first.Timestamp,
+			Last:   last.Timestamp,
+		})
+	}
+	return result, nil
+}
+
+// DeleteSoundLevelsBefore deletes sound levels of periods starting before the
+// given time and returns the number of rows deleted
+func (ds *DataStore) DeleteSoundLevelsBefore(before time.Time) (int64, error) {
+	result := ds.DB.Where("timestamp < ?", before.UTC()).Delete(&SoundLevel{})
+	if result.Error != nil {
+		return 0, dbError(result.Error, "delete_sound_levels", errors.PriorityLow,
+			"before", before.UTC().Format(time.RFC3339))
+	}
+	return result.RowsAffected, nil
+}
+
+// AggregateSoundLevels combines sound levels into periods of the given length
+// aligned to the Unix epoch, per source and band. Leq is averaged on the energy
+// scale weighted by duration, Lmax and Lmin are the extremes of the period and
+// Duration is the total duration of the combined levels, so that partial periods
+// stored across restarts combine correctly. The result is ordered like GetSoundLevels.
+func AggregateSoundLevels(levels []SoundLevel, period time.Duration) []SoundLevel {
+	seconds := int64(period / time.Second)
+	if seconds <= 0 {
+		return levels
+	}
+
+	type key struct {
+		start        int64
+		source, band string
+	}
+	type accumulator struct {
+		level  SoundLevel
+		energy float64 // duration weighted sum of 10^(Leq/10)
+		weight float64 // total duration of the aggregated levels
+	}
+
+	groups := make(map[key]*accumulator)
+	for i := range levels {
+		l := &levels[i]
+		start := l.Timestamp.Unix() - floorMod(l.Timestamp.Unix(), seconds)
+		k := key{start: start, source: l.Source, band: l.Band}
+
+		weight := float64(l.Duration)
+		if weight <= 0 {
+			weight = 1
+		}
+
+		acc, ok := groups[k]
+		if !ok {
+			acc = &accumulator{level: SoundLevel{
+				Timestamp:  time.Unix(start, 0).UTC(),
+				Source:     l.Source,
+				Name:       l.Name,
+				Band:       l.Band,
+				CenterFreq: l.CenterFreq,
+				Lmax:       l.Lmax,
+				Lmin:       l.Lmin,
+			}}
+			groups[k] = acc
+		}
+		acc.energy += weight * math.Pow(10, l.Leq/10)
+		acc.weight += weight
+		acc.level.Lmax = math.Max(acc.level.Lmax, l.Lmax)
+		acc.level.Lmin = math.Min(acc.level.Lmin, l.Lmin)
+		acc.level.Duration += int(weight)
+		acc.level.Samples += l.Samples
+		if l.Name != "" {
+			acc.level.Name = l.Name
+		}
+	}
+
+	result := make([]SoundLevel, 0, len(groups))
+	for _, acc := range groups {
+		acc.level.Leq = 10 * math.Log10(acc.energy/acc.weight)
+		result = append(result, acc.level)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := &result[i], &result[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.CenterFreq < b.CenterFreq
+	})
+	return result
+}
+
+// floorMod returns the non-negative remainder of a divided by b
+func floorMod(a, b int64) int64 {
+	return ((a % b) + b) % b
+}
@@ -0,0 +1,128 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSoundLevelTestDB creates a test database with an hour of one minute
+// levels for two bands of one source and a single level of a second source
+func setupSoundLevelTestDB(t *testing.T, start time.Time) *DataStore {
+	t.Helper()
+
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&SoundLevel{}))
+
+	var levels []SoundLevel
+	for i := range 60 {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		levels = append(levels,
+			SoundLevel{Timestamp: ts, Source: "mic", Name: "Garden", Band: "1.0_kHz", CenterFreq: 1000, Duration: 60, Leq: 40, Lmax: 50, Lmin: 30, Samples: 60},
+			SoundLevel{Timestamp: ts, Source: "mic", Name: "Garden", Band: "125_Hz", CenterFreq: 125, Duration: 60, Leq: 55, Lmax: 60, Lmin: 45, Samples: 60},
+		)
+	}
+	levels = append(levels, SoundLevel{Timestamp: start, Source: "rtsp", Name: "Pond", Band: "1.0_kHz", CenterFreq: 1000, Duration: 60, Leq: 35, Lmax: 40, Lmin: 30, Samples: 60})
+	require.NoError(t, ds.SaveSoundLevels(levels))
+
+	return ds
+}
+
+func TestGetSoundLevels(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ds := setupSoundLevelTestDB(t, start)
+
+	all, err := ds.GetSoundLevels(&SoundLevelQuery{})
+	require.NoError(t, err)
+	assert.Len(t, all, 121)
+	// Bands of a period are ordered by frequency
+	assert.Equal(t, "125_Hz", all[0].Band)
+
+	levels, err := ds.GetSoundLevels(&SoundLevelQuery{
+		Source: "mic",
+		Bands:  []string{"1.0_kHz"},
+		Start:  start.Add(10 * time.Minute),
+		End:    start.Add(20 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, levels, 10)
+	assert.True(t, levels[0].Timestamp.Equal(start.Add(10*time.Minute)))
+	for i := range levels {
+		assert.Equal(t, "mic", levels[i].Source)
+		assert.Equal(t, "1.0_kHz", levels[i].Band)
+	}
+
+	limited, err := ds.GetSoundLevels(&SoundLevelQuery{Source: "mic", Limit: 5})
+	require.NoError(t, err)
+	assert.Len(t, limited, 5)
+
+	_, err = ds.GetSoundLevels(&SoundLevelQuery{Start: start, End: start})
+	require.Error(t, err)
+	_, err = ds.GetSoundLevels(&SoundLevelQuery{Limit: -1})
+	require.Error(t, err)
+}
+
+func TestGetSoundLevelSources(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ds := setupSoundLevelTestDB(t, start)
+
+	sources, err := ds.GetSoundLevelSources()
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "mic", sources[0].Source)
+	assert.Equal(t, "Garden", sources[0].Name)
+	assert.True(t, sources[0].First.Equal(start))
+	assert.True(t, sources[0].Last.Equal(start.Add(59*time.Minute)))
+	assert.Equal(t, "rtsp", sources[1].Source)
+}
+
+func TestDeleteSoundLevelsBefore(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ds := setupSoundLevelTestDB(t, start)
+
+	deleted, err := ds.DeleteSoundLevelsBefore(start.Add(30 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(61), deleted)
+
+	remaining, err := ds.GetSoundLevels(&SoundLevelQuery{})
+	require.NoError(t, err)
+	assert.Len(t, remaining, 60)
+	assert.True(t, remaining[0].Timestamp.Equal(start.Add(30*time.Minute)))
+}
+
+func TestAggregateSoundLevels(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	levels := []SoundLevel{
+		{Timestamp: start, Source: "mic", Band: "1.0_kHz", CenterFreq: 1000, Duration: 30, Leq: 40, Lmax: 45, Lmin: 35, Samples: 30},
+		{Timestamp: start.Add(30 * time.Second), Source: "mic", Band: "1.0_kHz", CenterFreq: 1000, Duration: 30, Leq: 50, Lmax: 60, Lmin: 38, Samples: 30},
+		{Timestamp: start.Add(time.Minute), Source: "mic", Band: "1.0_kHz", CenterFreq: 1000, Duration: 30, Leq: 42, Lmax: 44, Lmin: 40, Samples: 30},
+		{Timestamp: start.Add(10 * time.Second), Source: "mic", Band: "125_Hz", CenterFreq: 125, Duration: 30, Leq: 30, Lmax: 31, Lmin: 29, Samples: 30},
+	}
+
+	result := AggregateSoundLevels(levels, time.Minute)
+	require.Len(t, result, 3)
+
+	assert.Equal(t, "125_Hz", result[0].Band)
+	assert.True(t, result[0].Timestamp.Equal(start))
+
+	first := result[1]
+	assert.Equal(t, "1.0_kHz", first.Band)
+	assert.True(t, first.Timestamp.Equal(start))
+	assert.Equal(t, 60, first.Duration)
+	assert.Equal(t, 60, first.Samples)
+	// Energy average of 40 dB and 50 dB is dominated by the louder half
+	assert.InDelta(t, 47.4, first.Leq, 0.05)
+	assert.InDelta(t, 60, first.Lmax, 0)
+	assert.InDelta(t, 35, first.Lmin, 0)
+
+	assert.True(t, result[2].Timestamp.Equal(start.Add(time.Minute)))
+	assert.InDelta(t, 42, result[2].Leq, 1e-9)
+
+	assert.Equal(t, levels, AggregateSoundLevels(levels, 0))
+}
@@ -0,0 +1,398 @@
+// Package dwca writes detections as Darwin Core Archives for publishing
+// occurrence datasets to GBIF
+package dwca
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Files of the archive
+const (
+	occurrenceFile = "occurrence.txt"
+	multimediaFile = "multimedia.txt"
+	metaFile       = "meta.xml"
+	emlFile        = "eml.xml"
+)
+
+// defaultLicense is used when the settings do not name a license
+const defaultLicense = "CC-BY-4.0"
+
+// Metadata describes the dataset of an archive
+type Metadata struct {
+	Title              string
+	Description        string
+	Publisher          string
+	ContactName        string
+	ContactEmail       string
+	License            string // license identifier, one of conf.DarwinCoreLicenses
+	BaseURL            string // public URL of the node, audio clips are referenced by path if empty
+	OccurrenceIDPrefix string
+	PubDate            time.Time
+}
+
+// MetadataFromSettings returns the dataset metadata configured in the settings
+func MetadataFromSettings(settings *conf.Settings) Metadata {
+	dc := &settings.Output.DarwinCore
+	meta := Metadata{
+		Title:              dc.Title,
+		Description:        dc.Description,
+		Publisher:          dc.Publisher,
+		ContactName:        dc.ContactName,
+		ContactEmail:       dc.ContactEmail,
+		License:            dc.License,
+		BaseURL:            strings.TrimRight(dc.BaseURL, "/"),
+		OccurrenceIDPrefix: dc.OccurrenceIDPrefix,
+		PubDate:            time.Now(),
+	}
+	if meta.OccurrenceIDPrefix == "" {
+		node := settings.Main.Name
+		if node == "" {
+			node = "BirdNET-Go"
+		}
+		meta.OccurrenceIDPrefix = "urn:birdnet-go:" + strings.ReplaceAll(node, " ", "-") + ":"
+	}
+	return meta
+}
+
+// licenseURL returns the legal code URL of the metadata license
+func (m *Metadata) licenseURL() string {
+	if url, ok := conf.DarwinCoreLicenses[m.License]; ok {
+		return url
+	}
+	return conf.DarwinCoreLicenses[defaultLicense]
+}
+
+// Occurrence core columns, the first column is the record id
+var occurrenceTerms = []string{
+	dwcTerm("occurrenceID"),
+	dwcTerm("basisOfRecord"),
+	dwcTerm("eventDate"),
+	dwcTerm("year"),
+	dwcTerm("month"),
+	dwcTerm("day"),
+	dwcTerm("scientificName"),
+	dwcTerm("vernacularName"),
+	dwcTerm("kingdom"),
+	dwcTerm("decimalLatitude"),
+	dwcTerm("decimalLongitude"),
+	dwcTerm("geodeticDatum"),
+	dwcTerm("occurrenceStatus"),
+	dwcTerm("identifiedBy"),
+	dwcTerm("identificationVerificationStatus"),
+	dwcTerm("identificationRemarks"),
+	dwcTerm("samplingProtocol"),
+	dcTerm("license"),
+}
+
+// Multimedia extension columns, the first column is the occurrence id
+var multimediaTerms = []string{
+	"coreid",
+	dcTerm("type"),
+	dcTerm("format"),
+	dcTerm("identifier"),
+	dcTerm("title"),
+	dcTerm("created"),
+	dcTerm("license"),
+	dcTerm("publisher"),
+}
+
+func dwcTerm(name string) string { return "http://rs.tdwg.org/dwc/terms/" + name }
+func dcTerm(name string) string  { return "http://purl.org/dc/terms/" + name }
+
+// ArchiveWriter writes a Darwin Core Archive to a zip stream. Occurrences are
+// written to the archive as they are added, multimedia records are kept in a
+// temporary file until the archive is closed.
+type ArchiveWriter struct {
+	zip        *zip.Writer
+	occurrence io.Writer
+	media      *os.File
+	mediaBuf   *bufio.Writer
+	meta       Metadata
+	count      int
+	coverage   coverage
+}
+
+// coverage tracks the temporal and geographic extent of the added occurrences
+type coverage struct {
+	first, last              time.Time
+	south, north, west, east float64
+	located                  bool
+}
+
+// NewArchiveWriter starts an archive on w
+func NewArchiveWriter(w io.Writer, meta Metadata) (*ArchiveWriter, error) {
+	media, err := os.CreateTemp("", "dwca-multimedia-*.txt")
+	if err != nil {
+		return nil, archiveError(err, "create_multimedia_file")
+	}
+
+	a := &ArchiveWriter{
+		zip:      zip.NewWriter(w),
+		media:    media,
+		mediaBuf: bufio.NewWriter(media),
+		meta:     meta,
+	}
+	if a.occurrence, err = a.zip.Create(occurrenceFile); err != nil {
+		a.Abort()
+		return nil, archiveError(err, "create_occurrence_file")
+	}
+	if err := writeRow(a.occurrence, headerRow(occurrenceTerms)); err != nil {
+		a.Abort()
+		return nil, archiveError(err, "write_occurrence_header")
+	}
+	if err := writeRow(a.mediaBuf, headerRow(multimediaTerms)); err != nil {
+		a.Abort()
+		return nil, archiveError(err, "write_multimedia_header")
+	}
+	return a, nil
+}
+
+// Add writes a detection as an occurrence record. Detections reviewed as false
+// positives and labels which are not taxa, such as noise or human voice, are
+// skipped. It returns true if the detection was written.
+func (a *ArchiveWriter) Add(note *datastore.Note) (bool, error) {
+	if note.Verified == "false_positive" || !isTaxonName(note) {
+		return false, nil
+	}
+
+	begin, end := note.DetectionTimes()
+	begin, end = begin.Truncate(time.Second), end.Truncate(time.Second)
+	id := a.meta.OccurrenceIDPrefix + strconv.FormatUint(uint64(note.ID), 10)
+	eventDate := begin.Format(time.RFC3339) + "/" + end.Format(time.RFC3339)
+
+	verification := "unverified"
+	if note.Verified == "correct" {
+		verification = "verified"
+	}
+
+	var lat, lon, datum string
+	if note.Latitude != 0 || note.Longitude != 0 {
+		lat = strconv.FormatFloat(note.Latitude, 'f', -1, 64)
+		lon = strconv.FormatFloat(note.Longitude, 'f', -1, 64)
+		datum = "WGS84"
+		a.coverage.addLocation(note.Latitude, note.Longitude)
+	}
+	a.coverage.addTime(begin)
+
+	row := []string{
+		id,
+		"MachineObservation",
+		eventDate,
+		strconv.Itoa(begin.Year()),
+		strconv.Itoa(int(begin.Month())),
+		strconv.Itoa(begin.Day()),
+		note.ScientificName,
+		note.CommonName,
+		"Animalia",
+		lat,
+		lon,
+		datum,
+		"present",
+		"BirdNET",
+		verification,
+		fmt.Sprintf("Automated identification by BirdNET-Go, confidence %.4f", note.Confidence),
+		"Passive acoustic monitoring",
+		a.meta.licenseURL(),
+	}
+	if err := writeRow(a.occurrence, row); err != nil {
+		return false, archiveError(err, "write_occurrence")
+	}
+	a.count++
+
+	if note.ClipName != "" {
+		media := []string{
+			id,
+			"Sound",
+			clipFormat(note.ClipName),
+			a.clipIdentifier(note),
+			fmt.Sprintf("%s (%s)", note.CommonName, note.ScientificName),
+			begin.Format(time.RFC3339),
+			a.meta.licenseURL(),
+			a.meta.Publisher,
+		}
+		if err := writeRow(a.mediaBuf, media); err != nil {
+			return false, archiveError(err, "write_multimedia")
+		}
+	}
+	return true, nil
+}
+
+// Count returns the number of occurrences written
+func (a *ArchiveWriter) Count() int {
+	return a.count
+}
+
+// Close writes the multimedia records and the metadata files and finishes the archive
+func (a *ArchiveWriter) Close() error {
+	defer a.Abort()
+
+	if err := a.mediaBuf.Flush(); err != nil {
+		return archiveError(err, "flush_multimedia")
+	}
+	if _, err := a.media.Seek(0, io.SeekStart); err != nil {
+		return archiveError(err, "read_multimedia")
+	}
+	w, err := a.zip.Create(multimediaFile)
+	if err != nil {
+		return archiveError(err, "create_multimedia_entry")
+	}
+	if _, err := io.Copy(w, a.media); err != nil {
+		return archiveError(err, "write_multimedia_entry")
+	}
+
+	if w, err = a.zip.Create(metaFile); err != nil {
+		return archiveError(err, "create_meta_entry")
+	}
+	if err := writeMetaXML(w); err != nil {
+		return archiveError(err, "write_meta")
+	}
+
+	if w, err = a.zip.Create(emlFile); err != nil {
+		return archiveError(err, "create_eml_entry")
+	}
+	if err := writeEML(w, &a.meta, &a.coverage); err != nil {
+		return archiveError(err, "write_eml")
+	}
+
+	if err := a.zip.Close(); err != nil {
+		return archiveError(err, "close_archive")
+	}
+	return nil
+}
+
+// Flush writes buffered occurrence data to the underlying writer
+func (a *ArchiveWriter) Flush() error {
+	if err := a.zip.Flush(); err != nil {
+		return archiveError(err, "flush_archive")
+	}
+	return nil
+}
+
+// Abort removes the temporary files of an archive which is not closed
+func (a *ArchiveWriter) Abort() {
+	if a.media == nil {
+		return
+	}
+	name := a.media.Name()
+	_ = a.media.Close()
+	_ = os.Remove(name)
+	a.media = nil
+}
+
+// clipIdentifier returns the URL of the audio clip of a detection, or its
+// path if no public URL is configured
+func (a *ArchiveWriter) clipIdentifier(note *datastore.Note) string {
+	if a.meta.BaseURL == "" {
+		return note.ClipName
+	}
+	return fmt.Sprintf("%s/api/v2/audio/%d", a.meta.BaseURL, note.ID)
+}
+
+// Export writes the detections matching the filters to w as a Darwin Core
+// Archive and returns the number of occurrences written
+func Export(ds datastore.Interface, filters *datastore.SearchFilters, w io.Writer, meta Metadata) (int, error) {
+	archive, err := NewArchiveWriter(w, meta)
+	if err != nil {
+		return 0, err
+	}
+	err = ds.ExportDetections(filters, func(note *datastore.Note) error {
+		_, err := archive.Add(note)
+		return err
+	})
+	if err != nil {
+		archive.Abort()
+		return archive.Count(), err
+	}
+	if err := archive.Close(); err != nil {
+		return archive.Count(), err
+	}
+	return archive.Count(), nil
+}
+
+// isTaxonName reports whether a detection label names a taxon. Non-species
+// labels such as "Engine" or "Human vocal" use the common name as scientific name.
+func isTaxonName(note *datastore.Note) bool {
+	name := strings.TrimSpace(note.ScientificName)
+	return strings.Contains(name, " ") && !strings.EqualFold(name, strings.TrimSpace(note.CommonName))
+}
+
+// clipFormat returns the MIME type of an audio clip
+func clipFormat(clipName string) string {
+	switch strings.ToLower(filepath.Ext(clipName)) {
+	case ".wav":
+		return "audio/wav"
+	case ".flac":
+		return "audio/flac"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".aac", ".m4a":
+		return "audio/aac"
+	case ".opus", ".ogg":
+		return "audio/ogg"
+	default:
+		return ""
+	}
+}
+
+// headerRow returns the column names of a data file
+func headerRow(terms []string) []string {
+	header := make([]string, len(terms))
+	for i, term := range terms {
+		header[i] = term[strings.LastIndex(term, "/")+1:]
+	}
+	header[0] = "id"
+	return header
+}
+
+// writeRow writes a tab separated row. Tabs and line breaks within values are
+// replaced by spaces since the files have no quoting.
+func writeRow(w io.Writer, values []string) error {
+	cleaner := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	for i, value := range values {
+		values[i] = cleaner.Replace(value)
+	}
+	_, err := io.WriteString(w, strings.Join(values, "\t")+"\n")
+	return err
+}
+
+// addTime extends the temporal coverage
+func (c *coverage) addTime(t time.Time) {
+	if c.first.IsZero() || t.Before(c.first) {
+		c.first = t
+	}
+	if t.After(c.last) {
+		c.last = t
+	}
+}
+
+// addLocation extends the geographic coverage
+func (c *coverage) addLocation(lat, lon float64) {
+	if !c.located {
+		c.south, c.north, c.west, c.east = lat, lat, lon, lon
+		c.located = true
+		return
+	}
+	c.south, c.north = min(c.south, lat), max(c.north, lat)
+	c.west, c.east = min(c.west, lon), max(c.east, lon)
+}
+
+// archiveError wraps an error of writing an archive
+func archiveError(err error, operation string) error {
+	return errors.New(err).
+		Component("dwca").
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Build()
+}
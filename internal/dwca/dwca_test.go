@@ -0,0 +1,140 @@
+package dwca
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// readArchive returns the files of a zip archive by name
+func readArchive(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestArchiveWriter(t *testing.T) {
+	t.Parallel()
+
+	meta := Metadata{
+		Title:              "Garden birds",
+		Publisher:          "Example Bird Club",
+		ContactEmail:       "birds@example.org",
+		License:            "CC0-1.0",
+		BaseURL:            "https://birdnet.example.org",
+		OccurrenceIDPrefix: "urn:test:",
+		PubDate:            time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	begin := time.Date(2024, 6, 1, 6, 30, 0, 0, time.UTC)
+	notes := []datastore.Note{
+		{ID: 1, Date: "2024-06-01", Time: "06:30:00", BeginTime: begin, EndTime: begin.Add(3 * time.Second),
+			ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.91, Latitude: 60.1, Longitude: 24.9,
+			ClipName: "clips/2024/06/great_tit.flac", Verified: "correct"},
+		{ID: 2, Date: "2024-06-03", Time: "07:00:00", ScientificName: "Erithacus rubecula",
+			CommonName: "European\tRobin", Confidence: 0.75, Latitude: 60.3, Longitude: 24.5},
+		{ID: 3, Date: "2024-06-03", Time: "08:00:00", ScientificName: "Engine", CommonName: "Engine", Confidence: 0.9},
+		{ID: 4, Date: "2024-06-03", Time: "09:00:00", ScientificName: "Human vocal", CommonName: "Human vocal", Confidence: 0.9},
+		{ID: 5, Date: "2024-06-03", Time: "10:00:00", ScientificName: "Pica pica", CommonName: "Magpie",
+			Confidence: 0.8, Verified: "false_positive"},
+	}
+
+	var buf bytes.Buffer
+	archive, err := NewArchiveWriter(&buf, meta)
+	require.NoError(t, err)
+	var added []bool
+	for i := range notes {
+		ok, err := archive.Add(&notes[i])
+		require.NoError(t, err)
+		added = append(added, ok)
+	}
+	assert.Equal(t, []bool{true, true, false, false, false}, added)
+	assert.Equal(t, 2, archive.Count())
+	require.NoError(t, archive.Close())
+
+	files := readArchive(t, buf.Bytes())
+	require.Contains(t, files, occurrenceFile)
+	require.Contains(t, files, multimediaFile)
+	require.Contains(t, files, metaFile)
+	require.Contains(t, files, emlFile)
+
+	occurrences := strings.Split(strings.TrimSpace(files[occurrenceFile]), "\n")
+	require.Len(t, occurrences, 3)
+	header := strings.Split(occurrences[0], "\t")
+	require.Len(t, header, len(occurrenceTerms))
+	assert.Equal(t, "id", header[0])
+	assert.Equal(t, "eventDate", header[2])
+
+	first := strings.Split(occurrences[1], "\t")
+	require.Len(t, first, len(occurrenceTerms))
+	assert.Equal(t, "urn:test:1", first[0])
+	assert.Equal(t, "MachineObservation", first[1])
+	assert.Equal(t, "2024-06-01T06:30:00Z/2024-06-01T06:30:03Z", first[2])
+	assert.Equal(t, "Parus major", first[6])
+	assert.Equal(t, "60.1", first[9])
+	assert.Equal(t, "verified", first[14])
+	assert.Contains(t, first[15], "0.9100")
+	assert.Equal(t, conf.DarwinCoreLicenses["CC0-1.0"], first[17])
+
+	second := strings.Split(occurrences[2], "\t")
+	require.Len(t, second, len(occurrenceTerms))
+	assert.Equal(t, "European Robin", second[7])
+	assert.Equal(t, "unverified", second[14])
+
+	media := strings.Split(strings.TrimSpace(files[multimediaFile]), "\n")
+	require.Len(t, media, 2)
+	clip := strings.Split(media[1], "\t")
+	assert.Equal(t, []string{"urn:test:1", "Sound", "audio/flac", "https://birdnet.example.org/api/v2/audio/1"}, clip[:4])
+
+	var descriptor metaArchive
+	require.NoError(t, xml.Unmarshal([]byte(files[metaFile]), &descriptor))
+	assert.Equal(t, occurrenceFile, descriptor.Core.Location)
+	assert.Equal(t, `\t`, descriptor.Core.FieldsTerminatedBy)
+	require.NotNil(t, descriptor.Core.ID)
+	assert.Len(t, descriptor.Core.Fields, len(occurrenceTerms))
+	assert.Equal(t, dwcTerm("occurrenceID"), descriptor.Core.Fields[0].Term)
+	require.NotNil(t, descriptor.Extension.CoreID)
+	assert.Len(t, descriptor.Extension.Fields, len(multimediaTerms)-1)
+
+	eml := files[emlFile]
+	assert.Contains(t, eml, "<eml:eml xmlns:eml=\"eml://ecoinformatics.org/eml-2.1.1\"")
+	assert.Contains(t, eml, "<title>Garden birds</title>")
+	assert.Contains(t, eml, "<organizationName>Example Bird Club</organizationName>")
+	assert.Contains(t, eml, "<westBoundingCoordinate>24.5</westBoundingCoordinate>")
+	assert.Contains(t, eml, "<northBoundingCoordinate>60.3</northBoundingCoordinate>")
+	assert.Contains(t, eml, "<calendarDate>2024-06-01</calendarDate>")
+	assert.Contains(t, eml, "<calendarDate>2024-06-03</calendarDate>")
+}
+
+func TestMetadataFromSettings(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Main.Name = "Garden Node"
+	settings.Output.DarwinCore.BaseURL = "https://birdnet.example.org/"
+	meta := MetadataFromSettings(settings)
+	assert.Equal(t, "urn:birdnet-go:Garden-Node:", meta.OccurrenceIDPrefix)
+	assert.Equal(t, "https://birdnet.example.org", meta.BaseURL)
+	assert.Equal(t, conf.DarwinCoreLicenses[defaultLicense], meta.licenseURL())
+
+	settings.Output.DarwinCore.OccurrenceIDPrefix = "urn:club:"
+	assert.Equal(t, "urn:club:", MetadataFromSettings(settings).OccurrenceIDPrefix)
+}
@@ -0,0 +1,208 @@
+package dwca
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// metaArchive is the archive descriptor meta.xml
+type metaArchive struct {
+	XMLName   xml.Name    `xml:"archive"`
+	Xmlns     string      `xml:"xmlns,attr"`
+	Metadata  string      `xml:"metadata,attr"`
+	Core      metaFileSet `xml:"core"`
+	Extension metaFileSet `xml:"extension"`
+}
+
+// metaFileSet describes one data file of the archive
+type metaFileSet struct {
+	Encoding           string      `xml:"encoding,attr"`
+	FieldsTerminatedBy string      `xml:"fieldsTerminatedBy,attr"`
+	LinesTerminatedBy  string      `xml:"linesTerminatedBy,attr"`
+	FieldsEnclosedBy   string      `xml:"fieldsEnclosedBy,attr"`
+	IgnoreHeaderLines  int         `xml:"ignoreHeaderLines,attr"`
+	RowType            string      `xml:"rowType,attr"`
+	Location           string      `xml:"files>location"`
+	ID                 *metaIndex  `xml:"id,omitempty"`
+	CoreID             *metaIndex  `xml:"coreid,omitempty"`
+	Fields             []metaField `xml:"field"`
+}
+
+type metaIndex struct {
+	Index int `xml:"index,attr"`
+}
+
+type metaField struct {
+	Index int    `xml:"index,attr"`
+	Term  string `xml:"term,attr"`
+}
+
+// newMetaFileSet describes a tab separated data file with the given columns.
+// The first column holds the record id.
+func newMetaFileSet(location, rowType string, terms []string, core bool) metaFileSet {
+	set := metaFileSet{
+		Encoding:           "UTF-8",
+		FieldsTerminatedBy: `\t`,
+		LinesTerminatedBy:  `\n`,
+		IgnoreHeaderLines:  1,
+		RowType:            rowType,
+		Location:           location,
+	}
+	first := 1
+	if core {
+		set.ID = &metaIndex{Index: 0}
+		first = 0 // The occurrence id is also the occurrenceID term
+	} else {
+		set.CoreID = &metaIndex{Index: 0}
+	}
+	for i := first; i < len(terms); i++ {
+		set.Fields = append(set.Fields, metaField{Index: i, Term: terms[i]})
+	}
+	return set
+}
+
+// writeMetaXML writes the archive descriptor
+func writeMetaXML(w io.Writer) error {
+	archive := metaArchive{
+		Xmlns:     "http://rs.tdwg.org/dwc/text/",
+		Metadata:  emlFile,
+		Core:      newMetaFileSet(occurrenceFile, dwcTerm("Occurrence"), occurrenceTerms, true),
+		Extension: newMetaFileSet(multimediaFile, "http://rs.gbif.org/terms/1.0/Multimedia", multimediaTerms, false),
+	}
+	return writeXML(w, archive)
+}
+
+// emlDocument is the dataset metadata eml.xml in the GBIF EML profile
+type emlDocument struct {
+	XMLName        xml.Name   `xml:"eml:eml"`
+	XmlnsEML       string     `xml:"xmlns:eml,attr"`
+	XmlnsXSI       string     `xml:"xmlns:xsi,attr"`
+	SchemaLocation string     `xml:"xsi:schemaLocation,attr"`
+	PackageID      string     `xml:"packageId,attr"`
+	System         string     `xml:"system,attr"`
+	Scope          string     `xml:"scope,attr"`
+	Lang           string     `xml:"xml:lang,attr"`
+	Dataset        emlDataset `xml:"dataset"`
+}
+
+type emlDataset struct {
+	Title              string       `xml:"title"`
+	Creator            emlParty     `xml:"creator"`
+	MetadataProvider   emlParty     `xml:"metadataProvider"`
+	PubDate            string       `xml:"pubDate"`
+	Language           string       `xml:"language"`
+	Abstract           emlPara      `xml:"abstract"`
+	IntellectualRights emlPara      `xml:"intellectualRights"`
+	Coverage           *emlCoverage `xml:"coverage,omitempty"`
+	Contact            emlParty     `xml:"contact"`
+}
+
+type emlParty struct {
+	IndividualName   *emlName `xml:"individualName,omitempty"`
+	OrganizationName string   `xml:"organizationName,omitempty"`
+	Email            string   `xml:"electronicMailAddress,omitempty"`
+}
+
+type emlName struct {
+	SurName string `xml:"surName"`
+}
+
+type emlPara struct {
+	Para string `xml:"para"`
+}
+
+type emlCoverage struct {
+	Geographic *emlGeographic `xml:"geographicCoverage,omitempty"`
+	Temporal   *emlTemporal   `xml:"temporalCoverage,omitempty"`
+}
+
+type emlGeographic struct {
+	Description string `xml:"geographicDescription"`
+	West        string `xml:"boundingCoordinates>westBoundingCoordinate"`
+	East        string `xml:"boundingCoordinates>eastBoundingCoordinate"`
+	North       string `xml:"boundingCoordinates>northBoundingCoordinate"`
+	South       string `xml:"boundingCoordinates>southBoundingCoordinate"`
+}
+
+type emlTemporal struct {
+	Begin string `xml:"rangeOfDates>beginDate>calendarDate"`
+	End   string `xml:"rangeOfDates>endDate>calendarDate"`
+}
+
+// writeEML writes the dataset metadata
+func writeEML(w io.Writer, meta *Metadata, cov *coverage) error {
+	party := emlParty{OrganizationName: meta.Publisher, Email: meta.ContactEmail}
+	if meta.ContactName != "" {
+		party.IndividualName = &emlName{SurName: meta.ContactName}
+	}
+	if party.IndividualName == nil && party.OrganizationName == "" {
+		// EML requires a name for every party
+		party.OrganizationName = "BirdNET-Go"
+	}
+
+	description := meta.Description
+	if description == "" {
+		description = "Bird vocalizations detected in audio recordings with the BirdNET model by BirdNET-Go."
+	}
+
+	doc := emlDocument{
+		XmlnsEML:       "eml://ecoinformatics.org/eml-2.1.1",
+		XmlnsXSI:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "eml://ecoinformatics.org/eml-2.1.1 http://rs.gbif.org/schema/eml-gbif-profile/1.1/eml.xsd",
+		PackageID:      meta.OccurrenceIDPrefix + "dataset",
+		System:         "http://gbif.org",
+		Scope:          "system",
+		Lang:           "en",
+		Dataset: emlDataset{
+			Title:              meta.Title,
+			Creator:            party,
+			MetadataProvider:   party,
+			PubDate:            meta.PubDate.Format("2006-01-02"),
+			Language:           "en",
+			Abstract:           emlPara{Para: description},
+			IntellectualRights: emlPara{Para: "This work is licensed under " + meta.licenseURL()},
+			Contact:            party,
+		},
+	}
+
+	if cov.located || !cov.first.IsZero() {
+		doc.Dataset.Coverage = &emlCoverage{}
+		if cov.located {
+			doc.Dataset.Coverage.Geographic = &emlGeographic{
+				Description: "Locations of the recording stations",
+				West:        formatCoordinate(cov.west),
+				East:        formatCoordinate(cov.east),
+				North:       formatCoordinate(cov.north),
+				South:       formatCoordinate(cov.south),
+			}
+		}
+		if !cov.first.IsZero() {
+			doc.Dataset.Coverage.Temporal = &emlTemporal{
+				Begin: cov.first.Format("2006-01-02"),
+				End:   cov.last.Format("2006-01-02"),
+			}
+		}
+	}
+
+	return writeXML(w, doc)
+}
+
+// formatCoordinate formats a decimal degree coordinate
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// writeXML writes an indented XML document with declaration
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
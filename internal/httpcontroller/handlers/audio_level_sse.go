@@ -50,7 +50,7 @@ func (h *Handlers) initializeLevelsData(isAuthenticated bool) (levels map[string
 	}
 
 	// Add all configured RTSP sources
-	for i, url := range h.Settings.Realtime.RTSP.URLs {
+	for i, url := range h.Settings.Realtime.RTSP.AllURLs() {
 		var displayName string
 		if isAuthenticated {
 			displayName = conf.SanitizeRTSPUrl(url)
@@ -101,7 +101,7 @@ func (h *Handlers) updateAudioLevels(audioData myaudio.AudioLevelData, levels ma
 		if isAuthenticated {
 			audioData.Name = conf.SanitizeRTSPUrl(audioData.Source)
 		} else {
-			for i, url := range h.Settings.Realtime.RTSP.URLs {
+			for i, url := range h.Settings.Realtime.RTSP.AllURLs() {
 				if url == audioData.Source {
 					audioData.Name = fmt.Sprintf("camera-%d", i+1)
 					break
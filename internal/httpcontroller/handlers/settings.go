@@ -792,6 +792,11 @@ func rtspSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		return true
 	}
 
+	// Check for changes in named RTSP sources
+	if !reflect.DeepEqual(oldSettings.Realtime.RTSP.Sources, currentSettings.Realtime.RTSP.Sources) {
+		return true
+	}
+
 	return false
 }
 
@@ -799,6 +804,7 @@ func rtspSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 func hasRTSPSettings(formParams map[string][]string) bool {
 	rtspPrefixes := []string{
 		"realtime.rtsp.urls",
+		"realtime.rtsp.sources",
 		"realtime.rtsp.transport",
 	}
 
@@ -979,6 +985,27 @@ func deepCopySettings(settings *conf.Settings) conf.Settings {
 		copy(settingsCopy.Realtime.RTSP.URLs, settings.Realtime.RTSP.URLs)
 	}
 
+	// Deep copy named RTSP sources, their overrides are pointers and slices
+	if settings.Realtime.RTSP.Sources != nil {
+		settingsCopy.Realtime.RTSP.Sources = make([]conf.RTSPSourceSettings, len(settings.Realtime.RTSP.Sources))
+		for i, src := range settings.Realtime.RTSP.Sources {
+			src.Latitude = copyPointer(src.Latitude)
+			src.Longitude = copyPointer(src.Longitude)
+			src.Threshold = copyPointer(src.Threshold)
+			src.RangeFilter.Threshold = copyPointer(src.RangeFilter.Threshold)
+			if src.Include != nil {
+				src.Include = append([]string(nil), src.Include...)
+			}
+			if src.Exclude != nil {
+				src.Exclude = append([]string(nil), src.Exclude...)
+			}
+			if src.RangeFilter.Species != nil {
+				src.RangeFilter.Species = append([]string(nil), src.RangeFilter.Species...)
+			}
+			settingsCopy.Realtime.RTSP.Sources[i] = src
+		}
+	}
+
 	// Deep copy Audio Equalizer Filters
 	if settings.Realtime.Audio.Equalizer.Filters != nil {
 		settingsCopy.Realtime.Audio.Equalizer.Filters = make([]conf.EqualizerFilter, len(settings.Realtime.Audio.Equalizer.Filters))
@@ -993,3 +1020,12 @@ func deepCopySettings(settings *conf.Settings) conf.Settings {
 
 	return settingsCopy
 }
+
+// copyPointer returns a pointer to a copy of the value, nil for a nil pointer
+func copyPointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -231,6 +231,11 @@ func (m *mockStore) SearchDetections(filters *datastore.SearchFilters) ([]datast
 	return nil, 0, nil
 }
 
+// ExportDetections implements the datastore.Interface ExportDetections method
+func (m *mockStore) ExportDetections(filters *datastore.SearchFilters, fn func(note *datastore.Note) error) error {
+	return nil
+}
+
 // GetHourlyDistribution implements the datastore.Interface GetHourlyDistribution method
 func (m *mockStore) GetHourlyDistribution(startDate, endDate, species string) ([]datastore.HourlyDistributionData, error) {
 	// Default implementation returns empty array for this mock
@@ -249,6 +254,61 @@ func (m *mockStore) GetSpeciesFirstDetectionInPeriod(startDate, endDate string,
 	return []datastore.NewSpeciesData{}, nil
 }
 
+// GetReviewedDetections implements the datastore.Interface GetReviewedDetections method
+func (m *mockStore) GetReviewedDetections(scientificName string) ([]datastore.ReviewedDetection, error) {
+	return []datastore.ReviewedDetection{}, nil
+}
+
+// GetReviewQueue implements the datastore.Interface GetReviewQueue method
+func (m *mockStore) GetReviewQueue(query *datastore.ReviewQueueQuery) ([]datastore.ReviewQueueItem, int64, error) {
+	return []datastore.ReviewQueueItem{}, 0, nil
+}
+
+// ClaimReviews implements the datastore.Interface ClaimReviews method
+func (m *mockStore) ClaimReviews(query *datastore.ReviewQueueQuery, ttl time.Duration) ([]datastore.ReviewQueueItem, error) {
+	return []datastore.ReviewQueueItem{}, nil
+}
+
+// ReleaseReviewClaims implements the datastore.Interface ReleaseReviewClaims method
+func (m *mockStore) ReleaseReviewClaims(reviewer string, noteIDs []uint) (int64, error) {
+	return 0, nil
+}
+
+// ApplyReviewVerdicts implements the datastore.Interface ApplyReviewVerdicts method
+func (m *mockStore) ApplyReviewVerdicts(reviewer string, verdicts []datastore.ReviewVerdict) ([]datastore.ReviewVerdictResult, error) {
+	return []datastore.ReviewVerdictResult{}, nil
+}
+
+// GetNoteReviewHistory implements the datastore.Interface GetNoteReviewHistory method
+func (m *mockStore) GetNoteReviewHistory(noteID uint) ([]datastore.NoteReviewHistory, error) {
+	return []datastore.NoteReviewHistory{}, nil
+}
+
+// GetReviewProgress implements the datastore.Interface GetReviewProgress method
+func (m *mockStore) GetReviewProgress(since time.Time) (*datastore.ReviewProgress, error) {
+	return &datastore.ReviewProgress{}, nil
+}
+
+// SaveSoundLevels implements the datastore.Interface SaveSoundLevels method
+func (m *mockStore) SaveSoundLevels(levels []datastore.SoundLevel) error {
+	return nil
+}
+
+// GetSoundLevels implements the datastore.Interface GetSoundLevels method
+func (m *mockStore) GetSoundLevels(query *datastore.SoundLevelQuery) ([]datastore.SoundLevel, error) {
+	return []datastore.SoundLevel{}, nil
+}
+
+// GetSoundLevelSources implements the datastore.Interface GetSoundLevelSources method
+func (m *mockStore) GetSoundLevelSources() ([]datastore.SoundLevelSource, error) {
+	return []datastore.SoundLevelSource{}, nil
+}
+
+// DeleteSoundLevelsBefore implements the datastore.Interface DeleteSoundLevelsBefore method
+func (m *mockStore) DeleteSoundLevelsBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
 // mockFailingStore is a mock implementation that simulates database failures
 type mockFailingStore struct {
 	mockStore
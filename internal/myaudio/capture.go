@@ -219,13 +219,14 @@ func initializeBuffersForSource(sourceID string) error {
 
 func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, unifiedAudioChan chan UnifiedAudioData) {
 	// If no RTSP URLs and no audio device configured, return early
-	if len(settings.Realtime.RTSP.URLs) == 0 && settings.Realtime.Audio.Source == "" {
+	rtspURLs := settings.Realtime.RTSP.AllURLs()
+	if len(rtspURLs) == 0 && settings.Realtime.Audio.Source == "" {
 		return
 	}
 
 	// Initialize RTSP sources - the FFmpegManager will handle buffer initialization
-	if len(settings.Realtime.RTSP.URLs) > 0 {
-		for _, url := range settings.Realtime.RTSP.URLs {
+	if len(rtspURLs) > 0 {
+		for _, url := range rtspURLs {
 			// CaptureAudioRTSP delegates to FFmpegManager which handles everything
 			go CaptureAudioRTSP(url, settings.Realtime.RTSP.Transport, wg, quitChan, restartChan, unifiedAudioChan)
 		}
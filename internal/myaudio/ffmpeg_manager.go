@@ -225,7 +225,7 @@ func (m *FFmpegManager) SyncWithConfig(audioChan chan UnifiedAudioData) error {
 	configuredURLs := make(map[string]string) // url -> transport
 
 	// Build map of configured URLs
-	for _, url := range settings.Realtime.RTSP.URLs {
+	for _, url := range settings.Realtime.RTSP.AllURLs() {
 		configuredURLs[url] = settings.Realtime.RTSP.Transport
 	}
 
@@ -2,6 +2,7 @@
 package myaudio
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
@@ -76,8 +77,12 @@ func ProcessData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source s
 		return fmt.Errorf("error converting %v bit PCM data to float32: %w", conf.BitDepth, err)
 	}
 
-	// run BirdNET inference
-	results, err := bn.Predict(sampleData)
+	// run BirdNET inference, chunk metadata is passed along for the prediction archive
+	ctx := birdnet.WithPredictionMeta(context.Background(), birdnet.PredictionMeta{
+		Source:    source,
+		StartTime: startTime,
+	})
+	results, err := bn.PredictWithContext(ctx, sampleData)
 	
 	// Return float32 buffer to pool after prediction
 	// This is safe because Predict copies the data to the input tensor
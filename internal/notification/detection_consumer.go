@@ -12,9 +12,11 @@ import (
 )
 
 // DetectionNotificationConsumer handles detection events and creates notifications for new species
+// and for detections matching the detection rules
 type DetectionNotificationConsumer struct {
 	service *Service
 	logger  *slog.Logger
+	rules   *DetectionRuleEngine // nil without detection rules
 }
 
 // NewDetectionNotificationConsumer creates a new consumer for detection events
@@ -47,11 +49,37 @@ func (c *DetectionNotificationConsumer) SupportsBatching() bool {
 	return false
 }
 
+// SetRules sets the detection rules evaluated for each detection event,
+// it must be called before the consumer is registered with the event bus
+func (c *DetectionNotificationConsumer) SetRules(rules *DetectionRuleEngine) {
+	c.rules = rules
+}
+
+// Rules returns the detection rules of the consumer, nil without rules
+func (c *DetectionNotificationConsumer) Rules() *DetectionRuleEngine {
+	return c.rules
+}
+
 // ProcessDetectionEvent processes a single detection event
 func (c *DetectionNotificationConsumer) ProcessDetectionEvent(event events.DetectionEvent) error {
+	var ruleErr error
+	if c.rules != nil {
+		for _, notification := range c.rules.Evaluate(event) {
+			if err := c.publish(notification); err != nil {
+				ruleErr = err
+				continue
+			}
+			c.logger.Info("created detection rule notification",
+				"rule", notification.Metadata["rule"],
+				"species", event.GetSpeciesName(),
+				"confidence", event.GetConfidence(),
+			)
+		}
+	}
+
 	// Only process new species detections
 	if !event.IsNewSpecies() {
-		return nil
+		return ruleErr
 	}
 
 	// Sanitize location to remove RTSP credentials
@@ -78,18 +106,9 @@ func (c *DetectionNotificationConsumer) ProcessDetectionEvent(event events.Detec
 		WithMetadata("days_since_first_seen", event.GetDaysSinceFirstSeen()).
 		WithExpiry(24 * time.Hour) // New species notifications expire after 24 hours
 
-	// Add the notification through the service
-	// First save to store
-	if err := c.service.store.Save(notification); err != nil {
-		c.logger.Error("failed to save new species notification",
-			"species", event.GetSpeciesName(),
-			"error", err,
-		)
-		return fmt.Errorf("failed to save notification: %w", err)
+	if err := c.publish(notification); err != nil {
+		return err
 	}
-	
-	// Then broadcast to subscribers
-	c.service.broadcast(notification)
 
 	c.logger.Info("created new species notification",
 		"species", event.GetSpeciesName(),
@@ -97,5 +116,22 @@ func (c *DetectionNotificationConsumer) ProcessDetectionEvent(event events.Detec
 		"location", sanitizedLocation,
 	)
 
+	return ruleErr
+}
+
+// publish saves a detection notification and broadcasts it to subscribers.
+// Detection notifications bypass the rate limit of the service.
+func (c *DetectionNotificationConsumer) publish(notification *Notification) error {
+	// First save to store
+	if err := c.service.getStore().Save(notification); err != nil {
+		c.logger.Error("failed to save detection notification",
+			"title", notification.Title,
+			"error", err,
+		)
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	// Then broadcast to subscribers
+	c.service.broadcast(notification)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,284 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/events"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// DetectionRule is a user-defined rule creating notifications of detections.
+// All configured conditions of the rule must match a detection.
+type DetectionRule struct {
+	Name string
+
+	species       map[string]bool // lower case common and scientific names, empty for all species
+	sources       []string
+	minConfidence float64
+	maxConfidence float64
+	notSeen       time.Duration
+	timeOfDay     bool
+	startMinute   int // minutes after local midnight
+	endMinute     int
+	burstCount    int
+	burstWindow   time.Duration
+	priority      Priority
+	expiry        time.Duration
+	cooldown      time.Duration
+
+	lastNotified map[string]time.Time   // last notification per species
+	detections   map[string][]time.Time // detections per species within the burst window
+}
+
+// NewDetectionRule creates a detection rule from its settings
+func NewDetectionRule(settings *conf.DetectionRuleSettings) (*DetectionRule, error) {
+	rule := &DetectionRule{
+		Name:          settings.Name,
+		species:       make(map[string]bool, len(settings.Species)),
+		sources:       settings.Sources,
+		minConfidence: settings.MinConfidence,
+		maxConfidence: settings.MaxConfidence,
+		notSeen:       time.Duration(settings.NotSeenDays) * 24 * time.Hour,
+		burstCount:    settings.Burst.Count,
+		burstWindow:   time.Duration(settings.Burst.Window) * time.Second,
+		priority:      PriorityMedium,
+		expiry:        time.Duration(settings.Expiry) * time.Second,
+		cooldown:      time.Duration(settings.Cooldown) * time.Second,
+		lastNotified:  make(map[string]time.Time),
+		detections:    make(map[string][]time.Time),
+	}
+	if rule.Name == "" {
+		rule.Name = "Detection rule"
+	}
+	for _, species := range settings.Species {
+		rule.species[strings.ToLower(strings.TrimSpace(species))] = true
+	}
+	if settings.Priority != "" {
+		rule.priority = Priority(settings.Priority)
+	}
+
+	if settings.TimeOfDay.Start != "" || settings.TimeOfDay.End != "" {
+		start, err := parseClockMinutes(settings.TimeOfDay.Start)
+		if err != nil {
+			return nil, ruleError(rule.Name, err)
+		}
+		end, err := parseClockMinutes(settings.TimeOfDay.End)
+		if err != nil {
+			return nil, ruleError(rule.Name, err)
+		}
+		rule.timeOfDay = true
+		rule.startMinute, rule.endMinute = start, end
+	}
+	if rule.burstCount > 0 && rule.burstWindow <= 0 {
+		return nil, ruleError(rule.Name, fmt.Errorf("burst window is required with burst count"))
+	}
+
+	return rule, nil
+}
+
+// matchesDetection checks the conditions that depend only on the detection itself
+func (r *DetectionRule) matchesDetection(event events.DetectionEvent) bool {
+	if len(r.species) > 0 &&
+		!r.species[strings.ToLower(event.GetSpeciesName())] &&
+		!r.species[strings.ToLower(event.GetScientificName())] {
+		return false
+	}
+	if len(r.sources) > 0 && !r.matchesSource(event.GetLocation()) {
+		return false
+	}
+
+	confidence := event.GetConfidence()
+	if r.minConfidence > 0 && confidence < r.minConfidence {
+		return false
+	}
+	if r.maxConfidence > 0 && confidence > r.maxConfidence {
+		return false
+	}
+
+	if r.timeOfDay {
+		local := event.GetTimestamp().Local()
+		minute := local.Hour()*60 + local.Minute()
+		if r.startMinute <= r.endMinute {
+			if minute < r.startMinute || minute >= r.endMinute {
+				return false
+			}
+		} else if minute < r.startMinute && minute >= r.endMinute {
+			// Range spans midnight
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSource checks the detection source against the configured sources,
+// sources may be configured with or without RTSP credentials
+func (r *DetectionRule) matchesSource(location string) bool {
+	sanitized := privacy.SanitizeRTSPUrl(location)
+	for _, source := range r.sources {
+		if strings.EqualFold(source, location) || strings.EqualFold(privacy.SanitizeRTSPUrl(source), sanitized) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordBurst records the detection and returns the number of detections of
+// the species within the burst window
+func (r *DetectionRule) recordBurst(key string, ts time.Time) int {
+	cutoff := ts.Add(-r.burstWindow)
+	recent := r.detections[key][:0]
+	for _, t := range r.detections[key] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, ts)
+	r.detections[key] = recent
+	return len(recent)
+}
+
+// DetectionRuleEngine evaluates detection rules against detection events
+type DetectionRuleEngine struct {
+	mu       sync.Mutex
+	rules    []*DetectionRule
+	lastSeen map[string]time.Time // last detection per lower case scientific name
+}
+
+// NewDetectionRuleEngine creates an engine of the enabled rules
+func NewDetectionRuleEngine(settings []conf.DetectionRuleSettings) (*DetectionRuleEngine, error) {
+	engine := &DetectionRuleEngine{lastSeen: make(map[string]time.Time)}
+	for i := range settings {
+		if !settings[i].Enabled {
+			continue
+		}
+		rule, err := NewDetectionRule(&settings[i])
+		if err != nil {
+			return nil, err
+		}
+		engine.rules = append(engine.rules, rule)
+	}
+	return engine, nil
+}
+
+// Len returns the number of rules of the engine, 0 for a nil engine
+func (e *DetectionRuleEngine) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.rules)
+}
+
+// SeedLastSeen sets the last detection times of species by scientific name,
+// e.g. from the database at startup, so that rules matching species not seen
+// in a number of days work across restarts. Newer detections are kept.
+func (e *DetectionRuleEngine) SeedLastSeen(lastSeen map[string]time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, ts := range lastSeen {
+		key := strings.ToLower(name)
+		if ts.After(e.lastSeen[key]) {
+			e.lastSeen[key] = ts
+		}
+	}
+}
+
+// Evaluate records the detection and returns the notifications of the rules it matches
+func (e *DetectionRuleEngine) Evaluate(event events.DetectionEvent) []*Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ts := event.GetTimestamp()
+	key := strings.ToLower(event.GetScientificName())
+	previous, known := e.lastSeen[key]
+	if ts.After(previous) {
+		e.lastSeen[key] = ts
+	}
+
+	var notifications []*Notification
+	for _, rule := range e.rules {
+		if !rule.matchesDetection(event) {
+			continue
+		}
+
+		var details []string
+		if rule.notSeen > 0 {
+			// Species without history are reported by new species notifications
+			if !known || ts.Sub(previous) < rule.notSeen {
+				continue
+			}
+			details = append(details, fmt.Sprintf("first detection in %d days", int(ts.Sub(previous).Hours()/24)))
+		}
+
+		burst := 0
+		if rule.burstCount > 0 {
+			burst = rule.recordBurst(key, ts)
+			if burst <= rule.burstCount {
+				continue
+			}
+			details = append(details, fmt.Sprintf("%d detections within %s", burst, rule.burstWindow))
+		}
+
+		if rule.cooldown > 0 {
+			if last, ok := rule.lastNotified[key]; ok && ts.Sub(last) < rule.cooldown {
+				continue
+			}
+		}
+		rule.lastNotified[key] = ts
+
+		notification := newRuleNotification(rule, event, details)
+		if rule.notSeen > 0 {
+			notification.WithMetadata("days_since_last_seen", int(ts.Sub(previous).Hours()/24))
+		}
+		if burst > 0 {
+			notification.WithMetadata("burst_count", burst)
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications
+}
+
+// newRuleNotification creates the notification of a detection matching a rule
+func newRuleNotification(rule *DetectionRule, event events.DetectionEvent, details []string) *Notification {
+	location := privacy.SanitizeRTSPUrl(event.GetLocation())
+
+	title := fmt.Sprintf("%s: %s", rule.Name, event.GetSpeciesName())
+	message := fmt.Sprintf("%s (%s) detected at %s", event.GetSpeciesName(), event.GetScientificName(), location)
+	if len(details) > 0 {
+		message += ", " + strings.Join(details, ", ")
+	}
+
+	notification := NewNotification(TypeDetection, rule.priority, title, message).
+		WithComponent("detection").
+		WithMetadata("species", event.GetSpeciesName()).
+		WithMetadata("scientific_name", event.GetScientificName()).
+		WithMetadata("confidence", event.GetConfidence()).
+		WithMetadata("location", location).
+		WithMetadata("rule", rule.Name)
+	if rule.expiry > 0 {
+		notification.WithExpiry(rule.expiry)
+	}
+	return notification
+}
+
+// parseClockMinutes parses a HH:MM time of day to minutes after midnight
+func parseClockMinutes(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, use HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ruleError wraps an error in a detection rule
+func ruleError(name string, err error) error {
+	return errors.New(err).
+		Component("notification").
+		Category(errors.CategoryConfiguration).
+		Context("rule", name).
+		Build()
+}
@@ -2,7 +2,9 @@ package notification
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/events"
 	"github.com/tphakala/birdnet-go/internal/logging"
 	"log/slog"
@@ -110,13 +112,24 @@ func InitializeEventBusIntegration() error {
 	)
 
 	// Create and register detection notification consumer
-	detectionConsumer = NewDetectionNotificationConsumer(service)
+	consumer := NewDetectionNotificationConsumer(service)
+	if settings := conf.GetSettings(); settings != nil && len(settings.Notification.Rules) > 0 {
+		// Invalid rules are logged, notifications of new species keep working
+		rules, err := NewDetectionRuleEngine(settings.Notification.Rules)
+		if err != nil {
+			logger.Warn("failed to load detection notification rules", "error", err)
+		} else if rules.Len() > 0 {
+			consumer.SetRules(rules)
+		}
+	}
+	detectionConsumer = consumer
 	if err := eventBus.RegisterConsumer(detectionConsumer); err != nil {
 		return fmt.Errorf("failed to register detection notification consumer: %w", err)
 	}
 
 	logger.Info("detection notification consumer registered with event bus",
 		"consumer", detectionConsumer.Name(),
+		"detection_rules", detectionConsumer.Rules().Len(),
 		"debug", resourceConfig.Debug,
 	)
 
@@ -138,6 +151,15 @@ func GetDetectionConsumer() *DetectionNotificationConsumer {
 	return detectionConsumer
 }
 
+// SeedDetectionRuleHistory sets the last detection times of species by
+// scientific name for rules matching species not seen in a number of days
+func SeedDetectionRuleHistory(lastSeen map[string]time.Time) {
+	if detectionConsumer == nil || detectionConsumer.Rules() == nil {
+		return
+	}
+	detectionConsumer.Rules().SeedLastSeen(lastSeen)
+}
+
 // GetWorkerStats returns notification worker statistics
 func GetWorkerStats() *WorkerStats {
 	if notificationWorker == nil {
@@ -70,7 +70,7 @@ func NotifyDetection(species string, confidence float64, metadata map[string]any
 		for k, v := range metadata {
 			notification.WithMetadata(k, v)
 		}
-		_ = service.store.Update(notification)
+		_ = service.getStore().Update(notification)
 	}
 }
 
@@ -129,7 +129,7 @@ func NotifyResourceAlert(resource string, current, threshold float64, unit strin
 			WithMetadata("threshold", threshold).
 			WithMetadata("unit", unit).
 			WithExpiry(30 * time.Minute) // Auto-expire resource alerts after 30 minutes
-		_ = service.store.Update(notification)
+		_ = service.getStore().Update(notification)
 	}
 }
 
@@ -178,7 +178,7 @@ func NotifyStartup(version string) {
 	notification, _ := service.CreateWithComponent(TypeInfo, PriorityLow, title, message, "system")
 	if notification != nil {
 		notification.WithExpiry(5 * time.Minute) // Auto-expire after 5 minutes
-		_ = service.store.Update(notification)
+		_ = service.getStore().Update(notification)
 	}
 }
 
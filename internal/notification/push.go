@@ -0,0 +1,307 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	defaultPushTimeout         = 10 * time.Second
+	defaultPushRateLimitWindow = time.Minute
+	pushQueueSize              = 32 // notifications waiting for delivery per channel
+	pushMaxAttempts            = 3
+	pushRetryDelay             = 2 * time.Second // multiplied by the attempt number
+)
+
+// PushChannel delivers notifications to an external service
+type PushChannel interface {
+	// Name returns the name of the channel used in logs
+	Name() string
+	// Send delivers a notification, the context carries the delivery timeout
+	Send(ctx context.Context, notification *Notification) error
+}
+
+// PushFilter selects the notifications delivered to a channel. Empty lists match all values.
+type PushFilter struct {
+	Types      []Type
+	Priorities []Priority
+	Components []string
+}
+
+// NewPushFilter creates a filter from channel filter settings
+func NewPushFilter(settings *conf.PushFilterSettings) PushFilter {
+	var f PushFilter
+	for _, t := range settings.Types {
+		f.Types = append(f.Types, Type(t))
+	}
+	for _, p := range settings.Priorities {
+		f.Priorities = append(f.Priorities, Priority(p))
+	}
+	f.Components = append(f.Components, settings.Components...)
+	return f
+}
+
+// Matches reports whether the notification passes the filter
+func (f *PushFilter) Matches(notification *Notification) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, notification.Type) {
+		return false
+	}
+	if len(f.Priorities) > 0 && !slices.Contains(f.Priorities, notification.Priority) {
+		return false
+	}
+	if len(f.Components) > 0 && !slices.Contains(f.Components, notification.Component) {
+		return false
+	}
+	return true
+}
+
+// pushTarget is a channel subscribed to the notification service
+type pushTarget struct {
+	channel PushChannel
+	filter  PushFilter
+	limiter *RateLimiter // nil for no rate limit
+	timeout time.Duration
+	queue   chan *Notification
+}
+
+// PushDispatcher delivers the notifications of the service to external channels.
+// Every channel has its own queue so that a slow channel doesn't delay others.
+type PushDispatcher struct {
+	service *Service
+	targets []*pushTarget
+	logger  *slog.Logger
+	sub     <-chan *Notification
+	cancel  context.CancelFunc
+	// retryDelay is the delay before the first retry, it grows with each attempt
+	retryDelay time.Duration
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+}
+
+// NewPushDispatcher creates a dispatcher for the enabled channels of the settings
+func NewPushDispatcher(service *Service, settings *conf.PushSettings) (*PushDispatcher, error) {
+	if service == nil {
+		return nil, errors.Newf("notification service is required for push notifications").
+			Component("notification").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	d := &PushDispatcher{service: service, logger: service.logger, retryDelay: pushRetryDelay}
+	for i := range settings.Channels {
+		channelSettings := &settings.Channels[i]
+		if !channelSettings.Enabled {
+			continue
+		}
+		channel, err := NewPushChannel(channelSettings)
+		if err != nil {
+			return nil, err
+		}
+		d.AddChannel(channel, NewPushFilter(&channelSettings.Filter), channelSettings.RateLimit, time.Duration(channelSettings.Timeout)*time.Second)
+	}
+	return d, nil
+}
+
+// AddChannel adds a delivery channel, it must be called before Start
+func (d *PushDispatcher) AddChannel(channel PushChannel, filter PushFilter, rateLimit conf.PushRateLimitSettings, timeout time.Duration) {
+	target := &pushTarget{
+		channel: channel,
+		filter:  filter,
+		timeout: timeout,
+		queue:   make(chan *Notification, pushQueueSize),
+	}
+	if target.timeout <= 0 {
+		target.timeout = defaultPushTimeout
+	}
+	if rateLimit.MaxEvents > 0 {
+		window := time.Duration(rateLimit.Window) * time.Second
+		if window <= 0 {
+			window = defaultPushRateLimitWindow
+		}
+		target.limiter = NewRateLimiter(window, rateLimit.MaxEvents)
+	}
+	d.targets = append(d.targets, target)
+}
+
+// Start subscribes to the notification service and starts delivery
+func (d *PushDispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sub != nil {
+		return
+	}
+
+	sub, subCtx := d.service.Subscribe()
+	ctx, cancel := context.WithCancel(subCtx)
+	d.sub = sub
+	d.cancel = cancel
+
+	for _, target := range d.targets {
+		d.wg.Add(1)
+		go d.deliverLoop(ctx, target)
+	}
+	d.wg.Add(1)
+	go d.dispatchLoop(ctx, sub)
+
+	d.logger.Info("push notification channels started", "channels", len(d.targets))
+}
+
+// Stop unsubscribes from the service and waits for deliveries in progress
+func (d *PushDispatcher) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sub == nil {
+		return
+	}
+
+	d.service.Unsubscribe(d.sub)
+	d.cancel()
+	d.wg.Wait()
+	d.sub = nil
+}
+
+// dispatchLoop queues the notifications of the service for the matching channels
+func (d *PushDispatcher) dispatchLoop(ctx context.Context, sub <-chan *Notification) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification, ok := <-sub:
+			if !ok || notification == nil {
+				return
+			}
+			d.dispatch(notification)
+		}
+	}
+}
+
+// dispatch queues a notification for every channel it passes the filter and rate limit of
+func (d *PushDispatcher) dispatch(notification *Notification) {
+	// Toasts are short lived messages for the web interface
+	if isToastNotification(notification) || notification.IsExpired() {
+		return
+	}
+
+	for _, target := range d.targets {
+		if !target.filter.Matches(notification) {
+			continue
+		}
+		if target.limiter != nil && !target.limiter.Allow() {
+			d.logger.Debug("push notification rate limited",
+				"channel", target.channel.Name(),
+				"notification_id", notification.ID)
+			continue
+		}
+		select {
+		case target.queue <- notification:
+		default:
+			d.logger.Warn("push notification queue full, dropping notification",
+				"channel", target.channel.Name(),
+				"notification_id", notification.ID)
+		}
+	}
+}
+
+// deliverLoop sends the queued notifications of one channel
+func (d *PushDispatcher) deliverLoop(ctx context.Context, target *pushTarget) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-target.queue:
+			d.deliver(ctx, target, notification)
+		}
+	}
+}
+
+// deliver sends a notification to a channel, retrying failed deliveries
+func (d *PushDispatcher) deliver(ctx context.Context, target *pushTarget, notification *Notification) {
+	for attempt := 1; attempt <= pushMaxAttempts; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, target.timeout)
+		err := target.channel.Send(sendCtx, notification)
+		cancel()
+		if err == nil {
+			d.logger.Debug("push notification delivered",
+				"channel", target.channel.Name(),
+				"notification_id", notification.ID,
+				"attempt", attempt)
+			return
+		}
+
+		// Failures are only logged, a notification about them could fail in turn
+		retry := attempt < pushMaxAttempts && isRetryablePushError(err)
+		d.logger.Warn("push notification delivery failed",
+			"channel", target.channel.Name(),
+			"notification_id", notification.ID,
+			"attempt", attempt,
+			"retry", retry,
+			"error", err)
+		if !retry {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(attempt) * d.retryDelay):
+		}
+	}
+}
+
+// isRetryablePushError reports whether a failed delivery may succeed on retry
+func isRetryablePushError(err error) bool {
+	var enhancedErr *errors.EnhancedError
+	if errors.As(err, &enhancedErr) {
+		if retryable, ok := enhancedErr.GetContext()["retryable"].(bool); ok {
+			return retryable
+		}
+	}
+	return true
+}
+
+var (
+	pushDispatcher   *PushDispatcher
+	pushDispatcherMu sync.Mutex
+)
+
+// InitializePush starts delivery of the notifications of the global service to
+// the enabled push channels. It replaces channels started by an earlier call.
+func InitializePush(settings *conf.PushSettings) error {
+	pushDispatcherMu.Lock()
+	defer pushDispatcherMu.Unlock()
+
+	if pushDispatcher != nil {
+		pushDispatcher.Stop()
+		pushDispatcher = nil
+	}
+	if !settings.Enabled {
+		return nil
+	}
+
+	dispatcher, err := NewPushDispatcher(GetService(), settings)
+	if err != nil {
+		return err
+	}
+	dispatcher.Start()
+	pushDispatcher = dispatcher
+	return nil
+}
+
+// StopPush stops delivery to push channels
+func StopPush() {
+	pushDispatcherMu.Lock()
+	defer pushDispatcherMu.Unlock()
+
+	if pushDispatcher != nil {
+		pushDispatcher.Stop()
+		pushDispatcher = nil
+	}
+}
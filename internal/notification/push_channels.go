@@ -0,0 +1,365 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	// PushoverAPIURL is the default endpoint of Pushover style channels
+	PushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+	defaultSMTPPort  = 587
+	maxPushErrorBody = 512 // bytes of the response body included in errors
+)
+
+// pushHTTPClient is shared by all HTTP channels, timeouts come from the request context
+var pushHTTPClient = &http.Client{}
+
+// NewPushChannel creates the channel of the given settings
+func NewPushChannel(settings *conf.PushChannelSettings) (PushChannel, error) {
+	name := settings.Name
+	if name == "" {
+		name = settings.Type
+	}
+
+	switch settings.Type {
+	case "webhook":
+		method := strings.ToUpper(settings.Method)
+		if method == "" {
+			method = http.MethodPost
+		}
+		return &WebhookChannel{name: name, url: settings.URL, method: method, headers: settings.Headers}, nil
+	case "ntfy":
+		return &NtfyChannel{name: name, server: settings.URL, topic: settings.Topic, token: settings.Token, headers: settings.Headers}, nil
+	case "gotify":
+		return &GotifyChannel{name: name, server: settings.URL, token: settings.Token}, nil
+	case "pushover":
+		apiURL := settings.URL
+		if apiURL == "" {
+			apiURL = PushoverAPIURL
+		}
+		return &PushoverChannel{name: name, url: apiURL, token: settings.Token, userKey: settings.UserKey}, nil
+	case "smtp":
+		return &SMTPChannel{name: name, settings: settings.SMTP}, nil
+	default:
+		return nil, errors.Newf("unsupported push channel type %q", settings.Type).
+			Component("notification").
+			Category(errors.CategoryConfiguration).
+			Context("channel", name).
+			Build()
+	}
+}
+
+// WebhookChannel posts notifications as JSON to an HTTP endpoint
+type WebhookChannel struct {
+	name    string
+	url     string
+	method  string
+	headers map[string]string
+}
+
+// Name returns the name of the channel
+func (c *WebhookChannel) Name() string { return c.name }
+
+// Send posts the notification as JSON
+func (c *WebhookChannel) Send(ctx context.Context, notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return pushError(c.name, "webhook", err, false)
+	}
+	return sendPushRequest(ctx, c.name, "webhook", c.method, c.url, "application/json", bytes.NewReader(body), c.headers)
+}
+
+// NtfyChannel publishes notifications to an ntfy topic
+type NtfyChannel struct {
+	name    string
+	server  string
+	topic   string
+	token   string
+	headers map[string]string
+}
+
+// Name returns the name of the channel
+func (c *NtfyChannel) Name() string { return c.name }
+
+// Send publishes the notification message with title, priority and tags headers
+func (c *NtfyChannel) Send(ctx context.Context, notification *Notification) error {
+	headers := map[string]string{
+		// Non-ASCII titles are encoded as RFC 2047 encoded words
+		"Title":    mime.QEncoding.Encode("utf-8", notification.Title),
+		"Priority": strconv.Itoa(ntfyPriority(notification.Priority)),
+		"Tags":     string(notification.Type),
+	}
+	if c.token != "" {
+		headers["Authorization"] = "Bearer " + c.token
+	}
+	for key, value := range c.headers {
+		headers[key] = value
+	}
+
+	topicURL := strings.TrimSuffix(c.server, "/") + "/" + url.PathEscape(c.topic)
+	return sendPushRequest(ctx, c.name, "ntfy", http.MethodPost, topicURL, "text/plain; charset=utf-8",
+		strings.NewReader(notification.Message), headers)
+}
+
+// GotifyChannel sends notifications to a Gotify server
+type GotifyChannel struct {
+	name   string
+	server string
+	token  string
+}
+
+// Name returns the name of the channel
+func (c *GotifyChannel) Name() string { return c.name }
+
+// Send creates a Gotify message with the application token
+func (c *GotifyChannel) Send(ctx context.Context, notification *Notification) error {
+	body, err := json.Marshal(map[string]any{
+		"title":    notification.Title,
+		"message":  notification.Message,
+		"priority": gotifyPriority(notification.Priority),
+	})
+	if err != nil {
+		return pushError(c.name, "gotify", err, false)
+	}
+	messageURL := strings.TrimSuffix(c.server, "/") + "/message"
+	return sendPushRequest(ctx, c.name, "gotify", http.MethodPost, messageURL, "application/json",
+		bytes.NewReader(body), map[string]string{"X-Gotify-Key": c.token})
+}
+
+// PushoverChannel sends notifications to the Pushover API or a compatible service
+type PushoverChannel struct {
+	name    string
+	url     string
+	token   string
+	userKey string
+}
+
+// Name returns the name of the channel
+func (c *PushoverChannel) Name() string { return c.name }
+
+// Send posts the notification as a form to the messages API
+func (c *PushoverChannel) Send(ctx context.Context, notification *Notification) error {
+	form := url.Values{
+		"token":     {c.token},
+		"user":      {c.userKey},
+		"title":     {notification.Title},
+		"message":   {notification.Message},
+		"priority":  {strconv.Itoa(pushoverPriority(notification.Priority))},
+		"timestamp": {strconv.FormatInt(notification.Timestamp.Unix(), 10)},
+	}
+	return sendPushRequest(ctx, c.name, "pushover", http.MethodPost, c.url, "application/x-www-form-urlencoded",
+		strings.NewReader(form.Encode()), nil)
+}
+
+// SMTPChannel sends notifications by email
+type SMTPChannel struct {
+	name     string
+	settings conf.SMTPSettings
+}
+
+// Name returns the name of the channel
+func (c *SMTPChannel) Name() string { return c.name }
+
+// Send mails the notification to the recipients
+func (c *SMTPChannel) Send(ctx context.Context, notification *Notification) error {
+	port := c.settings.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(c.settings.Host, strconv.Itoa(port))
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if c.settings.Encryption == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: c.settings.Host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return pushError(c.name, "smtp", err, true)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.settings.Host)
+	if err != nil {
+		return pushError(c.name, "smtp", err, true)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if c.settings.Encryption == "" || c.settings.Encryption == "starttls" {
+		if err := client.StartTLS(&tls.Config{ServerName: c.settings.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return pushError(c.name, "smtp", err, false)
+		}
+	}
+	if c.settings.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.settings.Username, c.settings.Password, c.settings.Host)); err != nil {
+			return pushError(c.name, "smtp", err, false)
+		}
+	}
+
+	if err := client.Mail(c.settings.From); err != nil {
+		return pushError(c.name, "smtp", err, false)
+	}
+	for _, to := range c.settings.To {
+		if err := client.Rcpt(to); err != nil {
+			return pushError(c.name, "smtp", err, false)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return pushError(c.name, "smtp", err, true)
+	}
+	if _, err := w.Write(buildNotificationEmail(c.settings.From, c.settings.To, notification)); err != nil {
+		_ = w.Close()
+		return pushError(c.name, "smtp", err, true)
+	}
+	if err := w.Close(); err != nil {
+		return pushError(c.name, "smtp", err, true)
+	}
+	return client.Quit()
+}
+
+// buildNotificationEmail formats a notification as a plain text email
+func buildNotificationEmail(from string, to []string, notification *Notification) []byte {
+	// Header values must not contain line breaks
+	header := strings.NewReplacer("\r", " ", "\n", " ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(strings.Join(to, ", ")))
+	// Non-ASCII subjects are encoded as RFC 2047 encoded words
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "[BirdNET-Go] "+header.Replace(notification.Title)))
+	fmt.Fprintf(&b, "Date: %s\r\n", notification.Timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	// Normalize line endings so that CRLF messages don't end up with bare CRs
+	message := strings.ReplaceAll(notification.Message, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(message, "\n", "\r\n"))
+	b.WriteString("\r\n\r\n")
+	fmt.Fprintf(&b, "Type: %s\r\nPriority: %s\r\n", notification.Type, notification.Priority)
+	if notification.Component != "" {
+		fmt.Fprintf(&b, "Component: %s\r\n", notification.Component)
+	}
+	return []byte(b.String())
+}
+
+// sendPushRequest sends an HTTP request of a push channel and checks the response status
+func sendPushRequest(ctx context.Context, name, channelType, method, target, contentType string, body io.Reader, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return pushError(name, channelType, err, false)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "BirdNET-Go")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := pushHTTPClient.Do(req)
+	if err != nil {
+		// Drop the URL from the error, it may contain tokens
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = fmt.Errorf("%s %s: %w", urlErr.Op, name, urlErr.Err)
+		}
+		return pushError(name, channelType, err, true)
+	}
+	defer func() {
+		// Drain so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxPushErrorBody))
+		err := fmt.Errorf("%s returned status %d: %s", name, resp.StatusCode, strings.TrimSpace(string(respBody)))
+		// Server errors, rate limiting and timeouts may succeed on retry
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusRequestTimeout
+		return pushError(name, channelType, err, retryable)
+	}
+	return nil
+}
+
+// pushError wraps a delivery error. Delivery errors have low priority so that
+// they don't create notifications, which would be pushed to the failing channel.
+func pushError(name, channelType string, err error, retryable bool) error {
+	return errors.New(err).
+		Component("notification").
+		Category(errors.CategoryIntegration).
+		Priority(errors.PriorityLow).
+		Context("operation", "push_send").
+		Context("channel", name).
+		Context("channel_type", channelType).
+		Context("retryable", retryable).
+		Build()
+}
+
+// ntfyPriority maps a notification priority to the ntfy priorities 1-5
+func ntfyPriority(priority Priority) int {
+	switch priority {
+	case PriorityCritical:
+		return 5
+	case PriorityHigh:
+		return 4
+	case PriorityLow:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// gotifyPriority maps a notification priority to the Gotify priorities 0-10
+func gotifyPriority(priority Priority) int {
+	switch priority {
+	case PriorityCritical:
+		return 10
+	case PriorityHigh:
+		return 8
+	case PriorityLow:
+		return 2
+	default:
+		return 5
+	}
+}
+
+// pushoverPriority maps a notification priority to the Pushover priorities -2 to 1,
+// emergency priority 2 is not used as it requires acknowledgement settings
+func pushoverPriority(priority Priority) int {
+	switch priority {
+	case PriorityCritical:
+		return 1
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return -2
+	default:
+		return -1
+	}
+}
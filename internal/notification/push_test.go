@@ -0,0 +1,341 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// recordingChannel records delivered notifications and fails the first failures sends
+type recordingChannel struct {
+	mu        sync.Mutex
+	delivered []*Notification
+	failures  int
+	attempts  int
+	sent      chan struct{}
+}
+
+func newRecordingChannel() *recordingChannel {
+	return &recordingChannel{sent: make(chan struct{}, 16)}
+}
+
+func (c *recordingChannel) Name() string { return "recording" }
+
+func (c *recordingChannel) Send(_ context.Context, notification *Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.failures > 0 {
+		c.failures--
+		return pushError("recording", "test", io.ErrUnexpectedEOF, true)
+	}
+	c.delivered = append(c.delivered, notification)
+	c.sent <- struct{}{}
+	return nil
+}
+
+func (c *recordingChannel) titles() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	titles := make([]string, 0, len(c.delivered))
+	for _, n := range c.delivered {
+		titles = append(titles, n.Title)
+	}
+	return titles
+}
+
+// waitDelivered waits for count deliveries to the channel
+func waitDelivered(t *testing.T, c *recordingChannel, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		select {
+		case <-c.sent:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for delivery %d, got %v", i+1, c.titles())
+		}
+	}
+}
+
+func TestPushFilterMatches(t *testing.T) {
+	t.Parallel()
+
+	filter := NewPushFilter(&conf.PushFilterSettings{
+		Types:      []string{"detection", "system"},
+		Priorities: []string{"critical", "high"},
+		Components: []string{"detection", "diskmanager"},
+	})
+
+	tests := []struct {
+		name         string
+		notification *Notification
+		want         bool
+	}{
+		{"new species", NewNotification(TypeDetection, PriorityHigh, "t", "m").WithComponent("detection"), true},
+		{"critical disk", NewNotification(TypeSystem, PriorityCritical, "t", "m").WithComponent("diskmanager"), true},
+		{"wrong type", NewNotification(TypeError, PriorityHigh, "t", "m").WithComponent("detection"), false},
+		{"wrong priority", NewNotification(TypeSystem, PriorityMedium, "t", "m").WithComponent("diskmanager"), false},
+		{"wrong component", NewNotification(TypeSystem, PriorityCritical, "t", "m").WithComponent("mqtt"), false},
+	}
+	for _, tt := range tests {
+		if got := filter.Matches(tt.notification); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	empty := NewPushFilter(&conf.PushFilterSettings{})
+	if !empty.Matches(NewNotification(TypeInfo, PriorityLow, "t", "m")) {
+		t.Error("empty filter should match all notifications")
+	}
+}
+
+func TestPushDispatcherDelivery(t *testing.T) {
+	t.Parallel()
+
+	service := createTestService()
+	defer service.Stop()
+
+	dispatcher, err := NewPushDispatcher(service, &conf.PushSettings{})
+	if err != nil {
+		t.Fatalf("NewPushDispatcher() error = %v", err)
+	}
+	channel := newRecordingChannel()
+	dispatcher.AddChannel(channel, PushFilter{Types: []Type{TypeDetection, TypeSystem}}, conf.PushRateLimitSettings{}, time.Second)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	if _, err := service.Create(TypeInfo, PriorityLow, "filtered", "not delivered"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	toast := NewToast("toast", ToastTypeInfo).ToNotification()
+	toast.Type = TypeSystem
+	if err := service.CreateWithMetadata(toast); err != nil {
+		t.Fatalf("CreateWithMetadata() error = %v", err)
+	}
+	if _, err := service.Create(TypeDetection, PriorityHigh, "New Species Detected: Robin", "First detection"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	waitDelivered(t, channel, 1)
+	// Give filtered notifications time to show up if they were wrongly delivered
+	time.Sleep(50 * time.Millisecond)
+	if got := channel.titles(); len(got) != 1 || got[0] != "New Species Detected: Robin" {
+		t.Errorf("delivered = %v, want only the detection", got)
+	}
+}
+
+func TestPushDispatcherRateLimitAndRetry(t *testing.T) {
+	t.Parallel()
+
+	service := createTestService()
+	defer service.Stop()
+
+	dispatcher, err := NewPushDispatcher(service, &conf.PushSettings{})
+	if err != nil {
+		t.Fatalf("NewPushDispatcher() error = %v", err)
+	}
+	channel := newRecordingChannel()
+	channel.failures = 1 // First delivery is retried
+	dispatcher.retryDelay = time.Millisecond
+	dispatcher.AddChannel(channel, PushFilter{}, conf.PushRateLimitSettings{MaxEvents: 2, Window: 3600}, time.Second)
+
+	for i, title := range []string{"one", "two", "three"} {
+		notification := NewNotification(TypeSystem, PriorityCritical, title, "m")
+		notification.ID = string(rune('a' + i))
+		dispatcher.dispatch(notification)
+	}
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	waitDelivered(t, channel, 2)
+	time.Sleep(50 * time.Millisecond)
+	if got := channel.titles(); len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("delivered = %v, want one and two within the rate limit", got)
+	}
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+	if channel.attempts != 3 {
+		t.Errorf("attempts = %d, want 3 with one retry", channel.attempts)
+	}
+}
+
+func TestPushHTTPChannels(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		path    string
+		headers http.Header
+		body    string
+	}
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{path: r.URL.Path, headers: r.Header.Clone(), body: string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notification := NewNotification(TypeSystem, PriorityCritical, "Disk full", "Disk usage at 98%").WithComponent("diskmanager")
+
+	tests := []struct {
+		name     string
+		settings conf.PushChannelSettings
+		check    func(t *testing.T, r request)
+	}{
+		{
+			name:     "webhook",
+			settings: conf.PushChannelSettings{Type: "webhook", URL: server.URL + "/hook", Headers: map[string]string{"X-Key": "secret"}},
+			check: func(t *testing.T, r request) {
+				t.Helper()
+				var got Notification
+				if err := json.Unmarshal([]byte(r.body), &got); err != nil {
+					t.Fatalf("webhook body is not a notification: %v", err)
+				}
+				if r.path != "/hook" || got.Title != "Disk full" || r.headers.Get("X-Key") != "secret" {
+					t.Errorf("unexpected webhook request %s %v", r.path, got)
+				}
+			},
+		},
+		{
+			name:     "ntfy",
+			settings: conf.PushChannelSettings{Type: "ntfy", URL: server.URL + "/", Topic: "birds", Token: "tk"},
+			check: func(t *testing.T, r request) {
+				t.Helper()
+				if r.path != "/birds" || r.body != "Disk usage at 98%" || r.headers.Get("Title") != "Disk full" ||
+					r.headers.Get("Priority") != "5" || r.headers.Get("Authorization") != "Bearer tk" {
+					t.Errorf("unexpected ntfy request %s %q %v", r.path, r.body, r.headers)
+				}
+			},
+		},
+		{
+			name:     "gotify",
+			settings: conf.PushChannelSettings{Type: "gotify", URL: server.URL, Token: "app"},
+			check: func(t *testing.T, r request) {
+				t.Helper()
+				var got map[string]any
+				if err := json.Unmarshal([]byte(r.body), &got); err != nil {
+					t.Fatalf("gotify body: %v", err)
+				}
+				if r.path != "/message" || r.headers.Get("X-Gotify-Key") != "app" || got["priority"] != float64(10) {
+					t.Errorf("unexpected gotify request %s %v", r.path, got)
+				}
+			},
+		},
+		{
+			name:     "pushover",
+			settings: conf.PushChannelSettings{Type: "pushover", URL: server.URL + "/1/messages.json", Token: "app", UserKey: "user"},
+			check: func(t *testing.T, r request) {
+				t.Helper()
+				form, err := url.ParseQuery(r.body)
+				if err != nil {
+					t.Fatalf("pushover body: %v", err)
+				}
+				if form.Get("token") != "app" || form.Get("user") != "user" || form.Get("priority") != "1" || form.Get("title") != "Disk full" {
+					t.Errorf("unexpected pushover form %v", form)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		channel, err := NewPushChannel(&tt.settings)
+		if err != nil {
+			t.Fatalf("%s: NewPushChannel() error = %v", tt.name, err)
+		}
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("%s: Send() error = %v", tt.name, err)
+		}
+		tt.check(t, <-requests)
+	}
+}
+
+func TestPushHTTPChannelErrors(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", status)
+	}))
+	defer server.Close()
+
+	channel, err := NewPushChannel(&conf.PushChannelSettings{Name: "phone", Type: "gotify", URL: server.URL, Token: "bad"})
+	if err != nil {
+		t.Fatalf("NewPushChannel() error = %v", err)
+	}
+	notification := NewNotification(TypeError, PriorityHigh, "t", "m")
+
+	err = channel.Send(context.Background(), notification)
+	if err == nil || !strings.Contains(err.Error(), "invalid token") {
+		t.Fatalf("Send() error = %v, want response body in error", err)
+	}
+	if isRetryablePushError(err) {
+		t.Error("client errors should not be retried")
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := channel.Send(context.Background(), notification); err == nil || !isRetryablePushError(err) {
+		t.Errorf("Send() error = %v, want retryable server error", err)
+	}
+
+	if _, err := NewPushChannel(&conf.PushChannelSettings{Type: "pager"}); err == nil {
+		t.Error("unknown channel type should fail")
+	}
+}
+
+func TestBuildNotificationEmail(t *testing.T) {
+	t.Parallel()
+
+	notification := NewNotification(TypeDetection, PriorityHigh, "New Species\r\nBcc: x@example.com", "First detection\nof Robin\r\nat dawn").
+		WithComponent("detection")
+	email := string(buildNotificationEmail("birdnet@example.com", []string{"a@example.com", "b@example.com"}, notification))
+
+	for _, want := range []string{
+		"From: birdnet@example.com\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Subject: [BirdNET-Go] New Species  Bcc: x@example.com\r\n",
+		"\r\n\r\nFirst detection\r\nof Robin\r\nat dawn\r\n",
+		"Component: detection\r\n",
+	} {
+		if !strings.Contains(email, want) {
+			t.Errorf("email missing %q:\n%s", want, email)
+		}
+	}
+}
+
+func TestBuildNotificationEmailEncodesSubject(t *testing.T) {
+	t.Parallel()
+
+	notification := NewNotification(TypeDetection, PriorityHigh, "Mésange bleue", "First detection")
+	email := string(buildNotificationEmail("birdnet@example.com", []string{"a@example.com"}, notification))
+
+	want := "Subject: =?utf-8?q?[BirdNET-Go]_M=C3=A9sange_bleue?=\r\n"
+	if !strings.Contains(email, want) {
+		t.Errorf("email missing %q:\n%s", want, email)
+	}
+}
+
+func TestNtfyChannelEncodesTitle(t *testing.T) {
+	t.Parallel()
+
+	titles := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		titles <- r.Header.Get("Title")
+	}))
+	defer server.Close()
+
+	channel := &NtfyChannel{name: "phone", server: server.URL, topic: "birds"}
+	if err := channel.Send(context.Background(), NewNotification(TypeDetection, PriorityHigh, "Mésange bleue", "First detection")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got, want := <-titles, "=?utf-8?q?M=C3=A9sange_bleue?="; got != want {
+		t.Errorf("Title header = %q, want %q", got, want)
+	}
+}
@@ -223,7 +223,7 @@ func (w *ResourceEventWorker) ProcessResourceEvent(event events.ResourceEvent) e
 		}
 
 		// Update in store
-		_ = w.service.store.Update(notification)
+		_ = w.service.getStore().Update(notification)
 	}
 
 	w.processedCount.Add(1)
@@ -21,6 +21,7 @@ type Subscriber struct {
 // Service manages notifications and provides rate limiting
 type Service struct {
 	store         NotificationStore
+	storeMu       sync.RWMutex
 	subscribers   []*Subscriber
 	subscribersMu sync.RWMutex
 	rateLimiter   *RateLimiter
@@ -129,7 +130,7 @@ func (s *Service) Create(notifType Type, priority Priority, title, message strin
 	}
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return nil, errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -163,7 +164,7 @@ func (s *Service) CreateWithComponent(notifType Type, priority Priority, title,
 		WithComponent(component)
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return nil, errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -177,14 +178,54 @@ func (s *Service) CreateWithComponent(notifType Type, priority Priority, title,
 	return notification, nil
 }
 
+// getStore returns the current notification store
+func (s *Service) getStore() NotificationStore {
+	s.storeMu.RLock()
+	defer s.storeMu.RUnlock()
+	return s.store
+}
+
+// SetStore replaces the notification store, e.g. with a persistent store once
+// the database is available. Notifications of the previous store that are not
+// in the new store are copied to it.
+func (s *Service) SetStore(store NotificationStore) error {
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
+	previous, err := s.store.List(nil)
+	if err != nil {
+		return err
+	}
+	// List returns newest first, save oldest first so that stores with a size
+	// limit keep the newest notifications
+	migrated := 0
+	for i := len(previous) - 1; i >= 0; i-- {
+		if _, err := store.Get(previous[i].ID); err == nil {
+			continue
+		}
+		if err := store.Save(previous[i]); err != nil {
+			return errors.New(err).
+				Component("notification").
+				Category(errors.CategorySystem).
+				Context("operation", "migrate_notification_store").
+				Build()
+		}
+		migrated++
+	}
+
+	s.store = store
+	s.logger.Info("notification store replaced", "migrated_notifications", migrated)
+	return nil
+}
+
 // Get retrieves a notification by ID
 func (s *Service) Get(id string) (*Notification, error) {
-	return s.store.Get(id)
+	return s.getStore().Get(id)
 }
 
 // List returns notifications based on filter options
 func (s *Service) List(filter *FilterOptions) ([]*Notification, error) {
-	return s.store.List(filter)
+	return s.getStore().List(filter)
 }
 
 // MarkAsRead updates a notification's status to read
@@ -196,13 +237,13 @@ func (s *Service) MarkAsRead(id string) error {
 			Build()
 	}
 
-	notification, err := s.store.Get(id)
+	notification, err := s.getStore().Get(id)
 	if err != nil {
 		return err
 	}
 
 	notification.MarkAsRead()
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // MarkAsAcknowledged updates a notification's status to acknowledged
@@ -214,13 +255,13 @@ func (s *Service) MarkAsAcknowledged(id string) error {
 			Build()
 	}
 
-	notification, err := s.store.Get(id)
+	notification, err := s.getStore().Get(id)
 	if err != nil {
 		return err
 	}
 
 	notification.MarkAsAcknowledged()
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // Delete removes a notification
@@ -232,7 +273,7 @@ func (s *Service) Delete(id string) error {
 			Build()
 	}
 
-	return s.store.Delete(id)
+	return s.getStore().Delete(id)
 }
 
 // Subscribe creates a channel to receive real-time notifications.
@@ -311,7 +352,7 @@ func (s *Service) Unsubscribe(ch <-chan *Notification) {
 
 // GetUnreadCount returns the number of unread notifications
 func (s *Service) GetUnreadCount() (int, error) {
-	return s.store.GetUnreadCount()
+	return s.getStore().GetUnreadCount()
 }
 
 // CreateErrorNotification creates a notification from an error
@@ -414,7 +455,7 @@ func (s *Service) cleanupLoop() {
 			if s.config.Debug {
 				// Count expired notifications before cleanup
 				filter := &FilterOptions{}
-				notifications, _ := s.store.List(filter)
+				notifications, _ := s.getStore().List(filter)
 				var expiredCount int
 				for _, n := range notifications {
 					if n.IsExpired() {
@@ -428,7 +469,7 @@ func (s *Service) cleanupLoop() {
 				}
 			}
 			
-			if err := s.store.DeleteExpired(); err != nil {
+			if err := s.getStore().DeleteExpired(); err != nil {
 				// Log error but don't stop the cleanup loop
 				if s.logger != nil {
 					s.logger.Error("error cleaning up expired notifications", "error", err)
@@ -337,4 +337,54 @@ func BenchmarkService_CreateWithMetadata(b *testing.B) {
 			b.Fatalf("CreateWithMetadata() error = %v", err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestSetStore(t *testing.T) {
+	t.Parallel()
+
+	service := createTestService()
+	defer service.Stop()
+
+	older, err := service.Create(TypeInfo, PriorityLow, "older", "m")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := service.MarkAsRead(older.ID); err != nil {
+		t.Fatalf("MarkAsRead() error = %v", err)
+	}
+	if _, err := service.Create(TypeError, PriorityHigh, "newer", "m"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// The new store already has a notification, e.g. from a previous run
+	store := NewInMemoryStore(10)
+	stored := NewNotification(TypeSystem, PriorityMedium, "stored", "m")
+	stored.Timestamp = time.Now().Add(-time.Hour)
+	if err := store.Save(stored); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := service.SetStore(store); err != nil {
+		t.Fatalf("SetStore() error = %v", err)
+	}
+
+	notifications, err := service.List(nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(notifications) != 3 || notifications[0].Title != "newer" || notifications[2].Title != "stored" {
+		t.Errorf("List() = %v, want newer, older and stored", notifications)
+	}
+	if got, _ := service.Get(older.ID); got == nil || got.Status != StatusRead {
+		t.Errorf("migrated notification should keep its read status, got %v", got)
+	}
+
+	// New notifications go to the new store
+	created, err := service.Create(TypeInfo, PriorityLow, "after", "m")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := store.Get(created.ID); err != nil {
+		t.Errorf("notification created after SetStore() not in new store: %v", err)
+	}
+}
@@ -121,34 +121,12 @@ func WriteNotesTable(settings *conf.Settings, notes []datastore.Note, filename s
 	// Add color functions
 	yellow := color.New(color.FgYellow)
 
-	// Write the header to the output destination.
-	header := "Selection\tView\tChannel\tBegin File\tBegin Time (s)\tEnd Time (s)\tLow Freq (Hz)\tHigh Freq (Hz)\tSpecies Code\tCommon Name\tConfidence\n"
-	if _, err := w.Write([]byte(header)); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
-	}
-
-	// Pre-declare err outside the loop to avoid re-declaration
-	var err error
-
-	for i := range notes {
-		if notes[i].Confidence <= settings.BirdNET.Threshold {
-			continue // Skip the current iteration as the note doesn't meet the threshold
-		}
-
-		// Prepare the line for notes above the threshold, assuming note.BeginTime and note.EndTime are of type time.Time
-		line := fmt.Sprintf("%d\tSpectrogram 1\t1\t%s\t%s\t%s\t0\t15000\t%s\t%s\t%.4f\n",
-			i+1, notes[i].Source, notes[i].BeginTime.Format("15:04:05"), notes[i].EndTime.Format("15:04:05"),
-			notes[i].SpeciesCode, notes[i].CommonName, notes[i].Confidence)
-
-		// Attempt to write the note
-		if _, err = w.Write([]byte(line)); err != nil {
-			break // If an error occurs, exit the loop
-		}
-	}
+	// Write the header and the notes above the threshold to the output destination.
+	_, err := WriteNotes(w, settings, notes, OutputTypeTable, 1, true)
 
-	// Check if an error occurred during the loop and return it
+	// Check if an error occurred while writing and return it
 	if err != nil {
-		return fmt.Errorf("failed to write note: %w", err)
+		return err
 	} else if filename != "" {
 		if _, err := yellow.Println("📁 Output written to", filename); err != nil {
 			fmt.Printf("failed to print output message: %v\n", err)
@@ -188,35 +166,12 @@ func WriteNotesCsv(settings *conf.Settings, notes []datastore.Note, filename str
 		w = os.Stdout
 	}
 
-	// Define the CSV header.
-	header := "Start (s),End (s),Scientific name,Common name,Confidence\n"
-	// Write the header to the output destination.
-	if _, err := w.Write([]byte(header)); err != nil {
-		return fmt.Errorf("failed to write header to CSV: %w", err)
-	}
-
-	// Pre-declare err outside the loop to avoid re-declaration
-	var err error
-
-	for i := range notes {
-		if notes[i].Confidence <= settings.BirdNET.Threshold {
-			continue // Skip the current iteration as the note doesn't meet the threshold
-		}
-
-		line := fmt.Sprintf("%s,%s,%s,%s,%.4f\n",
-			notes[i].BeginTime.Format("2006-01-02 15:04:05"),
-			notes[i].EndTime.Format("2006-01-02 15:04:05"),
-			notes[i].ScientificName, notes[i].CommonName, notes[i].Confidence)
-
-		if _, err = w.Write([]byte(line)); err != nil {
-			// Break out of the loop at the first sign of an error
-			break
-		}
-	}
+	// Write the header and the notes above the threshold to the output destination.
+	_, err := WriteNotes(w, settings, notes, OutputTypeCSV, 1, true)
 
 	// Handle any errors that occurred during the write operation
 	if err != nil {
-		return fmt.Errorf("failed to write note to CSV: %w", err)
+		return fmt.Errorf("failed to write CSV: %w", err)
 	} else {
 		fmt.Println("Output written to", filename)
 	}
@@ -0,0 +1,173 @@
+package observation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// Output file types of file and directory analysis
+const (
+	OutputTypeTable    = "table"
+	OutputTypeCSV      = "csv"
+	OutputTypeRaven    = "raven"
+	OutputTypeAudacity = "audacity"
+)
+
+// Frequency range of detections in output files, BirdNET analyses audio up to 15 kHz
+const (
+	DetectionLowFreq  = 0
+	DetectionHighFreq = 15000
+)
+
+// Output file headers
+const (
+	tableHeader = "Selection\tView\tChannel\tBegin File\tBegin Time (s)\tEnd Time (s)\tLow Freq (Hz)\tHigh Freq (Hz)\tSpecies Code\tCommon Name\tConfidence\n"
+	csvHeader   = "Start (s),End (s),Scientific name,Common name,Confidence\n"
+	ravenHeader = "Selection\tView\tChannel\tBegin Time (s)\tEnd Time (s)\tLow Freq (Hz)\tHigh Freq (Hz)\tBegin Path\tFile Offset (s)\tSpecies Code\tCommon Name\tScientific Name\tConfidence\n"
+)
+
+// OutputExtension returns the file name suffix of an output file type
+func OutputExtension(fileType string) string {
+	switch fileType {
+	case OutputTypeCSV:
+		return ".csv"
+	case OutputTypeRaven:
+		return ".selections.txt"
+	case OutputTypeAudacity:
+		return ".labels.txt"
+	default:
+		return ".txt"
+	}
+}
+
+// IsOutputType reports whether fileType is a supported output file type
+func IsOutputType(fileType string) bool {
+	switch fileType {
+	case OutputTypeTable, OutputTypeCSV, OutputTypeRaven, OutputTypeAudacity:
+		return true
+	default:
+		return false
+	}
+}
+
+// WriteNotes writes the notes above the confidence threshold to w in the given
+// output file type. Rows are numbered from firstSelection and the header is
+// only written if header is true, so that the results of several audio files
+// can be combined in one output. It returns the next selection number.
+func WriteNotes(w io.Writer, settings *conf.Settings, notes []datastore.Note, fileType string, firstSelection int, header bool) (int, error) {
+	if header {
+		var err error
+		switch fileType {
+		case OutputTypeTable, "":
+			_, err = io.WriteString(w, tableHeader)
+		case OutputTypeCSV:
+			_, err = io.WriteString(w, csvHeader)
+		case OutputTypeRaven:
+			_, err = io.WriteString(w, ravenHeader)
+		}
+		if err != nil {
+			return firstSelection, fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	selection := firstSelection
+	for i := range notes {
+		note := &notes[i]
+		if note.Confidence <= settings.BirdNET.Threshold {
+			continue // Skip notes which don't meet the threshold
+		}
+
+		var line string
+		switch fileType {
+		case OutputTypeCSV:
+			line = fmt.Sprintf("%s,%s,%s,%s,%.4f\n",
+				note.BeginTime.Format("2006-01-02 15:04:05"),
+				note.EndTime.Format("2006-01-02 15:04:05"),
+				note.ScientificName, note.CommonName, note.Confidence)
+		case OutputTypeRaven:
+			line = fmt.Sprintf("%d\tSpectrogram 1\t1\t%.3f\t%.3f\t%d\t%d\t%s\t%.3f\t%s\t%s\t%s\t%.4f\n",
+				selection, fileOffset(note.BeginTime), fileOffset(note.EndTime),
+				DetectionLowFreq, DetectionHighFreq, outputField(note.Source), fileOffset(note.BeginTime),
+				outputField(note.SpeciesCode), outputField(note.CommonName), outputField(note.ScientificName), note.Confidence)
+		case OutputTypeAudacity:
+			// The second line of a label holds its frequency range
+			line = fmt.Sprintf("%.3f\t%.3f\t%s (%.2f)\n\\\t%d\t%d\n",
+				fileOffset(note.BeginTime), fileOffset(note.EndTime), outputField(note.CommonName), note.Confidence,
+				DetectionLowFreq, DetectionHighFreq)
+		default:
+			// Table rows keep the note position as selection number
+			line = fmt.Sprintf("%d\tSpectrogram 1\t1\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%.4f\n",
+				firstSelection+i, note.Source, note.BeginTime.Format("15:04:05"), note.EndTime.Format("15:04:05"),
+				DetectionLowFreq, DetectionHighFreq, note.SpeciesCode, note.CommonName, note.Confidence)
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return selection, fmt.Errorf("failed to write note: %w", err)
+		}
+		selection++
+	}
+
+	if fileType == OutputTypeTable || fileType == "" {
+		return firstSelection + len(notes), nil
+	}
+	return selection, nil
+}
+
+// WriteNotesRaven writes the notes to a Raven Pro selection table. Begin and
+// end times are seconds from the start of the analysed file. If the filename
+// is an empty string, it writes to stdout.
+func WriteNotesRaven(settings *conf.Settings, notes []datastore.Note, filename string) error {
+	return writeNotesFile(settings, notes, filename, OutputTypeRaven)
+}
+
+// WriteNotesAudacity writes the notes to an Audacity label track with the
+// frequency range of each label. If the filename is an empty string, it
+// writes to stdout.
+func WriteNotesAudacity(settings *conf.Settings, notes []datastore.Note, filename string) error {
+	return writeNotesFile(settings, notes, filename, OutputTypeAudacity)
+}
+
+// writeNotesFile writes the notes of one audio file to a new output file of
+// the given type, or to stdout if the filename is empty
+func writeNotesFile(settings *conf.Settings, notes []datastore.Note, filename, fileType string) error {
+	var w io.Writer = os.Stdout
+	if filename != "" {
+		if ext := OutputExtension(fileType); !strings.HasSuffix(filename, ext) {
+			filename += ext
+		}
+		file, err := os.Create(filename)
+		if err != nil {
+			return fmt.Errorf("failed to create file %s: %w", filename, err)
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				fmt.Printf("failed to close output file: %v\n", err)
+			}
+		}()
+		w = file
+	}
+
+	if _, err := WriteNotes(w, settings, notes, fileType, 1, true); err != nil {
+		return err
+	}
+	if filename != "" {
+		fmt.Println("Output written to", filename)
+	}
+	return nil
+}
+
+// fileOffset returns the seconds from the start of the analysed file to a
+// detection time, analysis of files counts detection times from the zero time
+func fileOffset(t time.Time) float64 {
+	return t.Sub(time.Time{}).Seconds()
+}
+
+// outputField replaces the tab and newline separators of the output files in a value
+func outputField(value string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+}
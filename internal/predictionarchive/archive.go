@@ -0,0 +1,481 @@
+// Package predictionarchive stores the full BirdNET prediction vector of every
+// analysed audio chunk in compact, daily rotated binary files. The archive
+// keeps the confidence of every label, not just the top results that end up
+// in the datastore, so detection thresholds can be re-tuned offline without
+// running the model on the audio again.
+//
+// File layout (all integers little endian):
+//
+//	header: magic "BNPA" | uint16 version | uint32 label count | labels (uint16 length + UTF-8 bytes)
+//	block:  uint32 compressed length | DEFLATE compressed block payload
+//
+// Each block payload is stored column by column:
+//
+//	uint32 row count
+//	int64   timestamps (unix nanoseconds), one per row
+//	float32 sensitivity, one per row
+//	float32 overlap, one per row
+//	string  source (uint16 length + UTF-8 bytes), one per row
+//	float32 confidences, row count x label count, row major
+package predictionarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	// FileExtension is the file extension used for prediction archive files
+	FileExtension = ".bnpa"
+
+	// filePrefix is the prefix of every archive file name
+	filePrefix = "predictions-"
+
+	// formatVersion is the current on-disk format version
+	formatVersion uint16 = 1
+
+	// DefaultBlockSize is the default number of predictions per compressed block
+	DefaultBlockSize = 256
+
+	// DefaultFlushInterval is the default maximum time predictions are buffered in memory
+	DefaultFlushInterval = 60 * time.Second
+
+	// queueSize is the number of records buffered between the inference path and the writer goroutine
+	queueSize = 64
+
+	// maxStringLength is the maximum length of a length prefixed string
+	maxStringLength = 1<<16 - 1
+)
+
+// magic identifies prediction archive files
+var magic = [4]byte{'B', 'N', 'P', 'A'}
+
+// Record is a single prediction vector produced for one audio chunk.
+type Record struct {
+	Timestamp   time.Time // Start time of the analysed audio chunk
+	Source      string    // Audio source of the chunk, RTSP URL without credentials or audio card name
+	Sensitivity float32   // Sigmoid sensitivity used for the prediction
+	Overlap     float32   // Chunk overlap in seconds used for the prediction
+	Labels      []string  // Labels matching Confidences, shared between records of the same model
+	Confidences []float32 // Confidence for every label, in label order
+}
+
+// Options contains the configuration of a Writer.
+type Options struct {
+	Dir           string        // Directory where archive files are written
+	BlockSize     int           // Number of predictions per compressed block
+	FlushInterval time.Duration // Maximum time predictions are kept in memory before being written
+	RetentionDays int           // Number of days of archive files to keep, 0 keeps everything
+}
+
+// Writer appends prediction records to daily rotated archive files.
+// Records are handed to a background goroutine so that the inference
+// path never waits for compression or disk I/O.
+type Writer struct {
+	opts    Options
+	records chan Record
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	closeOnce sync.Once
+	mu        sync.Mutex // protects dropped
+	dropped   uint64     // number of records dropped because the queue was full
+
+	// Fields below are only accessed by the writer goroutine
+	file    *os.File
+	bufw    *bufio.Writer
+	day     string
+	labels  []string
+	pending []Record
+}
+
+// NewWriter creates the archive directory and starts the background writer.
+func NewWriter(opts Options) (*Writer, error) {
+	if opts.Dir == "" {
+		return nil, errors.Newf("prediction archive directory must not be empty").
+			Component("predictionarchive").
+			Category(errors.CategoryConfiguration).
+			Build()
+	}
+	if opts.BlockSize <= 0 {
+		opts.BlockSize = DefaultBlockSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, errors.New(err).
+			Component("predictionarchive").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_archive_dir").
+			Context("path", opts.Dir).
+			Build()
+	}
+
+	w := &Writer{
+		opts:    opts,
+		records: make(chan Record, queueSize),
+		done:    make(chan struct{}),
+		pending: make([]Record, 0, opts.BlockSize),
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+// Write queues a record for archiving. The confidence slice is copied, so
+// callers may reuse their buffers. If the writer falls behind the record is
+// dropped rather than blocking the caller.
+func (w *Writer) Write(rec *Record) {
+	if rec == nil || len(rec.Confidences) == 0 {
+		return
+	}
+
+	r := *rec
+	r.Confidences = make([]float32, len(rec.Confidences))
+	copy(r.Confidences, rec.Confidences)
+
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	select {
+	case w.records <- r:
+	default:
+		w.mu.Lock()
+		w.dropped++
+		dropped := w.dropped
+		w.mu.Unlock()
+		// Avoid flooding the log, report the first drop and then every 100th
+		if dropped == 1 || dropped%100 == 0 {
+			log.Printf("⚠️ Prediction archive queue is full, %d predictions dropped so far", dropped)
+		}
+	}
+}
+
+// Dropped returns the number of records dropped because the writer could not keep up.
+func (w *Writer) Dropped() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+// Close flushes buffered predictions and closes the current archive file.
+func (w *Writer) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+	return nil
+}
+
+// run is the writer goroutine, it batches records into blocks and writes them to disk.
+func (w *Writer) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case rec := <-w.records:
+			w.add(&rec)
+		case <-ticker.C:
+			w.flush()
+		case <-w.done:
+			// Drain whatever is still queued before shutting down
+			for {
+				select {
+				case rec := <-w.records:
+					w.add(&rec)
+				default:
+					w.flush()
+					w.closeFile()
+					return
+				}
+			}
+		}
+	}
+}
+
+// add appends a record to the pending block, rotating files when the day or label set changes.
+func (w *Writer) add(rec *Record) {
+	day := rec.Timestamp.Format("2006-01-02")
+	if day != w.day || !sameLabels(w.labels, rec.Labels) {
+		w.flush()
+		w.closeFile()
+		if err := w.openFile(day, rec.Labels); err != nil {
+			log.Printf("❌ Failed to open prediction archive file: %v", err)
+			return
+		}
+	}
+
+	w.pending = append(w.pending, *rec)
+	if len(w.pending) >= w.opts.BlockSize {
+		w.flush()
+	}
+}
+
+// flush writes pending records as a single compressed block.
+func (w *Writer) flush() {
+	if len(w.pending) == 0 || w.bufw == nil {
+		return
+	}
+
+	if err := writeBlock(w.bufw, w.pending, len(w.labels)); err != nil {
+		log.Printf("❌ Failed to write prediction archive block: %v", err)
+	} else if err := w.bufw.Flush(); err != nil {
+		log.Printf("❌ Failed to flush prediction archive file: %v", err)
+	}
+
+	w.pending = w.pending[:0]
+}
+
+// openFile opens a new archive part for the given day and writes the file header.
+func (w *Writer) openFile(day string, labels []string) error {
+	path, err := nextPartPath(w.opts.Dir, day)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.New(err).
+			Component("predictionarchive").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_archive_file").
+			Context("path", path).
+			Build()
+	}
+
+	bufw := bufio.NewWriter(file)
+	if err := writeHeader(bufw, labels); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := bufw.Flush(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.bufw = bufw
+	w.labels = labels
+
+	// Cleanup only needs to run once per day
+	if day != w.day {
+		w.day = day
+		w.applyRetention()
+	}
+
+	return nil
+}
+
+// closeFile closes the current archive file if one is open.
+func (w *Writer) closeFile() {
+	if w.file == nil {
+		return
+	}
+	if err := w.bufw.Flush(); err != nil {
+		log.Printf("❌ Failed to flush prediction archive file: %v", err)
+	}
+	if err := w.file.Close(); err != nil {
+		log.Printf("❌ Failed to close prediction archive file: %v", err)
+	}
+	w.file = nil
+	w.bufw = nil
+}
+
+// applyRetention removes archive files older than the configured retention.
+func (w *Writer) applyRetention() {
+	if w.opts.RetentionDays <= 0 {
+		return
+	}
+
+	files, err := ListFiles(w.opts.Dir)
+	if err != nil {
+		log.Printf("❌ Failed to list prediction archive files: %v", err)
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -w.opts.RetentionDays).Format("2006-01-02")
+	for _, f := range files {
+		if fileDay(f) < cutoff {
+			if err := os.Remove(f); err != nil {
+				log.Printf("❌ Failed to remove expired prediction archive %s: %v", f, err)
+			}
+		}
+	}
+}
+
+// ListFiles returns the archive files in dir sorted by name, which is also chronological order.
+func ListFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, FileExtension) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fileDay extracts the YYYY-MM-DD part of an archive file name.
+func fileDay(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), filePrefix)
+	if len(name) < len("2006-01-02") {
+		return ""
+	}
+	return name[:len("2006-01-02")]
+}
+
+// nextPartPath returns the first unused file name for the given day.
+// The first part is named predictions-YYYY-MM-DD.bnpa, following parts
+// (after restarts or label changes) get a numeric suffix that sorts after it.
+func nextPartPath(dir, day string) (string, error) {
+	for part := 0; part < 10000; part++ {
+		name := filePrefix + day + FileExtension
+		if part > 0 {
+			name = fmt.Sprintf("%s%s_%04d%s", filePrefix, day, part, FileExtension)
+		}
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path, nil
+		}
+	}
+	return "", errors.Newf("too many prediction archive parts for %s", day).
+		Component("predictionarchive").
+		Category(errors.CategoryLimit).
+		Build()
+}
+
+// sameLabels reports whether two label slices are identical.
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	// Labels normally come from the same backing array, avoid a full compare
+	if len(a) > 0 && &a[0] == &b[0] {
+		return true
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeHeader writes the archive file header.
+func writeHeader(w io.Writer, labels []string) error {
+	if _, err := w.Write(magic[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, formatVersion); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(labels))); err != nil {
+		return err
+	}
+	for _, label := range labels {
+		if err := writeString(w, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBlock encodes records column by column, compresses them and writes the block.
+func writeBlock(w io.Writer, records []Record, labelCount int) error {
+	var payload bytes.Buffer
+	le := binary.LittleEndian
+
+	rows := len(records)
+	if err := binary.Write(&payload, le, uint32(rows)); err != nil {
+		return err
+	}
+
+	timestamps := make([]int64, rows)
+	sensitivity := make([]float32, rows)
+	overlap := make([]float32, rows)
+	for i := range records {
+		timestamps[i] = records[i].Timestamp.UnixNano()
+		sensitivity[i] = records[i].Sensitivity
+		overlap[i] = records[i].Overlap
+	}
+	for _, column := range []any{timestamps, sensitivity, overlap} {
+		if err := binary.Write(&payload, le, column); err != nil {
+			return err
+		}
+	}
+	for i := range records {
+		if err := writeString(&payload, records[i].Source); err != nil {
+			return err
+		}
+	}
+
+	row := make([]float32, labelCount)
+	for i := range records {
+		// Guard against vectors that do not match the header, pad or truncate
+		clear(row)
+		copy(row, records[i].Confidences)
+		if err := binary.Write(&payload, le, row); err != nil {
+			return err
+		}
+	}
+
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, flate.BestSpeed)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(payload.Bytes()); err != nil {
+		return err
+	}
+	if err := fw.Close(); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, le, uint32(compressed.Len())); err != nil {
+		return err
+	}
+	_, err = w.Write(compressed.Bytes())
+	return err
+}
+
+// writeString writes a uint16 length prefixed string, truncating overly long values.
+func writeString(w io.Writer, s string) error {
+	if len(s) > maxStringLength {
+		s = s[:maxStringLength]
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
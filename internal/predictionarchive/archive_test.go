@@ -0,0 +1,124 @@
+package predictionarchive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w, err := NewWriter(Options{Dir: dir, BlockSize: 3, FlushInterval: time.Hour})
+	require.NoError(t, err)
+
+	labels := []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit", "Human vocal_Human vocal"}
+	start := time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)
+	confidences := make([]float32, len(labels))
+
+	for i := 0; i < 7; i++ {
+		// Reuse the same buffer to verify the writer copies the vector
+		for j := range confidences {
+			confidences[j] = float32(i*10+j) / 100
+		}
+		w.Write(&Record{
+			Timestamp:   start.Add(time.Duration(i) * 3 * time.Second),
+			Source:      "rtsp://camera-1",
+			Sensitivity: 1.0,
+			Overlap:     1.5,
+			Labels:      labels,
+			Confidences: confidences,
+		})
+	}
+	require.NoError(t, w.Close())
+
+	files, err := ListFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "predictions-2024-05-01.bnpa", filepath.Base(files[0]))
+
+	r, err := Open(files[0])
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+
+	assert.Equal(t, labels, r.Labels())
+
+	var got []Record
+	require.NoError(t, r.ForEach(func(rec *Record) error {
+		got = append(got, *rec)
+		return nil
+	}))
+
+	require.Len(t, got, 7)
+	for i, rec := range got {
+		assert.True(t, rec.Timestamp.Equal(start.Add(time.Duration(i)*3*time.Second)))
+		assert.Equal(t, "rtsp://camera-1", rec.Source)
+		assert.InDelta(t, 1.5, rec.Overlap, 0.0001)
+		require.Len(t, rec.Confidences, len(labels))
+		for j, c := range rec.Confidences {
+			assert.InDelta(t, float32(i*10+j)/100, c, 0.0001)
+		}
+	}
+}
+
+func TestWriterRotatesDailyAndOnLabelChange(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w, err := NewWriter(Options{Dir: dir, FlushInterval: time.Hour})
+	require.NoError(t, err)
+
+	day1 := time.Date(2024, 5, 1, 23, 59, 58, 0, time.UTC)
+	w.Write(&Record{Timestamp: day1, Labels: []string{"a", "b"}, Confidences: []float32{0.1, 0.2}})
+	w.Write(&Record{Timestamp: day1.Add(3 * time.Second), Labels: []string{"a", "b"}, Confidences: []float32{0.3, 0.4}})
+	w.Write(&Record{Timestamp: day1.Add(6 * time.Second), Labels: []string{"a", "b", "c"}, Confidences: []float32{0.5, 0.6, 0.7}})
+	require.NoError(t, w.Close())
+
+	files, err := ListFiles(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	assert.Equal(t, []string{
+		"predictions-2024-05-01.bnpa",
+		"predictions-2024-05-02.bnpa",
+		"predictions-2024-05-02_0001.bnpa",
+	}, names)
+}
+
+func TestWriterRetention(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	old := filepath.Join(dir, "predictions-2000-01-01.bnpa")
+	require.NoError(t, os.WriteFile(old, []byte("BNPA"), 0o644))
+
+	w, err := NewWriter(Options{Dir: dir, RetentionDays: 7, FlushInterval: time.Hour})
+	require.NoError(t, err)
+	w.Write(&Record{Timestamp: time.Now(), Labels: []string{"a"}, Confidences: []float32{0.5}})
+	require.NoError(t, w.Close())
+
+	_, err = os.Stat(old)
+	assert.True(t, os.IsNotExist(err), "expired archive should be removed")
+
+	files, err := ListFiles(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestOpenRejectsForeignFiles(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "predictions-2024-01-01.bnpa")
+	require.NoError(t, os.WriteFile(path, []byte("not an archive"), 0o644))
+
+	_, err := Open(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package predictionarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// maxBlockSize protects the reader from allocating huge buffers on corrupted files
+const maxBlockSize = 1 << 30
+
+// Reader reads prediction records from a single archive file.
+type Reader struct {
+	file   *os.File
+	r      *bufio.Reader
+	labels []string
+}
+
+// Open opens an archive file and reads its header.
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("predictionarchive").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_archive").
+			Context("path", path).
+			Build()
+	}
+
+	r := &Reader{file: file, r: bufio.NewReader(file)}
+	if err := r.readHeader(); err != nil {
+		_ = file.Close()
+		return nil, errors.New(err).
+			Component("predictionarchive").
+			Category(errors.CategoryFileParsing).
+			Context("operation", "read_archive_header").
+			Context("path", path).
+			Build()
+	}
+
+	return r, nil
+}
+
+// Labels returns the labels of the model that produced the archive.
+func (r *Reader) Labels() []string {
+	return r.labels
+}
+
+// Close closes the underlying file.
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+// NextBlock returns the records of the next block. It returns io.EOF
+// when the end of the file has been reached. A block that was only
+// partially written, for example after a power loss, is reported as
+// io.ErrUnexpectedEOF.
+func (r *Reader) NextBlock() ([]Record, error) {
+	var size uint32
+	if err := binary.Read(r.r, binary.LittleEndian, &size); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	if size > maxBlockSize {
+		return nil, errors.Newf("prediction archive block size %d exceeds limit", size).
+			Component("predictionarchive").
+			Category(errors.CategoryFileParsing).
+			Build()
+	}
+
+	compressed := make([]byte, size)
+	if _, err := io.ReadFull(r.r, compressed); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	payload, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return nil, errors.New(err).
+			Component("predictionarchive").
+			Category(errors.CategoryFileParsing).
+			Context("operation", "decompress_block").
+			Build()
+	}
+
+	return r.decodeBlock(bytes.NewReader(payload))
+}
+
+// ForEach calls fn for every record in the file, in the order they were written.
+func (r *Reader) ForEach(fn func(rec *Record) error) error {
+	for {
+		records, err := r.NextBlock()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			if err := fn(&records[i]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readHeader validates the magic and version and reads the label list.
+func (r *Reader) readHeader() error {
+	var m [4]byte
+	if _, err := io.ReadFull(r.r, m[:]); err != nil {
+		return err
+	}
+	if m != magic {
+		return errors.Newf("not a prediction archive file").
+			Component("predictionarchive").
+			Category(errors.CategoryFileParsing).
+			Build()
+	}
+
+	var version uint16
+	if err := binary.Read(r.r, binary.LittleEndian, &version); err != nil {
+		return err
+	}
+	if version != formatVersion {
+		return errors.Newf("unsupported prediction archive version %d", version).
+			Component("predictionarchive").
+			Category(errors.CategoryFileParsing).
+			Build()
+	}
+
+	var count uint32
+	if err := binary.Read(r.r, binary.LittleEndian, &count); err != nil {
+		return err
+	}
+
+	r.labels = make([]string, 0, count)
+	for i := uint32(0); i < count; i++ {
+		label, err := readString(r.r)
+		if err != nil {
+			return err
+		}
+		r.labels = append(r.labels, label)
+	}
+	return nil
+}
+
+// decodeBlock decodes the columnar payload of a block into records.
+func (r *Reader) decodeBlock(payload io.Reader) ([]Record, error) {
+	le := binary.LittleEndian
+
+	var rows uint32
+	if err := binary.Read(payload, le, &rows); err != nil {
+		return nil, err
+	}
+
+	timestamps := make([]int64, rows)
+	sensitivity := make([]float32, rows)
+	overlap := make([]float32, rows)
+	for _, column := range []any{timestamps, sensitivity, overlap} {
+		if err := binary.Read(payload, le, column); err != nil {
+			return nil, err
+		}
+	}
+
+	records := make([]Record, rows)
+	for i := range records {
+		source, err := readString(payload)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = Record{
+			Timestamp:   time.Unix(0, timestamps[i]),
+			Source:      source,
+			Sensitivity: sensitivity[i],
+			Overlap:     overlap[i],
+			Labels:      r.labels,
+		}
+	}
+
+	// Read the confidence matrix in one go and slice it per row
+	matrix := make([]float32, int(rows)*len(r.labels))
+	if err := binary.Read(payload, le, matrix); err != nil {
+		return nil, err
+	}
+	for i := range records {
+		start := i * len(r.labels)
+		records[i].Confidences = matrix[start : start+len(r.labels) : start+len(r.labels)]
+	}
+
+	return records, nil
+}
+
+// readString reads a uint16 length prefixed string.
+func readString(r io.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return "", err
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -149,6 +149,13 @@ func (m *SystemInitManager) initializeNotification() error {
 		}
 		
 		m.logger.Info("notification service initialized successfully", "debug", debug)
+
+		// Start external push channels, failures leave the in-app notifications working
+		if settings != nil && settings.Notification.Push.Enabled {
+			if err := notification.InitializePush(&settings.Notification.Push); err != nil {
+				m.logger.Warn("failed to start push notification channels", "error", err)
+			}
+		}
 	})
 	
 	return m.notificationErr
@@ -327,6 +334,7 @@ func (m *SystemInitManager) Shutdown(ctx context.Context) error {
 	if notification.IsInitialized() {
 		if service := notification.GetService(); service != nil {
 			m.logger.Info("stopping notification service")
+			notification.StopPush()
 			service.Stop()
 		}
 	}